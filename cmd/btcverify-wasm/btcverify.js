@@ -0,0 +1,49 @@
+// btcverify.js loads btcverify.wasm and wraps the functions it registers.
+// wasm_exec.js, from the lib/wasm directory of the Go release btcverify.wasm
+// was built with, must be loaded first, as it defines globalThis.Go.
+//
+//   import { load } from "./btcverify.js";
+//   const btcverify = await load();
+//   const { valid, code, error } = btcverify.verify(address, message, signature);
+
+/**
+ * Loads btcverify.wasm from a URL, a fetch Response or its bytes, by default
+ * next to this file, and returns the verify and sign functions.
+ */
+export async function load(source = new URL("btcverify.wasm", import.meta.url)) {
+  if (typeof globalThis.Go !== "function") {
+    throw new Error("btcverify: load wasm_exec.js before btcverify.js");
+  }
+
+  const go = new globalThis.Go();
+  let result;
+  if (source instanceof ArrayBuffer || ArrayBuffer.isView(source)) {
+    result = await WebAssembly.instantiate(source, go.importObject);
+  } else {
+    const response = source instanceof Response ? source : fetch(source);
+    result = await WebAssembly.instantiateStreaming(response, go.importObject);
+  }
+  // The Go program registers globalThis.btcverify and then blocks forever,
+  // so the promise returned by run never settles
+  go.run(result.instance);
+
+  const api = globalThis.btcverify;
+  return {
+    /**
+     * Verifies a signed message, returning {valid, network} or, when
+     * verification fails, {valid: false, code, error}.
+     */
+    verify(address, message, signature, network = "mainnet") {
+      return api.verify({ address, message, signature, network });
+    },
+
+    /**
+     * Signs a message with a WIF private key for an address of the given
+     * type, returning {address, message, signature, network, type} or
+     * {code, error}.
+     */
+    sign(wif, message, { type = "p2wpkh", network = "mainnet" } = {}) {
+      return api.sign({ wif, message, type, network });
+    },
+  };
+}
@@ -0,0 +1,140 @@
+//go:build js && wasm
+
+// Package main builds btcverify.wasm, which exposes the verifier to
+// JavaScript so browsers can verify proofs client-side with the same code as
+// the server. It registers a global btcverify object with two functions,
+// wrapped by btcverify.js:
+//
+//	btcverify.verify({address, message, signature, network})
+//	btcverify.sign({wif, message, type, network})
+//
+// Both return a plain object; failures carry the error code and message in
+// its code and error properties instead of throwing. Build it with:
+//
+//	GOOS=js GOARCH=wasm go build -o btcverify.wasm ./cmd/btcverify-wasm
+package main
+
+import (
+	"syscall/js"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/signer"
+)
+
+// networks maps network names to their parameters
+var networks = map[string]*chaincfg.Params{
+	"mainnet":  &chaincfg.MainNetParams,
+	"testnet":  &chaincfg.TestNet3Params,
+	"testnet3": &chaincfg.TestNet3Params,
+	"regtest":  &chaincfg.RegressionNetParams,
+	"signet":   &chaincfg.SigNetParams,
+}
+
+func main() {
+	// Keep the library logs out of the console unless the page asks for them
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	js.Global().Set("btcverify", js.ValueOf(map[string]interface{}{
+		"verify": js.FuncOf(jsVerify),
+		"sign":   js.FuncOf(jsSign),
+	}))
+
+	// The exported functions are called from JavaScript after main returns
+	// control, so it must never exit
+	select {}
+}
+
+// jsVerify verifies the signed message passed as the first argument
+func jsVerify(_ js.Value, args []js.Value) interface{} {
+	req := argument(args)
+	params, failure := network(req)
+	if failure != nil {
+		failure["valid"] = false
+		return failure
+	}
+
+	result, err := verify.NewVerifier(verify.WithParams(params)).Verify(verify.SignedMessage{
+		Address:   stringProperty(req, "address"),
+		Message:   stringProperty(req, "message"),
+		Signature: stringProperty(req, "signature"),
+	})
+	if err != nil {
+		return errorObject(err, map[string]interface{}{"valid": false})
+	}
+	return map[string]interface{}{"valid": result.Valid, "network": params.Name}
+}
+
+// jsSign signs the message of the first argument with its WIF private key
+func jsSign(_ js.Value, args []js.Value) interface{} {
+	req := argument(args)
+	params, failure := network(req)
+	if failure != nil {
+		return failure
+	}
+
+	addrType := verify.AddressType(stringProperty(req, "type"))
+	if addrType == "" {
+		addrType = verify.AddressTypeP2WPKH
+	}
+	key, err := signer.ImportWIF(stringProperty(req, "wif"), params)
+	if err != nil {
+		return errorObject(err, nil)
+	}
+	msg, err := key.Sign(stringProperty(req, "message"), addrType)
+	if err != nil {
+		return errorObject(err, nil)
+	}
+	return map[string]interface{}{
+		"address":   msg.Address,
+		"message":   msg.Message,
+		"signature": msg.Signature,
+		"network":   params.Name,
+		"type":      string(addrType),
+	}
+}
+
+// argument returns the object passed as the first argument, or undefined
+func argument(args []js.Value) js.Value {
+	if len(args) == 0 || args[0].Type() != js.TypeObject {
+		return js.Undefined()
+	}
+	return args[0]
+}
+
+// stringProperty returns the string property of an object, or "" when the
+// object or property is missing or not a string
+func stringProperty(obj js.Value, name string) string {
+	if obj.Type() != js.TypeObject {
+		return ""
+	}
+	if v := obj.Get(name); v.Type() == js.TypeString {
+		return v.String()
+	}
+	return ""
+}
+
+// network returns the parameters of the network named by the request,
+// mainnet by default, or the error object of an unknown network
+func network(req js.Value) (*chaincfg.Params, map[string]interface{}) {
+	name := stringProperty(req, "network")
+	if name == "" {
+		name = "mainnet"
+	}
+	params, ok := networks[name]
+	if !ok {
+		return nil, map[string]interface{}{"code": string(verify.CodeUnknown), "error": "unknown network " + name}
+	}
+	return params, nil
+}
+
+// errorObject returns the properties with the error code and message of err
+// added
+func errorObject(err error, properties map[string]interface{}) map[string]interface{} {
+	if properties == nil {
+		properties = make(map[string]interface{})
+	}
+	properties["code"] = string(verify.ErrorCodeOf(err))
+	properties["error"] = err.Error()
+	return properties
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/sero/btc/verify"
+)
+
+// batchRecord is the result of one input row, written as a line of JSON
+type batchRecord struct {
+	Row     int              `json:"row"`
+	Address string           `json:"address"`
+	Valid   bool             `json:"valid"`
+	Code    verify.ErrorCode `json:"code,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// runBatch implements the batch command: it verifies the signed messages of
+// a CSV or JSONL file and writes one JSON result per row
+func runBatch(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("btcverify batch", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: btcverify batch --in FILE [--out FILE] [flags]")
+		fs.PrintDefaults()
+	}
+
+	var vf verifierFlags
+	in := fs.String("in", "-", "input file of address, message and signature rows, - for standard input")
+	out := fs.String("out", "-", "output file for the JSONL results, - for standard output")
+	format := fs.String("format", "", "input format: csv or jsonl (default from the input file extension, jsonl for standard input)")
+	concurrency := fs.Int("concurrency", runtime.GOMAXPROCS(0), "number of rows verified concurrently")
+	vf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitMalformed
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "btcverify batch: unexpected argument %q\n", fs.Arg(0))
+		return exitMalformed
+	}
+
+	cfg, err := vf.load(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify batch: %v\n", err)
+		return exitMalformed
+	}
+	v := cfg.verifier(stderr)
+
+	if *format == "" {
+		*format = "jsonl"
+		if strings.EqualFold(filepath.Ext(*in), ".csv") {
+			*format = "csv"
+		}
+	}
+
+	r := stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			fmt.Fprintf(stderr, "btcverify batch: %v\n", err)
+			return exitMalformed
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var msgs []verify.SignedMessage
+	switch *format {
+	case "csv":
+		msgs, err = readCSV(r)
+	case "jsonl":
+		msgs, err = readJSONL(r)
+	default:
+		err = fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify batch: %v\n", err)
+		return exitMalformed
+	}
+
+	var w io.Writer = stdout
+	if *out != "-" {
+		f, err := os.Create(*out)
+		if err != nil {
+			fmt.Fprintf(stderr, "btcverify batch: %v\n", err)
+			return exitInternal
+		}
+		defer f.Close()
+		w = f
+	}
+
+	records := verifyRows(v, msgs, *concurrency)
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	invalid := 0
+	for _, rec := range records {
+		if !rec.Valid {
+			invalid++
+		}
+		if err := enc.Encode(rec); err != nil {
+			fmt.Fprintf(stderr, "btcverify batch: %v\n", err)
+			return exitInternal
+		}
+	}
+	if err := bw.Flush(); err != nil {
+		fmt.Fprintf(stderr, "btcverify batch: %v\n", err)
+		return exitInternal
+	}
+
+	fmt.Fprintf(stderr, "verified %d rows: %d valid, %d invalid\n", len(records), len(records)-invalid, invalid)
+	if invalid > 0 {
+		return exitInvalid
+	}
+	return exitValid
+}
+
+// verifyRows verifies the messages with up to concurrency goroutines,
+// returning their records in input order
+func verifyRows(v *verify.Verifier, msgs []verify.SignedMessage, concurrency int) []batchRecord {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	records := make([]batchRecord, len(msgs))
+	rows := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range rows {
+				rec := batchRecord{Row: i + 1, Address: msgs[i].Address}
+				result, err := v.Verify(msgs[i])
+				rec.Valid = err == nil && result.Valid
+				if err != nil {
+					rec.Code = verify.ErrorCodeOf(err)
+					rec.Error = err.Error()
+				} else if !result.Valid {
+					rec.Code = verify.CodeInvalidSignature
+				}
+				records[i] = rec
+			}
+		}()
+	}
+	for i := range msgs {
+		rows <- i
+	}
+	close(rows)
+	wg.Wait()
+
+	return records
+}
+
+// readCSV reads signed messages from CSV with a header row naming the
+// address, message and signature columns
+func readCSV(r io.Reader) ([]verify.SignedMessage, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"address", "message", "signature"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header has no %q column", name)
+		}
+	}
+
+	var msgs []verify.SignedMessage
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return msgs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, verify.SignedMessage{
+			Address:   row[columns["address"]],
+			Message:   row[columns["message"]],
+			Signature: row[columns["signature"]],
+		})
+	}
+}
+
+// readJSONL reads signed messages from JSON objects with address, message
+// and signature fields, one per line. Blank lines are skipped.
+func readJSONL(r io.Reader) ([]verify.SignedMessage, error) {
+	var msgs []verify.SignedMessage
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16<<20)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var msg verify.SignedMessage
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, scanner.Err()
+}
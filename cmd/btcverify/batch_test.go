@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+func TestRunBatch(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	const (
+		address   = "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"
+		signature = "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="
+	)
+
+	tests := []struct {
+		name     string
+		file     string
+		input    string
+		wantCode int
+		want     []batchRecord
+	}{
+		{
+			name:     "CSV",
+			file:     "proofs.csv",
+			input:    "signature,address,message\n" + signature + "," + address + ",test message\n" + signature + "," + address + ",\"other, message\"\n",
+			wantCode: exitInvalid,
+			want: []batchRecord{
+				{Row: 1, Address: address, Valid: true},
+				{Row: 2, Address: address, Code: verify.CodeAddressMismatch},
+			},
+		},
+		{
+			name:     "JSONL",
+			file:     "proofs.jsonl",
+			input:    `{"address":"` + address + `","message":"test message","signature":"` + signature + `"}` + "\n\n" + `{"address":"` + address + `","message":"test message"}` + "\n",
+			wantCode: exitInvalid,
+			want: []batchRecord{
+				{Row: 1, Address: address, Valid: true},
+				{Row: 2, Address: address, Code: verify.CodeEmptySignature},
+			},
+		},
+		{
+			name:     "All valid",
+			file:     "proofs.jsonl",
+			input:    `{"address":"` + address + `","message":"test message","signature":"` + signature + `"}` + "\n",
+			wantCode: exitValid,
+			want: []batchRecord{
+				{Row: 1, Address: address, Valid: true},
+			},
+		},
+		{
+			name:     "CSV without signature column",
+			file:     "proofs.csv",
+			input:    "address,message\n" + address + ",test message\n",
+			wantCode: exitMalformed,
+		},
+		{
+			name:     "Malformed JSONL",
+			file:     "proofs.jsonl",
+			input:    "{\n",
+			wantCode: exitMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			in := filepath.Join(dir, tt.file)
+			out := filepath.Join(dir, "results.jsonl")
+			if err := os.WriteFile(in, []byte(tt.input), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout, stderr bytes.Buffer
+			if got := run([]string{"batch", "--in", in, "--out", out}, strings.NewReader(""), &stdout, &stderr); got != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", got, tt.wantCode, stderr.String())
+			}
+			if tt.want == nil {
+				return
+			}
+
+			got := readRecords(t, out)
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d records, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				got[i].Error = ""
+				if got[i] != tt.want[i] {
+					t.Errorf("record %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+// Helper function to read the records of a results file
+func readRecords(t *testing.T, path string) []batchRecord {
+	t.Helper()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var records []batchRecord
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var rec batchRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid result line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/httpserver"
+	"github.com/sero/btc/verify/ratelimit"
+	"gopkg.in/yaml.v3"
+)
+
+// envPrefix prefixes the environment variables read by the commands
+const envPrefix = "BTCVERIFY_"
+
+// config holds the settings shared by the commands. Settings are read from
+// the config file, then from BTCVERIFY_* environment variables, then from
+// flags, each overriding the previous ones.
+type config struct {
+	Network        string `yaml:"network"`
+	LogLevel       string `yaml:"log_level"`
+	MaxMessageSize *int64 `yaml:"max_message_size"`
+	Listen         string `yaml:"listen"`
+	Base64         string `yaml:"base64"`
+	LineEndings    string `yaml:"line_endings"`
+	RequireLowS    bool   `yaml:"require_low_s"`
+	StrictLength   bool   `yaml:"strict_length"`
+	StrictHeader   bool   `yaml:"strict_header"`
+	CrossCheck     bool   `yaml:"cross_check"`
+
+	// Message hashes cached by the verifier, 0 for no cache
+	CacheSize int `yaml:"cache_size"`
+
+	// Addresses proofs are only accepted from and addresses they are
+	// rejected from, as exact addresses or prefixes ending with "*"
+	AllowAddresses []string `yaml:"allow_addresses"`
+	DenyAddresses  []string `yaml:"deny_addresses"`
+
+	// Requests per minute allowed per client IP and per address by the
+	// serve command, 0 for no limit
+	RateLimitIP      int `yaml:"rate_limit_ip"`
+	RateLimitAddress int `yaml:"rate_limit_address"`
+
+	// PEM files of the serve command's TLS certificate and key, and of the
+	// CAs client certificates must be signed by to enable mutual TLS
+	TLSCert     string `yaml:"tls_cert"`
+	TLSKey      string `yaml:"tls_key"`
+	TLSClientCA string `yaml:"tls_client_ca"`
+}
+
+// defaultConfig returns the settings used when nothing overrides them
+func defaultConfig() config {
+	return config{
+		Network:     "mainnet",
+		LogLevel:    "none",
+		Listen:      ":8080",
+		Base64:      "default",
+		LineEndings: "exact",
+	}
+}
+
+// networks maps network names to their parameters
+var networks = map[string]*chaincfg.Params{
+	"mainnet":  &chaincfg.MainNetParams,
+	"testnet":  &chaincfg.TestNet3Params,
+	"testnet3": &chaincfg.TestNet3Params,
+	"regtest":  &chaincfg.RegressionNetParams,
+	"signet":   &chaincfg.SigNetParams,
+}
+
+// base64Modes maps base64 mode names to their modes
+var base64Modes = map[string]verify.Base64Mode{
+	"default":    verify.Base64Default,
+	"strict":     verify.Base64Strict,
+	"permissive": verify.Base64Permissive,
+}
+
+// lineEndings maps line ending mode names to their modes
+var lineEndings = map[string]verify.LineEndings{
+	"exact": verify.LineEndingsExact,
+	"lf":    verify.LineEndingsLF,
+	"crlf":  verify.LineEndingsCRLF,
+	"any":   verify.LineEndingsAny,
+}
+
+// logLevels maps log level names to their levels
+var logLevels = map[string]verify.LogLevel{
+	"none":    verify.LogLevelNone,
+	"error":   verify.LogLevelError,
+	"warning": verify.LogLevelWarning,
+	"info":    verify.LogLevelInfo,
+	"debug":   verify.LogLevelDebug,
+	"trace":   verify.LogLevelTrace,
+}
+
+// loadFile reads the YAML config file at path into c. Unknown keys are
+// rejected, so typos don't go unnoticed.
+func (c *config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %v", path, err)
+	}
+	return nil
+}
+
+// loadEnv overrides the settings of c with the BTCVERIFY_* environment
+// variables that are set, such as BTCVERIFY_NETWORK
+func (c *config) loadEnv(getenv func(string) string) error {
+	stringVars := map[string]*string{
+		"NETWORK":       &c.Network,
+		"LOG_LEVEL":     &c.LogLevel,
+		"LISTEN":        &c.Listen,
+		"BASE64":        &c.Base64,
+		"LINE_ENDINGS":  &c.LineEndings,
+		"TLS_CERT":      &c.TLSCert,
+		"TLS_KEY":       &c.TLSKey,
+		"TLS_CLIENT_CA": &c.TLSClientCA,
+	}
+	for name, p := range stringVars {
+		if v := getenv(envPrefix + name); v != "" {
+			*p = v
+		}
+	}
+
+	boolVars := map[string]*bool{
+		"REQUIRE_LOW_S": &c.RequireLowS,
+		"STRICT_LENGTH": &c.StrictLength,
+		"STRICT_HEADER": &c.StrictHeader,
+		"CROSS_CHECK":   &c.CrossCheck,
+	}
+	for name, p := range boolVars {
+		if v := getenv(envPrefix + name); v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return fmt.Errorf("%s%s: %v", envPrefix, name, err)
+			}
+			*p = b
+		}
+	}
+
+	listVars := map[string]*[]string{
+		"ALLOW_ADDRESSES": &c.AllowAddresses,
+		"DENY_ADDRESSES":  &c.DenyAddresses,
+	}
+	for name, p := range listVars {
+		if v := getenv(envPrefix + name); v != "" {
+			*p = splitList(v)
+		}
+	}
+
+	intVars := map[string]*int{
+		"RATE_LIMIT_IP":      &c.RateLimitIP,
+		"RATE_LIMIT_ADDRESS": &c.RateLimitAddress,
+		"CACHE_SIZE":         &c.CacheSize,
+	}
+	for name, p := range intVars {
+		if v := getenv(envPrefix + name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return fmt.Errorf("%s%s: %v", envPrefix, name, err)
+			}
+			*p = n
+		}
+	}
+
+	if v := getenv(envPrefix + "MAX_MESSAGE_SIZE"); v != "" {
+		size, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%sMAX_MESSAGE_SIZE: %v", envPrefix, err)
+		}
+		c.MaxMessageSize = &size
+	}
+	return nil
+}
+
+// validate checks that every setting has a known value
+func (c *config) validate() error {
+	if _, ok := networks[c.Network]; !ok {
+		return fmt.Errorf("unknown network %q", c.Network)
+	}
+	if _, ok := logLevels[strings.ToLower(c.LogLevel)]; !ok {
+		return fmt.Errorf("unknown log level %q", c.LogLevel)
+	}
+	if _, ok := base64Modes[c.Base64]; !ok {
+		return fmt.Errorf("unknown base64 mode %q", c.Base64)
+	}
+	if _, ok := lineEndings[c.LineEndings]; !ok {
+		return fmt.Errorf("unknown line endings %q", c.LineEndings)
+	}
+	if c.MaxMessageSize != nil && *c.MaxMessageSize < 0 {
+		return fmt.Errorf("negative max message size %d", *c.MaxMessageSize)
+	}
+	if c.CacheSize < 0 {
+		return fmt.Errorf("negative cache size %d", c.CacheSize)
+	}
+	if c.Listen == "" {
+		return errors.New("empty listen address")
+	}
+	if c.RateLimitIP < 0 || c.RateLimitAddress < 0 {
+		return errors.New("negative rate limit")
+	}
+	if (c.TLSCert == "") != (c.TLSKey == "") {
+		return errors.New("tls_cert and tls_key must be set together")
+	}
+	if c.TLSClientCA != "" && c.TLSCert == "" {
+		return errors.New("tls_client_ca requires tls_cert and tls_key")
+	}
+	return nil
+}
+
+// verifier creates the Verifier configured by c, logging to logOut. c must
+// be valid.
+func (c *config) verifier(logOut io.Writer) *verify.Verifier {
+	opts := []verify.Option{
+		verify.WithParams(networks[c.Network]),
+		verify.WithBase64Mode(base64Modes[c.Base64]),
+		verify.WithLineEndings(lineEndings[c.LineEndings]),
+		verify.WithLogger(verify.NewStdLogger(log.New(logOut, "", log.LstdFlags))),
+		verify.WithLogLevel(logLevels[strings.ToLower(c.LogLevel)]),
+	}
+	if c.MaxMessageSize != nil {
+		opts = append(opts, verify.WithMaxMessageSize(*c.MaxMessageSize))
+	}
+	if c.CacheSize > 0 {
+		opts = append(opts, verify.WithHashCacheSize(c.CacheSize))
+	}
+	if c.RequireLowS {
+		opts = append(opts, verify.WithRequireLowS())
+	}
+	if c.StrictLength {
+		opts = append(opts, verify.WithStrictLength())
+	}
+	if c.StrictHeader {
+		opts = append(opts, verify.WithStrictHeader())
+	}
+	if c.CrossCheck {
+		opts = append(opts, verify.WithCrossCheck())
+	}
+	if len(c.AllowAddresses) > 0 || len(c.DenyAddresses) > 0 {
+		opts = append(opts, verify.WithAddressFilter(verify.AddressFilter{Allow: c.AllowAddresses, Deny: c.DenyAddresses}))
+	}
+	return verify.NewVerifier(opts...)
+}
+
+// splitList splits a comma-separated list, dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// tlsConfig returns the TLS configuration of the serve command, or nil when
+// TLS isn't enabled. With a client CA, clients must present a certificate
+// signed by it.
+func (c *config) tlsConfig() (*tls.Config, error) {
+	if c.TLSCert == "" {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.TLSCert, c.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("loading TLS certificate: %v", err)
+	}
+	tlsConfig := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if c.TLSClientCA != "" {
+		pem, err := os.ReadFile(c.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("loading client CA: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("loading client CA: no certificate in %s", c.TLSClientCA)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// serverOptions returns the options of the HTTP server configured by c,
+// verifying with v
+func (c *config) serverOptions(v *verify.Verifier) []httpserver.Option {
+	opts := []httpserver.Option{httpserver.WithVerifier(v)}
+	if c.RateLimitIP > 0 {
+		opts = append(opts, httpserver.WithIPRateLimit(ratelimit.PerMinute(c.RateLimitIP)))
+	}
+	if c.RateLimitAddress > 0 {
+		opts = append(opts, httpserver.WithAddressRateLimit(ratelimit.PerMinute(c.RateLimitAddress)))
+	}
+	return opts
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerifierFlagsLoad(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "btcverify.yaml")
+	if err := os.WriteFile(file, []byte("network: testnet\nlisten: :9000\nmax_message_size: 1024\ncache_size: 500\nstrict_header: true\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	typo := filepath.Join(dir, "typo.yaml")
+	if err := os.WriteFile(typo, []byte("netwrok: testnet\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		env     map[string]string
+		check   func(t *testing.T, cfg config)
+		wantErr bool
+	}{
+		{
+			name: "Defaults",
+			check: func(t *testing.T, cfg config) {
+				if cfg.Network != "mainnet" || cfg.Listen != ":8080" || cfg.MaxMessageSize != nil {
+					t.Errorf("config = %+v, want the defaults", cfg)
+				}
+			},
+		},
+		{
+			name: "Config file",
+			args: []string{"--config", file},
+			check: func(t *testing.T, cfg config) {
+				if cfg.Network != "testnet" || cfg.Listen != ":9000" || *cfg.MaxMessageSize != 1024 || cfg.CacheSize != 500 || !cfg.StrictHeader {
+					t.Errorf("config = %+v, want the settings of the file", cfg)
+				}
+			},
+		},
+		{
+			name: "Config file from environment",
+			env:  map[string]string{"BTCVERIFY_CONFIG": file},
+			check: func(t *testing.T, cfg config) {
+				if cfg.Network != "testnet" {
+					t.Errorf("network = %q, want %q", cfg.Network, "testnet")
+				}
+			},
+		},
+		{
+			name: "Environment overrides config file",
+			args: []string{"--config", file},
+			env:  map[string]string{"BTCVERIFY_NETWORK": "signet", "BTCVERIFY_STRICT_HEADER": "false"},
+			check: func(t *testing.T, cfg config) {
+				if cfg.Network != "signet" || cfg.StrictHeader || cfg.Listen != ":9000" {
+					t.Errorf("config = %+v, want network and strict header from the environment", cfg)
+				}
+			},
+		},
+		{
+			name: "Flags override environment",
+			args: []string{"--config", file, "--network", "regtest", "--max-message-size", "0", "--listen", ":7000"},
+			env:  map[string]string{"BTCVERIFY_NETWORK": "signet", "BTCVERIFY_MAX_MESSAGE_SIZE": "10"},
+			check: func(t *testing.T, cfg config) {
+				if cfg.Network != "regtest" || *cfg.MaxMessageSize != 0 || cfg.Listen != ":7000" {
+					t.Errorf("config = %+v, want the settings of the flags", cfg)
+				}
+			},
+		},
+		{
+			name:    "Unknown key in config file",
+			args:    []string{"--config", typo},
+			wantErr: true,
+		},
+		{
+			name:    "Missing config file",
+			args:    []string{"--config", filepath.Join(dir, "missing.yaml")},
+			wantErr: true,
+		},
+		{
+			name:    "Invalid environment value",
+			env:     map[string]string{"BTCVERIFY_CROSS_CHECK": "maybe"},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown log level",
+			env:     map[string]string{"BTCVERIFY_LOG_LEVEL": "loud"},
+			wantErr: true,
+		},
+		{
+			name: "Rate limits",
+			args: []string{"--rate-limit-ip", "600"},
+			env:  map[string]string{"BTCVERIFY_RATE_LIMIT_IP": "60", "BTCVERIFY_RATE_LIMIT_ADDRESS": "30"},
+			check: func(t *testing.T, cfg config) {
+				if cfg.RateLimitIP != 600 || cfg.RateLimitAddress != 30 {
+					t.Errorf("rate limits = %d per IP and %d per address, want 600 and 30", cfg.RateLimitIP, cfg.RateLimitAddress)
+				}
+			},
+		},
+		{
+			name:    "Negative rate limit",
+			env:     map[string]string{"BTCVERIFY_RATE_LIMIT_ADDRESS": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "TLS certificate without key",
+			args:    []string{"--tls-cert", "server.pem"},
+			wantErr: true,
+		},
+		{
+			name:    "Client CA without certificate",
+			env:     map[string]string{"BTCVERIFY_TLS_CLIENT_CA": "ca.pem"},
+			wantErr: true,
+		},
+		{
+			name: "Address lists",
+			args: []string{"--deny-address", "bc1p*", "--deny-address", "1BoatSLRHtKNngkdXEeobR76b53LETtpyT"},
+			env:  map[string]string{"BTCVERIFY_ALLOW_ADDRESSES": "bc1q*, 3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"},
+			check: func(t *testing.T, cfg config) {
+				if len(cfg.AllowAddresses) != 2 || len(cfg.DenyAddresses) != 2 || cfg.DenyAddresses[0] != "bc1p*" {
+					t.Errorf("addresses = allow %q, deny %q, want two of each", cfg.AllowAddresses, cfg.DenyAddresses)
+				}
+			},
+		},
+		{
+			name: "Cache size",
+			args: []string{"--config", file, "--cache-size", "2000"},
+			env:  map[string]string{"BTCVERIFY_CACHE_SIZE": "1000"},
+			check: func(t *testing.T, cfg config) {
+				if cfg.CacheSize != 2000 {
+					t.Errorf("cache size = %d, want 2000", cfg.CacheSize)
+				}
+			},
+		},
+		{
+			name: "Cache size from environment",
+			env:  map[string]string{"BTCVERIFY_CACHE_SIZE": "1000"},
+			check: func(t *testing.T, cfg config) {
+				if cfg.CacheSize != 1000 {
+					t.Errorf("cache size = %d, want 1000", cfg.CacheSize)
+				}
+			},
+		},
+		{
+			name:    "Negative cache size",
+			env:     map[string]string{"BTCVERIFY_CACHE_SIZE": "-1"},
+			wantErr: true,
+		},
+		{
+			name:    "Negative message size",
+			args:    []string{"--max-message-size", "-1"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("test", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			var vf verifierFlags
+			vf.register(fs)
+			vf.registerServe(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := vf.loadWithEnv(fs, func(key string) string { return tt.env[key] })
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, cfg)
+			}
+		})
+	}
+}
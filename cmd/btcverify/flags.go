@@ -0,0 +1,121 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/sero/btc/verify"
+)
+
+// verifierFlags are the flags configuring the Verifier of a command. Flags
+// that are given override the config file and the environment.
+type verifierFlags struct {
+	configPath     string
+	values         config
+	maxMessageSize int64
+}
+
+// register adds the flags to fs
+func (f *verifierFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.configPath, "config", "", "YAML config file (default $BTCVERIFY_CONFIG)")
+	fs.StringVar(&f.values.Network, "network", "mainnet", "network of the address: mainnet, testnet, regtest or signet")
+	fs.StringVar(&f.values.LogLevel, "log-level", "none", "library log level: none, error, warning, info, debug or trace")
+	fs.Int64Var(&f.maxMessageSize, "max-message-size", verify.DefaultMaxMessageSize, "maximum message size in bytes, 0 for no limit")
+	fs.IntVar(&f.values.CacheSize, "cache-size", 0, "message hashes cached by the verifier, 0 for no cache")
+	fs.StringVar(&f.values.Base64, "base64", "default", "base64 decoding of the signature: default, strict or permissive")
+	fs.StringVar(&f.values.LineEndings, "line-endings", "exact", "line endings of the message: exact, lf, crlf or any")
+	fs.BoolVar(&f.values.RequireLowS, "require-low-s", false, "reject signatures with a high S value")
+	fs.BoolVar(&f.values.StrictLength, "strict-length", false, "only accept 65-byte compact signatures")
+	fs.BoolVar(&f.values.StrictHeader, "strict-header", false, "reject header bytes for another address type")
+	fs.BoolVar(&f.values.CrossCheck, "cross-check", false, "verify with both verification engines")
+	fs.Func("allow-address", "only accept proofs from this address, or prefix ending with *; repeatable", func(s string) error {
+		f.values.AllowAddresses = append(f.values.AllowAddresses, splitList(s)...)
+		return nil
+	})
+	fs.Func("deny-address", "reject proofs from this address, or prefix ending with *; repeatable", func(s string) error {
+		f.values.DenyAddresses = append(f.values.DenyAddresses, splitList(s)...)
+		return nil
+	})
+}
+
+// registerServe adds the flags of the serve command to fs
+func (f *verifierFlags) registerServe(fs *flag.FlagSet) {
+	fs.StringVar(&f.values.Listen, "listen", ":8080", "address to listen on")
+	fs.IntVar(&f.values.RateLimitIP, "rate-limit-ip", 0, "verifications per minute allowed per client IP, 0 for no limit")
+	fs.IntVar(&f.values.RateLimitAddress, "rate-limit-address", 0, "verifications per minute allowed per address, 0 for no limit")
+	fs.StringVar(&f.values.TLSCert, "tls-cert", "", "PEM certificate file, enables TLS with --tls-key")
+	fs.StringVar(&f.values.TLSKey, "tls-key", "", "PEM private key file of --tls-cert")
+	fs.StringVar(&f.values.TLSClientCA, "tls-client-ca", "", "PEM file of the CAs client certificates must be signed by, enables mutual TLS")
+}
+
+// load resolves the settings of a command after fs was parsed: defaults,
+// then the config file, then the environment, then the flags given
+func (f *verifierFlags) load(fs *flag.FlagSet) (config, error) {
+	return f.loadWithEnv(fs, os.Getenv)
+}
+
+// loadWithEnv is like load, reading the environment with getenv
+func (f *verifierFlags) loadWithEnv(fs *flag.FlagSet, getenv func(string) string) (config, error) {
+	cfg := defaultConfig()
+
+	path := f.configPath
+	if path == "" {
+		path = getenv(envPrefix + "CONFIG")
+	}
+	if path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return config{}, err
+		}
+	}
+
+	if err := cfg.loadEnv(getenv); err != nil {
+		return config{}, err
+	}
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "network":
+			cfg.Network = f.values.Network
+		case "log-level":
+			cfg.LogLevel = f.values.LogLevel
+		case "max-message-size":
+			size := f.maxMessageSize
+			cfg.MaxMessageSize = &size
+		case "cache-size":
+			cfg.CacheSize = f.values.CacheSize
+		case "listen":
+			cfg.Listen = f.values.Listen
+		case "rate-limit-ip":
+			cfg.RateLimitIP = f.values.RateLimitIP
+		case "rate-limit-address":
+			cfg.RateLimitAddress = f.values.RateLimitAddress
+		case "tls-cert":
+			cfg.TLSCert = f.values.TLSCert
+		case "tls-key":
+			cfg.TLSKey = f.values.TLSKey
+		case "tls-client-ca":
+			cfg.TLSClientCA = f.values.TLSClientCA
+		case "base64":
+			cfg.Base64 = f.values.Base64
+		case "line-endings":
+			cfg.LineEndings = f.values.LineEndings
+		case "require-low-s":
+			cfg.RequireLowS = f.values.RequireLowS
+		case "strict-length":
+			cfg.StrictLength = f.values.StrictLength
+		case "strict-header":
+			cfg.StrictHeader = f.values.StrictHeader
+		case "cross-check":
+			cfg.CrossCheck = f.values.CrossCheck
+		case "allow-address":
+			cfg.AllowAddresses = f.values.AllowAddresses
+		case "deny-address":
+			cfg.DenyAddresses = f.values.DenyAddresses
+		}
+	})
+
+	if err := cfg.validate(); err != nil {
+		return config{}, err
+	}
+	return cfg, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+func TestRunGenVectors(t *testing.T) {
+	generate := func(args ...string) []testVector {
+		t.Helper()
+		var stdout, stderr bytes.Buffer
+		if got := run(append([]string{"gen-vectors"}, args...), strings.NewReader(""), &stdout, &stderr); got != exitValid {
+			t.Fatalf("run() = %d, want %d (stderr: %s)", got, exitValid, stderr.String())
+		}
+		var vectors []testVector
+		if err := json.Unmarshal(stdout.Bytes(), &vectors); err != nil {
+			t.Fatalf("output isn't JSON: %v", err)
+		}
+		return vectors
+	}
+
+	vectors := generate()
+	if want := len(headerClasses) * len(vectorNetworks) * len(vectorMessages); len(vectors) != want {
+		t.Fatalf("generated %d vectors, want %d", len(vectors), want)
+	}
+
+	offsets := map[string]byte{}
+	for _, class := range headerClasses {
+		offsets[class.name] = class.offset
+	}
+	for _, vec := range vectors {
+		sig, err := verify.DecodeCompactSignature(vec.Signature)
+		if err != nil {
+			t.Fatalf("vector %q: DecodeCompactSignature() error = %v", vec.Name, err)
+		}
+		if offset := offsets[vec.HeaderType]; sig.HeaderByte < offset || sig.HeaderByte > offset+3 {
+			t.Errorf("vector %q has header byte %d, want %d-%d", vec.Name, sig.HeaderByte, offset, offset+3)
+		}
+	}
+
+	again := generate()
+	for i := range vectors {
+		if vectors[i] != again[i] {
+			t.Fatalf("vector %d differs between runs: %+v, %+v", i, vectors[i], again[i])
+		}
+	}
+
+	other := generate("--seed", "other")
+	if other[0].Address == vectors[0].Address {
+		t.Errorf("vectors for different seeds share address %s", other[0].Address)
+	}
+}
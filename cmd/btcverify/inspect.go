@@ -0,0 +1,50 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/sero/btc/verify"
+)
+
+// runInspect implements the inspect command: it decodes a signature and
+// prints its parts without verifying it
+func runInspect(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("btcverify inspect", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: btcverify inspect SIGNATURE")
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitMalformed
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return exitMalformed
+	}
+
+	sig, err := verify.DecodeCompactSignature(fs.Arg(0))
+	if err != nil && !errors.Is(err, verify.ErrInvalidHeaderByte) {
+		fmt.Fprintf(stderr, "btcverify inspect: %v\n", err)
+		return exitCodeOf(err)
+	}
+
+	addressType := string(sig.AddressType)
+	if addressType == "" {
+		addressType = "unknown (not a BIP-0137 header byte)"
+	}
+	fmt.Fprintf(stdout, "header byte:  %d (0x%02x)\n", sig.HeaderByte, sig.HeaderByte)
+	fmt.Fprintf(stdout, "recovery id:  %d\n", sig.RecoveryID)
+	fmt.Fprintf(stdout, "compressed:   %t\n", sig.Compressed)
+	fmt.Fprintf(stdout, "address type: %s\n", addressType)
+	fmt.Fprintf(stdout, "r:            %s\n", sig.R)
+	fmt.Fprintf(stdout, "s:            %s\n", sig.S)
+	fmt.Fprintf(stdout, "low s:        %t\n", sig.LowS)
+
+	if err != nil {
+		return exitInvalid
+	}
+	return exitValid
+}
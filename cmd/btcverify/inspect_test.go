@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestRunInspect(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCode int
+		wantOut  []string
+	}{
+		{
+			name:     "Compressed P2PKH",
+			args:     []string{"inspect", "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="},
+			wantCode: exitValid,
+			wantOut: []string{
+				"header byte:  32 (0x20)",
+				"recovery id:  1",
+				"compressed:   true",
+				"address type: p2pkh",
+				"r:            5a94a38fecc4110591f2bc99979e379e95d7706a39cd03f74a0f042845de04ea",
+			},
+		},
+		{
+			name:     "Unknown header byte",
+			args:     []string{"inspect", "K1qUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="},
+			wantCode: exitInvalid,
+			wantOut:  []string{"header byte:  43 (0x2b)", "address type: unknown"},
+		},
+		{
+			name:     "Malformed signature",
+			args:     []string{"inspect", "AAAA"},
+			wantCode: exitMalformed,
+		},
+		{
+			name:     "Missing signature",
+			args:     []string{"inspect"},
+			wantCode: exitMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, strings.NewReader(""), &stdout, &stderr); got != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr: %s)", got, tt.wantCode, stderr.String())
+			}
+			for _, want := range tt.wantOut {
+				if !strings.Contains(stdout.String(), want) {
+					t.Errorf("output = %q, want it to contain %q", stdout.String(), want)
+				}
+			}
+		})
+	}
+}
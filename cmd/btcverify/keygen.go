@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/sero/btc/verify/keys"
+)
+
+// runKeygen implements the keygen command: it generates a private key, at
+// random or from a seed, and prints it in wallet import format with its
+// public key and addresses
+func runKeygen(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("btcverify keygen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: btcverify keygen [--network NETWORK] [--seed SEED] [--json]")
+		fs.PrintDefaults()
+	}
+	network := fs.String("network", "mainnet", "network of the key: mainnet, testnet, regtest or signet")
+	seed := fs.String("seed", "", "derive the key from a seed instead of at random, for testing only")
+	asJSON := fs.Bool("json", false, "print the key as JSON")
+	if err := fs.Parse(args); err != nil {
+		return exitMalformed
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "btcverify keygen: unexpected argument %q\n", fs.Arg(0))
+		return exitMalformed
+	}
+	params, ok := networks[*network]
+	if !ok {
+		fmt.Fprintf(stderr, "btcverify keygen: unknown network %q\n", *network)
+		return exitMalformed
+	}
+
+	var privKey *btcec.PrivateKey
+	if *seed != "" {
+		privKey = keys.FromSeed(*seed)
+	} else {
+		var err error
+		if privKey, err = keys.Generate(); err != nil {
+			fmt.Fprintf(stderr, "btcverify keygen: %v\n", err)
+			return exitInternal
+		}
+	}
+	info, err := keys.Describe(privKey, params)
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify keygen: %v\n", err)
+		return exitInternal
+	}
+
+	if *asJSON {
+		data, err := json.Marshal(info)
+		if err != nil {
+			fmt.Fprintf(stderr, "btcverify keygen: %v\n", err)
+			return exitInternal
+		}
+		fmt.Fprintf(stdout, "%s\n", data)
+		return exitValid
+	}
+	fmt.Fprintf(stdout, "network:            %s\n", info.Network)
+	fmt.Fprintf(stdout, "wif:                %s\n", info.WIF)
+	fmt.Fprintf(stdout, "pubkey:             %s\n", info.PubKey)
+	fmt.Fprintf(stdout, "p2pkh:              %s\n", info.P2PKH)
+	fmt.Fprintf(stdout, "p2pkh uncompressed: %s\n", info.P2PKHUncompressed)
+	fmt.Fprintf(stdout, "p2sh-p2wpkh:        %s\n", info.P2SHP2WPKH)
+	fmt.Fprintf(stdout, "p2wpkh:             %s\n", info.P2WPKH)
+	fmt.Fprintf(stdout, "p2tr:               %s\n", info.P2TR)
+	return exitValid
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sero/btc/verify/keys"
+)
+
+func TestRunKeygen(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantCode    int
+		wantNetwork string
+	}{
+		{name: "Random key", args: []string{"--json"}, wantCode: exitValid, wantNetwork: "mainnet"},
+		{name: "Seeded testnet key", args: []string{"--json", "--seed", "test", "--network", "testnet"}, wantCode: exitValid, wantNetwork: "testnet3"},
+		{name: "Unknown network", args: []string{"--network", "litecoin"}, wantCode: exitMalformed},
+		{name: "Unexpected argument", args: []string{"extra"}, wantCode: exitMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(append([]string{"keygen"}, tt.args...), nil, &stdout, &stderr); got != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", got, tt.wantCode, stderr.String())
+			}
+			if tt.wantCode != exitValid {
+				return
+			}
+
+			var info keys.Info
+			if err := json.Unmarshal(stdout.Bytes(), &info); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if info.Network != tt.wantNetwork || info.WIF == "" || info.P2TR == "" {
+				t.Errorf("keygen output = %+v, want a %s key with its addresses", info, tt.wantNetwork)
+			}
+		})
+	}
+}
+
+func TestRunKeygenSeed(t *testing.T) {
+	var first, second bytes.Buffer
+	run([]string{"keygen", "--seed", "test"}, nil, &first, &bytes.Buffer{})
+	run([]string{"keygen", "--seed", "test"}, nil, &second, &bytes.Buffer{})
+	if first.String() != second.String() {
+		t.Errorf("keygen --seed output differs between runs:\n%s\n%s", first.String(), second.String())
+	}
+	if !strings.Contains(first.String(), "wif:") {
+		t.Errorf("keygen output = %q, want a wif line", first.String())
+	}
+}
@@ -0,0 +1,130 @@
+// Package main implements btcverify, a command line tool for verifying
+// Bitcoin signed messages.
+//
+// Usage:
+//
+//	btcverify verify --address ADDRESS --message MESSAGE --signature SIGNATURE [flags]
+//	btcverify verify [flags] < signed-message.json
+//	btcverify inspect SIGNATURE
+//	btcverify sign --message MESSAGE [--type TYPE] [--wif-file FILE]
+//	btcverify keygen [--network NETWORK] [--seed SEED] [--json]
+//	btcverify batch --in FILE [--out FILE] [flags]
+//	btcverify gen-vectors [--seed SEED] [--out FILE]
+//	btcverify serve [--listen ADDRESS] [flags]
+//	btcverify --selfcheck
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sero/btc/verify"
+)
+
+// Exit codes of the commands, stable so scripts can rely on them
+const (
+	// exitValid means the signature is valid
+	exitValid = 0
+
+	// exitInvalid means the signature is invalid
+	exitInvalid = 1
+
+	// exitMalformed means the input couldn't be verified at all, such as an
+	// unknown flag, a missing field or an undecodable signature
+	exitMalformed = 2
+
+	// exitInternal means verification failed for reasons unrelated to the
+	// input, such as an unwritable output file
+	exitInternal = 3
+)
+
+func main() {
+	// Keep the library logs out of the command output
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command selected by args and returns its exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) > 0 {
+		switch args[0] {
+		case "verify":
+			return runVerify(args[1:], stdin, stdout, stderr)
+		case "inspect":
+			return runInspect(args[1:], stdout, stderr)
+		case "sign":
+			return runSign(args[1:], stdin, stdout, stderr)
+		case "keygen":
+			return runKeygen(args[1:], stdout, stderr)
+		case "batch":
+			return runBatch(args[1:], stdin, stdout, stderr)
+		case "gen-vectors":
+			return runGenVectors(args[1:], stdout, stderr)
+		case "serve":
+			return runServe(args[1:], stderr)
+		}
+	}
+
+	fs := flag.NewFlagSet("btcverify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprint(stderr, `Usage:
+  btcverify verify --address ADDRESS --message MESSAGE --signature SIGNATURE [flags]
+  btcverify verify [flags] < signed-message.json
+  btcverify inspect SIGNATURE
+  btcverify sign --message MESSAGE [--type TYPE] [--wif-file FILE]
+  btcverify keygen [--network NETWORK] [--seed SEED] [--json]
+  btcverify batch --in FILE [--out FILE] [flags]
+  btcverify gen-vectors [--seed SEED] [--out FILE]
+  btcverify serve [--listen ADDRESS] [flags]
+  btcverify --selfcheck
+
+Exit status is 0 for valid signatures, 1 for invalid ones, 2 for malformed
+input and 3 for internal errors.
+
+Commands:
+  verify       verify a signed message
+  inspect      decode a signature without verifying it
+  sign         sign a message with a WIF private key
+  keygen       generate a private key and print its addresses
+  batch        verify the signed messages of a CSV or JSONL file
+  gen-vectors  generate signatures for interoperability tests
+  serve        serve verification requests over HTTP
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+	selfCheck := fs.Bool("selfcheck", false, "run the embedded known-answer vectors and exit")
+	if err := fs.Parse(args); err != nil {
+		return exitMalformed
+	}
+
+	if *selfCheck {
+		if err := verify.SelfCheck(); err != nil {
+			fmt.Fprintf(stderr, "btcverify: %v\n", err)
+			return exitInternal
+		}
+		fmt.Fprintln(stdout, "self-check passed")
+		return exitValid
+	}
+
+	fs.Usage()
+	return exitMalformed
+}
+
+// exitCodeOf returns the exit code for a verification error
+func exitCodeOf(err error) int {
+	switch verify.ErrorCodeOf(err) {
+	case verify.CodeEmptyAddress, verify.CodeEmptyMessage, verify.CodeEmptySignature,
+		verify.CodeInvalidAddress, verify.CodeMalformedSignature, verify.CodeMessageTooLarge:
+		return exitMalformed
+	case verify.CodeUnknown, verify.CodeEngineDisagreement, verify.CodeSelfCheckFailed, verify.CodeVerificationTimeout:
+		return exitInternal
+	default:
+		return exitInvalid
+	}
+}
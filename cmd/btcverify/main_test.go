@@ -0,0 +1,34 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+func TestExitCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{verify.ErrAddressMismatch, exitInvalid},
+		{verify.ErrInvalidSignature, exitInvalid},
+		{verify.ErrNetworkMismatch, exitInvalid},
+		{verify.ErrHighS, exitInvalid},
+		{verify.ErrEmptySignature, exitMalformed},
+		{verify.ErrInvalidAddress, exitMalformed},
+		{fmt.Errorf("wrapped: %w", verify.ErrMalformedSignature), exitMalformed},
+		{verify.ErrEngineDisagreement, exitInternal},
+		{errors.New("unexpected"), exitInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			if got := exitCodeOf(tt.err); got != tt.want {
+				t.Errorf("exitCodeOf(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/httpserver"
+)
+
+// selfCheck runs the known-answer vectors before serving, replaced in tests
+var selfCheck = verify.SelfCheck
+
+// runServe implements the serve command: it serves the verification API of
+// the httpserver package until interrupted, over TLS when a certificate is
+// configured. It refuses to serve when the self-check fails.
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("btcverify serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: btcverify serve [--listen ADDRESS] [--rate-limit-ip N] [--rate-limit-address N] [--tls-cert FILE --tls-key FILE [--tls-client-ca FILE]] [flags]")
+		fs.PrintDefaults()
+	}
+
+	var vf verifierFlags
+	vf.register(fs)
+	vf.registerServe(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitMalformed
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "btcverify serve: unexpected argument %q\n", fs.Arg(0))
+		return exitMalformed
+	}
+
+	cfg, err := vf.load(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify serve: %v\n", err)
+		return exitMalformed
+	}
+
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify serve: %v\n", err)
+		return exitMalformed
+	}
+
+	if err := selfCheck(); err != nil {
+		fmt.Fprintf(stderr, "btcverify serve: %v\n", err)
+		return exitInternal
+	}
+
+	ln, err := net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify serve: %v\n", err)
+		return exitInternal
+	}
+	if tlsConfig != nil {
+		ln = tls.NewListener(ln, tlsConfig)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := serve(ctx, ln, httpserver.New(cfg.serverOptions(cfg.verifier(stderr))...)); err != nil {
+		fmt.Fprintf(stderr, "btcverify serve: %v\n", err)
+		return exitInternal
+	}
+	return exitValid
+}
+
+// serve serves handler on ln until ctx is done, then shuts down gracefully:
+// the handler turns new requests away and drains the ones in flight, then
+// the HTTP server closes the listener and connections
+func serve(ctx context.Context, ln net.Listener, handler *httpserver.Server) error {
+	srv := &http.Server{
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := handler.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
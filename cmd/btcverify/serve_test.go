@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/httpserver"
+)
+
+func TestServeShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- serve(ctx, ln, httpserver.New())
+	}()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("GET /healthz status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("serve() error = %v", err)
+	}
+}
+
+func TestServeSelfCheckFailure(t *testing.T) {
+	selfCheck = func() error { return verify.ErrSelfCheckFailed }
+	t.Cleanup(func() { selfCheck = verify.SelfCheck })
+
+	var stderr bytes.Buffer
+	if code := runServe([]string{"--listen", "127.0.0.1:0"}, &stderr); code != exitInternal {
+		t.Errorf("runServe() = %d, want %d", code, exitInternal)
+	}
+	if !strings.Contains(stderr.String(), verify.ErrSelfCheckFailed.Error()) {
+		t.Errorf("stderr = %q, want the self-check error", stderr.String())
+	}
+}
+
+func TestServeMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newTestCert(t, nil, "test CA")
+	server := newTestCert(t, ca, "127.0.0.1")
+	client := newTestCert(t, ca, "client")
+
+	cfg := config{
+		TLSCert:     server.writeCert(t, dir, "server.pem"),
+		TLSKey:      server.writeKey(t, dir, "server.key"),
+		TLSClientCA: ca.writeCert(t, dir, "ca.pem"),
+	}
+	tlsConfig, err := cfg.tlsConfig()
+	if err != nil {
+		t.Fatalf("tlsConfig() error = %v", err)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go serve(ctx, tls.NewListener(ln, tlsConfig), httpserver.New())
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	tests := []struct {
+		name    string
+		certs   []tls.Certificate
+		wantErr bool
+	}{
+		{
+			name:  "Client certificate",
+			certs: []tls.Certificate{client.tlsCertificate()},
+		},
+		{
+			name:    "No client certificate",
+			wantErr: true,
+		},
+		{
+			name:    "Self-signed client certificate",
+			certs:   []tls.Certificate{newTestCert(t, nil, "intruder").tlsCertificate()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: tt.certs},
+			}}
+			resp, err := httpClient.Get("https://" + ln.Addr().String() + "/healthz")
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GET /healthz error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Helper type holding a test certificate and its key
+type testCert struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+}
+
+// Helper function to create a certificate for name, signed by parent or
+// self-signed as a CA when parent is nil
+func newTestCert(t *testing.T, parent *testCert, name string) *testCert {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	if ip := net.ParseIP(name); ip != nil {
+		template.IPAddresses = []net.IP{ip}
+	}
+
+	signer, signerKey := template, key
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testCert{cert: cert, key: key}
+}
+
+// Helper function to write the PEM certificate to a file, returning its path
+func (c *testCert) writeCert(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.cert.Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Helper function to write the PEM private key to a file, returning its path
+func (c *testCert) writeKey(t *testing.T, dir, name string) string {
+	t.Helper()
+	der, err := x509.MarshalECPrivateKey(c.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// Helper function to return the certificate and key as a tls.Certificate
+func (c *testCert) tlsCertificate() tls.Certificate {
+	return tls.Certificate{Certificate: [][]byte{c.cert.Raw}, PrivateKey: c.key}
+}
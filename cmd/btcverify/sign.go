@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/signer"
+)
+
+// runSign implements the sign command: it signs a message with a WIF
+// private key and prints the signed message as JSON. The key is read from a
+// file or stdin, never from the command line, so it doesn't end up in the
+// shell history or the process list.
+func runSign(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("btcverify sign", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: btcverify sign --message MESSAGE [--type TYPE] [--network NETWORK] [--wif-file FILE]")
+		fs.PrintDefaults()
+	}
+	message := fs.String("message", "", "message to sign")
+	addrType := fs.String("type", string(verify.AddressTypeP2WPKH), "address type to sign for: p2pkh, p2sh-p2wpkh or p2wpkh")
+	network := fs.String("network", "mainnet", "network of the key: mainnet, testnet, regtest or signet")
+	wifFile := fs.String("wif-file", "-", "file holding the WIF private key, - for standard input")
+	if err := fs.Parse(args); err != nil {
+		return exitMalformed
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "btcverify sign: unexpected argument %q\n", fs.Arg(0))
+		return exitMalformed
+	}
+	params, ok := networks[*network]
+	if !ok {
+		fmt.Fprintf(stderr, "btcverify sign: unknown network %q\n", *network)
+		return exitMalformed
+	}
+
+	var wif []byte
+	var err error
+	if *wifFile == "-" {
+		wif, err = io.ReadAll(stdin)
+	} else {
+		wif, err = os.ReadFile(*wifFile)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify sign: reading private key: %v\n", err)
+		return exitMalformed
+	}
+	key, err := signer.ImportWIF(strings.TrimSpace(string(wif)), params)
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify sign: %v\n", err)
+		return exitMalformed
+	}
+
+	msg, err := key.Sign(*message, verify.AddressType(*addrType))
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify sign: %v\n", err)
+		return exitMalformed
+	}
+	msg.Network = params.Name
+	msg.Type = verify.AddressType(*addrType)
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify sign: %v\n", err)
+		return exitInternal
+	}
+	fmt.Fprintf(stdout, "%s\n", data)
+	return exitValid
+}
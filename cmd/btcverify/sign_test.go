@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+func TestRunSign(t *testing.T) {
+	const wif = "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		wantCode int
+		wantType verify.AddressType
+	}{
+		{name: "Default type", args: []string{"--message", "hello"}, stdin: wif, wantCode: exitValid, wantType: verify.AddressTypeP2WPKH},
+		{name: "P2PKH", args: []string{"--message", "hello", "--type", "p2pkh"}, stdin: wif, wantCode: exitValid, wantType: verify.AddressTypeP2PKH},
+		{name: "Key for other network", args: []string{"--message", "hello", "--network", "testnet"}, stdin: wif, wantCode: exitMalformed},
+		{name: "Unsupported type", args: []string{"--message", "hello", "--type", "p2tr"}, stdin: wif, wantCode: exitMalformed},
+		{name: "Missing message", stdin: wif, wantCode: exitMalformed},
+		{name: "Invalid key", args: []string{"--message", "hello"}, stdin: "not a key", wantCode: exitMalformed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(append([]string{"sign"}, tt.args...), strings.NewReader(tt.stdin), &stdout, &stderr); got != tt.wantCode {
+				t.Fatalf("run() = %d, want %d (stderr: %s)", got, tt.wantCode, stderr.String())
+			}
+			if tt.wantCode != exitValid {
+				return
+			}
+
+			msg, err := verify.ParseSignedMessageJSON(stdout.Bytes())
+			if err != nil {
+				t.Fatalf("ParseSignedMessageJSON() error = %v", err)
+			}
+			if msg.Type != tt.wantType {
+				t.Errorf("signed message type = %q, want %q", msg.Type, tt.wantType)
+			}
+			if valid, err := verify.VerifyBip137Signature(msg.Address, msg.Message, msg.Signature); err != nil || !valid {
+				t.Errorf("VerifyBip137Signature() = %v, %v, want true", valid, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/sero/btc/verify"
+)
+
+// runVerify implements the verify command: it verifies one signed message
+// and prints whether it is valid. Without the message flags, the message is
+// read from stdin as a JSON object with address, message and signature
+// fields.
+func runVerify(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("btcverify verify", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: btcverify verify --address ADDRESS --message MESSAGE --signature SIGNATURE [flags]")
+		fmt.Fprintln(stderr, "       btcverify verify [flags] < signed-message.json")
+		fs.PrintDefaults()
+	}
+
+	var msg verify.SignedMessage
+	var vf verifierFlags
+	fs.StringVar(&msg.Address, "address", "", "Bitcoin address that signed the message")
+	fs.StringVar(&msg.Message, "message", "", "signed message")
+	fs.StringVar(&msg.Signature, "signature", "", "base64-encoded signature")
+	vf.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return exitMalformed
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "btcverify verify: unexpected argument %q\n", fs.Arg(0))
+		return exitMalformed
+	}
+
+	cfg, err := vf.load(fs)
+	if err != nil {
+		fmt.Fprintf(stderr, "btcverify verify: %v\n", err)
+		return exitMalformed
+	}
+	v := cfg.verifier(stderr)
+
+	if !messageFlagSet(fs) {
+		if err := json.NewDecoder(stdin).Decode(&msg); err != nil {
+			fmt.Fprintf(stderr, "btcverify verify: reading signed message from stdin: %v\n", err)
+			return exitMalformed
+		}
+	}
+
+	result, err := v.Verify(msg)
+	if err != nil {
+		code := exitCodeOf(err)
+		if code != exitInvalid {
+			fmt.Fprintf(stderr, "btcverify verify: %v (%s)\n", err, verify.ErrorCodeOf(err))
+			return code
+		}
+		fmt.Fprintf(stdout, "invalid: %v (%s)\n", err, verify.ErrorCodeOf(err))
+		return exitInvalid
+	}
+	if !result.Valid {
+		fmt.Fprintln(stdout, "invalid")
+		return exitInvalid
+	}
+	fmt.Fprintln(stdout, "valid")
+	return exitValid
+}
+
+// messageFlagSet reports whether any of the flags holding the signed message
+// was given
+func messageFlagSet(fs *flag.FlagSet) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "address", "message", "signature":
+			set = true
+		}
+	})
+	return set
+}
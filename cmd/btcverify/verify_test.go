@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+func TestRunVerify(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	const (
+		address   = "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"
+		signature = "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="
+	)
+
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "Valid signature",
+			args:     []string{"verify", "--address", address, "--message", "test message", "--signature", signature},
+			wantCode: exitValid,
+			wantOut:  "valid\n",
+		},
+		{
+			name:     "Wrong message",
+			args:     []string{"verify", "--address", address, "--message", "other message", "--signature", signature},
+			wantCode: exitInvalid,
+			wantOut:  "invalid: ",
+		},
+		{
+			name:     "Signed message on stdin",
+			args:     []string{"verify"},
+			stdin:    `{"address":"` + address + `","message":"test message","signature":"` + signature + `"}`,
+			wantCode: exitValid,
+			wantOut:  "valid\n",
+		},
+		{
+			name:     "Invalid signed message on stdin",
+			args:     []string{"verify"},
+			stdin:    `{"address":`,
+			wantCode: exitMalformed,
+		},
+		{
+			name:     "Empty signature",
+			args:     []string{"verify", "--address", address, "--message", "test message"},
+			wantCode: exitMalformed,
+		},
+		{
+			name:     "Malformed signature",
+			args:     []string{"verify", "--address", address, "--message", "test message", "--signature", "AAAA"},
+			wantCode: exitMalformed,
+		},
+		{
+			name:     "Network mismatch",
+			args:     []string{"verify", "--network", "testnet", "--address", address, "--message", "test message", "--signature", signature},
+			wantCode: exitInvalid,
+			wantOut:  "invalid: address is not valid for network",
+		},
+		{
+			name:     "Unknown network",
+			args:     []string{"verify", "--network", "moon", "--address", address, "--message", "test message", "--signature", signature},
+			wantCode: exitMalformed,
+		},
+		{
+			name:     "Unknown flag",
+			args:     []string{"verify", "--sig", signature},
+			wantCode: exitMalformed,
+		},
+		{
+			name:     "No command",
+			args:     nil,
+			wantCode: exitMalformed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if got := run(tt.args, strings.NewReader(tt.stdin), &stdout, &stderr); got != tt.wantCode {
+				t.Errorf("run() = %d, want %d (stderr: %s)", got, tt.wantCode, stderr.String())
+			}
+			if !strings.HasPrefix(stdout.String(), tt.wantOut) {
+				t.Errorf("output = %q, want it to start with %q", stdout.String(), tt.wantOut)
+			}
+		})
+	}
+}
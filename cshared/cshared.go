@@ -0,0 +1,138 @@
+// Package main builds a C shared library exposing the verifier, so services
+// in Python, Rust or C++ can verify signed messages in process instead of
+// over RPC:
+//
+//	go build -buildmode=c-shared -o libbtcverify.so ./cshared
+//
+// The build also writes libbtcverify.h, declaring the exported functions:
+//
+//	char* VerifyMessage(char* address, char* message, char* signature, char* network);
+//	char* SignMessage(char* wif, char* message, char* addressType, char* network);
+//	char* RecoverAddress(char* message, char* signature, char* network);
+//	void FreeString(char* s);
+//
+// Strings are UTF-8 and NUL-terminated. Every function returns a JSON object
+// that the caller must release with FreeString; failures are reported in
+// its code and error members, like the HTTP API does. Networks are named
+// mainnet, testnet, regtest or signet, and an empty name stands for mainnet.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/signer"
+)
+
+// verifyResponse is the result of VerifyMessage
+type verifyResponse struct {
+	Valid bool             `json:"valid"`
+	Code  verify.ErrorCode `json:"code,omitempty"`
+	Error string           `json:"error,omitempty"`
+}
+
+// errorResponse is the result of a failed SignMessage or RecoverAddress
+type errorResponse struct {
+	Code  verify.ErrorCode `json:"code"`
+	Error string           `json:"error"`
+}
+
+// recoverResponse is the result of RecoverAddress
+type recoverResponse struct {
+	PubKey      string             `json:"pubkey"`
+	Compressed  bool               `json:"compressed"`
+	AddressType verify.AddressType `json:"address_type"`
+	Address     string             `json:"address"`
+}
+
+// A shared library needs a main package, but its main is never called
+func main() {}
+
+func init() {
+	// Keep the library logs out of the host process output
+	verify.SetLogLevel(verify.LogLevelNone)
+}
+
+// verifyMessage implements VerifyMessage
+func verifyMessage(address, message, signature, network string) []byte {
+	params, err := networkParams(network)
+	if err != nil {
+		return marshal(verifyResponse{Code: verify.ErrorCodeOf(err), Error: err.Error()})
+	}
+	result, err := verify.NewVerifier(verify.WithParams(params)).Verify(verify.SignedMessage{
+		Address:   address,
+		Message:   message,
+		Signature: signature,
+	})
+	if err != nil {
+		return marshal(verifyResponse{Code: verify.ErrorCodeOf(err), Error: err.Error()})
+	}
+	return marshal(verifyResponse{Valid: result.Valid})
+}
+
+// signMessage implements SignMessage
+func signMessage(wif, message, addressType, network string) []byte {
+	params, err := networkParams(network)
+	if err != nil {
+		return marshalError(err)
+	}
+	key, err := signer.ImportWIF(wif, params)
+	if err != nil {
+		return marshalError(err)
+	}
+	msg, err := key.Sign(message, verify.AddressType(addressType))
+	if err != nil {
+		return marshalError(err)
+	}
+	msg.Network = params.Name
+	msg.Type = verify.AddressType(addressType)
+	return marshal(msg)
+}
+
+// recoverAddress implements RecoverAddress
+func recoverAddress(message, signature, network string) []byte {
+	params, err := networkParams(network)
+	if err != nil {
+		return marshalError(err)
+	}
+	rec, err := verify.Recover(message, signature, params)
+	if err != nil {
+		return marshalError(err)
+	}
+	return marshal(recoverResponse{
+		PubKey:      rec.PubKey,
+		Compressed:  rec.Compressed,
+		AddressType: rec.AddressType,
+		Address:     rec.Address,
+	})
+}
+
+// networkParams returns the parameters of the named network
+func networkParams(name string) (*chaincfg.Params, error) {
+	switch name {
+	case "", "mainnet":
+		return &chaincfg.MainNetParams, nil
+	case "testnet", "testnet3":
+		return &chaincfg.TestNet3Params, nil
+	case "regtest":
+		return &chaincfg.RegressionNetParams, nil
+	case "signet":
+		return &chaincfg.SigNetParams, nil
+	default:
+		return nil, fmt.Errorf("unknown network %q", name)
+	}
+}
+
+// marshalError returns the JSON error response of err
+func marshalError(err error) []byte {
+	return marshal(errorResponse{Code: verify.ErrorCodeOf(err), Error: err.Error()})
+}
+
+// marshal encodes a response. The responses only hold strings and booleans,
+// so encoding can't fail.
+func marshal(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
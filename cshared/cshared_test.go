@@ -0,0 +1,77 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+// Compressed private key of the Bitcoin wiki WIF example
+const testWIF = "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"
+
+func TestSignVerifyRecover(t *testing.T) {
+	var msg verify.SignedMessage
+	unmarshal(t, signMessage(testWIF, "hello from C", "p2pkh", "mainnet"), &msg)
+	if msg.Address != "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK" || msg.Signature == "" {
+		t.Fatalf("signMessage() = %+v, want a signature by 1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK", msg)
+	}
+
+	tests := []struct {
+		name     string
+		message  string
+		network  string
+		want     verifyResponse
+		wantCode verify.ErrorCode
+	}{
+		{name: "Valid", message: msg.Message, want: verifyResponse{Valid: true}},
+		{name: "Other message", message: "edited", wantCode: verify.CodeAddressMismatch},
+		{name: "Other network", message: msg.Message, network: "testnet", wantCode: verify.CodeNetworkMismatch},
+		{name: "Unknown network", message: msg.Message, network: "moon", wantCode: verify.CodeUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got verifyResponse
+			unmarshal(t, verifyMessage(msg.Address, tt.message, msg.Signature, tt.network), &got)
+			if got.Valid != tt.want.Valid || got.Code != tt.wantCode || (tt.wantCode != "") != (got.Error != "") {
+				t.Errorf("verifyMessage() = %+v, want valid %v and code %q", got, tt.want.Valid, tt.wantCode)
+			}
+		})
+	}
+
+	var rec recoverResponse
+	unmarshal(t, recoverAddress(msg.Message, msg.Signature, ""), &rec)
+	if rec.Address != msg.Address || rec.AddressType != verify.AddressTypeP2PKH || !rec.Compressed {
+		t.Errorf("recoverAddress() = %+v, want %s", rec, msg.Address)
+	}
+}
+
+func TestErrorResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		wantCode verify.ErrorCode
+	}{
+		{name: "Invalid WIF", response: signMessage("not a key", "hello", "p2pkh", ""), wantCode: verify.CodeUnknown},
+		{name: "Unsupported address type", response: signMessage(testWIF, "hello", "p2tr", ""), wantCode: verify.CodeUnsupportedAddressType},
+		{name: "Malformed signature", response: recoverAddress("hello", "not base64", ""), wantCode: verify.CodeMalformedSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got errorResponse
+			unmarshal(t, tt.response, &got)
+			if got.Code != tt.wantCode || got.Error == "" {
+				t.Errorf("response = %+v, want code %q", got, tt.wantCode)
+			}
+		})
+	}
+}
+
+// Helper function to decode a JSON response
+func unmarshal(t *testing.T, data []byte, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("response %s isn't JSON: %v", data, err)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+// #include <stdlib.h>
+import "C"
+
+import (
+	"unsafe"
+)
+
+// VerifyMessage verifies a signature of the message by the address, returning
+// {"valid":true} or {"valid":false,"code":...,"error":...}
+//
+//export VerifyMessage
+func VerifyMessage(address, message, signature, network *C.char) *C.char {
+	return cResponse(verifyMessage(C.GoString(address), C.GoString(message), C.GoString(signature), C.GoString(network)))
+}
+
+// SignMessage signs the message with a WIF private key for an address of
+// the type p2pkh, p2sh-p2wpkh or p2wpkh, returning the signed message as
+// {"address":...,"message":...,"signature":...}
+//
+//export SignMessage
+func SignMessage(wif, message, addressType, network *C.char) *C.char {
+	return cResponse(signMessage(C.GoString(wif), C.GoString(message), C.GoString(addressType), C.GoString(network)))
+}
+
+// RecoverAddress recovers the public key and address that signed the message
+// with a compact signature, returning
+// {"pubkey":...,"compressed":...,"address_type":...,"address":...}
+//
+//export RecoverAddress
+func RecoverAddress(message, signature, network *C.char) *C.char {
+	return cResponse(recoverAddress(C.GoString(message), C.GoString(signature), C.GoString(network)))
+}
+
+// FreeString releases a string returned by the other functions
+//
+//export FreeString
+func FreeString(s *C.char) {
+	C.free(unsafe.Pointer(s))
+}
+
+// cResponse copies a response to a C string allocated with malloc
+func cResponse(data []byte) *C.char {
+	return C.CString(string(data))
+}
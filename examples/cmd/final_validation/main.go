@@ -4,6 +4,7 @@ package main
 import (
 	"encoding/hex"
 	"fmt"
+	"log"
 	"os"
 	"time"
 
@@ -25,7 +26,7 @@ type TestVector struct {
 func main() {
 	// Configure logging
 	verify.SetLogLevel(verify.LogLevelInfo)
-	verify.Logger.SetOutput(os.Stdout)
+	verify.SetLogger(verify.NewStdLogger(log.New(os.Stdout, "", log.LstdFlags)))
 
 	// Define the test vectors
 	testVectors := []TestVector{
@@ -4,6 +4,7 @@ package main
 import (
 	"encoding/hex"
 	"fmt"
+	"log"
 	"os"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -15,9 +16,10 @@ func main() {
 	verify.SetLogLevel(verify.LogLevelDebug)
 
 	// Configure logging to stdout
-	verify.Logger.SetOutput(os.Stdout)
+	verify.SetLogger(verify.NewStdLogger(log.New(os.Stdout, "", log.LstdFlags)))
 
-	// Use the values from our key generation script
+	// A test key and signature; generate your own key with btcverify keygen
+	// and sign with btcverify sign
 	address := "1C9YVXK12TBeDMJEFFMuTZMHMQgcRAuR1E"
 	message := "Hello, Bitcoin testing!"
 	signature := "IJNFSGvr6aaXsWFHQNJmWL9Jq6t/4IRdIzst8X4Af90JY7C0rStfn1NLgnQt8xWGSxouz5y/G7KWL8dKmt+FpME="
@@ -0,0 +1,174 @@
+package verify
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// adaptiveCeilingFactor bounds the adaptive worker count to this multiple of
+// GOMAXPROCS unless WithMaxConcurrency sets the bound
+const adaptiveCeilingFactor = 4
+
+// adaptiveMinWindow is the minimum number of latency samples the controller
+// collects before adjusting the worker count
+const adaptiveMinWindow = 32
+
+// adaptiveThroughputTolerance is the relative throughput drop that makes the
+// controller back off instead of adding workers
+const adaptiveThroughputTolerance = 0.05
+
+// ConcurrencyStats describes how the adaptive concurrency controller tuned a
+// batch verification
+type ConcurrencyStats struct {
+	// TargetP99 is the per-message latency the controller kept the 99th
+	// percentile under
+	TargetP99 time.Duration
+
+	// Initial is the number of workers the batch started with
+	Initial int
+
+	// Final is the number of workers when the batch completed
+	Final int
+
+	// Peak is the highest number of workers used
+	Peak int
+
+	// Adjustments is the number of times the worker count changed
+	Adjustments int
+
+	// LastP99 is the 99th percentile latency of the last measured window
+	LastP99 time.Duration
+}
+
+// concurrencyController limits the number of concurrently verified messages,
+// adjusting the limit to the observed per-message latency. While the p99 of a
+// window of latencies stays under the target, it adds a worker as long as
+// throughput keeps up and removes one when throughput drops; when the p99
+// exceeds the target it backs off multiplicatively.
+type concurrencyController struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+
+	target   time.Duration
+	min, max int
+	limit    int
+	active   int
+
+	window         []time.Duration
+	windowStart    time.Time
+	lastThroughput float64
+
+	stats ConcurrencyStats
+
+	// now returns the current time, replaced in tests
+	now func() time.Time
+}
+
+// newConcurrencyController creates a controller starting at initial workers,
+// bounded by max
+func newConcurrencyController(target time.Duration, initial, max int) *concurrencyController {
+	if initial > max {
+		initial = max
+	}
+	c := &concurrencyController{
+		target: target,
+		min:    1,
+		max:    max,
+		limit:  initial,
+		now:    time.Now,
+		stats: ConcurrencyStats{
+			TargetP99: target,
+			Initial:   initial,
+			Final:     initial,
+			Peak:      initial,
+		},
+	}
+	c.cond = sync.NewCond(&c.mu)
+	c.windowStart = c.now()
+	return c
+}
+
+// acquire blocks until a worker may verify a message
+func (c *concurrencyController) acquire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.active >= c.limit {
+		c.cond.Wait()
+	}
+	c.active++
+}
+
+// release frees the slot of a worker. When the worker verified a message its
+// latency is recorded, which may adjust the limit.
+func (c *concurrencyController) release(latency time.Duration, measured bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.active--
+	if measured {
+		c.record(latency)
+	}
+	c.cond.Broadcast()
+}
+
+// record adds a latency sample, adjusting the limit once the window is full.
+// The window grows with the limit so every worker contributes samples.
+func (c *concurrencyController) record(latency time.Duration) {
+	c.window = append(c.window, latency)
+	if len(c.window) < max(adaptiveMinWindow, 4*c.limit) {
+		return
+	}
+
+	now := c.now()
+	elapsed := now.Sub(c.windowStart)
+	throughput := float64(len(c.window)) / math.Max(elapsed.Seconds(), 1e-9)
+	p99 := percentile(c.window, 0.99)
+
+	limit := c.limit
+	switch {
+	case p99 > c.target:
+		limit = limit * 3 / 4
+	case throughput < c.lastThroughput*(1-adaptiveThroughputTolerance):
+		limit--
+	default:
+		limit++
+	}
+	limit = min(max(limit, c.min), c.max)
+
+	if limit != c.limit {
+		logEvent(LogLevelDebug, "Adaptive concurrency adjusted",
+			"from", c.limit, "to", limit, "p99", p99, "target", c.target, "throughput", throughput)
+		c.limit = limit
+		c.stats.Adjustments++
+		c.stats.Peak = max(c.stats.Peak, limit)
+	}
+	c.stats.Final = c.limit
+	c.stats.LastP99 = p99
+
+	c.lastThroughput = throughput
+	c.window = c.window[:0]
+	c.windowStart = now
+}
+
+// snapshot returns the statistics collected so far
+func (c *concurrencyController) snapshot() *ConcurrencyStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := c.stats
+	stats.Final = c.limit
+	return &stats
+}
+
+// percentile returns the p-th percentile of the latencies, reordering them
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	k := int(math.Ceil(p*float64(len(latencies)))) - 1
+	return latencies[min(max(k, 0), len(latencies)-1)]
+}
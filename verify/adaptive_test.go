@@ -0,0 +1,134 @@
+package verify
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestConcurrencyController(t *testing.T) {
+	tests := []struct {
+		name      string
+		initial   int
+		latency   time.Duration
+		perWindow time.Duration
+		windows   int
+		wantLimit int
+	}{
+		{
+			name:      "Grows while under target",
+			initial:   2,
+			latency:   time.Millisecond,
+			perWindow: 10 * time.Millisecond,
+			windows:   3,
+			wantLimit: 5,
+		},
+		{
+			name:      "Stops at the ceiling",
+			initial:   7,
+			latency:   time.Millisecond,
+			perWindow: 10 * time.Millisecond,
+			windows:   5,
+			wantLimit: 8,
+		},
+		{
+			name:      "Backs off above target",
+			initial:   8,
+			latency:   20 * time.Millisecond,
+			perWindow: 10 * time.Millisecond,
+			windows:   1,
+			wantLimit: 6,
+		},
+		{
+			name:      "Never drops below one worker",
+			initial:   2,
+			latency:   20 * time.Millisecond,
+			perWindow: 10 * time.Millisecond,
+			windows:   5,
+			wantLimit: 1,
+		},
+		{
+			name:      "Removes a worker when throughput drops",
+			initial:   4,
+			latency:   time.Millisecond,
+			perWindow: 0, // set per window below
+			windows:   2,
+			wantLimit: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := time.Unix(0, 0)
+			ctrl := newConcurrencyController(10*time.Millisecond, tt.initial, 8)
+			ctrl.now = func() time.Time { return clock }
+			ctrl.windowStart = clock
+
+			for w := 0; w < tt.windows; w++ {
+				perWindow := tt.perWindow
+				if perWindow == 0 {
+					// Each window is twice as slow as the previous one
+					perWindow = time.Duration(w+1) * 100 * time.Millisecond
+				}
+				clock = clock.Add(perWindow)
+
+				size := max(adaptiveMinWindow, 4*ctrl.limit)
+				for i := 0; i < size; i++ {
+					ctrl.acquire()
+					ctrl.release(tt.latency, true)
+				}
+			}
+
+			stats := ctrl.snapshot()
+			if stats.Final != tt.wantLimit {
+				t.Errorf("limit after %d windows = %d, want %d", tt.windows, stats.Final, tt.wantLimit)
+			}
+			if stats.Peak < stats.Final || stats.Peak < tt.initial {
+				t.Errorf("Peak = %d, want at least %d and %d", stats.Peak, stats.Final, tt.initial)
+			}
+		})
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	latencies := make([]time.Duration, 100)
+	for i := range latencies {
+		latencies[len(latencies)-1-i] = time.Duration(i+1) * time.Millisecond
+	}
+
+	if got := percentile(latencies, 0.99); got != 99*time.Millisecond {
+		t.Errorf("percentile(0.99) = %s, want 99ms", got)
+	}
+	if got := percentile(latencies, 0.5); got != 50*time.Millisecond {
+		t.Errorf("percentile(0.5) = %s, want 50ms", got)
+	}
+	if got := percentile(nil, 0.99); got != 0 {
+		t.Errorf("percentile(nil) = %s, want 0", got)
+	}
+}
+
+func TestVerifyBatchAdaptiveConcurrency(t *testing.T) {
+	var msgs []SignedMessage
+	for i := 0; i < 50; i++ {
+		msgs = append(msgs, walletTestVectors[i%len(walletTestVectors)].msg)
+	}
+
+	report, err := VerifyBatch(context.Background(), msgs, WithAdaptiveConcurrency(time.Second), WithMaxConcurrency(4))
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if report.Valid != len(msgs) {
+		t.Errorf("VerifyBatch() valid = %d, want %d", report.Valid, len(msgs))
+	}
+	if report.Concurrency == nil {
+		t.Fatal("VerifyBatch() Concurrency = nil, want stats")
+	}
+	if report.Concurrency.Final < 1 || report.Concurrency.Peak > 4 {
+		t.Errorf("VerifyBatch() Concurrency = %+v, want between 1 and 4 workers", report.Concurrency)
+	}
+
+	report, _ = VerifyBatch(context.Background(), msgs[:1])
+	if report.Concurrency != nil {
+		t.Errorf("VerifyBatch() Concurrency = %+v without adaptive concurrency, want nil", report.Concurrency)
+	}
+}
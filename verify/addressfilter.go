@@ -0,0 +1,61 @@
+package verify
+
+import (
+	"strings"
+)
+
+// AddressFilter decides which addresses proofs are accepted from before their
+// signature is verified. Entries are exact addresses, or prefixes when they
+// end with "*", such as "bc1p*" for every mainnet Taproot address. Bech32
+// addresses are matched case-insensitively, like they are decoded.
+type AddressFilter struct {
+	// Allow lists the only addresses accepted. Empty allows every address
+	// that isn't denied.
+	Allow []string
+
+	// Deny lists addresses rejected even when they are allowed
+	Deny []string
+}
+
+// Check returns an error wrapping ErrAddressBlocked when the address is
+// denied or not allowed by the filter
+func (f *AddressFilter) Check(address string) error {
+	if entry, ok := matchAddressFilter(f.Deny, address); ok {
+		return newVerifyError(ErrAddressBlocked, "%s is denied by %q", address, entry)
+	}
+	if len(f.Allow) > 0 {
+		if _, ok := matchAddressFilter(f.Allow, address); !ok {
+			return newVerifyError(ErrAddressBlocked, "%s is not allowed", address)
+		}
+	}
+	return nil
+}
+
+// matchAddressFilter returns the first entry of a filter list matching the
+// address
+func matchAddressFilter(entries []string, address string) (string, bool) {
+	address = normalizeFilterAddress(address)
+	for _, entry := range entries {
+		pattern := normalizeFilterAddress(entry)
+		if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+			if strings.HasPrefix(address, prefix) {
+				return entry, true
+			}
+		} else if address == pattern {
+			return entry, true
+		}
+	}
+	return "", false
+}
+
+// normalizeFilterAddress lowercases bech32 addresses and prefixes, whose
+// case doesn't matter, keeping base58 ones as they are
+func normalizeFilterAddress(s string) string {
+	lower := strings.ToLower(s)
+	for _, params := range knownNetworks {
+		if strings.HasPrefix(lower, params.Bech32HRPSegwit+"1") {
+			return lower
+		}
+	}
+	return s
+}
@@ -0,0 +1,43 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddressFilterCheck(t *testing.T) {
+	const (
+		p2pkh  = "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"
+		p2wpkh = "bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq"
+		p2tr   = "bc1p5cyxnuxmeuwuvkwfem96lqzszd02n6xdcjrs20cac6yqjjwudpxqkedrcr"
+	)
+
+	tests := []struct {
+		name    string
+		filter  AddressFilter
+		address string
+		wantErr bool
+	}{
+		{name: "Empty filter", address: p2pkh},
+		{name: "Denied address", filter: AddressFilter{Deny: []string{p2pkh}}, address: p2pkh, wantErr: true},
+		{name: "Other address", filter: AddressFilter{Deny: []string{p2pkh}}, address: p2wpkh},
+		{name: "Denied prefix", filter: AddressFilter{Deny: []string{"bc1p*"}}, address: p2tr, wantErr: true},
+		{name: "Uppercase bech32 address", filter: AddressFilter{Deny: []string{p2wpkh}}, address: "BC1QAR0SRRR7XFKVY5L643LYDNW9RE59GTZZWF5MDQ", wantErr: true},
+		{name: "Base58 is case-sensitive", filter: AddressFilter{Deny: []string{"1bvbmseystwetqtfn5au4m4gfg7xjanvn2"}}, address: p2pkh},
+		{name: "Allowed prefix", filter: AddressFilter{Allow: []string{"bc1*"}}, address: p2wpkh},
+		{name: "Not allowed", filter: AddressFilter{Allow: []string{"bc1*"}}, address: p2pkh, wantErr: true},
+		{name: "Deny wins over allow", filter: AddressFilter{Allow: []string{"bc1*"}, Deny: []string{p2tr}}, address: p2tr, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Check(tt.address)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AddressFilter.Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrAddressBlocked) {
+				t.Errorf("AddressFilter.Check() error = %v, want %v", err, ErrAddressBlocked)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package verify
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// VerifyAnyAddress verifies a signature against each of a user's registered
+// addresses in turn, using the Bitcoin mainnet parameters. It returns the
+// first address the signature verifies against, or false if there is none.
+func VerifyAnyAddress(addresses []string, message, signatureBase64 string) (string, bool, error) {
+	return VerifyAnyAddressWithParams(addresses, message, signatureBase64, &chaincfg.MainNetParams)
+}
+
+// VerifyAnyAddressWithParams is like VerifyAnyAddress, using the provided
+// network parameters. Addresses the signature can't be checked against, such
+// as ones of another network or of an unsupported type, are skipped, while
+// problems with the message or signature fail verification at once as they
+// would fail for every address.
+func VerifyAnyAddressWithParams(addresses []string, message, signatureBase64 string, params *chaincfg.Params) (string, bool, error) {
+	if len(addresses) == 0 {
+		return "", false, ErrEmptyAddress
+	}
+
+	for _, address := range addresses {
+		valid, err := VerifyBip137SignatureWithParams(address, message, signatureBase64, params)
+		if valid {
+			logEvent(LogLevelInfo, "Signature matched a candidate address", "address", address, "candidates", len(addresses))
+			return address, true, nil
+		}
+		if isSignatureError(err) {
+			return "", false, err
+		}
+	}
+
+	logEvent(LogLevelInfo, "Signature matched no candidate address", "candidates", len(addresses))
+	return "", false, nil
+}
+
+// isSignatureError reports whether err is about the message or signature
+// rather than the address they were verified against
+func isSignatureError(err error) bool {
+	for _, target := range []error{ErrEmptyMessage, ErrEmptySignature, ErrMessageTooLarge, ErrMalformedSignature} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyAnyAddress(t *testing.T) {
+	const message = "login to example.com"
+	alice := signTestMessage(t, "alice", nil, message)
+	bob := signTestMessage(t, "bob", nil, message)
+	testnet := "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx"
+
+	tests := []struct {
+		name      string
+		addresses []string
+		signature string
+		want      string
+		wantOK    bool
+		wantErr   error
+	}{
+		{
+			name:      "First address",
+			addresses: []string{alice.Address, bob.Address},
+			signature: alice.Signature,
+			want:      alice.Address,
+			wantOK:    true,
+		},
+		{
+			name:      "Later address",
+			addresses: []string{alice.Address, bob.Address},
+			signature: bob.Signature,
+			want:      bob.Address,
+			wantOK:    true,
+		},
+		{
+			name:      "Unusable addresses are skipped",
+			addresses: []string{"not an address", testnet, bob.Address},
+			signature: bob.Signature,
+			want:      bob.Address,
+			wantOK:    true,
+		},
+		{
+			name:      "No match",
+			addresses: []string{alice.Address},
+			signature: bob.Signature,
+		},
+		{
+			name:      "Malformed signature",
+			addresses: []string{alice.Address, bob.Address},
+			signature: "not base64",
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "No addresses",
+			signature: alice.Signature,
+			wantErr:   ErrEmptyAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := VerifyAnyAddress(tt.addresses, message, tt.signature)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("VerifyAnyAddress() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("VerifyAnyAddress() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
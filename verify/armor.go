@@ -0,0 +1,150 @@
+package verify
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Boundary lines of an ASCII-armored signed message
+const (
+	armorBeginMessage   = "-----BEGIN BITCOIN SIGNED MESSAGE-----"
+	armorBeginSignature = "-----BEGIN BITCOIN SIGNATURE-----"
+	armorEndSignature   = "-----END BITCOIN SIGNATURE-----"
+)
+
+// Boundary lines used by other wallets, accepted when parsing. Electrum
+// opens the signature section with "-----BEGIN SIGNATURE-----" and closes
+// the block with "-----END BITCOIN SIGNED MESSAGE-----".
+var (
+	armorSignatureBoundaries = []string{armorBeginSignature, "-----BEGIN SIGNATURE-----"}
+	armorEndBoundaries       = []string{armorEndSignature, "-----END BITCOIN SIGNED MESSAGE-----", "-----END SIGNATURE-----"}
+)
+
+// FormatArmored formats a signed message as the ASCII-armored block emitted
+// by Bitcoin Core-compatible wallets and Coldcard. Messages containing a line
+// that looks like an armor boundary are rejected, as they can't be armored
+// unambiguously.
+func FormatArmored(msg SignedMessage) (string, error) {
+	if !canArmor(msg.Message) {
+		return "", newVerifyError(ErrInvalidArmor, "message contains an armor boundary line")
+	}
+	if strings.ContainsAny(msg.Address, " \r\n") || strings.ContainsAny(msg.Signature, "\r\n") {
+		return "", newVerifyError(ErrInvalidArmor, "address and signature must be single words")
+	}
+	return formatArmored(msg), nil
+}
+
+// VerifyArmored verifies an ASCII-armored signed message, as emitted by
+// Bitcoin-Qt, Electrum and Coldcard, using the Bitcoin mainnet parameters.
+func VerifyArmored(text string) (bool, error) {
+	return VerifyArmoredWithParams(text, &chaincfg.MainNetParams)
+}
+
+// VerifyArmoredWithParams is like VerifyArmored, using the provided network
+// parameters.
+func VerifyArmoredWithParams(text string, params *chaincfg.Params) (bool, error) {
+	msg, err := ParseArmored(text)
+	if err != nil {
+		logEvent(LogLevelError, "Failed to parse armored message", "error", err)
+		return false, err
+	}
+	return VerifyBip137SignatureWithParams(msg.Address, msg.Message, msg.Signature, params)
+}
+
+// formatArmored formats a signed message as an ASCII-armored block without
+// checking that it can be armored
+func formatArmored(msg SignedMessage) string {
+	var sb strings.Builder
+	sb.WriteString(armorBeginMessage + "\n")
+	sb.WriteString(msg.Message + "\n")
+	sb.WriteString(armorBeginSignature + "\n")
+	sb.WriteString(msg.Address + "\n")
+	sb.WriteString(msg.Signature + "\n")
+	sb.WriteString(armorEndSignature + "\n")
+	return sb.String()
+}
+
+// canArmor reports whether the message can be armored without becoming
+// ambiguous, i.e. it doesn't contain a line that looks like an armor boundary
+func canArmor(message string) bool {
+	for _, line := range strings.Split(message, "\n") {
+		if isArmorBoundary(strings.TrimRight(line, "\r")) {
+			return false
+		}
+	}
+	return true
+}
+
+// isArmorBoundary reports whether a line is an armor boundary line
+func isArmorBoundary(line string) bool {
+	return strings.HasPrefix(line, "-----BEGIN ") || strings.HasPrefix(line, "-----END ")
+}
+
+// ParseArmored parses an ASCII-armored signed message block. The signature
+// section holds the address followed by the signature. Text before the begin
+// line and after the end line is ignored.
+func ParseArmored(text string) (SignedMessage, error) {
+	lines := strings.Split(text, "\n")
+
+	begin := -1
+	for i, line := range lines {
+		if strings.TrimRight(line, "\r") == armorBeginMessage {
+			begin = i
+			break
+		}
+	}
+	if begin < 0 {
+		return SignedMessage{}, newVerifyError(ErrInvalidArmor, "missing %q line", armorBeginMessage)
+	}
+
+	end := -1
+	for i := begin + 1; i < len(lines); i++ {
+		if slices.Contains(armorEndBoundaries, strings.TrimRight(lines[i], "\r")) {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return SignedMessage{}, newVerifyError(ErrInvalidArmor, "missing %q line", armorEndSignature)
+	}
+
+	// The signature section is the last one, the message may contain anything
+	sigStart := -1
+	for i := end - 1; i > begin; i-- {
+		if slices.Contains(armorSignatureBoundaries, strings.TrimRight(lines[i], "\r")) {
+			sigStart = i
+			break
+		}
+	}
+	if sigStart < 0 {
+		return SignedMessage{}, newVerifyError(ErrInvalidArmor, "missing %q line", armorBeginSignature)
+	}
+
+	var fields []string
+	for _, line := range lines[sigStart+1 : end] {
+		if line = strings.TrimSpace(line); line != "" {
+			fields = append(fields, line)
+		}
+	}
+	if len(fields) != 2 {
+		return SignedMessage{}, newVerifyError(ErrInvalidArmor, "signature section has %d lines, want address and signature", len(fields))
+	}
+
+	// A block whose lines end with CRLF, such as one saved as a Windows text
+	// file, had the line endings of its message converted too
+	messageLines := lines[begin+1 : sigStart]
+	if strings.HasSuffix(lines[begin], "\r") {
+		messageLines = slices.Clone(messageLines)
+		for i, line := range messageLines {
+			messageLines[i] = strings.TrimSuffix(line, "\r")
+		}
+	}
+
+	return SignedMessage{
+		Address:   fields[0],
+		Message:   strings.Join(messageLines, "\n"),
+		Signature: fields[1],
+	}, nil
+}
@@ -0,0 +1,91 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyArmored(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	const (
+		address   = "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"
+		signature = "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="
+	)
+
+	tests := []struct {
+		name      string
+		text      string
+		wantValid bool
+		wantErr   error
+	}{
+		{
+			name: "Coldcard",
+			text: "-----BEGIN BITCOIN SIGNED MESSAGE-----\ntest message\n-----BEGIN BITCOIN SIGNATURE-----\n" +
+				address + "\n" + signature + "\n-----END BITCOIN SIGNATURE-----\n",
+			wantValid: true,
+		},
+		{
+			name: "Electrum",
+			text: "-----BEGIN BITCOIN SIGNED MESSAGE-----\ntest message\n-----BEGIN SIGNATURE-----\n" +
+				address + "\n" + signature + "\n-----END BITCOIN SIGNED MESSAGE-----",
+			wantValid: true,
+		},
+		{
+			name: "CRLF line endings and surrounding text",
+			text: "Proof of reserves:\r\n-----BEGIN BITCOIN SIGNED MESSAGE-----\r\ntest message\r\n-----BEGIN BITCOIN SIGNATURE-----\r\n" +
+				address + "\r\n" + signature + "\r\n-----END BITCOIN SIGNATURE-----\r\n",
+			wantValid: true,
+		},
+		{
+			name: "Tampered message",
+			text: "-----BEGIN BITCOIN SIGNED MESSAGE-----\ntest message!\n-----BEGIN BITCOIN SIGNATURE-----\n" +
+				address + "\n" + signature + "\n-----END BITCOIN SIGNATURE-----\n",
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			name:    "Not armored",
+			text:    "test message",
+			wantErr: ErrInvalidArmor,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifyArmored(tt.text)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyArmored() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyArmored() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestFormatArmored(t *testing.T) {
+	msg := SignedMessage{Address: "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5", Message: "test message", Signature: "c2ln"}
+	text, err := FormatArmored(msg)
+	if err != nil {
+		t.Fatalf("FormatArmored() error = %v", err)
+	}
+	if got, err := ParseArmored(text); err != nil || got != msg {
+		t.Errorf("ParseArmored(FormatArmored()) = %+v, %v, want %+v", got, err, msg)
+	}
+
+	msg.Message = "-----BEGIN SIGNATURE-----"
+	if _, err := FormatArmored(msg); !errors.Is(err, ErrInvalidArmor) {
+		t.Errorf("FormatArmored() with a boundary line error = %v, want %v", err, ErrInvalidArmor)
+	}
+}
+
+func TestParseArmoredCRLF(t *testing.T) {
+	text := "-----BEGIN BITCOIN SIGNED MESSAGE-----\r\nline one\r\nline two\r\n-----BEGIN SIGNATURE-----\r\naddr\r\nsig\r\n-----END BITCOIN SIGNED MESSAGE-----\r\n"
+	got, err := ParseArmored(text)
+	if err != nil {
+		t.Fatalf("ParseArmored() error = %v", err)
+	}
+	if want := (SignedMessage{Address: "addr", Message: "line one\nline two", Signature: "sig"}); got != want {
+		t.Errorf("ParseArmored() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,35 @@
+package verify
+
+import "context"
+
+// AsyncResult is the outcome of a verification started with VerifyAsync
+type AsyncResult struct {
+	// Result is the result of the verification, never nil
+	Result *Result
+
+	// Err explains why verification failed, if it did
+	Err error
+}
+
+// VerifyAsync verifies a signed message in the background like VerifyContext,
+// returning a channel that delivers the outcome and is then closed, so
+// event-driven code can select on it alongside other events:
+//
+//	select {
+//	case r := <-v.VerifyAsync(ctx, msg):
+//		...
+//	case <-shutdown:
+//		cancel()
+//	}
+//
+// The channel is buffered, so abandoning it doesn't leak the goroutine
+// verifying the message; cancelling ctx stops the verification early.
+func (v *Verifier) VerifyAsync(ctx context.Context, msg SignedMessage) <-chan AsyncResult {
+	ch := make(chan AsyncResult, 1)
+	go func() {
+		defer close(ch)
+		result, err := v.VerifyContext(ctx, msg)
+		ch <- AsyncResult{Result: result, Err: err}
+	}()
+	return ch
+}
@@ -0,0 +1,44 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestVerifierVerifyAsync(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tampered := walletTestVectors[2].msg
+	tampered.Message = "tampered"
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		msg       SignedMessage
+		wantValid bool
+		wantErr   error
+	}{
+		{name: "Valid signature", ctx: context.Background(), msg: walletTestVectors[2].msg, wantValid: true},
+		{name: "Invalid signature", ctx: context.Background(), msg: tampered},
+		{name: "Cancelled", ctx: cancelled, msg: walletTestVectors[2].msg, wantErr: ErrVerificationTimeout},
+	}
+
+	v := NewVerifier()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch := v.VerifyAsync(tt.ctx, tt.msg)
+			r, ok := <-ch
+			if !ok {
+				t.Fatal("VerifyAsync() channel closed without a result")
+			}
+			if r.Result.Valid != tt.wantValid || (tt.wantErr != nil && !errors.Is(r.Err, tt.wantErr)) {
+				t.Errorf("VerifyAsync() = %+v, %v, want valid %v, error %v", r.Result, r.Err, tt.wantValid, tt.wantErr)
+			}
+			if _, ok := <-ch; ok {
+				t.Error("VerifyAsync() channel delivered a second result")
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+// Package auth implements challenge-response authentication with Bitcoin
+// signed messages. The server issues a random, expiring challenge bound to
+// its domain and the claimed address; the client signs the challenge
+// message with the key of that address and sends back the signature:
+//
+//	a := auth.New("example.com")
+//
+//	c, err := a.Challenge(ctx, address) // send c.Message to the client
+//	...
+//	address, err := a.Verify(ctx, c.Nonce, signature)
+//
+// With a TokenIssuer, Login answers the challenge and mints a JWT for the
+// address, so downstream services can authorize requests with Parse without
+// verifying signatures again.
+//
+// Each challenge can be answered once: it is consumed by the first Verify
+// call, whether the signature is valid or not.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sero/btc/verify"
+)
+
+// DefaultTTL is the default lifetime of a challenge
+const DefaultTTL = 5 * time.Minute
+
+// nonceLength is the number of random bytes of a nonce
+const nonceLength = 16
+
+// Errors returned when a challenge can't be answered
+var (
+	ErrEmptyAddress     = errors.New("empty address")
+	ErrUnknownChallenge = errors.New("unknown or already used challenge")
+	ErrChallengeExpired = errors.New("challenge expired")
+)
+
+// Challenge is a message a client signs to prove it controls an address
+type Challenge struct {
+	// Nonce identifies the challenge
+	Nonce string `json:"nonce"`
+
+	// Domain is the domain of the server that issued the challenge
+	Domain string `json:"domain"`
+
+	// Address is the address the client claims to control
+	Address string `json:"address"`
+
+	// Message is the text to sign, stating the domain, address, nonce and
+	// validity period
+	Message string `json:"message"`
+
+	// IssuedAt is the time the challenge was issued
+	IssuedAt time.Time `json:"issued_at"`
+
+	// ExpiresAt is the time after which the challenge can't be answered
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Authenticator issues and verifies challenges. It is safe for concurrent
+// use.
+type Authenticator struct {
+	domain   string
+	ttl      time.Duration
+	verifier verify.MessageVerifier
+	tokens   *TokenIssuer
+	nonces   NonceStore
+	now      func() time.Time
+}
+
+// Option configures an Authenticator
+type Option func(*Authenticator)
+
+// WithTTL sets how long a challenge can be answered, DefaultTTL by default
+func WithTTL(ttl time.Duration) Option {
+	return func(a *Authenticator) {
+		if ttl > 0 {
+			a.ttl = ttl
+		}
+	}
+}
+
+// WithVerifier sets the verifier signatures are verified with, by default a
+// verifier for mainnet created with verify.NewVerifier. Any
+// verify.MessageVerifier can be used, such as a verifytest.MockVerifier in
+// tests.
+func WithVerifier(v verify.MessageVerifier) Option {
+	return func(a *Authenticator) {
+		a.verifier = v
+	}
+}
+
+// WithNonceStore sets the store challenges are kept in until they are
+// answered, by default a MemoryNonceStore. Servers running as several
+// instances need a shared store, such as the Redis store of the verifyredis
+// package.
+func WithNonceStore(store NonceStore) Option {
+	return func(a *Authenticator) {
+		a.nonces = store
+	}
+}
+
+// WithClock sets the function returning the current time, for tests
+func WithClock(now func() time.Time) Option {
+	return func(a *Authenticator) {
+		a.now = now
+	}
+}
+
+// New creates an Authenticator issuing challenges bound to domain
+func New(domain string, opts ...Option) *Authenticator {
+	a := &Authenticator{
+		domain: domain,
+		ttl:    DefaultTTL,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	if a.verifier == nil {
+		a.verifier = verify.NewVerifier()
+	}
+	if a.nonces == nil {
+		a.nonces = NewMemoryNonceStore()
+	}
+	return a
+}
+
+// Challenge issues a challenge for the address
+func (a *Authenticator) Challenge(ctx context.Context, address string) (Challenge, error) {
+	if address == "" {
+		return Challenge{}, ErrEmptyAddress
+	}
+
+	nonce := make([]byte, nonceLength)
+	if _, err := rand.Read(nonce); err != nil {
+		return Challenge{}, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	now := a.now().UTC().Truncate(time.Second)
+	c := Challenge{
+		Nonce:     hex.EncodeToString(nonce),
+		Domain:    a.domain,
+		Address:   address,
+		IssuedAt:  now,
+		ExpiresAt: now.Add(a.ttl),
+	}
+	c.Message = challengeMessage(c)
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("encoding challenge: %w", err)
+	}
+	stored, err := a.nonces.Put(ctx, c.Nonce, data, a.ttl)
+	if err != nil {
+		return Challenge{}, fmt.Errorf("storing challenge: %w", err)
+	}
+	if !stored {
+		return Challenge{}, fmt.Errorf("storing challenge: nonce %s already in use", c.Nonce)
+	}
+	return c, nil
+}
+
+// Verify consumes the challenge with the nonce and verifies the signature of
+// its message, returning the authenticated address. Invalid signatures are
+// reported with the errors of the verify package.
+func (a *Authenticator) Verify(ctx context.Context, nonce, signature string) (string, error) {
+	data, ok, err := a.nonces.Take(ctx, nonce)
+	if err != nil {
+		return "", fmt.Errorf("taking challenge: %w", err)
+	}
+	if !ok {
+		return "", ErrUnknownChallenge
+	}
+
+	var c Challenge
+	if err := json.Unmarshal(data, &c); err != nil {
+		return "", fmt.Errorf("decoding challenge: %w", err)
+	}
+	// The store's clock may lag behind
+	if a.now().After(c.ExpiresAt) {
+		return "", ErrChallengeExpired
+	}
+
+	result, err := a.verifier.VerifyContext(ctx, verify.SignedMessage{
+		Address:   c.Address,
+		Message:   c.Message,
+		Signature: signature,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !result.Valid {
+		return "", verify.ErrInvalidSignature
+	}
+	return c.Address, nil
+}
+
+// challengeMessage returns the text of a challenge to sign
+func challengeMessage(c Challenge) string {
+	return fmt.Sprintf("%s wants you to sign in with your Bitcoin account:\n%s\n\nNonce: %s\nIssued At: %s\nExpiration Time: %s",
+		c.Domain, c.Address, c.Nonce, c.IssuedAt.Format(time.RFC3339), c.ExpiresAt.Format(time.RFC3339))
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+)
+
+func TestChallenge(t *testing.T) {
+	key := newTestKey("auth test key")
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	a := New("example.com", WithTTL(time.Minute), WithClock(func() time.Time { return now }))
+
+	c, err := a.Challenge(context.Background(), key.address)
+	if err != nil {
+		t.Fatalf("Challenge() error = %v", err)
+	}
+	if len(c.Nonce) != 2*nonceLength || !c.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("Challenge() = %+v, want a %d-byte nonce expiring in a minute", c, nonceLength)
+	}
+	for _, want := range []string{"example.com", key.address, c.Nonce, "2025-01-02T03:05:05Z"} {
+		if !strings.Contains(c.Message, want) {
+			t.Errorf("Challenge().Message = %q, want it to contain %q", c.Message, want)
+		}
+	}
+
+	other, _ := a.Challenge(context.Background(), key.address)
+	if other.Nonce == c.Nonce {
+		t.Errorf("Challenge() returned nonce %s twice", c.Nonce)
+	}
+
+	if _, err := a.Challenge(context.Background(), ""); !errors.Is(err, ErrEmptyAddress) {
+		t.Errorf("Challenge(\"\") error = %v, want %v", err, ErrEmptyAddress)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	key := newTestKey("auth test key")
+	other := newTestKey("other auth test key")
+
+	tests := []struct {
+		name    string
+		sign    func(c Challenge) string
+		elapsed time.Duration
+		wantErr error
+	}{
+		{
+			name: "Valid signature",
+			sign: func(c Challenge) string { return key.sign(c.Message) },
+		},
+		{
+			name:    "Signed by another key",
+			sign:    func(c Challenge) string { return other.sign(c.Message) },
+			wantErr: verify.ErrAddressMismatch,
+		},
+		{
+			name:    "Signed another message",
+			sign:    func(c Challenge) string { return key.sign(c.Message + "\n") },
+			wantErr: verify.ErrAddressMismatch,
+		},
+		{
+			name:    "Expired challenge",
+			sign:    func(c Challenge) string { return key.sign(c.Message) },
+			elapsed: 2 * time.Minute,
+			wantErr: ErrChallengeExpired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Now()
+			a := New("example.com", WithTTL(time.Minute), WithClock(func() time.Time { return now }))
+
+			c, err := a.Challenge(context.Background(), key.address)
+			if err != nil {
+				t.Fatalf("Challenge() error = %v", err)
+			}
+			now = now.Add(tt.elapsed)
+
+			address, err := a.Verify(context.Background(), c.Nonce, tt.sign(c))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && address != key.address {
+				t.Errorf("Verify() = %q, want %q", address, key.address)
+			}
+
+			// A challenge can only be answered once
+			if _, err := a.Verify(context.Background(), c.Nonce, tt.sign(c)); !errors.Is(err, ErrUnknownChallenge) {
+				t.Errorf("second Verify() error = %v, want %v", err, ErrUnknownChallenge)
+			}
+		})
+	}
+}
+
+// Helper type holding a signing key and its compressed P2PKH address
+type testKey struct {
+	privKey *btcec.PrivateKey
+	address string
+}
+
+// Helper function to derive a test key from a seed phrase
+func newTestKey(seed string) testKey {
+	hash := sha256.Sum256([]byte(seed))
+	privKey, _ := btcec.PrivKeyFromBytes(hash[:])
+	addr, _ := btcutil.NewAddressPubKeyHash(btcutil.Hash160(privKey.PubKey().SerializeCompressed()), &chaincfg.MainNetParams)
+	return testKey{privKey: privKey, address: addr.EncodeAddress()}
+}
+
+// Helper function to sign a message, returning the base64 signature
+func (k testKey) sign(message string) string {
+	digest := verify.MessageHash(message)
+	return base64.StdEncoding.EncodeToString(ecdsa.SignCompact(k.privKey, digest[:], true))
+}
@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrInvalidTTL is returned by NonceStore.Put for a ttl that isn't positive
+var ErrInvalidTTL = errors.New("nonce ttl must be positive")
+
+// NonceStore records single-use nonces with their data. Authenticators
+// share challenges through it, and the httpauth middleware records the
+// signatures it accepted, so a challenge or signature is consumed once even
+// across server instances. Implementations must be safe for concurrent use
+// and make Put and Take atomic.
+type NonceStore interface {
+	// Put records a nonce with its data until ttl elapses. It returns false,
+	// leaving the store unchanged, if the nonce is already recorded. Nonces
+	// always expire: a ttl that isn't positive fails with ErrInvalidTTL.
+	Put(ctx context.Context, nonce string, data []byte, ttl time.Duration) (bool, error)
+
+	// Take removes a recorded nonce and returns its data. It returns false
+	// if the nonce isn't recorded or has expired.
+	Take(ctx context.Context, nonce string) ([]byte, bool, error)
+}
+
+// pruneInterval is the minimum time between two prunes of a MemoryNonceStore
+const pruneInterval = time.Minute
+
+// MemoryNonceStore is a NonceStore keeping nonces in memory, for servers
+// running as a single instance
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	nonces    map[string]storedNonce
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+// storedNonce is the data of a nonce and its expiry
+type storedNonce struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// NewMemoryNonceStore creates an empty MemoryNonceStore
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		nonces: make(map[string]storedNonce),
+		now:    time.Now,
+	}
+}
+
+// Put records a nonce with its data until ttl elapses
+func (s *MemoryNonceStore) Put(ctx context.Context, nonce string, data []byte, ttl time.Duration) (bool, error) {
+	if ttl <= 0 {
+		return false, ErrInvalidTTL
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.prune(now)
+	if stored, ok := s.nonces[nonce]; ok && now.Before(stored.expiresAt) {
+		return false, nil
+	}
+	s.nonces[nonce] = storedNonce{data: data, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// Take removes a recorded nonce and returns its data
+func (s *MemoryNonceStore) Take(ctx context.Context, nonce string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.nonces[nonce]
+	delete(s.nonces, nonce)
+	if !ok || !s.now().Before(stored.expiresAt) {
+		return nil, false, nil
+	}
+	return stored.data, true, nil
+}
+
+// prune drops expired nonces, at most once per pruneInterval. The caller
+// must hold the lock.
+func (s *MemoryNonceStore) prune(now time.Time) {
+	if now.Sub(s.lastPrune) < pruneInterval {
+		return
+	}
+	for nonce, stored := range s.nonces {
+		if !now.Before(stored.expiresAt) {
+			delete(s.nonces, nonce)
+		}
+	}
+	s.lastPrune = now
+}
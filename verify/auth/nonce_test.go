@@ -0,0 +1,65 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryNonceStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := NewMemoryNonceStore()
+	s.now = func() time.Time { return now }
+
+	if ok, err := s.Put(ctx, "a", []byte("data"), time.Minute); !ok || err != nil {
+		t.Fatalf("Put() = %v, %v, want true", ok, err)
+	}
+	if ok, _ := s.Put(ctx, "a", []byte("other"), time.Minute); ok {
+		t.Errorf("Put() of a recorded nonce = true, want false")
+	}
+
+	data, ok, err := s.Take(ctx, "a")
+	if !ok || err != nil || string(data) != "data" {
+		t.Errorf("Take() = %q, %v, %v, want %q", data, ok, err, "data")
+	}
+	if _, ok, _ := s.Take(ctx, "a"); ok {
+		t.Errorf("second Take() = true, want false")
+	}
+
+	s.Put(ctx, "b", nil, time.Minute)
+	now = now.Add(2 * time.Minute)
+	if _, ok, _ := s.Take(ctx, "b"); ok {
+		t.Errorf("Take() of an expired nonce = true, want false")
+	}
+}
+
+func TestMemoryNonceStoreInvalidTTL(t *testing.T) {
+	s := NewMemoryNonceStore()
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		if ok, err := s.Put(context.Background(), "a", nil, ttl); ok || !errors.Is(err, ErrInvalidTTL) {
+			t.Errorf("Put() with ttl %s = %v, %v, want %v", ttl, ok, err, ErrInvalidTTL)
+		}
+	}
+	if len(s.nonces) != 0 {
+		t.Errorf("%d nonces stored, want none", len(s.nonces))
+	}
+}
+
+func TestMemoryNonceStorePrune(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := NewMemoryNonceStore()
+	s.now = func() time.Time { return now }
+
+	for _, nonce := range []string{"a", "b", "c"} {
+		s.Put(ctx, nonce, nil, time.Minute)
+	}
+	now = now.Add(2 * time.Minute)
+	s.Put(ctx, "d", nil, time.Minute)
+
+	if len(s.nonces) != 1 {
+		t.Errorf("%d nonces stored, want only the unexpired one", len(s.nonces))
+	}
+}
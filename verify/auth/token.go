@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DefaultTokenTTL is the default lifetime of a token
+const DefaultTokenTTL = time.Hour
+
+// Errors of token issuance and parsing
+var (
+	ErrNoTokenIssuer = errors.New("no token issuer configured")
+	ErrInvalidToken  = errors.New("invalid token")
+)
+
+// ClaimsFunc returns the custom claims of a token for an authenticated
+// address, such as roles looked up in the application's user store
+type ClaimsFunc func(ctx context.Context, address string) (map[string]interface{}, error)
+
+// TokenIssuer mints and parses JWTs for authenticated addresses. The
+// address is the subject of the token. It is safe for concurrent use.
+type TokenIssuer struct {
+	method     jwt.SigningMethod
+	signingKey interface{}
+	verifyKey  interface{}
+	issuer     string
+	audience   []string
+	ttl        time.Duration
+	claims     ClaimsFunc
+	now        func() time.Time
+}
+
+// TokenOption configures a TokenIssuer
+type TokenOption func(*TokenIssuer)
+
+// WithIssuer sets the iss claim of tokens, which Parse then requires
+func WithIssuer(issuer string) TokenOption {
+	return func(ti *TokenIssuer) {
+		ti.issuer = issuer
+	}
+}
+
+// WithAudience sets the aud claim of tokens. Parse requires the first
+// audience.
+func WithAudience(audience ...string) TokenOption {
+	return func(ti *TokenIssuer) {
+		ti.audience = audience
+	}
+}
+
+// WithTokenTTL sets how long tokens are valid, DefaultTokenTTL by default
+func WithTokenTTL(ttl time.Duration) TokenOption {
+	return func(ti *TokenIssuer) {
+		if ttl > 0 {
+			ti.ttl = ttl
+		}
+	}
+}
+
+// WithClaims adds the custom claims returned by fn to tokens. They can't
+// override the registered claims set by the issuer.
+func WithClaims(fn ClaimsFunc) TokenOption {
+	return func(ti *TokenIssuer) {
+		ti.claims = fn
+	}
+}
+
+// WithTokenClock sets the function returning the current time, for tests
+func WithTokenClock(now func() time.Time) TokenOption {
+	return func(ti *TokenIssuer) {
+		ti.now = now
+	}
+}
+
+// NewTokenIssuer creates a TokenIssuer signing tokens with the method and
+// key, such as jwt.SigningMethodHS256 with a []byte secret or
+// jwt.SigningMethodES256 with an *ecdsa.PrivateKey. Tokens are parsed with
+// the public key of keys implementing crypto.Signer, and with the key
+// itself otherwise.
+func NewTokenIssuer(method jwt.SigningMethod, key interface{}, opts ...TokenOption) *TokenIssuer {
+	ti := &TokenIssuer{
+		method:     method,
+		signingKey: key,
+		verifyKey:  key,
+		ttl:        DefaultTokenTTL,
+		now:        time.Now,
+	}
+	if signer, ok := key.(crypto.Signer); ok {
+		ti.verifyKey = signer.Public()
+	}
+	for _, opt := range opts {
+		opt(ti)
+	}
+	return ti
+}
+
+// Issue mints a token for the address
+func (ti *TokenIssuer) Issue(ctx context.Context, address string) (string, error) {
+	claims := jwt.MapClaims{}
+	if ti.claims != nil {
+		custom, err := ti.claims(ctx, address)
+		if err != nil {
+			return "", fmt.Errorf("getting claims: %w", err)
+		}
+		for name, value := range custom {
+			claims[name] = value
+		}
+	}
+
+	now := ti.now()
+	claims["sub"] = address
+	claims["iat"] = jwt.NewNumericDate(now)
+	claims["nbf"] = jwt.NewNumericDate(now)
+	claims["exp"] = jwt.NewNumericDate(now.Add(ti.ttl))
+	if ti.issuer != "" {
+		claims["iss"] = ti.issuer
+	}
+	if len(ti.audience) > 0 {
+		claims["aud"] = ti.audience
+	}
+
+	token, err := jwt.NewWithClaims(ti.method, claims).SignedString(ti.signingKey)
+	if err != nil {
+		return "", fmt.Errorf("signing token: %w", err)
+	}
+	return token, nil
+}
+
+// Parse validates a token minted by the issuer, returning its address and
+// claims. Tokens signed with another method or key, expired, or for another
+// issuer or audience are rejected with ErrInvalidToken.
+func (ti *TokenIssuer) Parse(token string) (string, jwt.MapClaims, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{ti.method.Alg()}),
+		jwt.WithTimeFunc(ti.now),
+		jwt.WithExpirationRequired(),
+	}
+	if ti.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(ti.issuer))
+	}
+	if len(ti.audience) > 0 {
+		opts = append(opts, jwt.WithAudience(ti.audience[0]))
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return ti.verifyKey, nil
+	}, opts...)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+
+	address, err := claims.GetSubject()
+	if err != nil || address == "" {
+		return "", nil, fmt.Errorf("%w: no subject", ErrInvalidToken)
+	}
+	return address, claims, nil
+}
+
+// WithTokenIssuer lets the Authenticator mint tokens with Login
+func WithTokenIssuer(ti *TokenIssuer) Option {
+	return func(a *Authenticator) {
+		a.tokens = ti
+	}
+}
+
+// Login verifies the answer to a challenge like Verify, returning a token
+// for the authenticated address minted by the token issuer
+func (a *Authenticator) Login(ctx context.Context, nonce, signature string) (string, error) {
+	if a.tokens == nil {
+		return "", ErrNoTokenIssuer
+	}
+
+	address, err := a.Verify(ctx, nonce, signature)
+	if err != nil {
+		return "", err
+	}
+	return a.tokens.Issue(ctx, address)
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sero/btc/verify"
+)
+
+func TestTokenIssuer(t *testing.T) {
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	now := time.Now()
+	clock := WithTokenClock(func() time.Time { return now })
+	roles := WithClaims(func(ctx context.Context, address string) (map[string]interface{}, error) {
+		return map[string]interface{}{"role": "admin", "sub": "ignored"}, nil
+	})
+
+	tests := []struct {
+		name    string
+		issuer  *TokenIssuer
+		parser  *TokenIssuer
+		elapsed time.Duration
+		wantErr error
+	}{
+		{
+			name:   "HMAC",
+			issuer: NewTokenIssuer(jwt.SigningMethodHS256, []byte("secret"), clock, roles),
+		},
+		{
+			name:   "ECDSA",
+			issuer: NewTokenIssuer(jwt.SigningMethodES256, ecKey, clock, roles, WithIssuer("example.com"), WithAudience("api")),
+		},
+		{
+			name:    "Wrong key",
+			issuer:  NewTokenIssuer(jwt.SigningMethodES256, ecKey, clock, roles),
+			parser:  NewTokenIssuer(jwt.SigningMethodES256, otherKey, clock),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Wrong method",
+			issuer:  NewTokenIssuer(jwt.SigningMethodHS256, []byte("secret"), clock, roles),
+			parser:  NewTokenIssuer(jwt.SigningMethodHS512, []byte("secret"), clock),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Wrong audience",
+			issuer:  NewTokenIssuer(jwt.SigningMethodHS256, []byte("secret"), clock, roles, WithAudience("api")),
+			parser:  NewTokenIssuer(jwt.SigningMethodHS256, []byte("secret"), clock, WithAudience("admin")),
+			wantErr: ErrInvalidToken,
+		},
+		{
+			name:    "Expired",
+			issuer:  NewTokenIssuer(jwt.SigningMethodHS256, []byte("secret"), clock, roles, WithTokenTTL(time.Minute)),
+			elapsed: 2 * time.Minute,
+			wantErr: ErrInvalidToken,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := now
+			defer func() { now = start }()
+
+			token, err := tt.issuer.Issue(context.Background(), "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5")
+			if err != nil {
+				t.Fatalf("Issue() error = %v", err)
+			}
+			now = now.Add(tt.elapsed)
+
+			parser := tt.parser
+			if parser == nil {
+				parser = tt.issuer
+			}
+			address, claims, err := parser.Parse(token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if address != "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5" || claims["role"] != "admin" {
+				t.Errorf("Parse() = %q, %v, want the address and custom claims", address, claims)
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	key := newTestKey("auth test key")
+	tokens := NewTokenIssuer(jwt.SigningMethodHS256, []byte("secret"))
+	a := New("example.com", WithTokenIssuer(tokens))
+
+	c, err := a.Challenge(context.Background(), key.address)
+	if err != nil {
+		t.Fatalf("Challenge() error = %v", err)
+	}
+	token, err := a.Login(context.Background(), c.Nonce, key.sign(c.Message))
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if address, _, err := tokens.Parse(token); err != nil || address != key.address {
+		t.Errorf("Parse() = %q, %v, want %q", address, err, key.address)
+	}
+
+	if _, err := New("example.com").Login(context.Background(), c.Nonce, ""); !errors.Is(err, ErrNoTokenIssuer) {
+		t.Errorf("Login() without token issuer error = %v, want %v", err, ErrNoTokenIssuer)
+	}
+}
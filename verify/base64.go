@@ -0,0 +1,89 @@
+package verify
+
+import (
+	"encoding/base64"
+	"strings"
+	"unicode"
+)
+
+// Base64Mode selects how strictly base64-encoded signatures are decoded
+type Base64Mode int
+
+const (
+	// Base64Default decodes standard padded base64, ignoring line breaks like
+	// the package-level functions do
+	Base64Default Base64Mode = iota
+
+	// Base64Strict only accepts canonical standard base64: no whitespace,
+	// no URL-safe characters and zero padding bits
+	Base64Strict
+
+	// Base64Permissive normalizes signatures before decoding: whitespace is
+	// removed, URL-safe characters are mapped onto the standard alphabet and
+	// missing padding is added
+	Base64Permissive
+)
+
+// String returns the name of the mode
+func (m Base64Mode) String() string {
+	switch m {
+	case Base64Default:
+		return "default"
+	case Base64Strict:
+		return "strict"
+	case Base64Permissive:
+		return "permissive"
+	default:
+		return "unknown"
+	}
+}
+
+// decodeSignature decodes a base64-encoded signature according to the mode
+func decodeSignature(signatureBase64 string, mode Base64Mode) ([]byte, error) {
+	var sigBytes []byte
+	var err error
+
+	switch mode {
+	case Base64Strict:
+		if strings.IndexFunc(signatureBase64, unicode.IsSpace) >= 0 {
+			return nil, newVerifyError(ErrMalformedSignature, "base64 signature contains whitespace")
+		}
+		if strings.ContainsAny(signatureBase64, "-_") {
+			return nil, newVerifyError(ErrMalformedSignature, "base64 signature contains URL-safe characters")
+		}
+		sigBytes, err = base64.StdEncoding.Strict().DecodeString(signatureBase64)
+
+	case Base64Permissive:
+		sigBytes, err = base64.StdEncoding.DecodeString(normalizeBase64(signatureBase64))
+
+	default:
+		sigBytes, err = base64.StdEncoding.DecodeString(signatureBase64)
+	}
+
+	if err != nil {
+		return nil, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	return sigBytes, nil
+}
+
+// normalizeBase64 turns a sloppily encoded base64 string into standard padded
+// base64
+func normalizeBase64(s string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return -1
+		case r == '-':
+			return '+'
+		case r == '_':
+			return '/'
+		}
+		return r
+	}, s)
+
+	s = strings.TrimRight(s, "=")
+	if rem := len(s) % 4; rem != 0 {
+		s += strings.Repeat("=", 4-rem)
+	}
+	return s
+}
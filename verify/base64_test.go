@@ -0,0 +1,72 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDecodeSignature(t *testing.T) {
+	canonical := walletTestVectors[0].msg.Signature
+	want, _ := base64.StdEncoding.DecodeString(canonical)
+
+	nonCanonicalPadding := strings.TrimSuffix(canonical, "U=") + "V="
+	withLineBreak := canonical[:44] + "\n" + canonical[44:]
+	withSpace := canonical[:44] + " " + canonical[44:]
+	urlSafe := base64.URLEncoding.EncodeToString(append(want[:1:1], bytes.Repeat([]byte{0xfb}, 64)...))
+	unpadded := strings.TrimRight(canonical, "=")
+
+	tests := []struct {
+		name           string
+		signature      string
+		wantDefault    bool
+		wantStrict     bool
+		wantPermissive bool
+	}{
+		{name: "Canonical", signature: canonical, wantDefault: true, wantStrict: true, wantPermissive: true},
+		{name: "Non-canonical padding bits", signature: nonCanonicalPadding, wantDefault: true, wantStrict: false, wantPermissive: true},
+		{name: "Embedded line break", signature: withLineBreak, wantDefault: true, wantStrict: false, wantPermissive: true},
+		{name: "Embedded space", signature: withSpace, wantDefault: false, wantStrict: false, wantPermissive: true},
+		{name: "URL-safe alphabet", signature: urlSafe, wantDefault: false, wantStrict: false, wantPermissive: true},
+		{name: "Missing padding", signature: unpadded, wantDefault: false, wantStrict: false, wantPermissive: true},
+		{name: "Garbage", signature: "not*base64", wantDefault: false, wantStrict: false, wantPermissive: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for mode, wantOK := range map[Base64Mode]bool{
+				Base64Default:    tt.wantDefault,
+				Base64Strict:     tt.wantStrict,
+				Base64Permissive: tt.wantPermissive,
+			} {
+				sigBytes, err := decodeSignature(tt.signature, mode)
+				if (err == nil) != wantOK {
+					t.Errorf("decodeSignature(%s) error = %v, want ok %v", mode, err, wantOK)
+					continue
+				}
+				if err != nil && !errors.Is(err, ErrMalformedSignature) {
+					t.Errorf("decodeSignature(%s) error = %v, want %v", mode, err, ErrMalformedSignature)
+				}
+				if err == nil && len(sigBytes) != compactSignatureLength {
+					t.Errorf("decodeSignature(%s) returned %d bytes, want %d", mode, len(sigBytes), compactSignatureLength)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifierBase64Mode(t *testing.T) {
+	msg := walletTestVectors[0].msg
+	msg.Signature = " " + strings.TrimRight(msg.Signature, "=") + "\r\n"
+
+	if _, err := NewVerifier().Verify(msg); !errors.Is(err, ErrMalformedSignature) {
+		t.Errorf("Verifier.Verify() error = %v, want %v", err, ErrMalformedSignature)
+	}
+
+	result, err := NewVerifier(WithBase64Mode(Base64Permissive)).Verify(msg)
+	if err != nil || !result.Valid {
+		t.Errorf("Verifier.Verify() with permissive base64 = %+v, %v, want valid", result, err)
+	}
+}
@@ -0,0 +1,397 @@
+package verify
+
+import (
+	"container/list"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// BatchResult is the outcome of verifying a single message of a batch
+type BatchResult struct {
+	// Index is the position of the message in the batch input
+	Index int
+
+	// Message is the message that was verified
+	Message SignedMessage
+
+	// Valid reports whether the signature is valid
+	Valid bool
+
+	// Err is the error that occurred while verifying the message, if any
+	Err error
+
+	// Cancelled reports that the message wasn't verified because the
+	// context was done before or while it was verified. Err then wraps
+	// ErrVerificationTimeout and the context error.
+	Cancelled bool
+}
+
+// BatchReport summarizes the verification of a batch of signed messages
+type BatchReport struct {
+	// Results holds one result per verified message, in input order
+	Results []BatchResult
+
+	// Total is the number of messages in the batch input
+	Total int
+
+	// Sample describes the random sample that was verified, or is nil when
+	// the whole batch was verified
+	Sample *SampleStats
+
+	// Valid is the number of messages with a valid signature
+	Valid int
+
+	// Invalid is the number of messages that failed verification
+	Invalid int
+
+	// Cancelled is the number of messages left unverified because the
+	// context was done; they count as neither valid nor invalid
+	Cancelled int
+
+	// Concurrency describes how the worker count was tuned, or is nil when
+	// adaptive concurrency wasn't enabled
+	Concurrency *ConcurrencyStats
+}
+
+// BatchOption configures a batch verification
+type BatchOption func(*batchConfig)
+
+// batchConfig holds the settings of a batch verification
+type batchConfig struct {
+	params         *chaincfg.Params
+	maxConcurrency int
+	sampleFraction float64
+	sampleSeed     int64
+	adaptiveTarget time.Duration
+
+	progress         func(BatchProgress)
+	progressInterval time.Duration
+}
+
+// WithBatchParams sets the network parameters used to verify the batch.
+// Mainnet parameters are used by default.
+func WithBatchParams(params *chaincfg.Params) BatchOption {
+	return func(c *batchConfig) {
+		c.params = params
+	}
+}
+
+// WithMaxConcurrency sets the number of messages verified concurrently.
+// It defaults to GOMAXPROCS. With adaptive concurrency it bounds the number
+// of workers instead.
+func WithMaxConcurrency(n int) BatchOption {
+	return func(c *batchConfig) {
+		if n > 0 {
+			c.maxConcurrency = n
+		}
+	}
+}
+
+// WithSample verifies only a reproducible random sample of the batch instead
+// of every message. The sample holds the given fraction of the messages
+// (rounded up) and is drawn deterministically from seed, so auditors can
+// re-run the exact same sample. The report's Sample field estimates the valid
+// rate of the whole batch with a confidence interval.
+//
+// A fraction outside (0, 1) verifies the whole batch.
+func WithSample(fraction float64, seed int64) BatchOption {
+	return func(c *batchConfig) {
+		c.sampleFraction = fraction
+		c.sampleSeed = seed
+	}
+}
+
+// WithAdaptiveConcurrency tunes the number of concurrently verified messages
+// while the batch runs: the controller measures the latency of each message
+// and adds workers while throughput improves, backing off when the 99th
+// percentile latency exceeds targetP99. Without WithMaxConcurrency, the worker
+// count is bounded by four times GOMAXPROCS.
+func WithAdaptiveConcurrency(targetP99 time.Duration) BatchOption {
+	return func(c *batchConfig) {
+		if targetP99 > 0 {
+			c.adaptiveTarget = targetP99
+		}
+	}
+}
+
+// VerifyBatch verifies a batch of signed messages concurrently. Many flows
+// verify the same challenge message for many addresses, so the magic hash of
+// each unique message is computed only once per batch.
+//
+// When the context is done, verification stops scheduling new messages and
+// the messages being verified stop at their next stage, so the workers wind
+// down promptly. The report holds the results verified so far; the messages
+// left unverified are marked Cancelled, and an error wrapping
+// ErrVerificationTimeout and the context error is returned alongside it.
+func VerifyBatch(ctx context.Context, msgs []SignedMessage, opts ...BatchOption) (*BatchReport, error) {
+	cfg := batchConfig{
+		params: &chaincfg.MainNetParams,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	events := eventLoggerFromContext(ctx)
+	spans, batchSpan := newSpanScope(ctx, GetTracer()).start(SpanBatch)
+	batchSpan.SetAttribute("count", len(msgs))
+
+	var ctrl *concurrencyController
+	if cfg.adaptiveTarget > 0 {
+		if cfg.maxConcurrency == 0 {
+			cfg.maxConcurrency = adaptiveCeilingFactor * runtime.GOMAXPROCS(0)
+		}
+		ctrl = newConcurrencyController(cfg.adaptiveTarget, runtime.GOMAXPROCS(0), cfg.maxConcurrency)
+	} else if cfg.maxConcurrency == 0 {
+		cfg.maxConcurrency = runtime.GOMAXPROCS(0)
+	}
+
+	events.log(LogLevelInfo, "Starting batch verification", "count", len(msgs), "network", cfg.params.Name)
+	events.log(LogLevelDebug, "Batch concurrency", "concurrency", cfg.maxConcurrency, "adaptive", ctrl != nil)
+
+	startTime := time.Now()
+	defer func() {
+		events.log(LogLevelDebug, "Batch verification completed", "count", len(msgs), "duration", time.Since(startTime))
+	}()
+
+	selected := selectBatchIndexes(len(msgs), cfg)
+	if len(selected) < len(msgs) {
+		events.log(LogLevelInfo, "Verifying a sample of the batch", "sample", len(selected), "count", len(msgs), "seed", cfg.sampleSeed)
+	}
+
+	results := make([]BatchResult, len(selected))
+	for k, i := range selected {
+		results[k] = BatchResult{Index: i, Message: msgs[i]}
+	}
+
+	cache := newHashCache(magicHash, 0)
+	progress := newProgressTracker(cfg, len(results))
+	indexes := make(chan int)
+	m := GetMetrics()
+
+	var wg sync.WaitGroup
+	for w := 0; w < cfg.maxConcurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ctrl == nil {
+				for k := range indexes {
+					verifyBatchResult(spans, &results[k], cache, cfg.params, m)
+					progress.record(&results[k])
+				}
+				return
+			}
+
+			// Only take a message once the controller grants a slot
+			for {
+				ctrl.acquire()
+				k, ok := <-indexes
+				if !ok {
+					ctrl.release(0, false)
+					return
+				}
+				itemStart := time.Now()
+				verifyBatchResult(spans, &results[k], cache, cfg.params, m)
+				progress.record(&results[k])
+				ctrl.release(time.Since(itemStart), true)
+			}
+		}()
+	}
+
+	// Schedule the messages until all are scheduled or the context is done.
+	// The context is checked first, as select picks a ready worker over a
+	// done context at random.
+	next := 0
+schedule:
+	for ; next < len(results) && ctx.Err() == nil; next++ {
+		select {
+		case <-ctx.Done():
+			break schedule
+		case indexes <- next:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	ctxErr := checkContext(ctx)
+	for k := next; k < len(results); k++ {
+		results[k].Err = ctxErr
+		results[k].Cancelled = true
+	}
+
+	report := &BatchReport{Results: results, Total: len(msgs)}
+	for _, result := range results {
+		switch {
+		case result.Cancelled:
+			report.Cancelled++
+		case result.Valid:
+			report.Valid++
+		default:
+			report.Invalid++
+		}
+	}
+	if report.Cancelled == 0 {
+		// The context was done once every message had been verified
+		ctxErr = nil
+	}
+	progress.finish(report)
+	if ctxErr != nil {
+		events.log(LogLevelError, "Batch verification stopped",
+			"verified", len(results)-report.Cancelled, "count", len(results), "error", ctx.Err())
+	}
+
+	if ctrl != nil {
+		report.Concurrency = ctrl.snapshot()
+		events.log(LogLevelDebug, "Adaptive concurrency finished",
+			"workers", report.Concurrency.Final, "peak", report.Concurrency.Peak, "adjustments", report.Concurrency.Adjustments)
+	}
+
+	if len(selected) < len(msgs) {
+		report.Sample = newSampleStats(cfg, len(msgs), report.Valid, len(results)-report.Cancelled)
+		events.log(LogLevelInfo, "Estimated valid rate",
+			"valid_rate", report.Sample.ValidRate, "confidence", report.Sample.ConfidenceLevel,
+			"valid_rate_low", report.Sample.ValidRateLow, "valid_rate_high", report.Sample.ValidRateHigh)
+	}
+
+	hits, misses := cache.stats()
+	if m != nil {
+		m.ObserveHashCache(hits, misses)
+	}
+	events.log(LogLevelDebug, "Batch message hash cache", "hits", hits, "misses", misses)
+	events.log(LogLevelInfo, "Batch verification result", "valid", report.Valid, "invalid", report.Invalid, "cancelled", report.Cancelled)
+
+	batchSpan.SetAttribute("valid", report.Valid)
+	batchSpan.SetAttribute("invalid", report.Invalid)
+	batchSpan.SetAttribute("cancelled", report.Cancelled)
+	batchSpan.End(ctxErr)
+
+	return report, ctxErr
+}
+
+// verifyBatchResult verifies the message of a batch result and records the
+// outcome. A message taken after the context is done isn't verified, and one
+// stopped by the context while being verified is marked Cancelled as well.
+func verifyBatchResult(spans spanScope, result *BatchResult, cache *hashCache, params *chaincfg.Params, m Metrics) {
+	if err := spans.err(); err != nil {
+		result.Err, result.Cancelled = err, true
+		return
+	}
+
+	itemStart := time.Now()
+	result.Valid, result.Err = verifyBatchItem(spans, result.Message, cache, params)
+	result.Cancelled = errors.Is(result.Err, ErrVerificationTimeout)
+	observeVerification(m, itemStart, result.Valid, result.Err)
+}
+
+// verifyBatchItem verifies a single message of a batch, taking the magic hash
+// of the message from the batch cache.
+func verifyBatchItem(spans spanScope, msg SignedMessage, cache *hashCache, params *chaincfg.Params) (valid bool, err error) {
+	spans, span := spans.start(SpanVerify)
+	span.SetAttribute("address", msg.Address)
+	defer func() {
+		span.SetAttribute("valid", valid)
+		span.End(err)
+	}()
+
+	if msg.Address == "" {
+		return false, ErrEmptyAddress
+	}
+	if msg.Message == "" {
+		return false, ErrEmptyMessage
+	}
+	if err := checkMessageSize(eventLogger{}, msg.Message, MaxMessageSize()); err != nil {
+		return false, err
+	}
+	if msg.Signature == "" {
+		return false, ErrEmptySignature
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return false, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+
+	digest := cache.get(msg.Message)
+	valid, err = verifyDigest(spans, msg.Address, digest[:], sigBytes, params)
+	if err != nil {
+		return false, fmt.Errorf("signature verification error: %w", err)
+	}
+
+	return valid, nil
+}
+
+// hashCache caches message hashes keyed by message content. When it holds
+// limit entries, the least recently used one is evicted to make room.
+type hashCache struct {
+	hash  func(string) [32]byte
+	limit int
+
+	mu     sync.Mutex
+	hashes map[string]*list.Element
+	order  *list.List
+	hits   int
+	misses int
+}
+
+// hashEntry is a message hash held by a hashCache
+type hashEntry struct {
+	message string
+	digest  [32]byte
+}
+
+// newHashCache creates an empty cache of the digests computed by hash,
+// holding up to limit entries, or any number when limit is 0
+func newHashCache(hash func(string) [32]byte, limit int) *hashCache {
+	return &hashCache{
+		hash:   hash,
+		limit:  limit,
+		hashes: make(map[string]*list.Element),
+		order:  list.New(),
+	}
+}
+
+// get returns the hash of the message, computing it on a cache miss
+func (c *hashCache) get(message string) [32]byte {
+	c.mu.Lock()
+	elem, ok := c.hashes[message]
+	if ok {
+		c.hits++
+		c.order.MoveToFront(elem)
+		digest := elem.Value.(*hashEntry).digest
+		c.mu.Unlock()
+		return digest
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// Hash outside the lock; concurrent misses for the same message compute
+	// the same digest
+	digest := c.hash(message)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.hashes[message]; ok {
+		return digest
+	}
+	c.hashes[message] = c.order.PushFront(&hashEntry{message: message, digest: digest})
+	if c.limit > 0 && c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.hashes, oldest.Value.(*hashEntry).message)
+	}
+
+	return digest
+}
+
+// stats returns the number of cache hits and misses
+func (c *hashCache) stats() (hits, misses int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
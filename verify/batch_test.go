@@ -0,0 +1,332 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Signatures produced by various wallets, collected by the BitonicNL verifier
+var walletTestVectors = []struct {
+	name string
+	msg  SignedMessage
+}{
+	{
+		name: "Generated test signature",
+		msg: SignedMessage{
+			Address:   "194vDb9xwY6XQi5bLa7FRPBewJdUqympZ9",
+			Message:   "Hello, Bitcoin testing!",
+			Signature: "IOeVH/0KqgmS3XKwqCJiwlcHonwxKMQN6fbOW5UsXSDZB4EGCVTXx6c+ZU/Ae5qO94MSBZn2aPOiUsupRIwBaAU=",
+		},
+	},
+	{
+		name: "BMS uncompressed P2PKH",
+		msg: SignedMessage{
+			Address:   "19f7adDYqhHSJm2v7igFWZAqxXHj1vUa3T",
+			Message:   "test message",
+			Signature: "HFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+		},
+	},
+	{
+		name: "BMS compressed P2PKH",
+		msg: SignedMessage{
+			Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			Message:   "test message",
+			Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+		},
+	},
+	{
+		name: "Electrum P2SH-P2WPKH",
+		msg: SignedMessage{
+			Address:   "3LbZqMMHu371r5Fjve9qNhSQzuNi7EzqUR",
+			Message:   "test123",
+			Signature: "H2ehXowFWMZohHrJN+1IRdDwqN/UILqVmhIOHpeBdS4BYDCQpfDL1tTH7mNg6eeypno+Is8ApgWinkPnnz1NEq8=",
+		},
+	},
+	{
+		name: "Trezor P2SH-P2WPKH",
+		msg: SignedMessage{
+			Address:   "3L6TyTisPBmrDAj6RoKmDzNnj4eQi54gD2",
+			Message:   "This is an example of a signed message.",
+			Signature: "I3RN5FFvrFwUCAgBVmRRajL+rZTeiXdc7H4k28JP4TMHWsCTAcTMjhl76ktkgWYdW46b8Z2Le4o4Ls21PC7gdQ0=",
+		},
+	},
+	{
+		name: "Trezor P2WPKH",
+		msg: SignedMessage{
+			Address:   "bc1qannfxke2tfd4l7vhepehpvt05y83v3qsf6nfkk",
+			Message:   "This is an example of a signed message.",
+			Signature: "KLVddgDZ6afipJFV3fPP2455bCB/qrgzAQ+kH7eCiIm8R89iNIp6qgkjwIMqWJ+rVB6PEutU+3EckOIwfw9msZQ=",
+		},
+	},
+	{
+		name: "Mycelium P2WPKH",
+		msg: SignedMessage{
+			Address:   "bc1q58dh2fpwms37g29nw979pa65lsvjkqxq82jzvv",
+			Message:   "Test message!",
+			Signature: "ILNax/LC+m3WwzIhnrieNN8DRzWTAgcVStSJmwdabUQII2fIlYUlEgnlNf4j2G4yJQoO4zFqCwaLOX4PDj1XwjA=",
+		},
+	},
+	{
+		name: "UniSat P2TR",
+		msg: SignedMessage{
+			Address:   "bc1pgc9k3vdmr9aecmwj09qg5qv550qyyrydufyfmxrsvk5474rxenuqrq4lcz",
+			Message:   "hello world",
+			Signature: "H/KLWcCfl/P34V9TdPzcSlG3sdhllArBXjypbz9BBY1GXDRCwYogO50Crznm8I9P/JAfhnojgbV5vPYSAhWA1p0=",
+		},
+	},
+}
+
+func TestVerifyBatch(t *testing.T) {
+	var msgs []SignedMessage
+	for _, tv := range walletTestVectors {
+		msgs = append(msgs, tv.msg)
+	}
+
+	invalid := []SignedMessage{
+		// Valid signature for a different address
+		{
+			Address:   "14wPe34dikRzK4tMYvtwMMJCEZbJ7ar35V",
+			Message:   "test message",
+			Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+		},
+		// SegWit header byte with a P2PKH address
+		{
+			Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			Message:   "test message",
+			Signature: "KFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+		},
+		{Address: "", Message: "test message", Signature: "Base64Signature=="},
+		{Address: "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5", Message: "test message", Signature: "not base64"},
+	}
+	msgs = append(msgs, invalid...)
+
+	report, err := VerifyBatch(context.Background(), msgs, WithMaxConcurrency(3))
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+
+	if len(report.Results) != len(msgs) {
+		t.Fatalf("VerifyBatch() returned %d results, want %d", len(report.Results), len(msgs))
+	}
+	if report.Valid != len(walletTestVectors) || report.Invalid != len(invalid) {
+		t.Errorf("VerifyBatch() valid = %d, invalid = %d, want %d, %d",
+			report.Valid, report.Invalid, len(walletTestVectors), len(invalid))
+	}
+
+	for i, result := range report.Results {
+		if result.Index != i {
+			t.Errorf("Results[%d].Index = %d", i, result.Index)
+		}
+		wantValid := i < len(walletTestVectors)
+		if result.Valid != wantValid || (result.Err != nil) == wantValid {
+			t.Errorf("Results[%d] = %v, %v, want valid %v", i, result.Valid, result.Err, wantValid)
+		}
+	}
+}
+
+func TestVerifyBatchWithParams(t *testing.T) {
+	msgs := []SignedMessage{walletTestVectors[0].msg}
+
+	report, err := VerifyBatch(context.Background(), msgs, WithBatchParams(&chaincfg.TestNet3Params))
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if report.Valid != 0 || report.Results[0].Err == nil {
+		t.Errorf("VerifyBatch() accepted a mainnet address with testnet parameters")
+	}
+}
+
+func TestVerifyBatchCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	msgs := []SignedMessage{walletTestVectors[0].msg, walletTestVectors[1].msg}
+	report, err := VerifyBatch(ctx, msgs)
+	if !errors.Is(err, ErrVerificationTimeout) {
+		t.Fatalf("VerifyBatch() error = %v, want %v", err, ErrVerificationTimeout)
+	}
+	if len(report.Results) != len(msgs) {
+		t.Fatalf("VerifyBatch() returned %d results, want %d", len(report.Results), len(msgs))
+	}
+	if report.Cancelled != len(msgs) || report.Valid != 0 || report.Invalid != 0 {
+		t.Errorf("VerifyBatch() valid = %d, invalid = %d, cancelled = %d, want 0, 0, %d",
+			report.Valid, report.Invalid, report.Cancelled, len(msgs))
+	}
+	for i, result := range report.Results {
+		if !result.Cancelled || !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Results[%d] = cancelled %v, %v, want cancelled with %v", i, result.Cancelled, result.Err, context.Canceled)
+		}
+	}
+}
+
+func TestVerifyBatchCancelledPartway(t *testing.T) {
+	defer SetTracer(nil)
+
+	for _, adaptive := range []bool{false, true} {
+		t.Run(fmt.Sprintf("adaptive %v", adaptive), func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			// Cancel when the third message starts verifying, which
+			// stops it after decoding and leaves the fourth unscheduled
+			SetTracer(&cancellingTracer{at: SpanVerify, skip: 2, cancel: cancel})
+
+			opts := []BatchOption{WithMaxConcurrency(1)}
+			if adaptive {
+				opts = append(opts, WithAdaptiveConcurrency(time.Minute))
+			}
+			msgs := []SignedMessage{walletTestVectors[1].msg, walletTestVectors[2].msg, walletTestVectors[3].msg, walletTestVectors[4].msg}
+			report, err := VerifyBatch(ctx, msgs, opts...)
+			if !errors.Is(err, ErrVerificationTimeout) {
+				t.Fatalf("VerifyBatch() error = %v, want %v", err, ErrVerificationTimeout)
+			}
+			if report.Valid != 2 || report.Invalid != 0 || report.Cancelled != 2 {
+				t.Errorf("VerifyBatch() valid = %d, invalid = %d, cancelled = %d, want 2, 0, 2",
+					report.Valid, report.Invalid, report.Cancelled)
+			}
+			for i, result := range report.Results {
+				if wantCancelled := i >= 2; result.Cancelled != wantCancelled {
+					t.Errorf("Results[%d].Cancelled = %v, want %v (error: %v)", i, result.Cancelled, wantCancelled, result.Err)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyBatchProgress(t *testing.T) {
+	var msgs []SignedMessage
+	for range 50 {
+		msgs = append(msgs, walletTestVectors[2].msg, walletTestVectors[0].msg)
+	}
+	msgs[1].Message = "tampered"
+
+	var snapshots []BatchProgress
+	report, err := VerifyBatch(context.Background(), msgs, WithMaxConcurrency(2),
+		WithProgress(time.Millisecond, func(p BatchProgress) {
+			snapshots = append(snapshots, p)
+		}))
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+
+	if len(snapshots) == 0 {
+		t.Fatal("WithProgress() reported nothing")
+	}
+	last := snapshots[len(snapshots)-1]
+	want := BatchProgress{Done: len(msgs), Total: len(msgs), Valid: report.Valid, Invalid: report.Invalid, Elapsed: last.Elapsed, Final: true}
+	if last != want {
+		t.Errorf("final progress = %+v, want %+v", last, want)
+	}
+	for i, p := range snapshots[:len(snapshots)-1] {
+		if p.Final || p.Done != p.Valid+p.Invalid || p.Done > len(msgs) || (i > 0 && p.Done < snapshots[i-1].Done) {
+			t.Errorf("progress %d = %+v, inconsistent with the previous %+v", i, p, snapshots[max(i-1, 0)])
+		}
+		if p.Done > 0 && p.Done < len(msgs) && p.ETA <= 0 {
+			t.Errorf("progress %d = %+v, want an ETA", i, p)
+		}
+	}
+}
+
+func TestHashCache(t *testing.T) {
+	cache := newHashCache(magicHash, 0)
+
+	first := cache.get("challenge")
+	second := cache.get("challenge")
+	cache.get("other challenge")
+
+	if first != second || first != magicHash("challenge") {
+		t.Errorf("hashCache.get() returned inconsistent digests")
+	}
+	if hits, misses := cache.stats(); hits != 1 || misses != 2 {
+		t.Errorf("hashCache.stats() = %d, %d, want 1, 2", hits, misses)
+	}
+}
+
+func TestHashCacheLimit(t *testing.T) {
+	cache := newHashCache(magicHash, 2)
+
+	cache.get("first")
+	cache.get("second")
+	cache.get("first")
+	cache.get("third") // evicts "second", the least recently used
+	cache.get("first")
+	cache.get("second")
+
+	if hits, misses := cache.stats(); hits != 2 || misses != 4 {
+		t.Errorf("hashCache.stats() = %d, %d, want 2, 4", hits, misses)
+	}
+	if n := cache.order.Len(); n != 2 || len(cache.hashes) != 2 {
+		t.Errorf("hashCache holds %d entries, want 2", n)
+	}
+}
+
+func TestVerifyBatchSample(t *testing.T) {
+	var msgs []SignedMessage
+	for i := 0; i < 40; i++ {
+		msgs = append(msgs, walletTestVectors[i%len(walletTestVectors)].msg)
+	}
+
+	sampledIndexes := func(seed int64) []int {
+		report, err := VerifyBatch(context.Background(), msgs, WithSample(0.25, seed))
+		if err != nil {
+			t.Fatalf("VerifyBatch() error = %v", err)
+		}
+		if report.Total != len(msgs) || len(report.Results) != 10 {
+			t.Fatalf("VerifyBatch() total = %d, results = %d, want %d, 10", report.Total, len(report.Results), len(msgs))
+		}
+		if report.Sample == nil {
+			t.Fatalf("VerifyBatch() Sample = nil")
+		}
+		if report.Sample.ValidRate != 1 || report.Sample.ValidRateLow > 1 || report.Sample.ValidRateLow <= 0.5 || report.Sample.ValidRateHigh != 1 {
+			t.Errorf("VerifyBatch() Sample = %+v", report.Sample)
+		}
+
+		var indexes []int
+		for _, result := range report.Results {
+			indexes = append(indexes, result.Index)
+		}
+		return indexes
+	}
+
+	first := sampledIndexes(42)
+	second := sampledIndexes(42)
+	for i := range first {
+		if first[i] != second[i] {
+			t.Fatalf("samples drawn with the same seed differ: %v and %v", first, second)
+		}
+		if i > 0 && first[i] <= first[i-1] {
+			t.Fatalf("sampled results are not in input order: %v", first)
+		}
+	}
+
+	report, err := VerifyBatch(context.Background(), msgs, WithSample(1, 42))
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if report.Sample != nil || len(report.Results) != len(msgs) {
+		t.Errorf("VerifyBatch() with a full sample verified %d messages, sample = %+v", len(report.Results), report.Sample)
+	}
+}
+
+func TestNewSampleStats(t *testing.T) {
+	cfg := batchConfig{sampleFraction: 0.1, sampleSeed: 1}
+
+	stats := newSampleStats(cfg, 1000, 90, 100)
+	if stats.ValidRate != 0.9 {
+		t.Errorf("ValidRate = %v, want 0.9", stats.ValidRate)
+	}
+	if stats.ValidRateLow >= 0.9 || stats.ValidRateLow < 0.8 || stats.ValidRateHigh <= 0.9 || stats.ValidRateHigh > 0.97 {
+		t.Errorf("confidence interval = [%v, %v], want around 0.9", stats.ValidRateLow, stats.ValidRateHigh)
+	}
+
+	// Sampling the whole population leaves no uncertainty
+	stats = newSampleStats(cfg, 100, 90, 100)
+	if stats.ValidRateLow != 0.9 || stats.ValidRateHigh != 0.9 {
+		t.Errorf("confidence interval = [%v, %v], want [0.9, 0.9]", stats.ValidRateLow, stats.ValidRateHigh)
+	}
+}
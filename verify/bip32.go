@@ -0,0 +1,141 @@
+package verify
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// DerivationPath is a BIP-32 derivation path. Hardened steps have the
+// hdkeychain.HardenedKeyStart bit set.
+type DerivationPath []uint32
+
+// ParseDerivationPath parses a derivation path such as "m/84'/0'/0'/0/5".
+// Hardened steps may be marked with ', h or H. The leading "m/" is optional.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(path), "m"), "/")
+	if path == "" {
+		return DerivationPath{}, nil
+	}
+
+	steps := strings.Split(path, "/")
+	p := make(DerivationPath, 0, len(steps))
+	for _, step := range steps {
+		index, ok := parsePathStep(step)
+		if !ok {
+			return nil, newVerifyError(ErrInvalidDerivationPath, "invalid step %q", step)
+		}
+		p = append(p, index)
+	}
+	return p, nil
+}
+
+// String formats the path with the m/ prefix and ' for hardened steps
+func (p DerivationPath) String() string {
+	var sb strings.Builder
+	sb.WriteString("m")
+	for _, index := range p {
+		sb.WriteByte('/')
+		if index >= hdkeychain.HardenedKeyStart {
+			sb.WriteString(strconv.FormatUint(uint64(index-hdkeychain.HardenedKeyStart), 10) + "'")
+		} else {
+			sb.WriteString(strconv.FormatUint(uint64(index), 10))
+		}
+	}
+	return sb.String()
+}
+
+// DeriveChildKey derives the public key at path from an extended public
+// key for the network. A path starting with "m/" is the full path from the
+// master key: its first steps, which must lead to the extended key and may
+// be hardened, are skipped. Other paths are relative to the extended key.
+// Steps derived from the extended key can't be hardened.
+func DeriveChildKey(xpub, path string, params *chaincfg.Params) (*btcec.PublicKey, error) {
+	key, _, err := parseXpub(xpub, params)
+	if err != nil {
+		return nil, err
+	}
+	child, err := deriveChild(key, path)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := child.ECPubKey()
+	if err != nil {
+		return nil, newVerifyError(ErrInvalidExtendedKey, "%v", err)
+	}
+	return pubKey, nil
+}
+
+// DeriveAddress derives the address at path from an extended public key,
+// as DeriveChildKey does. The version of the key selects the address type,
+// as for VerifyAgainstXpub.
+func DeriveAddress(xpub, path string, params *chaincfg.Params) (string, error) {
+	key, addrType, err := parseXpub(xpub, params)
+	if err != nil {
+		return "", err
+	}
+	child, err := deriveChild(key, path)
+	if err != nil {
+		return "", err
+	}
+	pubKey, err := child.ECPubKey()
+	if err != nil {
+		return "", newVerifyError(ErrInvalidExtendedKey, "%v", err)
+	}
+	addr, err := pubKeyHashAddress(addrType, btcutil.Hash160(pubKey.SerializeCompressed()), params)
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// deriveChild derives the key at a full or relative path from an extended
+// public key
+func deriveChild(key *hdkeychain.ExtendedKey, path string) (*hdkeychain.ExtendedKey, error) {
+	steps, err := ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(path), "m") {
+		depth := int(key.Depth())
+		if len(steps) < depth {
+			return nil, newVerifyError(ErrInvalidDerivationPath, "path %s is above the extended key at depth %d", steps, depth)
+		}
+		if depth > 0 && steps[depth-1] != key.ChildIndex() {
+			return nil, newVerifyError(ErrInvalidDerivationPath, "path %s doesn't lead to the extended key", steps)
+		}
+		steps = steps[depth:]
+	}
+
+	for _, index := range steps {
+		if index >= hdkeychain.HardenedKeyStart {
+			return nil, newVerifyError(ErrInvalidDerivationPath, "hardened step %d' can't be derived from a public key", index-hdkeychain.HardenedKeyStart)
+		}
+		if key, err = key.Derive(index); err != nil {
+			return nil, newVerifyError(ErrInvalidDerivationPath, "deriving %d: %v", index, err)
+		}
+	}
+	return key, nil
+}
+
+// parsePathStep parses a derivation step, hardened when marked with ', h
+// or H
+func parsePathStep(step string) (uint32, bool) {
+	var hardened bool
+	if trimmed := strings.TrimRight(step, "'hH"); len(trimmed) == len(step)-1 {
+		step, hardened = trimmed, true
+	}
+	index, err := strconv.ParseUint(step, 10, 32)
+	if err != nil || index >= hdkeychain.HardenedKeyStart {
+		return 0, false
+	}
+	if hardened {
+		index += hdkeychain.HardenedKeyStart
+	}
+	return uint32(index), true
+}
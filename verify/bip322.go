@@ -0,0 +1,35 @@
+package verify
+
+import (
+	"encoding/base64"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// ConvertToBIP322 converts a stored BIP-0137 proof into the BIP-322 proof
+// for the same address and message, for services migrating to BIP-322. The
+// proof is verified first, as converting an invalid proof is pointless.
+//
+// BIP-322 signs a different digest than BIP-0137, so a new signature can't
+// be derived without the private key. P2PKH proofs are returned unchanged,
+// as BIP-322 accepts compact signatures as its legacy format for P2PKH
+// addresses, and so are proofs that already are BIP-322 signatures. Compact
+// signatures of other addresses fail with ErrConversionUnsupported; their
+// owners need to sign the message again.
+func ConvertToBIP322(msg SignedMessage, params *chaincfg.Params) (SignedMessage, error) {
+	valid, info, err := VerifyBip137SignatureExWithParams(msg.Address, msg.Message, msg.Signature, params)
+	if err != nil {
+		return SignedMessage{}, err
+	}
+	if !valid {
+		return SignedMessage{}, ErrInvalidSignature
+	}
+
+	// The signature decoded when it was verified
+	sigBytes, _ := base64.StdEncoding.DecodeString(msg.Signature)
+	if len(sigBytes) == compactSignatureLength && info.AddressType != AddressTypeP2PKH {
+		return SignedMessage{}, newVerifyError(ErrConversionUnsupported,
+			"BIP-322 has no legacy format for %s addresses, the message must be signed again", info.AddressType)
+	}
+	return msg, nil
+}
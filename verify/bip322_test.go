@@ -0,0 +1,45 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestConvertToBIP322(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	bip322 := SignedMessage{
+		Address:   "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l",
+		Message:   "Hello World",
+		Signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+	}
+	tampered := walletTestVectors[2].msg
+	tampered.Message = "tampered"
+
+	tests := []struct {
+		name    string
+		msg     SignedMessage
+		wantErr error
+	}{
+		{name: "Uncompressed P2PKH", msg: walletTestVectors[1].msg},
+		{name: "Compressed P2PKH", msg: walletTestVectors[2].msg},
+		{name: "Already BIP-322", msg: bip322},
+		{name: "P2SH-P2WPKH", msg: walletTestVectors[3].msg, wantErr: ErrConversionUnsupported},
+		{name: "P2WPKH", msg: walletTestVectors[5].msg, wantErr: ErrConversionUnsupported},
+		{name: "Invalid signature", msg: tampered, wantErr: ErrAddressMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertToBIP322(tt.msg, &chaincfg.MainNetParams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ConvertToBIP322() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.msg {
+				t.Errorf("ConvertToBIP322() = %+v, want %+v", got, tt.msg)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "m/84'/0'/0'/0/5", want: "m/84'/0'/0'/0/5"},
+		{path: "m/84h/0H/0'/1/2", want: "m/84'/0'/0'/1/2"},
+		{path: "0/5", want: "m/0/5"},
+		{path: "m", want: "m"},
+		{path: "m/84''", wantErr: true},
+		{path: "m/x", wantErr: true},
+		{path: "m/2147483648", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ParseDerivationPath(tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDerivationPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("ParseDerivationPath() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeriveChildKey(t *testing.T) {
+	// BIP-32 test vector 1, chain m/0H/1/2H and m/0H/1/2H/2/1000000000
+	const (
+		xpub = "xpub6D4BDPcP2GT577Vvch3R8wDkScZWzQzMMUm3PWbmWvVJrZwQY4VUNgqFJPMM3No2dFDFGTsxxpG5uJh7n7epu4trkrX7x7DogT5Uv6fcLW5"
+		leaf = "xpub6H1LXWLaKsWFhvm6RVpEL9P4KfRZSW7abD2ttkWP3SSQvnyA8FSVqNTEcYFgJS2UaFcxupHiYkro49S8yGasTvXEYBVPamhGW6cFJodrTHy"
+	)
+	leafKey, err := hdkeychain.NewKeyFromString(leaf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := leafKey.ECPubKey()
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "Full path", path: "m/0'/1/2'/2/1000000000"},
+		{name: "Relative path", path: "2/1000000000"},
+		{name: "Other branch", path: "m/0'/1/3'/2/1000000000", wantErr: ErrInvalidDerivationPath},
+		{name: "Above the key", path: "m/0'", wantErr: ErrInvalidDerivationPath},
+		{name: "Hardened step below the key", path: "2'/1000000000", wantErr: ErrInvalidDerivationPath},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeriveChildKey(xpub, tt.path, &chaincfg.MainNetParams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DeriveChildKey() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !got.IsEqual(want) {
+				t.Errorf("DeriveChildKey() = %x, want %x", got.SerializeCompressed(), want.SerializeCompressed())
+			}
+		})
+	}
+}
+
+func TestDeriveAddress(t *testing.T) {
+	account := newTestAccount(t, &chaincfg.MainNetParams)
+	want := signWithAccount(t, account, 0, 5, AddressTypeP2WPKH, "m").Address
+
+	got, err := DeriveAddress(testXpub(t, account, xpubVersions[2].version), "m/0'/0/5", &chaincfg.MainNetParams)
+	if err != nil || got != want {
+		t.Errorf("DeriveAddress() = %q, %v, want %q", got, err, want)
+	}
+}
@@ -0,0 +1,230 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil/bech32"
+)
+
+// BOLT-11 tagged field types, as bech32 character values
+const (
+	invoiceFieldPaymentHash     = 1  // p
+	invoiceFieldExpiry          = 6  // x
+	invoiceFieldDescription     = 13 // d
+	invoiceFieldPayee           = 19 // n
+	invoiceFieldDescriptionHash = 23 // h
+)
+
+// Sizes of the BOLT-11 data part, in 5-bit groups
+const (
+	invoiceTimestampLength = 7
+	invoiceSignatureLength = 104
+	invoiceHashLength      = 52
+	invoicePubKeyLength    = 53
+)
+
+// invoiceDefaultExpiry is the expiry of invoices without an expiry field
+const invoiceDefaultExpiry = time.Hour
+
+// invoiceMsatPerBTC is the number of millisatoshis in a bitcoin
+const invoiceMsatPerBTC = 100_000_000_000
+
+// InvoiceSummary holds the fields of a BOLT-11 Lightning invoice that help a
+// reviewer understand what a signed invoice commits to
+type InvoiceSummary struct {
+	// Currency is the currency prefix of the invoice, e.g. "bc" or "tb"
+	Currency string
+
+	// AmountMsat is the requested amount in millisatoshis, 0 if the invoice
+	// leaves the amount to the payer
+	AmountMsat uint64
+
+	// Payee is the hex-encoded public key of the payee node
+	Payee string
+
+	// PaymentHash is the hex-encoded payment hash
+	PaymentHash string
+
+	// Description is the purpose of the payment, if given in full
+	Description string
+
+	// DescriptionHash is the hex-encoded hash of the description, if the
+	// invoice only commits to its hash
+	DescriptionHash string
+
+	// Timestamp is the creation time of the invoice
+	Timestamp time.Time
+
+	// Expiry is how long after Timestamp the invoice can be paid
+	Expiry time.Duration
+}
+
+// String returns a one-line summary of the invoice
+func (s *InvoiceSummary) String() string {
+	amount := "any amount"
+	if s.AmountMsat > 0 {
+		amount = fmt.Sprintf("%d msat", s.AmountMsat)
+	}
+
+	summary := fmt.Sprintf("BOLT-11 invoice (%s) for %s to %s", s.Currency, amount, s.Payee)
+	if s.Description != "" {
+		summary += fmt.Sprintf(" for %q", s.Description)
+	}
+	return summary
+}
+
+// looksLikeInvoice reports whether a message is meant to be a BOLT-11 invoice
+func looksLikeInvoice(message string) bool {
+	return strings.HasPrefix(strings.ToLower(trimInvoiceScheme(message)), "ln")
+}
+
+// trimInvoiceScheme removes surrounding whitespace and a "lightning:" URI
+// scheme from an invoice
+func trimInvoiceScheme(invoice string) string {
+	invoice = strings.TrimSpace(invoice)
+	if len(invoice) > len("lightning:") && strings.EqualFold(invoice[:len("lightning:")], "lightning:") {
+		invoice = invoice[len("lightning:"):]
+	}
+	return invoice
+}
+
+// decodeInvoice decodes a BOLT-11 invoice and checks its signature. Only the
+// fields needed for an InvoiceSummary are decoded.
+func decodeInvoice(invoice string) (*InvoiceSummary, error) {
+	hrp, data, err := bech32.DecodeNoLimit(trimInvoiceScheme(invoice))
+	if err != nil {
+		return nil, fmt.Errorf("invalid bech32: %w", err)
+	}
+	if !strings.HasPrefix(hrp, "ln") {
+		return nil, fmt.Errorf("invalid prefix %q", hrp)
+	}
+	if len(data) < invoiceTimestampLength+invoiceSignatureLength {
+		return nil, errors.New("invoice too short")
+	}
+
+	summary := &InvoiceSummary{Expiry: invoiceDefaultExpiry}
+	if summary.Currency, summary.AmountMsat, err = parseInvoiceAmount(hrp[2:]); err != nil {
+		return nil, err
+	}
+
+	fields := data[:len(data)-invoiceSignatureLength]
+	summary.Timestamp = time.Unix(int64(groupsToUint(fields[:invoiceTimestampLength])), 0).UTC()
+
+	var payee []byte
+	for rest := fields[invoiceTimestampLength:]; len(rest) > 0; {
+		if len(rest) < 3 {
+			return nil, errors.New("truncated tagged field")
+		}
+		fieldType := rest[0]
+		length := int(rest[1])<<5 | int(rest[2])
+		if len(rest) < 3+length {
+			return nil, errors.New("truncated tagged field")
+		}
+		value := rest[3 : 3+length]
+		rest = rest[3+length:]
+
+		// Fields with an unexpected length must be skipped, per BOLT-11
+		switch {
+		case fieldType == invoiceFieldPaymentHash && length == invoiceHashLength:
+			summary.PaymentHash = hex.EncodeToString(groupsToBytes(value))
+		case fieldType == invoiceFieldDescriptionHash && length == invoiceHashLength:
+			summary.DescriptionHash = hex.EncodeToString(groupsToBytes(value))
+		case fieldType == invoiceFieldPayee && length == invoicePubKeyLength:
+			payee = groupsToBytes(value)
+		case fieldType == invoiceFieldDescription:
+			summary.Description = string(groupsToBytes(value))
+		case fieldType == invoiceFieldExpiry:
+			summary.Expiry = time.Duration(groupsToUint(value)) * time.Second
+		}
+	}
+
+	// The signature covers the human-readable part and the data before the
+	// signature, padded to whole bytes
+	signed, err := bech32.ConvertBits(fields, 5, 8, true)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256(append([]byte(hrp), signed...))
+
+	sig := groupsToBytes(data[len(data)-invoiceSignatureLength:])
+	recoveryID := sig[64]
+	if recoveryID > 3 {
+		return nil, fmt.Errorf("invalid recovery id %d", recoveryID)
+	}
+
+	compact := append([]byte{headerP2PKHCompressed + recoveryID}, sig[:64]...)
+	pubKey, _, err := ecdsa.RecoverCompact(compact, digest[:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid invoice signature: %w", err)
+	}
+
+	recovered := pubKey.SerializeCompressed()
+	if payee != nil && string(payee) != string(recovered) {
+		return nil, errors.New("invoice signature does not match payee")
+	}
+	summary.Payee = hex.EncodeToString(recovered)
+
+	return summary, nil
+}
+
+// parseInvoiceAmount splits the human-readable part after "ln" into the
+// currency prefix and the amount in millisatoshis
+func parseInvoiceAmount(s string) (string, uint64, error) {
+	i := strings.IndexAny(s, "0123456789")
+	if i < 0 {
+		return s, 0, nil
+	}
+	currency, amount := s[:i], s[i:]
+
+	divisor := int64(1)
+	switch amount[len(amount)-1] {
+	case 'm':
+		divisor = 1_000
+	case 'u':
+		divisor = 1_000_000
+	case 'n':
+		divisor = 1_000_000_000
+	case 'p':
+		divisor = 1_000_000_000_000
+	}
+	if divisor != 1 {
+		amount = amount[:len(amount)-1]
+	}
+
+	value, ok := new(big.Int).SetString(amount, 10)
+	if !ok || value.Sign() <= 0 {
+		return "", 0, fmt.Errorf("invalid amount %q", s[i:])
+	}
+
+	msat, rem := new(big.Int).QuoRem(value.Mul(value, big.NewInt(invoiceMsatPerBTC)), big.NewInt(divisor), new(big.Int))
+	if rem.Sign() != 0 {
+		return "", 0, fmt.Errorf("amount %q is not a whole number of millisatoshis", s[i:])
+	}
+	if !msat.IsUint64() {
+		return "", 0, fmt.Errorf("amount %q out of range", s[i:])
+	}
+
+	return currency, msat.Uint64(), nil
+}
+
+// groupsToUint interprets 5-bit groups as a big-endian unsigned integer
+func groupsToUint(groups []byte) uint64 {
+	var n uint64
+	for _, g := range groups {
+		n = n<<5 | uint64(g)
+	}
+	return n
+}
+
+// groupsToBytes converts 5-bit groups to bytes, dropping the padding bits
+func groupsToBytes(groups []byte) []byte {
+	b, _ := bech32.ConvertBits(groups, 5, 8, false)
+	return b
+}
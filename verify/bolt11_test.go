@@ -0,0 +1,116 @@
+package verify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// BOLT-11 specification examples, signed by the same node
+const (
+	testInvoiceDonation = "lnbc1pvjluezsp5zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zygspp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdpl2pkx2ctnv5sxxmmwwd5kgetjypeh2ursdae8g6twvus8g6rfwvs8qun0dfjkxaq9qrsgq357wnc5r2ueh7ck6q93dj32dlqnls087fxdwk8qakdyafkq3yap9us6v52vjjsrvywa6rt52cm9r9zqt8r2t7mlcwspyetp5h2tztugp9lfyql"
+	testInvoiceCoffee   = "lnbc2500u1pvjluezsp5zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zyg3zygspp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqypqdq5xysxxatsyp3k7enxv4jsxqzpu9qrsgquk0rl77nj30yxdy8j9vdx85fkpmdla2087ne0xh8nhedh8w27kyke0lp53ut353s06fv3qfegext0eh0ymjpf39tuven09sam30g4vgpfna3rh"
+	testInvoicePayee    = "03e7156ae33b0a208d0744199163177e909e80176e55d97a2f221ede0f934dd9ad"
+)
+
+func TestDecodeInvoice(t *testing.T) {
+	tests := []struct {
+		name            string
+		invoice         string
+		wantAmount      uint64
+		wantDescription string
+		wantExpiry      time.Duration
+		wantErr         bool
+	}{
+		{
+			name:            "Donation of any amount",
+			invoice:         testInvoiceDonation,
+			wantAmount:      0,
+			wantDescription: "Please consider supporting this project",
+			wantExpiry:      time.Hour,
+		},
+		{
+			name:            "Coffee with amount and expiry",
+			invoice:         testInvoiceCoffee,
+			wantAmount:      250_000_000,
+			wantDescription: "1 cup coffee",
+			wantExpiry:      time.Minute,
+		},
+		{
+			name:            "Lightning URI in upper case",
+			invoice:         "LIGHTNING:" + strings.ToUpper(testInvoiceCoffee),
+			wantAmount:      250_000_000,
+			wantDescription: "1 cup coffee",
+			wantExpiry:      time.Minute,
+		},
+		{
+			name:    "Bad checksum",
+			invoice: testInvoiceCoffee[:len(testInvoiceCoffee)-1] + "q",
+			wantErr: true,
+		},
+		{
+			name:    "Not an invoice",
+			invoice: "bc1qannfxke2tfd4l7vhepehpvt05y83v3qsf6nfkk",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeInvoice(tt.invoice)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeInvoice() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			if got.Currency != "bc" {
+				t.Errorf("decodeInvoice().Currency = %s, want bc", got.Currency)
+			}
+			if got.Payee != testInvoicePayee {
+				t.Errorf("decodeInvoice().Payee = %s, want %s", got.Payee, testInvoicePayee)
+			}
+			if got.AmountMsat != tt.wantAmount {
+				t.Errorf("decodeInvoice().AmountMsat = %d, want %d", got.AmountMsat, tt.wantAmount)
+			}
+			if got.Description != tt.wantDescription {
+				t.Errorf("decodeInvoice().Description = %q, want %q", got.Description, tt.wantDescription)
+			}
+			if got.Expiry != tt.wantExpiry {
+				t.Errorf("decodeInvoice().Expiry = %s, want %s", got.Expiry, tt.wantExpiry)
+			}
+		})
+	}
+}
+
+func TestParseInvoiceAmount(t *testing.T) {
+	tests := []struct {
+		hrp          string
+		wantCurrency string
+		wantMsat     uint64
+		wantErr      bool
+	}{
+		{hrp: "bc", wantCurrency: "bc"},
+		{hrp: "bc1", wantCurrency: "bc", wantMsat: 100_000_000_000},
+		{hrp: "tb20m", wantCurrency: "tb", wantMsat: 2_000_000_000},
+		{hrp: "bcrt2500u", wantCurrency: "bcrt", wantMsat: 250_000_000},
+		{hrp: "bc10n", wantCurrency: "bc", wantMsat: 1_000},
+		{hrp: "bc10p", wantCurrency: "bc", wantMsat: 1},
+		{hrp: "bc1p", wantErr: true},
+		{hrp: "bc0", wantErr: true},
+		{hrp: "bc99999999999999999999", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.hrp, func(t *testing.T) {
+			currency, msat, err := parseInvoiceAmount(tt.hrp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseInvoiceAmount() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (currency != tt.wantCurrency || msat != tt.wantMsat) {
+				t.Errorf("parseInvoiceAmount() = %s, %d, want %s, %d", currency, msat, tt.wantCurrency, tt.wantMsat)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package verify
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// bundleVersion is the version of the bundle format written by WriteBundle
+const bundleVersion = 1
+
+// Bundle is a set of proofs of control of many addresses, exchanged as one
+// JSON file, as requested by exchanges from users proving they control
+// several addresses:
+//
+//	{
+//	  "manifest": {"version": 1, "network": "mainnet", "count": 2, "digest": "sha256:...", ...},
+//	  "proofs": [{"address": "...", "message": "...", "signature": "..."}, ...]
+//	}
+type Bundle struct {
+	Manifest BundleManifest  `json:"manifest"`
+	Proofs   []SignedMessage `json:"proofs"`
+}
+
+// BundleManifest describes the proofs of a bundle
+type BundleManifest struct {
+	// Version is the version of the bundle format
+	Version int `json:"version"`
+
+	// Network is the name of the network of the addresses, e.g. "mainnet"
+	Network string `json:"network"`
+
+	// Description says what the bundle proves, e.g. who it is for
+	Description string `json:"description,omitempty"`
+
+	// CreatedAt is when the bundle was created
+	CreatedAt time.Time `json:"created_at"`
+
+	// Count is the number of proofs
+	Count int `json:"count"`
+
+	// Digest is the SHA-256 digest of the JSON encoding of the proofs as
+	// "sha256:<hex>", detecting proofs added, removed or altered in transit
+	Digest string `json:"digest"`
+}
+
+// BundleReport is the outcome of verifying a bundle. The embedded batch
+// report holds the result of each proof.
+type BundleReport struct {
+	*BatchReport
+
+	// Manifest is the manifest of the verified bundle
+	Manifest BundleManifest
+
+	// Addresses lists the distinct addresses with a valid proof, in bundle
+	// order
+	Addresses []string
+
+	// Complete reports whether every proof of the bundle is valid
+	Complete bool
+}
+
+// NewBundle creates a bundle of proofs for addresses of the network, filling
+// in the manifest
+func NewBundle(params *chaincfg.Params, description string, proofs []SignedMessage) Bundle {
+	return Bundle{
+		Manifest: BundleManifest{
+			Version:     bundleVersion,
+			Network:     params.Name,
+			Description: description,
+			CreatedAt:   time.Now().UTC().Truncate(time.Second),
+			Count:       len(proofs),
+			Digest:      bundleDigest(proofs),
+		},
+		Proofs: proofs,
+	}
+}
+
+// WriteBundle writes a bundle as indented JSON
+func WriteBundle(w io.Writer, b Bundle) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// ReadBundle reads a bundle, checking its manifest: the version and network
+// must be known, and the count and digest must match the proofs. The proofs
+// themselves are checked by VerifyBundle.
+func ReadBundle(r io.Reader) (Bundle, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var b Bundle
+	if err := dec.Decode(&b); err != nil {
+		return Bundle{}, newVerifyError(ErrMalformedBundle, "%v", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return Bundle{}, newVerifyError(ErrMalformedBundle, "unexpected data after the bundle")
+	}
+
+	m := b.Manifest
+	switch {
+	case m.Version != bundleVersion:
+		return Bundle{}, newVerifyError(ErrMalformedBundle, "unsupported version %d", m.Version)
+	case networkByName(m.Network) == nil:
+		return Bundle{}, newVerifyError(ErrMalformedBundle, "unknown network %q", m.Network)
+	case m.Count != len(b.Proofs):
+		return Bundle{}, newVerifyError(ErrMalformedBundle, "manifest counts %d proofs, bundle holds %d", m.Count, len(b.Proofs))
+	case m.Digest != bundleDigest(b.Proofs):
+		return Bundle{}, newVerifyError(ErrMalformedBundle, "proofs don't match the manifest digest")
+	}
+	return b, nil
+}
+
+// VerifyBundle verifies every proof of a bundle with VerifyBatch, for the
+// network of its manifest. Like VerifyBatch, the report is returned
+// alongside the context error when the context is done.
+func VerifyBundle(ctx context.Context, b Bundle, opts ...BatchOption) (*BundleReport, error) {
+	params := networkByName(b.Manifest.Network)
+	if params == nil {
+		return nil, newVerifyError(ErrMalformedBundle, "unknown network %q", b.Manifest.Network)
+	}
+
+	batch, err := VerifyBatch(ctx, b.Proofs, append(opts, WithBatchParams(params))...)
+	report := &BundleReport{
+		BatchReport: batch,
+		Manifest:    b.Manifest,
+		Complete:    batch.Sample == nil && batch.Valid == batch.Total,
+	}
+
+	seen := make(map[string]bool)
+	for _, result := range batch.Results {
+		if result.Valid && !seen[result.Message.Address] {
+			seen[result.Message.Address] = true
+			report.Addresses = append(report.Addresses, result.Message.Address)
+		}
+	}
+	return report, err
+}
+
+// bundleDigest returns the digest of the proofs of a bundle
+func bundleDigest(proofs []SignedMessage) string {
+	if proofs == nil {
+		proofs = []SignedMessage{}
+	}
+	data, _ := json.Marshal(proofs)
+	sum := sha256.Sum256(data)
+	return digestPrefix + hex.EncodeToString(sum[:])
+}
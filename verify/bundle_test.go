@@ -0,0 +1,95 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestBundleRoundTrip(t *testing.T) {
+	var proofs []SignedMessage
+	for _, tv := range walletTestVectors {
+		proofs = append(proofs, tv.msg)
+	}
+	b := NewBundle(&chaincfg.MainNetParams, "Withdrawal address verification", proofs)
+
+	var buf bytes.Buffer
+	if err := WriteBundle(&buf, b); err != nil {
+		t.Fatalf("WriteBundle() error = %v", err)
+	}
+	got, err := ReadBundle(&buf)
+	if err != nil {
+		t.Fatalf("ReadBundle() error = %v", err)
+	}
+	if got.Manifest != b.Manifest || len(got.Proofs) != len(proofs) {
+		t.Errorf("ReadBundle() = %+v, want %+v", got, b)
+	}
+
+	report, err := VerifyBundle(context.Background(), got)
+	if err != nil {
+		t.Fatalf("VerifyBundle() error = %v", err)
+	}
+	if !report.Complete || report.Valid != len(proofs) || len(report.Addresses) != len(proofs) {
+		t.Errorf("VerifyBundle() = %d valid of %d, %d addresses, complete %v, want all valid",
+			report.Valid, report.Total, len(report.Addresses), report.Complete)
+	}
+}
+
+func TestVerifyBundleIncomplete(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	valid := walletTestVectors[0].msg
+	invalid := walletTestVectors[1].msg
+	invalid.Message = "tampered"
+	b := NewBundle(&chaincfg.MainNetParams, "", []SignedMessage{valid, invalid, valid})
+
+	report, err := VerifyBundle(context.Background(), b)
+	if err != nil {
+		t.Fatalf("VerifyBundle() error = %v", err)
+	}
+	if report.Complete || report.Valid != 2 || report.Invalid != 1 {
+		t.Errorf("VerifyBundle() = %d valid, %d invalid, complete %v, want 2, 1, false", report.Valid, report.Invalid, report.Complete)
+	}
+	if len(report.Addresses) != 1 || report.Addresses[0] != valid.Address {
+		t.Errorf("VerifyBundle() addresses = %v, want [%s]", report.Addresses, valid.Address)
+	}
+}
+
+func TestReadBundleInvalid(t *testing.T) {
+	b := NewBundle(&chaincfg.MainNetParams, "", []SignedMessage{walletTestVectors[0].msg})
+
+	tests := []struct {
+		name   string
+		modify func(b *Bundle)
+		text   string
+	}{
+		{name: "Unsupported version", modify: func(b *Bundle) { b.Manifest.Version = 2 }},
+		{name: "Unknown network", modify: func(b *Bundle) { b.Manifest.Network = "litecoin" }},
+		{name: "Wrong count", modify: func(b *Bundle) { b.Manifest.Count = 2 }},
+		{name: "Altered proof", modify: func(b *Bundle) { b.Proofs = []SignedMessage{walletTestVectors[1].msg} }},
+		{name: "Not JSON", text: "proofs"},
+		{name: "Unknown field", text: `{"manifest": {}, "proofs": [], "extra": 1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := tt.text
+			if tt.modify != nil {
+				modified := b
+				tt.modify(&modified)
+				var buf bytes.Buffer
+				if err := WriteBundle(&buf, modified); err != nil {
+					t.Fatal(err)
+				}
+				text = buf.String()
+			}
+			if _, err := ReadBundle(strings.NewReader(text)); !errors.Is(err, ErrMalformedBundle) {
+				t.Errorf("ReadBundle() error = %v, want %v", err, ErrMalformedBundle)
+			}
+		})
+	}
+}
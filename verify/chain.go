@@ -0,0 +1,77 @@
+package verify
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// SignatureChain is a message with a chain of signatures, for notarization
+// and approval workflows: the first signature signs the message, and each
+// following one countersigns the message together with every signature
+// before it. In JSON:
+//
+//	{
+//	  "message": "invoice #1234: 0.5 BTC",
+//	  "signatures": [{"address": "...", "signature": "..."}, ...]
+//	}
+type SignatureChain struct {
+	Message    string             `json:"message"`
+	Signatures []AddressSignature `json:"signatures"`
+}
+
+// SignedMessage returns the message the signature at index i of the chain
+// signs. The first signature signs the message itself, so it is an ordinary
+// BIP-0137 proof; each countersignature signs the message the signature
+// before it signed, followed by a line with that signature's address and
+// signature separated by a space.
+func (c SignatureChain) SignedMessage(i int) string {
+	var sb strings.Builder
+	sb.WriteString(c.Message)
+	for _, s := range c.Signatures[:i] {
+		sb.WriteString("\n" + s.Address + " " + s.Signature)
+	}
+	return sb.String()
+}
+
+// NextMessage returns the message the next countersignature of the chain
+// signs
+func (c SignatureChain) NextMessage() string {
+	return c.SignedMessage(len(c.Signatures))
+}
+
+// VerifyChain verifies every signature of a chain, using the Bitcoin mainnet
+// parameters. See VerifyChainWithParams.
+func VerifyChain(c SignatureChain) (bool, error) {
+	return VerifyChainWithParams(c, &chaincfg.MainNetParams)
+}
+
+// VerifyChainWithParams is like VerifyChain, using the provided network
+// parameters. The chain is valid when each signature verifies against its
+// address for the message returned by SignedMessage; the error of the first
+// that doesn't says which it is.
+func VerifyChainWithParams(c SignatureChain, params *chaincfg.Params) (bool, error) {
+	if c.Message == "" {
+		return false, ErrEmptyMessage
+	}
+	if len(c.Signatures) == 0 {
+		return false, ErrEmptySignature
+	}
+
+	for i, s := range c.Signatures {
+		if strings.ContainsAny(s.Address, " \r\n") || strings.ContainsAny(s.Signature, " \r\n") {
+			return false, newVerifyError(ErrMalformedSignature, "signature %d of the chain: address and signature must be single words", i)
+		}
+		valid, err := VerifyBip137SignatureWithParams(s.Address, c.SignedMessage(i), s.Signature, params)
+		if err != nil {
+			return false, fmt.Errorf("signature %d of the chain by %s: %w", i, s.Address, err)
+		}
+		if !valid {
+			return false, newVerifyError(ErrInvalidSignature, "signature %d of the chain by %s", i, s.Address)
+		}
+	}
+
+	logEvent(LogLevelInfo, "Signature chain verified", "signatures", len(c.Signatures), "network", params.Name)
+	return true, nil
+}
@@ -0,0 +1,88 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyChain(t *testing.T) {
+	const message = "invoice #1234: 0.5 BTC"
+
+	// Alice signs the invoice, Bob approves it and a notary countersigns both
+	chain := SignatureChain{Message: message}
+	for _, seed := range []string{"alice", "bob", "notary"} {
+		signed := signTestMessage(t, seed, nil, chain.NextMessage())
+		chain.Signatures = append(chain.Signatures, AddressSignature{Address: signed.Address, Signature: signed.Signature})
+	}
+	alice, bob := chain.Signatures[0], chain.Signatures[1]
+
+	tests := []struct {
+		name    string
+		chain   SignatureChain
+		wantErr error
+	}{
+		{name: "Chain", chain: chain},
+		{name: "Single signature", chain: SignatureChain{Message: message, Signatures: chain.Signatures[:1]}},
+		{
+			name: "Reordered signatures",
+			chain: SignatureChain{Message: message, Signatures: []AddressSignature{
+				chain.Signatures[0], chain.Signatures[2], chain.Signatures[1],
+			}},
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			name:    "Countersignature of another message",
+			chain:   SignatureChain{Message: "invoice #1234: 5 BTC", Signatures: chain.Signatures},
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			// Bob's signature only signs the message when it stands alone
+			name:    "Countersignature without the signature it countersigns",
+			chain:   SignatureChain{Message: message, Signatures: []AddressSignature{bob}},
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			name:    "Signature with a space",
+			chain:   SignatureChain{Message: message, Signatures: []AddressSignature{{Address: alice.Address + " x", Signature: alice.Signature}}},
+			wantErr: ErrMalformedSignature,
+		},
+		{name: "No signatures", chain: SignatureChain{Message: message}, wantErr: ErrEmptySignature},
+		{name: "Empty message", chain: SignatureChain{Signatures: chain.Signatures}, wantErr: ErrEmptyMessage},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifyChain(tt.chain)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("VerifyChain() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != (tt.wantErr == nil) {
+				t.Errorf("VerifyChain() = %v, want %v", valid, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestSignatureChainSignedMessage(t *testing.T) {
+	chain := SignatureChain{
+		Message:    "message",
+		Signatures: []AddressSignature{{Address: "addr1", Signature: "sig1"}, {Address: "addr2", Signature: "sig2"}},
+	}
+
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "message"},
+		{1, "message\naddr1 sig1"},
+		{2, "message\naddr1 sig1\naddr2 sig2"},
+	}
+	for _, tt := range tests {
+		if got := chain.SignedMessage(tt.index); got != tt.want {
+			t.Errorf("SignedMessage(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+	}
+	if got := chain.NextMessage(); got != chain.SignedMessage(2) {
+		t.Errorf("NextMessage() = %q, want %q", got, chain.SignedMessage(2))
+	}
+}
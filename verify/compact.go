@@ -0,0 +1,62 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// CompactSignature is a BIP-0137 compact signature split into its parts
+type CompactSignature struct {
+	// HeaderByte is the first byte of the signature
+	HeaderByte byte
+
+	// RecoveryID selects which of the candidate public keys signed
+	RecoveryID int
+
+	// Compressed reports whether the header byte asks for the compressed
+	// serialization of the public key
+	Compressed bool
+
+	// AddressType is the address type the header byte stands for
+	AddressType AddressType
+
+	// R and S are the hex-encoded signature values
+	R string
+	S string
+
+	// LowS reports whether S is in the lower half of the curve order
+	LowS bool
+}
+
+// DecodeCompactSignature decodes a base64-encoded 65-byte signature and
+// splits it into its parts, without verifying it. The signature is returned
+// along with ErrInvalidHeaderByte when the header byte is outside the
+// BIP-0137 ranges, so tools can still show its R and S values.
+func DecodeCompactSignature(signatureBase64 string) (CompactSignature, error) {
+	if signatureBase64 == "" {
+		return CompactSignature{}, ErrEmptySignature
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return CompactSignature{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	if len(sigBytes) != compactSignatureLength {
+		return CompactSignature{}, newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d", len(sigBytes), compactSignatureLength)
+	}
+
+	header := inspectHeader(sigBytes[0])
+	sig := CompactSignature{
+		HeaderByte:  header.HeaderByte,
+		RecoveryID:  header.RecoveryID,
+		Compressed:  header.Compressed,
+		AddressType: header.AddressType,
+		R:           hex.EncodeToString(sigBytes[1:33]),
+		S:           hex.EncodeToString(sigBytes[33:]),
+		LowS:        isLowS(sigBytes),
+	}
+	if !header.Valid() {
+		return sig, newVerifyError(ErrInvalidHeaderByte, "0x%02x", header.HeaderByte)
+	}
+	return sig, nil
+}
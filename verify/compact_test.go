@@ -0,0 +1,83 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestDecodeCompactSignature(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+		want      CompactSignature
+		wantErr   error
+	}{
+		{
+			name:      "Compressed P2PKH",
+			signature: walletTestVectors[2].msg.Signature,
+			want: CompactSignature{
+				HeaderByte:  32,
+				RecoveryID:  1,
+				Compressed:  true,
+				AddressType: AddressTypeP2PKH,
+				R:           "5a94a38fecc4110591f2bc99979e379e95d7706a39cd03f74a0f042845de04ea",
+				S:           "317b4d4824cdefd5310b7de467c322d1c1f2e336029d07c2c53c8ba4c55729e0",
+				LowS:        true,
+			},
+		},
+		{
+			name:      "Uncompressed P2PKH",
+			signature: walletTestVectors[1].msg.Signature,
+			want: CompactSignature{
+				HeaderByte:  28,
+				RecoveryID:  1,
+				AddressType: AddressTypeP2PKH,
+				R:           "5a94a38fecc4110591f2bc99979e379e95d7706a39cd03f74a0f042845de04ea",
+				S:           "317b4d4824cdefd5310b7de467c322d1c1f2e336029d07c2c53c8ba4c55729e0",
+				LowS:        true,
+			},
+		},
+		{
+			name:      "Empty signature",
+			signature: "",
+			wantErr:   ErrEmptySignature,
+		},
+		{
+			name:      "Invalid base64",
+			signature: "not base64!",
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "Wrong length",
+			signature: "AAAA",
+			wantErr:   ErrMalformedSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCompactSignature(tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeCompactSignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DecodeCompactSignature() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCompactSignatureInvalidHeader(t *testing.T) {
+	sig := make([]byte, compactSignatureLength)
+	sig[0] = 43
+	sig[64] = 1
+
+	got, err := DecodeCompactSignature(base64.StdEncoding.EncodeToString(sig))
+	if !errors.Is(err, ErrInvalidHeaderByte) {
+		t.Fatalf("DecodeCompactSignature() error = %v, want %v", err, ErrInvalidHeaderByte)
+	}
+	if got.HeaderByte != 43 || got.S == "" {
+		t.Errorf("DecodeCompactSignature() = %+v, want the decoded parts", got)
+	}
+}
@@ -0,0 +1,449 @@
+package verify
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Lines framing a multi-proof container
+const (
+	containerBegin      = "-----BEGIN BITCOIN PROOF CONTAINER-----"
+	containerEnd        = "-----END BITCOIN PROOF CONTAINER-----"
+	containerBeginIndex = "-----BEGIN PROOF INDEX-----"
+	containerEndIndex   = "-----END PROOF INDEX-----"
+	containerVersion    = "Version: 1"
+	containerIndexField = "Index-Offset: "
+	containerCountField = "Count: "
+)
+
+// containerTailSize is the number of bytes read from the end of a container
+// to locate its index
+const containerTailSize = 256
+
+// containerMinIndexLine is the length of the shortest possible index line,
+// which bounds the number of entries an index of a given size can hold
+const containerMinIndexLine = len("0 1 x\n")
+
+// containerArmorOverhead bounds the armor lines, address and signature an
+// armored entry holds besides its message
+const containerArmorOverhead = 1024
+
+// ContainerIndexEntry locates one armored signed message within a container
+type ContainerIndexEntry struct {
+	// Offset is the byte offset of the entry's begin line
+	Offset int64
+
+	// Length is the length of the armored entry in bytes
+	Length int64
+
+	// Address is the address of the entry, so entries can be looked up
+	// without parsing them
+	Address string
+}
+
+// ContainerWriter streams armored signed messages into a multi-proof
+// container. The container starts with a header, holds one armored block per
+// message and ends with an index of all entries, so thousands of proofs can be
+// exchanged as a single file and still be accessed randomly.
+//
+// The container format is:
+//
+//	-----BEGIN BITCOIN PROOF CONTAINER-----
+//	Version: 1
+//
+//	-----BEGIN BITCOIN SIGNED MESSAGE-----
+//	...one armored block per message...
+//	-----END BITCOIN SIGNATURE-----
+//	-----BEGIN PROOF INDEX-----
+//	<offset> <length> <address>
+//	-----END PROOF INDEX-----
+//	Index-Offset: <offset of the index begin line>
+//	Count: <number of entries>
+//	-----END BITCOIN PROOF CONTAINER-----
+type ContainerWriter struct {
+	w      io.Writer
+	offset int64
+	index  []ContainerIndexEntry
+	closed bool
+}
+
+// NewContainerWriter writes the container header to w and returns a writer
+// for its entries.
+func NewContainerWriter(w io.Writer) (*ContainerWriter, error) {
+	cw := &ContainerWriter{w: w}
+	if err := cw.writeString(containerBegin + "\n" + containerVersion + "\n\n"); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+// Write appends a signed message to the container. Messages containing a line
+// that looks like an armor boundary are rejected, as they can't be armored
+// unambiguously.
+func (cw *ContainerWriter) Write(msg SignedMessage) error {
+	if cw.closed {
+		return ErrContainerClosed
+	}
+	block, err := FormatArmored(msg)
+	if err != nil {
+		return err
+	}
+	entry := ContainerIndexEntry{Offset: cw.offset, Length: int64(len(block)), Address: msg.Address}
+	if err := cw.writeString(block); err != nil {
+		return err
+	}
+
+	cw.index = append(cw.index, entry)
+	return nil
+}
+
+// Close writes the index and the container trailer. It doesn't close the
+// underlying writer.
+func (cw *ContainerWriter) Close() error {
+	if cw.closed {
+		return nil
+	}
+	cw.closed = true
+
+	indexOffset := cw.offset
+
+	var sb strings.Builder
+	sb.WriteString(containerBeginIndex + "\n")
+	for _, entry := range cw.index {
+		fmt.Fprintf(&sb, "%d %d %s\n", entry.Offset, entry.Length, entry.Address)
+	}
+	sb.WriteString(containerEndIndex + "\n")
+	fmt.Fprintf(&sb, "%s%d\n%s%d\n", containerIndexField, indexOffset, containerCountField, len(cw.index))
+	sb.WriteString(containerEnd + "\n")
+
+	return cw.writeString(sb.String())
+}
+
+// writeString writes s to the underlying writer, tracking the offset
+func (cw *ContainerWriter) writeString(s string) error {
+	n, err := io.WriteString(cw.w, s)
+	cw.offset += int64(n)
+	return err
+}
+
+// ContainerReader streams the signed messages of a multi-proof container
+// without loading the whole file.
+type ContainerReader struct {
+	r      *bufio.Reader
+	offset int64
+	seen   []ContainerIndexEntry
+	index  []ContainerIndexEntry
+	done   bool
+}
+
+// NewContainerReader reads the container header from r and returns a reader
+// for its entries.
+func NewContainerReader(r io.Reader) (*ContainerReader, error) {
+	cr := &ContainerReader{r: bufio.NewReader(r)}
+
+	for _, want := range []string{containerBegin, containerVersion} {
+		line, err := cr.readLine()
+		if err != nil {
+			return nil, containerError("reading header: %v", err)
+		}
+		if line != want {
+			return nil, containerError("expected %q, got %q", want, line)
+		}
+	}
+
+	return cr, nil
+}
+
+// Next returns the next signed message of the container. It returns io.EOF
+// once all entries have been read and the index was validated.
+func (cr *ContainerReader) Next() (SignedMessage, error) {
+	if cr.done {
+		return SignedMessage{}, io.EOF
+	}
+
+	for {
+		start := cr.offset
+		line, err := cr.readLine()
+		if err != nil {
+			return SignedMessage{}, containerError("unexpected end of container: %v", err)
+		}
+
+		switch line {
+		case "":
+			continue
+		case armorBeginMessage:
+			return cr.readEntry(start)
+		case containerBeginIndex:
+			if err := cr.readIndex(start); err != nil {
+				return SignedMessage{}, err
+			}
+			cr.done = true
+			return SignedMessage{}, io.EOF
+		default:
+			return SignedMessage{}, containerError("unexpected line %q at offset %d", line, start)
+		}
+	}
+}
+
+// ReadAll reads all remaining signed messages of the container
+func (cr *ContainerReader) ReadAll() ([]SignedMessage, error) {
+	var msgs []SignedMessage
+	for {
+		msg, err := cr.Next()
+		if err == io.EOF {
+			return msgs, nil
+		}
+		if err != nil {
+			return msgs, err
+		}
+		msgs = append(msgs, msg)
+	}
+}
+
+// Index returns the index of the container. It is only available after Next
+// returned io.EOF.
+func (cr *ContainerReader) Index() []ContainerIndexEntry {
+	return cr.index
+}
+
+// readEntry reads an armored block whose begin line started at offset start
+func (cr *ContainerReader) readEntry(start int64) (SignedMessage, error) {
+	var sb strings.Builder
+	sb.WriteString(armorBeginMessage + "\n")
+
+	for {
+		line, err := cr.r.ReadString('\n')
+		cr.offset += int64(len(line))
+		if err != nil {
+			return SignedMessage{}, containerError("unterminated entry at offset %d: %v", start, err)
+		}
+		sb.WriteString(line)
+		if strings.TrimRight(line, "\r\n") == armorEndSignature {
+			break
+		}
+	}
+
+	msg, err := ParseArmored(sb.String())
+	if err != nil {
+		return SignedMessage{}, fmt.Errorf("entry at offset %d: %w", start, err)
+	}
+
+	cr.seen = append(cr.seen, ContainerIndexEntry{Offset: start, Length: cr.offset - start, Address: msg.Address})
+	return msg, nil
+}
+
+// readIndex reads the index and trailer, checking them against the entries
+// that were read
+func (cr *ContainerReader) readIndex(indexOffset int64) error {
+	for {
+		line, err := cr.readLine()
+		if err != nil {
+			return containerError("reading index: %v", err)
+		}
+		if line == containerEndIndex {
+			break
+		}
+
+		entry, err := parseContainerIndexLine(line)
+		if err != nil {
+			return err
+		}
+		cr.index = append(cr.index, entry)
+	}
+
+	var trailer []string
+	for len(trailer) < 3 {
+		line, err := cr.readLine()
+		if err != nil {
+			return containerError("reading trailer: %v", err)
+		}
+		trailer = append(trailer, line)
+	}
+
+	gotOffset, count, err := parseContainerTrailer(trailer)
+	if err != nil {
+		return err
+	}
+
+	if gotOffset != indexOffset {
+		return containerError("index offset is %d, found index at %d", gotOffset, indexOffset)
+	}
+	if count != len(cr.index) || count != len(cr.seen) {
+		return containerError("count is %d, index has %d entries, read %d entries", count, len(cr.index), len(cr.seen))
+	}
+	for i, entry := range cr.index {
+		if entry != cr.seen[i] {
+			return containerError("index entry %d is %+v, read %+v", i, entry, cr.seen[i])
+		}
+	}
+
+	return nil
+}
+
+// readLine reads a line without its line ending, tracking the offset
+func (cr *ContainerReader) readLine() (string, error) {
+	line, err := cr.r.ReadString('\n')
+	cr.offset += int64(len(line))
+	if err != nil && (err != io.EOF || line == "") {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// ReadContainerIndex reads the index of a container of the given size without
+// reading its entries, for random access with ReadContainerEntry.
+func ReadContainerIndex(r io.ReaderAt, size int64) ([]ContainerIndexEntry, error) {
+	tailSize := int64(containerTailSize)
+	if size < tailSize {
+		tailSize = size
+	}
+
+	tail := make([]byte, tailSize)
+	if _, err := r.ReadAt(tail, size-tailSize); err != nil && err != io.EOF {
+		return nil, containerError("reading trailer: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimRight(string(tail), "\r\n"), "\n")
+	if len(lines) < 3 {
+		return nil, containerError("missing trailer")
+	}
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+
+	indexOffset, count, err := parseContainerTrailer(lines[len(lines)-3:])
+	if err != nil {
+		return nil, err
+	}
+	if indexOffset < 0 || indexOffset >= size {
+		return nil, containerError("index offset %d out of range", indexOffset)
+	}
+
+	section := io.NewSectionReader(r, indexOffset, size-indexOffset)
+	br := bufio.NewReader(section)
+
+	line, err := br.ReadString('\n')
+	if err != nil || strings.TrimRight(line, "\r\n") != containerBeginIndex {
+		return nil, containerError("expected %q at offset %d", containerBeginIndex, indexOffset)
+	}
+
+	if maxCount := (size - indexOffset) / int64(containerMinIndexLine); int64(count) > maxCount {
+		return nil, containerError("count %d exceeds the %d entries the index can hold", count, maxCount)
+	}
+
+	index := make([]ContainerIndexEntry, 0, count)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, containerError("reading index: %v", err)
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == containerEndIndex {
+			break
+		}
+
+		entry, err := parseContainerIndexLine(line)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkContainerEntry(entry); err != nil {
+			return nil, err
+		}
+		if entry.Offset > indexOffset-entry.Length {
+			return nil, containerError("entry at offset %d runs past the index at %d", entry.Offset, indexOffset)
+		}
+		index = append(index, entry)
+	}
+
+	if len(index) != count {
+		return nil, containerError("count is %d, index has %d entries", count, len(index))
+	}
+
+	return index, nil
+}
+
+// ReadContainerEntry reads the signed message of a single index entry.
+// Entries longer than an armored message of MaxMessageSize are rejected
+// before reading them.
+func ReadContainerEntry(r io.ReaderAt, entry ContainerIndexEntry) (SignedMessage, error) {
+	if err := checkContainerEntry(entry); err != nil {
+		return SignedMessage{}, err
+	}
+
+	block := make([]byte, entry.Length)
+	n, err := r.ReadAt(block, entry.Offset)
+	if n < len(block) {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return SignedMessage{}, containerError("reading entry at offset %d: %v", entry.Offset, err)
+	}
+
+	msg, err := ParseArmored(string(block))
+	if err != nil {
+		return SignedMessage{}, fmt.Errorf("entry at offset %d: %w", entry.Offset, err)
+	}
+	if msg.Address != entry.Address {
+		return SignedMessage{}, containerError("entry at offset %d has address %s, index says %s", entry.Offset, msg.Address, entry.Address)
+	}
+
+	return msg, nil
+}
+
+// checkContainerEntry checks that an index entry lies within the file and
+// isn't longer than an armored message of MaxMessageSize
+func checkContainerEntry(entry ContainerIndexEntry) error {
+	if entry.Offset < 0 || entry.Length <= 0 {
+		return containerError("entry at offset %d has length %d", entry.Offset, entry.Length)
+	}
+	if maxLength := MaxMessageSize() + containerArmorOverhead; entry.Length > maxLength {
+		return containerError("entry at offset %d is %d bytes, limit is %d", entry.Offset, entry.Length, maxLength)
+	}
+	return nil
+}
+
+// parseContainerIndexLine parses an "<offset> <length> <address>" index line
+func parseContainerIndexLine(line string) (ContainerIndexEntry, error) {
+	fields := strings.Fields(line)
+	if len(fields) != 3 {
+		return ContainerIndexEntry{}, containerError("malformed index line %q", line)
+	}
+
+	offset, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return ContainerIndexEntry{}, containerError("malformed index offset %q", fields[0])
+	}
+	length, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || length <= 0 {
+		return ContainerIndexEntry{}, containerError("malformed index length %q", fields[1])
+	}
+
+	return ContainerIndexEntry{Offset: offset, Length: length, Address: fields[2]}, nil
+}
+
+// parseContainerTrailer parses the index offset, count and end lines
+func parseContainerTrailer(lines []string) (int64, int, error) {
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], containerIndexField) ||
+		!strings.HasPrefix(lines[1], containerCountField) || lines[2] != containerEnd {
+		return 0, 0, containerError("malformed trailer")
+	}
+
+	indexOffset, err := strconv.ParseInt(strings.TrimPrefix(lines[0], containerIndexField), 10, 64)
+	if err != nil {
+		return 0, 0, containerError("malformed index offset: %v", err)
+	}
+	count, err := strconv.Atoi(strings.TrimPrefix(lines[1], containerCountField))
+	if err != nil || count < 0 {
+		return 0, 0, containerError("malformed count %q", lines[1])
+	}
+
+	return indexOffset, count, nil
+}
+
+// containerError creates an error for a malformed container
+func containerError(format string, args ...interface{}) error {
+	return newVerifyError(ErrMalformedContainer, format, args...)
+}
@@ -0,0 +1,248 @@
+package verify
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestArmorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{name: "Single line", message: "Hello, Bitcoin testing!"},
+		{name: "Multi line", message: "line one\nline two\n\nline four"},
+		{name: "Trailing newline", message: "message\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := SignedMessage{Address: "194vDb9xwY6XQi5bLa7FRPBewJdUqympZ9", Message: tt.message, Signature: "c2ln"}
+			got, err := ParseArmored(formatArmored(msg))
+			if err != nil {
+				t.Fatalf("ParseArmored() error = %v", err)
+			}
+			if got != msg {
+				t.Errorf("ParseArmored() = %+v, want %+v", got, msg)
+			}
+		})
+	}
+}
+
+func TestParseArmoredInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "Missing begin", text: "hello\n" + armorBeginSignature + "\naddr\nsig\n" + armorEndSignature},
+		{name: "Missing end", text: armorBeginMessage + "\nhello\n" + armorBeginSignature + "\naddr\nsig\n"},
+		{name: "Missing signature section", text: armorBeginMessage + "\nhello\n" + armorEndSignature},
+		{name: "Missing signature line", text: armorBeginMessage + "\nhello\n" + armorBeginSignature + "\naddr\n" + armorEndSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseArmored(tt.text)
+			if !errors.Is(err, ErrInvalidArmor) {
+				t.Errorf("ParseArmored() error = %v, want %v", err, ErrInvalidArmor)
+			}
+		})
+	}
+}
+
+func TestContainerRoundTrip(t *testing.T) {
+	var msgs []SignedMessage
+	for _, tv := range walletTestVectors {
+		msgs = append(msgs, tv.msg)
+	}
+	msgs = append(msgs, SignedMessage{
+		Address:   "194vDb9xwY6XQi5bLa7FRPBewJdUqympZ9",
+		Message:   "multi\nline\r\nmessage",
+		Signature: "IOeVH/0KqgmS3XKwqCJiwlcHonwxKMQN6fbOW5UsXSDZB4EGCVTXx6c+ZU/Ae5qO94MSBZn2aPOiUsupRIwBaAU=",
+	})
+
+	data := writeTestContainer(t, msgs)
+
+	// Streaming
+	cr, err := NewContainerReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewContainerReader() error = %v", err)
+	}
+	got, err := cr.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(got) != len(msgs) {
+		t.Fatalf("ReadAll() returned %d messages, want %d", len(got), len(msgs))
+	}
+	for i := range msgs {
+		if got[i] != msgs[i] {
+			t.Errorf("ReadAll()[%d] = %+v, want %+v", i, got[i], msgs[i])
+		}
+	}
+	if len(cr.Index()) != len(msgs) {
+		t.Errorf("Index() has %d entries, want %d", len(cr.Index()), len(msgs))
+	}
+
+	// Random access
+	index, err := ReadContainerIndex(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadContainerIndex() error = %v", err)
+	}
+	if len(index) != len(msgs) {
+		t.Fatalf("ReadContainerIndex() returned %d entries, want %d", len(index), len(msgs))
+	}
+	for _, i := range []int{len(msgs) - 1, 0, 3} {
+		msg, err := ReadContainerEntry(bytes.NewReader(data), index[i])
+		if err != nil {
+			t.Fatalf("ReadContainerEntry(%d) error = %v", i, err)
+		}
+		if msg != msgs[i] {
+			t.Errorf("ReadContainerEntry(%d) = %+v, want %+v", i, msg, msgs[i])
+		}
+	}
+}
+
+func TestContainerEmpty(t *testing.T) {
+	data := writeTestContainer(t, nil)
+
+	cr, err := NewContainerReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("NewContainerReader() error = %v", err)
+	}
+	if _, err := cr.Next(); err != io.EOF {
+		t.Errorf("Next() error = %v, want io.EOF", err)
+	}
+
+	index, err := ReadContainerIndex(bytes.NewReader(data), int64(len(data)))
+	if err != nil || len(index) != 0 {
+		t.Errorf("ReadContainerIndex() = %v, %v, want empty index", index, err)
+	}
+}
+
+func TestContainerWriterRejectsBoundary(t *testing.T) {
+	cw, err := NewContainerWriter(io.Discard)
+	if err != nil {
+		t.Fatalf("NewContainerWriter() error = %v", err)
+	}
+
+	msg := SignedMessage{Address: "addr", Message: "before\n" + armorEndSignature + "\nafter", Signature: "sig"}
+	if err := cw.Write(msg); !errors.Is(err, ErrInvalidArmor) {
+		t.Errorf("Write() error = %v, want %v", err, ErrInvalidArmor)
+	}
+}
+
+func TestContainerReaderMalformed(t *testing.T) {
+	msgs := []SignedMessage{walletTestVectors[0].msg, walletTestVectors[1].msg}
+	data := string(writeTestContainer(t, msgs))
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Missing header", data: strings.Replace(data, containerBegin, "", 1)},
+		{name: "Wrong count", data: strings.Replace(data, containerCountField+"2", containerCountField+"3", 1)},
+		{name: "Wrong index offset", data: strings.Replace(data, containerIndexField, containerIndexField+"1", 1)},
+		{name: "Truncated", data: data[:len(data)/2]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cr, err := NewContainerReader(strings.NewReader(tt.data))
+			if err == nil {
+				_, err = cr.ReadAll()
+			}
+			if !errors.Is(err, ErrMalformedContainer) {
+				t.Errorf("reading container error = %v, want %v", err, ErrMalformedContainer)
+			}
+		})
+	}
+}
+
+func TestReadContainerIndexMalformed(t *testing.T) {
+	msgs := []SignedMessage{walletTestVectors[0].msg, walletTestVectors[1].msg}
+	data := string(writeTestContainer(t, msgs))
+	index, err := ReadContainerIndex(strings.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadContainerIndex() error = %v", err)
+	}
+	first := fmt.Sprintf("%d %d %s", index[0].Offset, index[0].Length, index[0].Address)
+	withFirst := func(line string) string {
+		return strings.Replace(data, first, line, 1)
+	}
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "Huge count", data: strings.Replace(data, containerCountField+"2", containerCountField+"4000000000000000000", 1)},
+		{name: "Count beyond index size", data: strings.Replace(data, containerCountField+"2", containerCountField+"100", 1)},
+		{name: "Huge length", data: withFirst(fmt.Sprintf("%d 9000000000000000000 %s", index[0].Offset, index[0].Address))},
+		{name: "Negative offset", data: withFirst(fmt.Sprintf("-1 %d %s", index[0].Length, index[0].Address))},
+		{name: "Negative length", data: withFirst(fmt.Sprintf("%d -5 %s", index[0].Offset, index[0].Address))},
+		{name: "Entry past index", data: withFirst(fmt.Sprintf("%d %d %s", index[1].Offset, index[1].Length+1, index[0].Address))},
+		{name: "Overflowing offset", data: withFirst(fmt.Sprintf("9223372036854775807 %d %s", index[0].Length, index[0].Address))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadContainerIndex(strings.NewReader(tt.data), int64(len(tt.data)))
+			if !errors.Is(err, ErrMalformedContainer) {
+				t.Errorf("ReadContainerIndex() error = %v, want %v", err, ErrMalformedContainer)
+			}
+		})
+	}
+}
+
+func TestReadContainerEntryMalformed(t *testing.T) {
+	data := writeTestContainer(t, []SignedMessage{walletTestVectors[0].msg})
+	index, err := ReadContainerIndex(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("ReadContainerIndex() error = %v", err)
+	}
+	entry := index[0]
+
+	tests := []struct {
+		name  string
+		entry ContainerIndexEntry
+	}{
+		{name: "Huge length", entry: ContainerIndexEntry{Offset: entry.Offset, Length: 1 << 62, Address: entry.Address}},
+		{name: "Longer than an armored message", entry: ContainerIndexEntry{Offset: entry.Offset, Length: MaxMessageSize() + containerArmorOverhead + 1, Address: entry.Address}},
+		{name: "Negative length", entry: ContainerIndexEntry{Offset: entry.Offset, Length: -1, Address: entry.Address}},
+		{name: "Negative offset", entry: ContainerIndexEntry{Offset: -1, Length: entry.Length, Address: entry.Address}},
+		{name: "Past end of file", entry: ContainerIndexEntry{Offset: int64(len(data)) - 10, Length: entry.Length, Address: entry.Address}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadContainerEntry(bytes.NewReader(data), tt.entry)
+			if !errors.Is(err, ErrMalformedContainer) {
+				t.Errorf("ReadContainerEntry() error = %v, want %v", err, ErrMalformedContainer)
+			}
+		})
+	}
+}
+
+// Helper function to write messages into an in-memory container
+func writeTestContainer(t *testing.T, msgs []SignedMessage) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	cw, err := NewContainerWriter(&buf)
+	if err != nil {
+		t.Fatalf("NewContainerWriter() error = %v", err)
+	}
+	for _, msg := range msgs {
+		if err := cw.Write(msg); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := cw.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,410 @@
+// Package corerpc cross-checks verifications against a Bitcoin Core node,
+// calling its verifymessage RPC and comparing the answer with the local
+// verifier. It helps build confidence during a rollout and settles disputes
+// with the reference implementation:
+//
+//	c := corerpc.New("http://127.0.0.1:8332", corerpc.WithCookieFile("/var/lib/bitcoind/.cookie"))
+//	cmp, err := c.CrossCheck(ctx, msg)
+//	if err == nil && !cmp.Agree {
+//		// investigate
+//	}
+//
+// Bitcoin Core only verifies signatures of P2PKH addresses; it rejects
+// other addresses with an RPC error, counted as an invalid signature.
+//
+// With WithRetry, calls failing transiently, such as while bitcoind restarts,
+// are retried with exponential backoff. Calls that still fail return a
+// *CallError telling transient failures from permanent ones.
+package corerpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/sero/btc/verify"
+)
+
+// ErrUnauthorized is returned when bitcoind rejects the RPC credentials
+var ErrUnauthorized = errors.New("bitcoind rejected the RPC credentials")
+
+// RPCError is an error returned by bitcoind for a call, such as an invalid
+// address or malformed base64 signature
+type RPCError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error returns the message and code of the error
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("bitcoind: %s (code %d)", e.Message, e.Code)
+}
+
+// rpcInWarmup is the code of the RPC error bitcoind answers with while it's
+// starting, before it serves calls
+const rpcInWarmup = -28
+
+// CallError is returned when bitcoind couldn't answer a call, after retrying
+// if the failure was transient
+type CallError struct {
+	// Method is the RPC method called
+	Method string
+
+	// Attempts is the number of calls made
+	Attempts int
+
+	// Transient reports whether the failure was transient, such as an
+	// unreachable node or one still starting, so a later call may succeed.
+	// Permanent failures, such as rejected credentials, need fixing first.
+	Transient bool
+
+	// Err is the error of the last call
+	Err error
+}
+
+// Error describes the failure and the number of attempts
+func (e *CallError) Error() string {
+	kind := "permanent"
+	if e.Transient {
+		kind = "transient"
+	}
+	return fmt.Sprintf("bitcoind %s: %s failure after %d attempts: %v", e.Method, kind, e.Attempts, e.Err)
+}
+
+// Unwrap returns the error of the last call
+func (e *CallError) Unwrap() error {
+	return e.Err
+}
+
+// IsTransient reports whether err is a *CallError of a transient failure
+func IsTransient(err error) bool {
+	var callErr *CallError
+	return errors.As(err, &callErr) && callErr.Transient
+}
+
+// statusError is an HTTP response without a JSON-RPC body, typically from a
+// proxy in front of bitcoind
+type statusError struct {
+	code   int
+	status string
+}
+
+// Error returns the HTTP status of the response
+func (e *statusError) Error() string {
+	return fmt.Sprintf("bitcoind: unexpected response with status %s", e.status)
+}
+
+// RetryPolicy configures how calls failing transiently are retried. The
+// delay before the n-th retry is InitialBackoff doubled n-1 times, capped at
+// MaxBackoff, with a random fraction of up to Jitter taken off so clients
+// failing together don't retry in lockstep.
+type RetryPolicy struct {
+	// MaxAttempts is the number of calls made before giving up, including
+	// the first one
+	MaxAttempts int
+
+	// InitialBackoff is the delay before the first retry
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration
+
+	// Jitter is the fraction of each delay that is randomized, from 0 to 1
+	Jitter float64
+}
+
+// DefaultRetryPolicy makes up to four attempts, waiting at most 1.4 seconds
+// in total between them
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    4,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Jitter:         0.5,
+}
+
+// backoff returns the delay before the retry-th retry
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < retry && d < p.MaxBackoff; i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	jitter := min(max(p.Jitter, 0), 1)
+	return d - time.Duration(rand.Float64()*jitter*float64(d))
+}
+
+// Client calls the verifymessage RPC of a bitcoind node. A Client is safe
+// for concurrent use.
+type Client struct {
+	url        string
+	httpClient *http.Client
+	user       string
+	password   string
+	cookieFile string
+	verifier   *verify.Verifier
+	retry      RetryPolicy
+	nextID     atomic.Uint64
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithBasicAuth authenticates with the rpcuser and rpcpassword of bitcoind
+func WithBasicAuth(user, password string) Option {
+	return func(c *Client) {
+		c.user, c.password = user, password
+	}
+}
+
+// WithCookieFile authenticates with the cookie file of bitcoind. The file
+// is read on every call, as bitcoind rewrites it when restarting.
+func WithCookieFile(path string) Option {
+	return func(c *Client) {
+		c.cookieFile = path
+	}
+}
+
+// WithHTTPClient sets the HTTP client calls are made with, by default
+// http.DefaultClient
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithVerifier sets the local verifier CrossCheck compares bitcoind with,
+// by default a verifier for mainnet created with verify.NewVerifier. It
+// should be configured for the network of the node.
+func WithVerifier(v *verify.Verifier) Option {
+	return func(c *Client) {
+		c.verifier = v
+	}
+}
+
+// WithRetry retries calls failing transiently according to the policy,
+// such as DefaultRetryPolicy. Calls are made once by default.
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// New creates a Client calling the bitcoind RPC server at url
+func New(url string, opts ...Option) *Client {
+	c := &Client{url: url, httpClient: http.DefaultClient, retry: RetryPolicy{MaxAttempts: 1}}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.verifier == nil {
+		c.verifier = verify.NewVerifier()
+	}
+	return c
+}
+
+// VerifyMessage calls verifymessage, returning whether bitcoind considers
+// the signature valid. Requests bitcoind rejects, such as those with a
+// non-P2PKH address, fail with an *RPCError; calls bitcoind couldn't answer
+// fail with a *CallError.
+func (c *Client) VerifyMessage(ctx context.Context, address, signature, message string) (bool, error) {
+	valid, _, err := c.verifyMessage(ctx, address, signature, message)
+	return valid, err
+}
+
+// verifyMessage calls verifymessage, also returning the number of attempts
+func (c *Client) verifyMessage(ctx context.Context, address, signature, message string) (bool, int, error) {
+	var valid bool
+	attempts, err := c.callWithRetry(ctx, "verifymessage", []any{address, signature, message}, &valid)
+	if err != nil {
+		return false, attempts, err
+	}
+	return valid, attempts, nil
+}
+
+// Comparison is the outcome of a cross-check
+type Comparison struct {
+	// Local reports whether the local verifier considers the signature valid
+	Local bool
+
+	// LocalErr explains why the local verifier rejected the signature
+	LocalErr error
+
+	// Core reports whether bitcoind considers the signature valid
+	Core bool
+
+	// CoreErr is the *RPCError bitcoind rejected the request with, if any
+	CoreErr error
+
+	// Agree reports whether both consider the signature valid, or both
+	// consider it invalid
+	Agree bool
+
+	// Attempts is the number of calls made to bitcoind
+	Attempts int
+
+	// Transient reports, along with an error of CrossCheck, that bitcoind
+	// couldn't answer because of a transient failure, so the cross-check
+	// may be retried later
+	Transient bool
+}
+
+// CrossCheck verifies a signed message both locally and with bitcoind. An
+// error is only returned when bitcoind couldn't answer, e.g. because it is
+// unreachable or the credentials are wrong; it's a *CallError, and the
+// comparison then holds the local verdict and whether the failure was
+// transient.
+func (c *Client) CrossCheck(ctx context.Context, msg verify.SignedMessage) (Comparison, error) {
+	var cmp Comparison
+	result, err := c.verifier.VerifyContext(ctx, msg)
+	cmp.Local, cmp.LocalErr = result.Valid, err
+
+	cmp.Core, cmp.Attempts, err = c.verifyMessage(ctx, msg.Address, msg.Signature, msg.Message)
+	var rpcErr *RPCError
+	var callErr *CallError
+	switch {
+	case errors.As(err, &callErr):
+		cmp.Transient = callErr.Transient
+		return cmp, err
+	case errors.As(err, &rpcErr):
+		cmp.CoreErr = err
+	}
+
+	cmp.Agree = cmp.Local == cmp.Core
+	return cmp, nil
+}
+
+// rpcRequest is a JSON-RPC 1.0 request as bitcoind expects it
+type rpcRequest struct {
+	JSONRPC string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	Method  string `json:"method"`
+	Params  []any  `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC response of bitcoind
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *RPCError       `json:"error"`
+}
+
+// callWithRetry calls a bitcoind RPC method, retrying transient failures
+// according to the retry policy, and returns the number of attempts. RPC
+// errors bitcoind answers with are returned as they are, other failures as a
+// *CallError.
+func (c *Client) callWithRetry(ctx context.Context, method string, params []any, result any) (int, error) {
+	attempts := max(c.retry.MaxAttempts, 1)
+	for attempt := 1; ; attempt++ {
+		err := c.call(ctx, method, params, result)
+		var rpcErr *RPCError
+		switch {
+		case err == nil:
+			return attempt, nil
+		case ctx.Err() != nil:
+			return attempt, &CallError{Method: method, Attempts: attempt, Err: err}
+		case errors.As(err, &rpcErr) && rpcErr.Code != rpcInWarmup:
+			return attempt, err
+		case !isTransient(err) || attempt == attempts:
+			return attempt, &CallError{Method: method, Attempts: attempt, Transient: isTransient(err), Err: err}
+		}
+
+		timer := time.NewTimer(c.retry.backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, &CallError{Method: method, Attempts: attempt, Err: ctx.Err()}
+		case <-timer.C:
+		}
+	}
+}
+
+// isTransient reports whether a call failed transiently: the node couldn't
+// be reached, the connection broke, a proxy answered with a server error or
+// bitcoind is still starting
+func isTransient(err error) bool {
+	var netErr net.Error
+	var rpcErr *RPCError
+	var statusErr *statusError
+	switch {
+	case errors.As(err, &rpcErr):
+		return rpcErr.Code == rpcInWarmup
+	case errors.As(err, &statusErr):
+		return statusErr.code == http.StatusTooManyRequests || statusErr.code >= 500
+	case errors.As(err, &netErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	}
+	return false
+}
+
+// call calls a bitcoind RPC method, decoding its result into result
+func (c *Client) call(ctx context.Context, method string, params []any, result any) error {
+	body, err := json.Marshal(rpcRequest{JSONRPC: "1.0", ID: c.nextID.Add(1), Method: method, Params: params})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := c.authenticate(req); err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return ErrUnauthorized
+	}
+	// bitcoind answers RPC errors with a non-200 status and a JSON body
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	var rpcResp rpcResponse
+	if err := json.Unmarshal(data, &rpcResp); err != nil {
+		return &statusError{code: resp.StatusCode, status: resp.Status}
+	}
+	if rpcResp.Error != nil {
+		return rpcResp.Error
+	}
+	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+		return fmt.Errorf("bitcoind: unexpected %s result: %w", method, err)
+	}
+	return nil
+}
+
+// authenticate sets the credentials of the request
+func (c *Client) authenticate(req *http.Request) error {
+	if c.cookieFile == "" {
+		if c.user != "" || c.password != "" {
+			req.SetBasicAuth(c.user, c.password)
+		}
+		return nil
+	}
+
+	cookie, err := os.ReadFile(c.cookieFile)
+	if err != nil {
+		return fmt.Errorf("reading cookie file: %w", err)
+	}
+	user, password, ok := strings.Cut(strings.TrimSpace(string(cookie)), ":")
+	if !ok {
+		return fmt.Errorf("cookie file %s is not user:password", c.cookieFile)
+	}
+	req.SetBasicAuth(user, password)
+	return nil
+}
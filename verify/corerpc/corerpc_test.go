@@ -0,0 +1,224 @@
+package corerpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sero/btc/verify"
+)
+
+var testMessage = verify.SignedMessage{
+	Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+	Message:   "test message",
+	Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+}
+
+func TestCrossCheck(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+	url := newFakeBitcoind(t, "user", "secret")
+	c := New(url, WithBasicAuth("user", "secret"))
+
+	tampered := testMessage
+	tampered.Message = "tampered message"
+	segwit := testMessage
+	segwit.Address = "bc1q3xg8dmtvmv8uy5c6ymghsg5aqkqeke0g5pgh2v"
+
+	tests := []struct {
+		name        string
+		msg         verify.SignedMessage
+		wantLocal   bool
+		wantCore    bool
+		wantCoreErr bool
+		wantAgree   bool
+	}{
+		{name: "Both valid", msg: testMessage, wantLocal: true, wantCore: true, wantAgree: true},
+		{name: "Both invalid", msg: tampered, wantAgree: true},
+		{name: "Address rejected by Core", msg: segwit, wantCoreErr: true, wantAgree: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmp, err := c.CrossCheck(context.Background(), tt.msg)
+			if err != nil {
+				t.Fatalf("CrossCheck() error = %v", err)
+			}
+			if cmp.Local != tt.wantLocal || cmp.Core != tt.wantCore || (cmp.CoreErr != nil) != tt.wantCoreErr || cmp.Agree != tt.wantAgree {
+				t.Errorf("CrossCheck() = %+v, want local %v, core %v, core error %v, agree %v",
+					cmp, tt.wantLocal, tt.wantCore, tt.wantCoreErr, tt.wantAgree)
+			}
+		})
+	}
+}
+
+func TestClientAuthentication(t *testing.T) {
+	url := newFakeBitcoind(t, "__cookie__", "c00k1e")
+
+	dir := t.TempDir()
+	cookie := filepath.Join(dir, ".cookie")
+	if err := os.WriteFile(cookie, []byte("__cookie__:c00k1e"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "Cookie file", opts: []Option{WithCookieFile(cookie)}},
+		{name: "Wrong password", opts: []Option{WithBasicAuth("__cookie__", "stale")}, wantErr: ErrUnauthorized},
+		{name: "Missing cookie file", opts: []Option{WithCookieFile(filepath.Join(dir, "missing"))}, wantErr: os.ErrNotExist},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := New(url, tt.opts...).VerifyMessage(context.Background(), testMessage.Address, testMessage.Signature, testMessage.Message)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != (tt.wantErr == nil) {
+				t.Errorf("VerifyMessage() = %v, want %v", valid, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestVerifyMessageRPCError(t *testing.T) {
+	url := newFakeBitcoind(t, "user", "secret")
+	c := New(url, WithBasicAuth("user", "secret"))
+
+	_, err := c.VerifyMessage(context.Background(), "bc1q3xg8dmtvmv8uy5c6ymghsg5aqkqeke0g5pgh2v", testMessage.Signature, testMessage.Message)
+	var rpcErr *RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -3 {
+		t.Errorf("VerifyMessage() error = %v, want an RPC error with code -3", err)
+	}
+}
+
+func TestCrossCheckRetry(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+	policy := RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond, Jitter: 0.5}
+
+	unavailable := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}
+	warmup := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]any{"result": nil, "error": map[string]any{"code": -28, "message": "Loading block index..."}})
+	}
+	unauthorized := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}
+
+	tests := []struct {
+		name          string
+		failures      int
+		fail          http.HandlerFunc
+		opts          []Option
+		wantAttempts  int
+		wantErr       bool
+		wantTransient bool
+	}{
+		{name: "Recovered from an unavailable node", failures: 2, fail: unavailable, opts: []Option{WithRetry(policy)}, wantAttempts: 3},
+		{name: "Recovered from warmup", failures: 1, fail: warmup, opts: []Option{WithRetry(policy)}, wantAttempts: 2},
+		{name: "Transient failure", failures: 3, fail: unavailable, opts: []Option{WithRetry(policy)}, wantAttempts: 3, wantErr: true, wantTransient: true},
+		{name: "Permanent failure", failures: 1, fail: unauthorized, opts: []Option{WithRetry(policy)}, wantAttempts: 1, wantErr: true},
+		{name: "No retry by default", failures: 1, fail: unavailable, wantAttempts: 1, wantErr: true, wantTransient: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			url, calls := newFlakyBitcoind(t, tt.failures, tt.fail)
+			c := New(url, append(tt.opts, WithBasicAuth("user", "secret"))...)
+
+			cmp, err := c.CrossCheck(context.Background(), testMessage)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CrossCheck() error = %v, want error %v", err, tt.wantErr)
+			}
+			if cmp.Attempts != tt.wantAttempts || int(calls.Load()) != tt.wantAttempts {
+				t.Errorf("CrossCheck() attempts = %d, calls = %d, want %d", cmp.Attempts, calls.Load(), tt.wantAttempts)
+			}
+			if cmp.Transient != tt.wantTransient || IsTransient(err) != tt.wantTransient {
+				t.Errorf("CrossCheck() transient = %v, IsTransient() = %v, want %v", cmp.Transient, IsTransient(err), tt.wantTransient)
+			}
+			if !cmp.Local || (err == nil && !cmp.Agree) {
+				t.Errorf("CrossCheck() = %+v, want a valid local verdict", cmp)
+			}
+		})
+	}
+}
+
+func TestCrossCheckUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	c := New(srv.URL, WithRetry(RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}))
+	_, err := c.CrossCheck(context.Background(), testMessage)
+	var callErr *CallError
+	if !errors.As(err, &callErr) || !callErr.Transient || callErr.Attempts != 2 {
+		t.Errorf("CrossCheck() error = %v, want a transient failure after 2 attempts", err)
+	}
+}
+
+// Helper function to start a fake bitcoind answering verifymessage like
+// Bitcoin Core does, returning its URL
+func newFakeBitcoind(t *testing.T, user, password string) string {
+	t.Helper()
+
+	srv := httptest.NewServer(fakeBitcoind(user, password))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+// Helper function to start a fake bitcoind failing the first calls with the
+// fail handler, returning its URL and the number of calls made
+func newFlakyBitcoind(t *testing.T, failures int, fail http.HandlerFunc) (string, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+	next := fakeBitcoind("user", "secret")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if int(calls.Add(1)) <= failures {
+			fail(w, r)
+			return
+		}
+		next.ServeHTTP(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL, &calls
+}
+
+// Helper function returning the handler of a fake bitcoind
+func fakeBitcoind(user, password string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if u, p, ok := r.BasicAuth(); !ok || u != user || p != password {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var req struct {
+			ID     uint64   `json:"id"`
+			Method string   `json:"method"`
+			Params []string `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Method != "verifymessage" || len(req.Params) != 3 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if !strings.HasPrefix(req.Params[0], "1") {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]any{"result": nil, "error": map[string]any{"code": -3, "message": "Address does not refer to key"}, "id": req.ID})
+			return
+		}
+		valid, _ := verify.VerifyBip137Signature(req.Params[0], req.Params[2], req.Params[1])
+		json.NewEncoder(w).Encode(map[string]any{"result": valid, "error": nil, "id": req.ID})
+	})
+}
@@ -0,0 +1,149 @@
+package verify
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"slices"
+)
+
+// Verification engines recorded in a DebugTrace
+const (
+	EngineNative    = "native"
+	EngineBitonicNL = "bitonicnl"
+)
+
+// DebugTrace records the intermediate values of a verification, so audit
+// tools can show how a verdict was reached and bug reports can include
+// exactly what the verifier saw. It is returned in Result.Trace when the
+// Verifier is created with WithDebugTrace, and marshals to JSON.
+type DebugTrace struct {
+	// Address and Network are the address and network verified against
+	Address string `json:"address"`
+	Network string `json:"network"`
+
+	// Signature is the hex-encoded decoded signature, empty if it didn't
+	// decode
+	Signature string `json:"signature,omitempty"`
+
+	// Engine is the engine that verified the signature, EngineNative for
+	// compact signatures and EngineBitonicNL otherwise
+	Engine string `json:"engine,omitempty"`
+
+	// Attempts holds one entry per message variant tried, in order
+	Attempts []TraceAttempt `json:"attempts"`
+
+	// Valid, Error and Code are the outcome of the verification
+	Valid bool      `json:"valid"`
+	Error string    `json:"error,omitempty"`
+	Code  ErrorCode `json:"code,omitempty"`
+}
+
+// TraceAttempt records the verification of one message variant. Fields of
+// stages that weren't reached are empty; the BitonicNL verifier only reports
+// the digest and the outcome.
+type TraceAttempt struct {
+	// LineEndings is the line ending convention of the message variant
+	LineEndings string `json:"line_endings"`
+
+	// Digest is the hex-encoded double SHA-256 of the message with the
+	// Bitcoin signed message prefix
+	Digest string `json:"digest"`
+
+	// HeaderByte and its decomposition into recovery ID, compression flag
+	// and the address type it stands for
+	HeaderByte        *int        `json:"header_byte,omitempty"`
+	RecoveryID        *int        `json:"recovery_id,omitempty"`
+	Compressed        bool        `json:"compressed"`
+	HeaderAddressType AddressType `json:"header_address_type,omitempty"`
+
+	// RecoveredPubKey is the hex-encoded public key recovered from the
+	// signature
+	RecoveredPubKey string `json:"recovered_pubkey,omitempty"`
+
+	// DerivedAddress is the address derived from the recovered public key
+	DerivedAddress string `json:"derived_address,omitempty"`
+
+	// Stage is the stage the attempt failed at, empty if it succeeded
+	Stage FailureStage `json:"stage,omitempty"`
+
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+}
+
+// WithDebugTrace makes the verifier record a DebugTrace of every
+// verification in Result.Trace. Recording costs allocations and the trace
+// contains the signature, so it is meant for debugging and audit tools.
+func WithDebugTrace() Option {
+	return func(v *Verifier) {
+		v.debugTrace = true
+	}
+}
+
+// JSON returns the trace as indented JSON
+func (t *DebugTrace) JSON() ([]byte, error) {
+	return json.MarshalIndent(t, "", "  ")
+}
+
+// clone returns a copy of the trace that shares no memory with it
+func (t *DebugTrace) clone() *DebugTrace {
+	trace := *t
+	trace.Attempts = slices.Clone(t.Attempts)
+	for i, attempt := range trace.Attempts {
+		if attempt.HeaderByte != nil {
+			header := *attempt.HeaderByte
+			trace.Attempts[i].HeaderByte = &header
+		}
+		if attempt.RecoveryID != nil {
+			recoveryID := *attempt.RecoveryID
+			trace.Attempts[i].RecoveryID = &recoveryID
+		}
+	}
+	return &trace
+}
+
+// addNative records an attempt of the native engine from its report
+func (t *DebugTrace) addNative(variant messageVariant, digest []byte, sigBytes []byte, report FailureReport) {
+	attempt := TraceAttempt{
+		LineEndings:     variant.lineEndings.String(),
+		Digest:          hex.EncodeToString(digest),
+		Compressed:      report.Compressed,
+		RecoveredPubKey: report.RecoveredPubKey,
+		DerivedAddress:  report.RecoveredAddress,
+		Stage:           report.Stage,
+		Valid:           report.Valid,
+	}
+	if len(sigBytes) == compactSignatureLength {
+		info := inspectHeader(sigBytes[0])
+		header := int(info.HeaderByte)
+		attempt.HeaderByte = &header
+		attempt.RecoveryID = &info.RecoveryID
+		attempt.HeaderAddressType = info.AddressType
+	}
+	if report.Err != nil {
+		attempt.Error = report.Err.Error()
+	}
+	t.Attempts = append(t.Attempts, attempt)
+}
+
+// addBitonicNL records an attempt of the BitonicNL verifier
+func (t *DebugTrace) addBitonicNL(variant messageVariant, valid bool, err error) {
+	digest := magicHash(variant.message)
+	attempt := TraceAttempt{
+		LineEndings: variant.lineEndings.String(),
+		Digest:      hex.EncodeToString(digest[:]),
+		Valid:       valid && err == nil,
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	t.Attempts = append(t.Attempts, attempt)
+}
+
+// finish records the outcome of the verification
+func (t *DebugTrace) finish(result *Result, err error) {
+	t.Valid = result.Valid
+	if err != nil {
+		t.Error = err.Error()
+		t.Code = ErrorCodeOf(err)
+	}
+}
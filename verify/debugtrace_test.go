@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestVerifierDebugTrace(t *testing.T) {
+	tv := walletTestVectors[2].msg
+	mismatch := tv
+	mismatch.Address = walletTestVectors[0].msg.Address
+
+	lf := signTestMessage(t, testKeySeed, nil, "line one\nline two\n")
+	crlf := lf
+	crlf.Message = "line one\r\nline two\r\n"
+
+	tests := []struct {
+		name         string
+		msg          SignedMessage
+		opts         []Option
+		wantValid    bool
+		wantCode     ErrorCode
+		wantAttempts int
+		wantDerived  string
+	}{
+		{
+			name:         "Valid signature",
+			msg:          tv,
+			wantValid:    true,
+			wantAttempts: 1,
+			wantDerived:  tv.Address,
+		},
+		{
+			name:         "Address mismatch",
+			msg:          mismatch,
+			wantCode:     CodeAddressMismatch,
+			wantAttempts: 1,
+			wantDerived:  tv.Address,
+		},
+		{
+			name:         "Line endings tried",
+			msg:          crlf,
+			opts:         []Option{WithLineEndings(LineEndingsAny)},
+			wantValid:    true,
+			wantAttempts: 3, // as is, trimmed and with LF line endings
+			wantDerived:  lf.Address,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, _ := NewVerifier(append(tt.opts, WithDebugTrace())...).Verify(tt.msg)
+			trace := result.Trace
+			if trace == nil {
+				t.Fatal("Verify() returned no trace")
+			}
+
+			if trace.Valid != tt.wantValid || trace.Code != tt.wantCode {
+				t.Errorf("trace valid = %v, code = %q, want %v, %q", trace.Valid, trace.Code, tt.wantValid, tt.wantCode)
+			}
+			if trace.Engine != EngineNative {
+				t.Errorf("trace engine = %q, want %q", trace.Engine, EngineNative)
+			}
+			if len(trace.Attempts) != tt.wantAttempts {
+				t.Fatalf("trace has %d attempts, want %d", len(trace.Attempts), tt.wantAttempts)
+			}
+
+			last := trace.Attempts[len(trace.Attempts)-1]
+			if last.DerivedAddress != tt.wantDerived {
+				t.Errorf("derived address = %q, want %q", last.DerivedAddress, tt.wantDerived)
+			}
+			if last.HeaderByte == nil || last.RecoveryID == nil || last.Digest == "" || last.RecoveredPubKey == "" {
+				t.Errorf("attempt is missing intermediate values: %+v", last)
+			}
+
+			data, err := trace.JSON()
+			if err != nil {
+				t.Fatalf("JSON() error = %v", err)
+			}
+			var decoded DebugTrace
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("trace JSON doesn't decode: %v", err)
+			}
+			if decoded.Address != tt.msg.Address || len(decoded.Attempts) != tt.wantAttempts {
+				t.Errorf("decoded trace = %+v, want address %q and %d attempts", decoded, tt.msg.Address, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestVerifierWithoutDebugTrace(t *testing.T) {
+	result, _ := NewVerifier().Verify(walletTestVectors[2].msg)
+	if result.Trace != nil {
+		t.Errorf("Verify() returned a trace without WithDebugTrace")
+	}
+}
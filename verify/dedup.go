@@ -0,0 +1,95 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+)
+
+// WithDeduplication collapses identical concurrent verifications: while a
+// message is being verified, callers verifying the same address, message and
+// signature wait for its result instead of verifying it again, so a popular
+// proof costs one verification however many goroutines check it. It sits in
+// front of every other step, rate limiting included.
+//
+// The hooks, metrics and spans of the verifier record the one verification
+// that ran; the callers that joined it get copies of its result, Violations
+// and Trace included. Callers whose context is done stop waiting with
+// ErrVerificationTimeout, and when the verification they joined stopped
+// because its own caller's context was done, the others verify the message
+// again.
+func WithDeduplication() Option {
+	return func(v *Verifier) {
+		v.flights = &flightGroup{flights: make(map[SignedMessage]*flight)}
+	}
+}
+
+// flightGroup tracks the verifications in flight, keyed by signed message
+type flightGroup struct {
+	mu      sync.Mutex
+	flights map[SignedMessage]*flight
+}
+
+// flight is a verification in flight
+type flight struct {
+	done   chan struct{}
+	result *Result
+	err    error
+}
+
+// do verifies msg with verify, unless an identical verification is in
+// flight, in which case it waits for that one's result
+func (g *flightGroup) do(ctx context.Context, events eventLogger, msg SignedMessage, verify func() (*Result, error)) (*Result, error) {
+	for {
+		g.mu.Lock()
+		f, ok := g.flights[msg]
+		if !ok {
+			f = &flight{done: make(chan struct{})}
+			g.flights[msg] = f
+			g.mu.Unlock()
+			return g.run(f, msg, verify)
+		}
+		g.mu.Unlock()
+
+		events.log(LogLevelDebug, "Joining an identical verification in flight", "address", msg.Address)
+		select {
+		case <-f.done:
+		case <-ctx.Done():
+			return &Result{}, checkContext(ctx)
+		}
+
+		// The verification stopped for its own caller; verify again
+		if errors.Is(f.err, ErrVerificationTimeout) && ctx.Err() == nil {
+			continue
+		}
+		return copyResult(f.result), f.err
+	}
+}
+
+// run runs the verification of a flight and hands its result to the callers
+// waiting for it. Every caller gets its own copy of the result, so none of
+// them sees another modify it.
+func (g *flightGroup) run(f *flight, msg SignedMessage, verify func() (*Result, error)) (*Result, error) {
+	// Should verify panic, the callers waiting fail instead of hanging
+	f.result, f.err = &Result{}, ErrInvalidSignature
+	defer func() {
+		g.mu.Lock()
+		delete(g.flights, msg)
+		g.mu.Unlock()
+		close(f.done)
+	}()
+
+	f.result, f.err = verify()
+	return copyResult(f.result), f.err
+}
+
+// copyResult returns a copy of a result that shares no memory with it
+func copyResult(r *Result) *Result {
+	result := *r
+	result.Violations = slices.Clone(r.Violations)
+	if r.Trace != nil {
+		result.Trace = r.Trace.clone()
+	}
+	return &result
+}
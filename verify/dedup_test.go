@@ -0,0 +1,154 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVerifierDeduplication(t *testing.T) {
+	const callers = 8
+	msg := walletTestVectors[2].msg
+
+	var started atomic.Int32
+	release := make(chan struct{})
+	joins := make(joinLogger, callers)
+	v := NewVerifier(WithDeduplication(), WithLogger(joins), WithLogLevel(LogLevelDebug), WithHooks(Hooks{
+		OnStart: func(ctx context.Context, msg SignedMessage) {
+			started.Add(1)
+			<-release
+		},
+	}))
+
+	var wg sync.WaitGroup
+	results := make([]*Result, callers)
+	errs := make([]error, callers)
+	for i := range callers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = v.Verify(msg)
+		}()
+	}
+	waitForJoiners(t, joins, callers-1)
+	close(release)
+	wg.Wait()
+
+	if n := started.Load(); n != 1 {
+		t.Errorf("verified %d times, want once", n)
+	}
+	for i := range callers {
+		if errs[i] != nil || !results[i].Valid {
+			t.Errorf("caller %d got %+v, %v, want valid", i, results[i], errs[i])
+		}
+		if i > 0 && results[i] == results[0] {
+			t.Errorf("callers 0 and %d share a result", i)
+		}
+	}
+
+	// Once the verification completed, the message is verified again
+	if _, err := v.Verify(msg); err != nil || started.Load() != 2 {
+		t.Errorf("Verify() after the flight = %v, verified %d times, want twice", err, started.Load())
+	}
+}
+
+func TestVerifierDeduplicationCancelled(t *testing.T) {
+	msg := walletTestVectors[2].msg
+
+	var started atomic.Int32
+	release := make(chan struct{})
+	joins := make(joinLogger, 1)
+	v := NewVerifier(WithDeduplication(), WithLogger(joins), WithLogLevel(LogLevelDebug), WithHooks(Hooks{
+		OnStart: func(ctx context.Context, msg SignedMessage) {
+			if started.Add(1) == 1 {
+				<-release
+			}
+		},
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error)
+	go func() {
+		_, err := v.VerifyContext(ctx, msg)
+		leader <- err
+	}()
+	for started.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	follower := make(chan error)
+	go func() {
+		result, err := v.Verify(msg)
+		if err == nil && !result.Valid {
+			err = ErrInvalidSignature
+		}
+		follower <- err
+	}()
+	waitForJoiners(t, joins, 1)
+
+	// The leader's context ends; the follower verifies on its own
+	cancel()
+	close(release)
+	if err := <-leader; !errors.Is(err, ErrVerificationTimeout) {
+		t.Errorf("cancelled VerifyContext() error = %v, want %v", err, ErrVerificationTimeout)
+	}
+	if err := <-follower; err != nil {
+		t.Errorf("Verify() joining a cancelled verification error = %v, want valid", err)
+	}
+	if n := started.Load(); n != 2 {
+		t.Errorf("verified %d times, want twice", n)
+	}
+}
+
+func TestCopyResult(t *testing.T) {
+	header := 31
+	orig := &Result{
+		Valid:      true,
+		Violations: []PolicyViolation{{Rule: PolicyRuleMessageAge, Detail: "old"}},
+		Trace:      &DebugTrace{Attempts: []TraceAttempt{{HeaderByte: &header}}},
+	}
+
+	c := copyResult(orig)
+	c.Violations[0].Detail = "changed"
+	c.Trace.Valid = true
+	*c.Trace.Attempts[0].HeaderByte = 32
+
+	if orig.Violations[0].Detail != "old" || orig.Trace.Valid || header != 31 {
+		t.Errorf("modifying the copy modified the original: %+v, %+v", orig.Violations, orig.Trace)
+	}
+	if c := copyResult(&Result{}); c.Violations != nil || c.Trace != nil {
+		t.Errorf("copyResult() of an empty result = %+v, want empty", c)
+	}
+}
+
+// joinLogger is a Logger signalling the callers joining a verification in
+// flight
+type joinLogger chan struct{}
+
+// Log implements Logger
+func (l joinLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	if msg != "Joining an identical verification in flight" {
+		return
+	}
+	select {
+	case l <- struct{}{}:
+	default:
+	}
+}
+
+// Helper function to wait until n callers joined a verification in flight
+func waitForJoiners(t *testing.T, joins joinLogger, n int) {
+	t.Helper()
+
+	timeout := time.After(5 * time.Second)
+	for i := range n {
+		select {
+		case <-joins:
+		case <-timeout:
+			t.Fatalf("%d of %d callers joined the verification in flight", i, n)
+		}
+	}
+}
@@ -0,0 +1,413 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// Character sets of BIP-380 descriptor checksums
+const (
+	descriptorInputCharset    = "0123456789()[],'/*abcdefgh@:$%{}IJKLMNOPQRSTUVWXYZ&+-.;<=>?!^_|~ijklmnopqrstuvwxyzABCDEFGH`#\"\\ "
+	descriptorChecksumCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+)
+
+// uncompressedPubKeyLength is the length of an uncompressed public key
+const uncompressedPubKeyLength = 65
+
+// descriptorGenerator holds the generator of the BIP-380 checksum code
+var descriptorGenerator = [5]uint64{0xf5dee51989, 0xa9fdca3312, 0x1bab10e32d, 0x3706b1677a, 0x644d626ffd}
+
+// Descriptor is a parsed output descriptor with a single key:
+//
+//	pkh(KEY)  wpkh(KEY)  sh(wpkh(KEY))  tr(KEY)
+//
+// KEY is a hex public key, or an extended public key followed by unhardened
+// derivation steps, optionally ending in a wildcard (/*) or a BIP-389
+// multipath step followed by one (/<0;1>/*). A key origin such as
+// [d34db33f/84h/0h/0h] and a checksum are accepted. Taproot descriptors
+// with script trees aren't supported.
+type Descriptor struct {
+	// AddressType is the type of the addresses of the descriptor
+	AddressType AddressType
+
+	params *chaincfg.Params
+
+	// key is a fixed public key, or nil when the descriptor derives its keys
+	// from ext
+	key []byte
+
+	ext      *hdkeychain.ExtendedKey
+	prefix   []string
+	branches []uint32
+	branched bool
+	wildcard bool
+}
+
+// DescriptorAddress is an address of a descriptor
+type DescriptorAddress struct {
+	// Address is the encoded address
+	Address string
+
+	// Path is the derivation path of its key below the extended key of the
+	// descriptor, such as "0/5", or empty for a fixed key
+	Path string
+}
+
+// descriptorKey is a candidate key of a descriptor
+type descriptorKey struct {
+	serialized []byte
+	path       string
+}
+
+// ParseDescriptor parses an output descriptor for the network. A checksum,
+// if present, must be valid.
+func ParseDescriptor(descriptor string, params *chaincfg.Params) (*Descriptor, error) {
+	desc, checksum, hasChecksum := strings.Cut(strings.TrimSpace(descriptor), "#")
+	if hasChecksum && descriptorChecksum(desc) != checksum {
+		return nil, newVerifyError(ErrInvalidDescriptor, "checksum %q doesn't match", checksum)
+	}
+
+	d := &Descriptor{params: params}
+	var expr string
+	switch {
+	case unwrap(desc, "sh(wpkh(", "))", &expr):
+		d.AddressType = AddressTypeP2SHP2WPKH
+	case unwrap(desc, "wpkh(", ")", &expr):
+		d.AddressType = AddressTypeP2WPKH
+	case unwrap(desc, "pkh(", ")", &expr):
+		d.AddressType = AddressTypeP2PKH
+	case unwrap(desc, "tr(", ")", &expr):
+		d.AddressType = AddressTypeP2TR
+		if strings.Contains(expr, ",") {
+			return nil, newVerifyError(ErrInvalidDescriptor, "taproot script trees aren't supported")
+		}
+	default:
+		return nil, newVerifyError(ErrInvalidDescriptor, "%q is not a pkh, wpkh, sh(wpkh) or tr descriptor", desc)
+	}
+
+	if err := d.parseKey(expr); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Addresses returns the addresses of the descriptor: the one address of a
+// fixed key, or the first gapLimit addresses of each derived chain. A gap
+// limit of 0 or less derives DefaultGapLimit addresses.
+func (d *Descriptor) Addresses(gapLimit int) ([]DescriptorAddress, error) {
+	keys, err := d.keys(gapLimit)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]DescriptorAddress, 0, len(keys))
+	for _, k := range keys {
+		addr, err := d.address(k.serialized)
+		if err != nil {
+			return nil, err
+		}
+		addrs = append(addrs, DescriptorAddress{Address: addr, Path: k.path})
+	}
+	return addrs, nil
+}
+
+// VerifyAgainstDescriptor verifies that a message was signed by one of the
+// addresses of an output descriptor, so descriptor-native wallets can prove
+// ownership directly. The public key recovered from the signature is
+// compared with the keys of the descriptor, derived up to the gap limit as
+// by Descriptor.Addresses.
+func VerifyAgainstDescriptor(descriptor, message, signatureBase64 string, gapLimit int, params *chaincfg.Params) (bool, DescriptorAddress, error) {
+	d, err := ParseDescriptor(descriptor, params)
+	if err != nil {
+		logEvent(LogLevelError, "Invalid output descriptor", "error", err)
+		return false, DescriptorAddress{}, err
+	}
+
+	rec, err := Recover(message, signatureBase64, params)
+	if err != nil {
+		return false, DescriptorAddress{}, err
+	}
+	recovered, _ := hex.DecodeString(rec.PubKey)
+	signer, err := btcec.ParsePubKey(recovered)
+	if err != nil {
+		return false, DescriptorAddress{}, newVerifyError(ErrInvalidSignature, "could not parse recovered pubkey: %v", err)
+	}
+
+	keys, err := d.keys(gapLimit)
+	if err != nil {
+		return false, DescriptorAddress{}, err
+	}
+	for _, k := range keys {
+		if !d.keyMatches(k.serialized, signer) {
+			continue
+		}
+		addr, err := d.address(k.serialized)
+		if err != nil {
+			return false, DescriptorAddress{}, err
+		}
+		// Verifying against the address also checks that the header byte
+		// and key serialization suit it
+		valid, err := VerifyBip137SignatureWithParams(addr, message, signatureBase64, params)
+		if err != nil || !valid {
+			return false, DescriptorAddress{}, err
+		}
+		logEvent(LogLevelInfo, "Signature matches output descriptor", "address", addr, "path", k.path)
+		return true, DescriptorAddress{Address: addr, Path: k.path}, nil
+	}
+
+	logEvent(LogLevelError, "Signer not derived from output descriptor", "recovered_pubkey", rec.PubKey, "keys", len(keys))
+	return false, DescriptorAddress{}, newVerifyError(ErrAddressMismatch, "signer is not among the %d keys of the descriptor", len(keys))
+}
+
+// parseKey parses the key expression of the descriptor
+func (d *Descriptor) parseKey(expr string) error {
+	// The key origin only documents where the key comes from
+	if strings.HasPrefix(expr, "[") {
+		end := strings.IndexByte(expr, ']')
+		if end < 0 {
+			return newVerifyError(ErrInvalidDescriptor, "unterminated key origin")
+		}
+		expr = expr[end+1:]
+	}
+
+	steps := strings.Split(expr, "/")
+	if raw, err := hex.DecodeString(steps[0]); err == nil {
+		if len(steps) > 1 {
+			return newVerifyError(ErrInvalidDescriptor, "hex key %q can't be derived", steps[0])
+		}
+		return d.parseHexKey(raw)
+	}
+
+	ext, _, err := parseXpub(steps[0], d.params)
+	if err != nil {
+		return newVerifyError(ErrInvalidDescriptor, "key %q: %w", steps[0], err)
+	}
+	d.ext = ext
+
+	for i, step := range steps[1:] {
+		last := i == len(steps)-2
+		switch {
+		case step == "*":
+			if !last {
+				return newVerifyError(ErrInvalidDescriptor, "wildcard must be the last derivation step")
+			}
+			d.wildcard = true
+		case strings.HasPrefix(step, "<") && strings.HasSuffix(step, ">"):
+			if d.branched {
+				return newVerifyError(ErrInvalidDescriptor, "only one multipath step is supported")
+			}
+			for _, branch := range strings.Split(step[1:len(step)-1], ";") {
+				index, err := parseDerivationStep(branch)
+				if err != nil {
+					return err
+				}
+				d.branches = append(d.branches, index)
+			}
+			if len(d.branches) < 2 {
+				return newVerifyError(ErrInvalidDescriptor, "multipath step %q needs at least two paths", step)
+			}
+			d.branched = true
+		default:
+			if d.branched {
+				return newVerifyError(ErrInvalidDescriptor, "only a wildcard may follow a multipath step")
+			}
+			index, err := parseDerivationStep(step)
+			if err != nil {
+				return err
+			}
+			if d.ext, err = d.ext.Derive(index); err != nil {
+				return newVerifyError(ErrInvalidDescriptor, "deriving %q: %v", step, err)
+			}
+			d.prefix = append(d.prefix, step)
+		}
+	}
+	return nil
+}
+
+// parseHexKey parses a fixed public key: compressed, uncompressed for pkh
+// descriptors, or x-only for tr descriptors
+func (d *Descriptor) parseHexKey(raw []byte) error {
+	var err error
+	switch {
+	case d.AddressType == AddressTypeP2TR && len(raw) == schnorr.PubKeyBytesLen:
+		_, err = schnorr.ParsePubKey(raw)
+	case len(raw) == uncompressedPubKeyLength && d.AddressType != AddressTypeP2PKH:
+		return newVerifyError(ErrInvalidDescriptor, "uncompressed keys are only allowed in pkh descriptors")
+	default:
+		_, err = btcec.ParsePubKey(raw)
+	}
+	if err != nil {
+		return newVerifyError(ErrInvalidDescriptor, "invalid public key: %v", err)
+	}
+	d.key = raw
+	return nil
+}
+
+// parseDerivationStep parses an unhardened derivation step
+func parseDerivationStep(step string) (uint32, error) {
+	index, ok := parsePathStep(step)
+	switch {
+	case !ok:
+		return 0, newVerifyError(ErrInvalidDescriptor, "invalid derivation step %q", step)
+	case index >= hdkeychain.HardenedKeyStart:
+		return 0, newVerifyError(ErrInvalidDescriptor, "hardened step %q can't be derived from a public key", step)
+	}
+	return index, nil
+}
+
+// keys returns the candidate keys of the descriptor
+func (d *Descriptor) keys(gapLimit int) ([]descriptorKey, error) {
+	if d.key != nil {
+		return []descriptorKey{{serialized: d.key}}, nil
+	}
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+
+	type chain struct {
+		key  *hdkeychain.ExtendedKey
+		path []string
+	}
+	chains := []chain{{key: d.ext, path: d.prefix}}
+	if d.branched {
+		chains = chains[:0]
+		for _, branch := range d.branches {
+			key, err := d.ext.Derive(branch)
+			if err != nil {
+				return nil, newVerifyError(ErrInvalidDescriptor, "deriving %d: %v", branch, err)
+			}
+			path := append(append([]string{}, d.prefix...), strconv.FormatUint(uint64(branch), 10))
+			chains = append(chains, chain{key: key, path: path})
+		}
+	}
+
+	var keys []descriptorKey
+	for _, c := range chains {
+		if !d.wildcard {
+			pubKey, err := c.key.ECPubKey()
+			if err != nil {
+				return nil, newVerifyError(ErrInvalidDescriptor, "%v", err)
+			}
+			keys = append(keys, descriptorKey{serialized: pubKey.SerializeCompressed(), path: strings.Join(c.path, "/")})
+			continue
+		}
+		for i := uint32(0); i < uint32(gapLimit); i++ {
+			child, err := c.key.Derive(i)
+			if err != nil {
+				// An invalid child is skipped, as BIP-32 prescribes
+				continue
+			}
+			pubKey, err := child.ECPubKey()
+			if err != nil {
+				continue
+			}
+			path := append(append([]string{}, c.path...), strconv.FormatUint(uint64(i), 10))
+			keys = append(keys, descriptorKey{serialized: pubKey.SerializeCompressed(), path: strings.Join(path, "/")})
+		}
+	}
+	return keys, nil
+}
+
+// keyMatches reports whether a candidate key is the recovered signer. Taproot
+// keys are compared by their x coordinate only.
+func (d *Descriptor) keyMatches(serialized []byte, signer *btcec.PublicKey) bool {
+	switch {
+	case d.AddressType == AddressTypeP2TR:
+		return bytes.Equal(xOnly(serialized), schnorr.SerializePubKey(signer))
+	case len(serialized) == uncompressedPubKeyLength:
+		return bytes.Equal(serialized, signer.SerializeUncompressed())
+	default:
+		return bytes.Equal(serialized, signer.SerializeCompressed())
+	}
+}
+
+// address returns the address of the descriptor for a key
+func (d *Descriptor) address(serialized []byte) (string, error) {
+	if d.AddressType != AddressTypeP2TR {
+		addr, err := pubKeyHashAddress(d.AddressType, btcutil.Hash160(serialized), d.params)
+		if err != nil {
+			return "", err
+		}
+		return addr.EncodeAddress(), nil
+	}
+
+	internal, err := schnorr.ParsePubKey(xOnly(serialized))
+	if err != nil {
+		return "", newVerifyError(ErrInvalidDescriptor, "invalid taproot key: %v", err)
+	}
+	outputKey := txscript.ComputeTaprootKeyNoScript(internal)
+	addr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), d.params)
+	if err != nil {
+		return "", newVerifyError(ErrInvalidAddress, "failed to derive taproot address: %v", err)
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// xOnly returns the x coordinate of a serialized public key
+func xOnly(serialized []byte) []byte {
+	if len(serialized) == schnorr.PubKeyBytesLen {
+		return serialized
+	}
+	return serialized[1:33]
+}
+
+// unwrap reports whether s is prefix + inner + suffix, storing inner
+func unwrap(s, prefix, suffix string, inner *string) bool {
+	if !strings.HasPrefix(s, prefix) || !strings.HasSuffix(s, suffix) || len(s) < len(prefix)+len(suffix) {
+		return false
+	}
+	*inner = s[len(prefix) : len(s)-len(suffix)]
+	return true
+}
+
+// descriptorChecksum computes the BIP-380 checksum of a descriptor, or
+// returns an empty string if it has characters outside the input charset
+func descriptorChecksum(desc string) string {
+	var symbols []uint64
+	var groups []uint64
+	for i := 0; i < len(desc); i++ {
+		v := strings.IndexByte(descriptorInputCharset, desc[i])
+		if v < 0 {
+			return ""
+		}
+		symbols = append(symbols, uint64(v&31))
+		groups = append(groups, uint64(v>>5))
+		if len(groups) == 3 {
+			symbols = append(symbols, groups[0]*9+groups[1]*3+groups[2])
+			groups = groups[:0]
+		}
+	}
+	switch len(groups) {
+	case 1:
+		symbols = append(symbols, groups[0])
+	case 2:
+		symbols = append(symbols, groups[0]*3+groups[1])
+	}
+	symbols = append(symbols, 0, 0, 0, 0, 0, 0, 0, 0)
+
+	chk := uint64(1)
+	for _, value := range symbols {
+		top := chk >> 35
+		chk = (chk&0x7ffffffff)<<5 ^ value
+		for i, gen := range descriptorGenerator {
+			if (top>>i)&1 == 1 {
+				chk ^= gen
+			}
+		}
+	}
+	chk ^= 1
+
+	var sb strings.Builder
+	for i := 0; i < 8; i++ {
+		sb.WriteByte(descriptorChecksumCharset[(chk>>(5*(7-i)))&31])
+	}
+	return sb.String()
+}
@@ -0,0 +1,153 @@
+package verify
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestDescriptorChecksum(t *testing.T) {
+	// BIP-380 test vector
+	if got := descriptorChecksum("raw(deadbeef)"); got != "89f8spxm" {
+		t.Errorf("descriptorChecksum() = %q, want %q", got, "89f8spxm")
+	}
+}
+
+func TestVerifyAgainstDescriptor(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	account := newTestAccount(t, &chaincfg.MainNetParams)
+	xpub := testXpub(t, account, xpubVersions[0].version)
+	const message = "prove descriptor ownership"
+
+	privKey := testPrivKey(testKeySeed)
+	fixedKey := hex.EncodeToString(privKey.PubKey().SerializeCompressed())
+	fixed := signTestMessage(t, testKeySeed, nil, message)
+
+	receive := signWithAccount(t, account, 0, 4, AddressTypeP2WPKH, message)
+	change := signWithAccount(t, account, 1, 3, AddressTypeP2WPKH, message)
+	nested := signWithAccount(t, account, 1, 2, AddressTypeP2SHP2WPKH, message)
+	taproot := signWithAccount(t, account, 0, 1, AddressTypeP2PKH, message)
+
+	trAddrs, err := mustParseDescriptor(t, "tr("+xpub+"/0/*)").Addresses(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	taproot.Address = trAddrs[1].Address
+
+	tests := []struct {
+		name       string
+		descriptor string
+		msg        SignedMessage
+		want       DescriptorAddress
+		wantErr    error
+	}{
+		{
+			name:       "Wildcard",
+			descriptor: "wpkh(" + xpub + "/0/*)",
+			msg:        receive,
+			want:       DescriptorAddress{Address: receive.Address, Path: "0/4"},
+		},
+		{
+			name:       "Multipath with key origin",
+			descriptor: "wpkh([d34db33f/84h/0h/0h]" + xpub + "/<0;1>/*)",
+			msg:        change,
+			want:       DescriptorAddress{Address: change.Address, Path: "1/3"},
+		},
+		{
+			name:       "Nested SegWit",
+			descriptor: "sh(wpkh(" + xpub + "/1/*))",
+			msg:        nested,
+			want:       DescriptorAddress{Address: nested.Address, Path: "1/2"},
+		},
+		{
+			name:       "Taproot",
+			descriptor: "tr(" + xpub + "/0/*)",
+			msg:        taproot,
+			want:       DescriptorAddress{Address: taproot.Address, Path: "0/1"},
+		},
+		{
+			name:       "Fixed key with checksum",
+			descriptor: "pkh(" + fixedKey + ")#" + descriptorChecksum("pkh("+fixedKey+")"),
+			msg:        fixed,
+			want:       DescriptorAddress{Address: fixed.Address},
+		},
+		{
+			name:       "Other chain",
+			descriptor: "wpkh(" + xpub + "/0/*)",
+			msg:        change,
+			wantErr:    ErrAddressMismatch,
+		},
+		{
+			name:       "Wrong checksum",
+			descriptor: "pkh(" + fixedKey + ")#89f8spxm",
+			msg:        fixed,
+			wantErr:    ErrInvalidDescriptor,
+		},
+		{
+			name:       "Hardened step",
+			descriptor: "wpkh(" + xpub + "/0h/*)",
+			msg:        receive,
+			wantErr:    ErrInvalidDescriptor,
+		},
+		{
+			name:       "Script tree",
+			descriptor: "tr(" + fixedKey + ",pk(" + fixedKey + "))",
+			msg:        fixed,
+			wantErr:    ErrInvalidDescriptor,
+		},
+		{
+			name:       "Unsupported descriptor",
+			descriptor: "wsh(pk(" + fixedKey + "))",
+			msg:        fixed,
+			wantErr:    ErrInvalidDescriptor,
+		},
+		{
+			name:       "Testnet key",
+			descriptor: "wpkh(" + testXpub(t, newTestAccount(t, &chaincfg.TestNet3Params), xpubVersions[3].version) + "/0/*)",
+			msg:        receive,
+			wantErr:    ErrNetworkMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, got, err := VerifyAgainstDescriptor(tt.descriptor, tt.msg.Message, tt.msg.Signature, 0, &chaincfg.MainNetParams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyAgainstDescriptor() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != (tt.wantErr == nil) || got != tt.want {
+				t.Errorf("VerifyAgainstDescriptor() = %v, %+v, want %+v", valid, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescriptorAddresses(t *testing.T) {
+	account := newTestAccount(t, &chaincfg.MainNetParams)
+	xpub := testXpub(t, account, xpubVersions[0].version)
+
+	addrs, err := mustParseDescriptor(t, "wpkh("+xpub+"/<0;1>/*)").Addresses(3)
+	if err != nil {
+		t.Fatalf("Addresses() error = %v", err)
+	}
+	if len(addrs) != 6 {
+		t.Fatalf("Addresses() returned %d addresses, want 6", len(addrs))
+	}
+	want := signWithAccount(t, account, 1, 2, AddressTypeP2WPKH, "m").Address
+	if addrs[5].Address != want || addrs[5].Path != "1/2" {
+		t.Errorf("Addresses()[5] = %+v, want %s at 1/2", addrs[5], want)
+	}
+}
+
+// Helper function to parse a mainnet descriptor
+func mustParseDescriptor(t *testing.T, descriptor string) *Descriptor {
+	t.Helper()
+	d, err := ParseDescriptor(descriptor, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("ParseDescriptor() error = %v", err)
+	}
+	return d
+}
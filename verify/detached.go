@@ -0,0 +1,214 @@
+package verify
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Boundary lines of a detached signature file
+const (
+	detachedBegin = "-----BEGIN BITCOIN DETACHED SIGNATURE-----"
+	detachedEnd   = "-----END BITCOIN DETACHED SIGNATURE-----"
+)
+
+// digestPrefix prefixes the hex-encoded SHA-256 digest of an artifact
+const digestPrefix = "sha256:"
+
+// DetachedSignature is a signature of an artifact kept in a separate .btcsig
+// file, like a GPG detached signature:
+//
+//	-----BEGIN BITCOIN DETACHED SIGNATURE-----
+//	Address: 1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5
+//	Digest: sha256:5f0a...
+//	Release: v1.2.0
+//	Signature: IFqUo4...
+//	-----END BITCOIN DETACHED SIGNATURE-----
+//
+// Without a digest, the signed message is the content of the artifact.
+// With a digest, the signed message is the digest itself, such as
+// "sha256:5f0a...", so artifacts of any size can be signed.
+type DetachedSignature struct {
+	// Address is the address that signed the artifact
+	Address string
+
+	// Signature is the base64-encoded signature
+	Signature string
+
+	// Digest is the SHA-256 digest of the artifact as "sha256:<hex>", or
+	// empty when the artifact content itself was signed
+	Digest string
+
+	// Metadata holds the other fields of the file, such as a release name.
+	// It isn't signed.
+	Metadata map[string]string
+}
+
+// ArtifactDigest returns the digest of an artifact in the form used by
+// DetachedSignature.Digest
+func ArtifactDigest(artifact io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, artifact); err != nil {
+		return "", err
+	}
+	return digestPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ReadDetachedSignature reads a detached signature file. Text before the
+// begin line and after the end line is ignored.
+func ReadDetachedSignature(r io.Reader) (DetachedSignature, error) {
+	scanner := bufio.NewScanner(r)
+	begun := false
+	sig := DetachedSignature{Metadata: make(map[string]string)}
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !begun {
+			begun = line == detachedBegin
+			continue
+		}
+		if line == detachedEnd {
+			return sig, sig.validate()
+		}
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return DetachedSignature{}, newVerifyError(ErrMalformedDetachedSignature, "line %q is not a field", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+
+		var field *string
+		switch strings.ToLower(key) {
+		case "address":
+			field = &sig.Address
+		case "signature":
+			field = &sig.Signature
+		case "digest":
+			field = &sig.Digest
+		default:
+			if _, ok := sig.Metadata[key]; ok {
+				return DetachedSignature{}, newVerifyError(ErrMalformedDetachedSignature, "duplicate field %q", key)
+			}
+			sig.Metadata[key] = value
+			continue
+		}
+		if *field != "" {
+			return DetachedSignature{}, newVerifyError(ErrMalformedDetachedSignature, "duplicate field %q", key)
+		}
+		*field = value
+	}
+	if err := scanner.Err(); err != nil {
+		return DetachedSignature{}, err
+	}
+
+	if !begun {
+		return DetachedSignature{}, newVerifyError(ErrMalformedDetachedSignature, "missing %q line", detachedBegin)
+	}
+	return DetachedSignature{}, newVerifyError(ErrMalformedDetachedSignature, "missing %q line", detachedEnd)
+}
+
+// WriteDetachedSignature writes a detached signature file. Metadata fields
+// are written in key order, so the same signature always produces the same
+// file.
+func WriteDetachedSignature(w io.Writer, sig DetachedSignature) error {
+	if err := sig.validate(); err != nil {
+		return err
+	}
+	for key, value := range sig.Metadata {
+		switch {
+		case key == "" || strings.ContainsAny(key, ":\r\n") || strings.TrimSpace(key) != key:
+			return newVerifyError(ErrMalformedDetachedSignature, "invalid metadata key %q", key)
+		case isReservedField(key):
+			return newVerifyError(ErrMalformedDetachedSignature, "metadata key %q is reserved", key)
+		case strings.ContainsAny(value, "\r\n"):
+			return newVerifyError(ErrMalformedDetachedSignature, "metadata value of %q spans several lines", key)
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(detachedBegin + "\n")
+	fmt.Fprintf(&sb, "Address: %s\n", sig.Address)
+	if sig.Digest != "" {
+		fmt.Fprintf(&sb, "Digest: %s\n", sig.Digest)
+	}
+	for _, key := range slices.Sorted(maps.Keys(sig.Metadata)) {
+		fmt.Fprintf(&sb, "%s: %s\n", key, strings.TrimSpace(sig.Metadata[key]))
+	}
+	fmt.Fprintf(&sb, "Signature: %s\n", sig.Signature)
+	sb.WriteString(detachedEnd + "\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// VerifyDetached verifies a detached signature of an artifact using the
+// provided network parameters. With a digest, the artifact must match it
+// and is never held in memory; otherwise its content, which must fit the
+// message size limit, is the signed message.
+func VerifyDetached(artifact io.Reader, sig DetachedSignature, params *chaincfg.Params) (bool, error) {
+	if err := sig.validate(); err != nil {
+		return false, err
+	}
+
+	var message string
+	if sig.Digest != "" {
+		digest, err := ArtifactDigest(artifact)
+		if err != nil {
+			return false, fmt.Errorf("reading artifact: %w", err)
+		}
+		if digest != strings.ToLower(sig.Digest) {
+			logEvent(LogLevelError, "Artifact digest mismatch", "digest", digest, "expected", sig.Digest)
+			return false, newVerifyError(ErrDigestMismatch, "artifact digest %s, signature is for %s", digest, sig.Digest)
+		}
+		message = sig.Digest
+	} else {
+		limit := MaxMessageSize()
+		if limit > 0 {
+			artifact = io.LimitReader(artifact, limit+1)
+		}
+		content, err := io.ReadAll(artifact)
+		if err != nil {
+			return false, fmt.Errorf("reading artifact: %w", err)
+		}
+		message = string(content)
+	}
+
+	return VerifyBip137SignatureWithParams(sig.Address, message, sig.Signature, params)
+}
+
+// validate checks that the signature has its required fields and a
+// well-formed digest
+func (sig DetachedSignature) validate() error {
+	switch {
+	case sig.Address == "":
+		return newVerifyError(ErrMalformedDetachedSignature, "missing address")
+	case sig.Signature == "":
+		return newVerifyError(ErrMalformedDetachedSignature, "missing signature")
+	}
+	if sig.Digest != "" {
+		hexDigest, ok := strings.CutPrefix(strings.ToLower(sig.Digest), digestPrefix)
+		if decoded, err := hex.DecodeString(hexDigest); !ok || err != nil || len(decoded) != sha256.Size {
+			return newVerifyError(ErrMalformedDetachedSignature, "digest %q is not %s followed by a SHA-256 hash", sig.Digest, digestPrefix)
+		}
+	}
+	return nil
+}
+
+// isReservedField reports whether a field name is used by the signature
+// itself rather than metadata
+func isReservedField(key string) bool {
+	switch strings.ToLower(key) {
+	case "address", "signature", "digest":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+package verify
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestDetachedSignatureRoundTrip(t *testing.T) {
+	sig := DetachedSignature{
+		Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+		Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+		Digest:    "sha256:" + strings.Repeat("ab", 32),
+		Metadata:  map[string]string{"Release": "v1.2.0", "Artifact": "btcverify-linux-amd64.tar.gz"},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDetachedSignature(&buf, sig); err != nil {
+		t.Fatalf("WriteDetachedSignature() error = %v", err)
+	}
+	want := detachedBegin + "\n" +
+		"Address: 1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5\n" +
+		"Digest: sha256:" + strings.Repeat("ab", 32) + "\n" +
+		"Artifact: btcverify-linux-amd64.tar.gz\n" +
+		"Release: v1.2.0\n" +
+		"Signature: " + sig.Signature + "\n" +
+		detachedEnd + "\n"
+	if buf.String() != want {
+		t.Errorf("WriteDetachedSignature() wrote\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	got, err := ReadDetachedSignature(strings.NewReader("signed release\n" + buf.String()))
+	if err != nil {
+		t.Fatalf("ReadDetachedSignature() error = %v", err)
+	}
+	if got.Address != sig.Address || got.Signature != sig.Signature || got.Digest != sig.Digest ||
+		len(got.Metadata) != 2 || got.Metadata["Release"] != "v1.2.0" {
+		t.Errorf("ReadDetachedSignature() = %+v, want %+v", got, sig)
+	}
+}
+
+func TestReadDetachedSignatureInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{name: "Missing begin", text: "Address: 1DAag8\nSignature: c2ln\n" + detachedEnd},
+		{name: "Missing end", text: detachedBegin + "\nAddress: 1DAag8\nSignature: c2ln\n"},
+		{name: "Missing signature", text: detachedBegin + "\nAddress: 1DAag8\n" + detachedEnd},
+		{name: "Not a field", text: detachedBegin + "\nAddress: 1DAag8\nc2ln\n" + detachedEnd},
+		{name: "Duplicate field", text: detachedBegin + "\nAddress: 1DAag8\naddress: 1BvBMS\nSignature: c2ln\n" + detachedEnd},
+		{name: "Malformed digest", text: detachedBegin + "\nAddress: 1DAag8\nDigest: md5:abc\nSignature: c2ln\n" + detachedEnd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadDetachedSignature(strings.NewReader(tt.text)); !errors.Is(err, ErrMalformedDetachedSignature) {
+				t.Errorf("ReadDetachedSignature() error = %v, want %v", err, ErrMalformedDetachedSignature)
+			}
+		})
+	}
+}
+
+func TestVerifyDetached(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	artifact := "release artifact\x00\x01\x02"
+	digest, err := ArtifactDigest(strings.NewReader(artifact))
+	if err != nil {
+		t.Fatal(err)
+	}
+	contentSig := signTestMessage(t, testKeySeed, nil, artifact)
+	digestSig := signTestMessage(t, testKeySeed, nil, digest)
+
+	tests := []struct {
+		name      string
+		artifact  string
+		sig       DetachedSignature
+		wantValid bool
+		wantErr   error
+	}{
+		{
+			name:      "Signed content",
+			artifact:  artifact,
+			sig:       DetachedSignature{Address: contentSig.Address, Signature: contentSig.Signature},
+			wantValid: true,
+		},
+		{
+			name:      "Signed digest",
+			artifact:  artifact,
+			sig:       DetachedSignature{Address: digestSig.Address, Signature: digestSig.Signature, Digest: digest},
+			wantValid: true,
+		},
+		{
+			name:     "Modified content",
+			artifact: artifact + "!",
+			sig:      DetachedSignature{Address: contentSig.Address, Signature: contentSig.Signature},
+			wantErr:  ErrAddressMismatch,
+		},
+		{
+			name:     "Modified artifact with digest",
+			artifact: artifact + "!",
+			sig:      DetachedSignature{Address: digestSig.Address, Signature: digestSig.Signature, Digest: digest},
+			wantErr:  ErrDigestMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifyDetached(strings.NewReader(tt.artifact), tt.sig, &chaincfg.MainNetParams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyDetached() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyDetached() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
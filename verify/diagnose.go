@@ -0,0 +1,145 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Diagnosis is a FailureReport enriched with context about the signed
+// message, for reviewers deciding whether to accept a proof
+type Diagnosis struct {
+	FailureReport
+
+	// Message is the signed message, or its SHA-256 hash once redacted
+	Message string
+
+	// Signature is the base64-encoded signature, truncated once redacted
+	Signature string
+
+	// IsRedacted reports whether the message contents were removed
+	IsRedacted bool
+
+	// Invoice summarizes the message when it is a BOLT-11 Lightning invoice,
+	// as exported by Electrum's "verify message" dialog
+	Invoice *InvoiceSummary
+
+	// Notes are observations about the message worth a reviewer's attention
+	Notes []string
+}
+
+// String returns a human-readable summary of the diagnosis
+func (d Diagnosis) String() string {
+	var sb strings.Builder
+	sb.WriteString(d.FailureReport.String())
+	if d.IsRedacted {
+		fmt.Fprintf(&sb, "; message %s, signature %s", d.Message, d.Signature)
+	}
+	if d.Invoice != nil {
+		fmt.Fprintf(&sb, "; message is a %s", d.Invoice)
+	}
+	for _, note := range d.Notes {
+		fmt.Fprintf(&sb, "; %s", note)
+	}
+	return sb.String()
+}
+
+// Diagnose verifies a signed message using the Bitcoin mainnet parameters
+// and describes what was signed. Messages are verified byte-exactly, so a
+// BOLT-11 invoice only verifies when it is identical to the signed one; when
+// it decodes, its payee and amount are included for context. The invoice
+// itself is not verified beyond its own signature.
+func Diagnose(msg SignedMessage) Diagnosis {
+	return DiagnoseWithParams(msg, &chaincfg.MainNetParams)
+}
+
+// DiagnoseWithParams is like Diagnose, using the provided network parameters.
+func DiagnoseWithParams(msg SignedMessage, params *chaincfg.Params) Diagnosis {
+	d := Diagnosis{
+		FailureReport: ExplainWithParams(msg.Address, msg.Message, msg.Signature, params),
+		Message:       msg.Message,
+		Signature:     msg.Signature,
+	}
+
+	if strings.TrimSpace(msg.Message) != msg.Message {
+		d.Notes = append(d.Notes, "message has leading or trailing whitespace, which is part of the signed bytes")
+	}
+
+	if looksLikeInvoice(msg.Message) {
+		invoice, err := decodeInvoice(msg.Message)
+		if err != nil {
+			d.Notes = append(d.Notes, fmt.Sprintf("message looks like a BOLT-11 invoice but could not be decoded: %v", err))
+		} else {
+			d.Invoice = invoice
+		}
+	}
+
+	return d
+}
+
+// defaultRedactedSignatureLength is the number of signature characters kept
+// by Redacted
+const defaultRedactedSignatureLength = 8
+
+// RedactOption configures Diagnosis.Redacted
+type RedactOption func(*redactConfig)
+
+// redactConfig holds the settings of a redaction
+type redactConfig struct {
+	signatureLength int
+	keepInvoice     bool
+}
+
+// WithRedactedSignatureLength sets how many leading characters of the
+// signature are kept, enough to tell signatures apart without sharing them
+func WithRedactedSignatureLength(n int) RedactOption {
+	return func(c *redactConfig) {
+		c.signatureLength = n
+	}
+}
+
+// WithInvoiceSummary keeps the amount, payee and payment hash of a signed
+// BOLT-11 invoice. Its description is removed regardless.
+func WithInvoiceSummary() RedactOption {
+	return func(c *redactConfig) {
+		c.keepInvoice = true
+	}
+}
+
+// Redacted returns a copy of the diagnosis that can be shared with third
+// parties: the message is replaced by its SHA-256 hash, the signature is
+// truncated and the invoice summary, which reveals the message contents, is
+// dropped. The verification outcome and the addresses are kept, as they are
+// what support staff need to act on.
+func (d Diagnosis) Redacted(opts ...RedactOption) Diagnosis {
+	if d.IsRedacted {
+		return d
+	}
+
+	cfg := redactConfig{signatureLength: defaultRedactedSignatureLength}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	sum := sha256.Sum256([]byte(d.Message))
+	d.Message = "sha256:" + hex.EncodeToString(sum[:])
+
+	if len(d.Signature) > cfg.signatureLength {
+		d.Signature = d.Signature[:max(cfg.signatureLength, 0)] + "..."
+	}
+
+	if d.Invoice != nil && cfg.keepInvoice {
+		invoice := *d.Invoice
+		invoice.Description = ""
+		d.Invoice = &invoice
+	} else {
+		d.Invoice = nil
+	}
+
+	d.Notes = append([]string(nil), d.Notes...)
+	d.IsRedacted = true
+	return d
+}
@@ -0,0 +1,173 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestDiagnose(t *testing.T) {
+	invoiceMsg := signTestMessage(t, testKeySeed, nil, testInvoiceCoffee)
+
+	// Electrum exports may carry a trailing newline the signer didn't sign
+	trailingNewline := invoiceMsg
+	trailingNewline.Message += "\n"
+
+	notInvoice := signTestMessage(t, testKeySeed, nil, "lnbc is not an invoice")
+
+	tests := []struct {
+		name        string
+		msg         SignedMessage
+		wantValid   bool
+		wantInvoice bool
+		wantNote    string
+	}{
+		{
+			name:        "Signed invoice",
+			msg:         invoiceMsg,
+			wantValid:   true,
+			wantInvoice: true,
+		},
+		{
+			name:        "Signed invoice with trailing newline",
+			msg:         trailingNewline,
+			wantValid:   false,
+			wantInvoice: true,
+			wantNote:    "whitespace",
+		},
+		{
+			name:      "Message that only looks like an invoice",
+			msg:       notInvoice,
+			wantValid: true,
+			wantNote:  "could not be decoded",
+		},
+		{
+			name:      "Plain message",
+			msg:       walletTestVectors[0].msg,
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := Diagnose(tt.msg)
+
+			if d.Valid != tt.wantValid {
+				t.Errorf("Diagnose().Valid = %v, want %v (%s)", d.Valid, tt.wantValid, d)
+			}
+			if (d.Invoice != nil) != tt.wantInvoice {
+				t.Errorf("Diagnose().Invoice = %v, want invoice %v", d.Invoice, tt.wantInvoice)
+			}
+			if tt.wantInvoice && d.Invoice.Payee != testInvoicePayee {
+				t.Errorf("Diagnose().Invoice.Payee = %s, want %s", d.Invoice.Payee, testInvoicePayee)
+			}
+			if tt.wantNote == "" && len(d.Notes) > 0 {
+				t.Errorf("Diagnose().Notes = %v, want none", d.Notes)
+			}
+			if tt.wantNote != "" && !strings.Contains(strings.Join(d.Notes, "\n"), tt.wantNote) {
+				t.Errorf("Diagnose().Notes = %v, want note containing %q", d.Notes, tt.wantNote)
+			}
+		})
+	}
+}
+
+func TestDiagnosisRedacted(t *testing.T) {
+	msg := signTestMessage(t, testKeySeed, nil, testInvoiceCoffee)
+	d := Diagnose(msg)
+	sum := sha256.Sum256([]byte(msg.Message))
+
+	tests := []struct {
+		name          string
+		opts          []RedactOption
+		wantSignature string
+		wantInvoice   bool
+	}{
+		{
+			name:          "Defaults",
+			wantSignature: msg.Signature[:8] + "...",
+		},
+		{
+			name:          "Longer signature prefix",
+			opts:          []RedactOption{WithRedactedSignatureLength(16)},
+			wantSignature: msg.Signature[:16] + "...",
+		},
+		{
+			name:          "Keep invoice summary",
+			opts:          []RedactOption{WithInvoiceSummary()},
+			wantSignature: msg.Signature[:8] + "...",
+			wantInvoice:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := d.Redacted(tt.opts...)
+
+			if !r.IsRedacted || r.Valid != d.Valid || r.ExpectedAddress != d.ExpectedAddress {
+				t.Errorf("Redacted() = %+v, want redacted copy of %+v", r, d)
+			}
+			if r.Message != "sha256:"+hex.EncodeToString(sum[:]) {
+				t.Errorf("Redacted().Message = %s, want message hash", r.Message)
+			}
+			if r.Signature != tt.wantSignature {
+				t.Errorf("Redacted().Signature = %s, want %s", r.Signature, tt.wantSignature)
+			}
+			if (r.Invoice != nil) != tt.wantInvoice {
+				t.Errorf("Redacted().Invoice = %v, want invoice %v", r.Invoice, tt.wantInvoice)
+			}
+			if r.Invoice != nil && (r.Invoice.Description != "" || r.Invoice.AmountMsat != d.Invoice.AmountMsat) {
+				t.Errorf("Redacted().Invoice = %+v, want amount without description", r.Invoice)
+			}
+			if strings.Contains(r.String(), "coffee") || strings.Contains(r.String(), msg.Signature) {
+				t.Errorf("Redacted().String() = %s, leaks message or signature", r)
+			}
+		})
+	}
+
+	// The original is left untouched
+	if d.IsRedacted || d.Message != msg.Message || d.Invoice.Description == "" {
+		t.Errorf("Redacted() modified the original diagnosis: %+v", d)
+	}
+}
+
+// testKeySeed is the seed of the key most tests sign with
+const testKeySeed = "verify test key"
+
+// Helper function to derive a deterministic test key from a seed
+func testPrivKey(seed string) *btcec.PrivateKey {
+	sum := sha256.Sum256([]byte(seed))
+	privKey, _ := btcec.PrivKeyFromBytes(sum[:])
+	return privKey
+}
+
+// Helper function to sign a message with the key derived from a seed,
+// returning a signed message for its compressed P2PKH address. The message
+// is hashed with hash, or with the Bitcoin message hash when it's nil.
+func signTestMessage(t *testing.T, seed string, hash func(string) [32]byte, message string) SignedMessage {
+	t.Helper()
+
+	privKey := testPrivKey(seed)
+	if hash == nil {
+		hash = magicHash
+	}
+	digest := hash(message)
+	sig := ecdsa.SignCompact(privKey, digest[:], true)
+
+	addr, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(privKey.PubKey().SerializeCompressed()), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("btcutil.NewAddressPubKeyHash() error = %v", err)
+	}
+
+	return SignedMessage{
+		Address:   addr.EncodeAddress(),
+		Message:   message,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}
+}
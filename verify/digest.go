@@ -0,0 +1,54 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// VerifyDigest verifies a BIP-0137 signature over a message digest computed
+// elsewhere, such as by an HSM or a streaming pipeline that only ships the
+// digest, using the Bitcoin mainnet parameters. The digest must be the one
+// MessageHash returns for the message. Only 65-byte compact signatures can
+// be verified this way; BIP-322 signatures commit to the message itself.
+func VerifyDigest(address string, digest [32]byte, signatureBase64 string) (bool, error) {
+	return VerifyDigestWithParams(address, digest, signatureBase64, &chaincfg.MainNetParams)
+}
+
+// VerifyDigestWithParams is like VerifyDigest, using the provided network
+// parameters.
+func VerifyDigestWithParams(address string, digest [32]byte, signatureBase64 string, params *chaincfg.Params) (valid bool, err error) {
+	start := time.Now()
+	defer func() {
+		observeVerification(GetMetrics(), start, valid, err)
+	}()
+
+	logEvent(LogLevelInfo, "Starting BIP-0137 digest verification", "address", address)
+	logEvent(LogLevelDebug, "Verification input", "address", address, "digest", hex.EncodeToString(digest[:]), "signature", signatureBase64)
+
+	switch {
+	case address == "":
+		return false, ErrEmptyAddress
+	case signatureBase64 == "":
+		return false, ErrEmptySignature
+	}
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		logEvent(LogLevelError, "Failed to decode base64 signature", "error", err)
+		return false, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	if len(sigBytes) != compactSignatureLength {
+		logEvent(LogLevelError, "Signature rejected: not a compact signature", "length", len(sigBytes))
+		return false, newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d: only compact signatures can be verified against a digest", len(sigBytes), compactSignatureLength)
+	}
+
+	valid, err = verifyDigest(spanScope{}, address, digest[:], sigBytes, params)
+	if err != nil {
+		logEvent(LogLevelError, "Digest verification failed", "address", address, "network", params.Name, "error", err)
+		return false, err
+	}
+	logEvent(LogLevelInfo, "Digest verification successful", "address", address, "network", params.Name)
+	return valid, nil
+}
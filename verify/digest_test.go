@@ -0,0 +1,46 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestVerifyDigest(t *testing.T) {
+	for _, tv := range walletTestVectors[:7] {
+		t.Run(tv.name, func(t *testing.T) {
+			valid, err := VerifyDigest(tv.msg.Address, MessageHash(tv.msg.Message), tv.msg.Signature)
+			if err != nil || !valid {
+				t.Errorf("VerifyDigest() = %v, %v, want true", valid, err)
+			}
+		})
+	}
+
+	tv := walletTestVectors[2].msg
+	tests := []struct {
+		name      string
+		address   string
+		digest    [32]byte
+		signature string
+		wantErr   error
+	}{
+		{name: "Digest of another message", address: tv.Address, digest: MessageHash("another message"), signature: tv.Signature, wantErr: ErrAddressMismatch},
+		{name: "Plain SHA-256 of the message", address: tv.Address, digest: sha256.Sum256([]byte(tv.Message)), signature: tv.Signature, wantErr: ErrAddressMismatch},
+		{name: "Not a compact signature", address: tv.Address, digest: MessageHash(tv.Message), signature: "AAAA", wantErr: ErrMalformedSignature},
+		{name: "Invalid base64", address: tv.Address, digest: MessageHash(tv.Message), signature: strings.Repeat("!", 88), wantErr: ErrMalformedSignature},
+		{name: "Empty address", digest: MessageHash(tv.Message), signature: tv.Signature, wantErr: ErrEmptyAddress},
+		{name: "Empty signature", address: tv.Address, digest: MessageHash(tv.Message), wantErr: ErrEmptySignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifyDigestWithParams(tt.address, tt.digest, tt.signature, &chaincfg.MainNetParams)
+			if valid || !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyDigestWithParams() = %v, %v, want error %v", valid, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// SignatureReport is the diagnostic dump of a signature the trace logs
+// carry, as a value tools can render themselves
+type SignatureReport struct {
+	// Signature is the base64-encoded signature
+	Signature string
+
+	// Bytes are the decoded signature bytes
+	Bytes []byte
+
+	// HexDump is the decoded signature as space-separated hex bytes, as
+	// DumpHex formats it
+	HexDump string
+
+	// Header is the analysis of the header byte, zero for an empty
+	// signature
+	Header SignatureHeaderInfo
+
+	// R and S are the hex-encoded signature values, empty unless the
+	// signature is 65 bytes long
+	R string
+	S string
+
+	// LowS reports whether S is in the lower half of the curve order
+	LowS bool
+}
+
+// DumpSignatureReport decodes a base64-encoded signature into a diagnostic
+// report of its bytes, hex dump and header analysis, so tools can show what
+// the trace logs would without scraping the logger. Signatures that aren't 65
+// bytes long or have a header byte outside the BIP-0137 ranges are reported
+// as far as they decode, along with ErrMalformedSignature or
+// ErrInvalidHeaderByte.
+func DumpSignatureReport(sig string) (SignatureReport, error) {
+	if sig == "" {
+		return SignatureReport{}, ErrEmptySignature
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return SignatureReport{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+
+	report := SignatureReport{
+		Signature: sig,
+		Bytes:     sigBytes,
+		HexDump:   DumpHex(sigBytes),
+	}
+	if len(sigBytes) > 0 {
+		report.Header = inspectHeader(sigBytes[0])
+	}
+	if len(sigBytes) != compactSignatureLength {
+		return report, newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d", len(sigBytes), compactSignatureLength)
+	}
+
+	report.R = hex.EncodeToString(sigBytes[1:33])
+	report.S = hex.EncodeToString(sigBytes[33:])
+	report.LowS = isLowS(sigBytes)
+	if !report.Header.Valid() {
+		return report, newVerifyError(ErrInvalidHeaderByte, "0x%02x", report.Header.HeaderByte)
+	}
+	return report, nil
+}
+
+// String renders the report as indented lines, one per field
+func (r SignatureReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "signature:   %s\n", r.Signature)
+	fmt.Fprintf(&sb, "length:      %d bytes\n", len(r.Bytes))
+	fmt.Fprintf(&sb, "hex:         %s\n", r.HexDump)
+	if len(r.Bytes) > 0 {
+		fmt.Fprintf(&sb, "header byte: %d (%s)\n", r.Header.HeaderByte, r.Header)
+		fmt.Fprintf(&sb, "recovery id: %d\n", r.Header.RecoveryID)
+		fmt.Fprintf(&sb, "compressed:  %v\n", r.Header.Compressed)
+	}
+	if r.R != "" {
+		fmt.Fprintf(&sb, "r:           %s\n", r.R)
+		fmt.Fprintf(&sb, "s:           %s\n", r.S)
+		fmt.Fprintf(&sb, "low s:       %v\n", r.LowS)
+	}
+	return sb.String()
+}
@@ -0,0 +1,83 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestDumpSignatureReport(t *testing.T) {
+	tests := []struct {
+		name       string
+		signature  string
+		wantLength int
+		wantHeader SignatureHeaderInfo
+		wantRS     bool
+		wantErr    error
+	}{
+		{
+			name:       "Compressed P2PKH",
+			signature:  walletTestVectors[2].msg.Signature,
+			wantLength: compactSignatureLength,
+			wantHeader: SignatureHeaderInfo{HeaderByte: 32, AddressType: AddressTypeP2PKH, Compressed: true, RecoveryID: 1},
+			wantRS:     true,
+		},
+		{
+			name:       "Truncated",
+			signature:  base64.StdEncoding.EncodeToString([]byte{39, 1, 2}),
+			wantLength: 3,
+			wantHeader: SignatureHeaderInfo{HeaderByte: 39, AddressType: AddressTypeP2WPKH, Compressed: true},
+			wantErr:    ErrMalformedSignature,
+		},
+		{
+			name:       "Header out of range",
+			signature:  base64.StdEncoding.EncodeToString(append([]byte{0xff}, make([]byte, 64)...)),
+			wantLength: compactSignatureLength,
+			wantHeader: SignatureHeaderInfo{HeaderByte: 0xff},
+			wantRS:     true,
+			wantErr:    ErrInvalidHeaderByte,
+		},
+		{
+			name:      "Invalid base64",
+			signature: "not base64!",
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:    "Empty signature",
+			wantErr: ErrEmptySignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DumpSignatureReport(tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DumpSignatureReport() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(got.Bytes) != tt.wantLength || got.Header != tt.wantHeader {
+				t.Errorf("DumpSignatureReport() = %+v, want %d bytes with header %+v", got, tt.wantLength, tt.wantHeader)
+			}
+			if (got.R != "" && got.S != "") != tt.wantRS {
+				t.Errorf("R = %q, S = %q, want values %v", got.R, got.S, tt.wantRS)
+			}
+			if tt.wantLength > 0 && got.HexDump != DumpHex(got.Bytes) {
+				t.Errorf("HexDump = %q, want %q", got.HexDump, DumpHex(got.Bytes))
+			}
+		})
+	}
+}
+
+func TestSignatureReportString(t *testing.T) {
+	report, err := DumpSignatureReport(walletTestVectors[2].msg.Signature)
+	if err != nil {
+		t.Fatalf("DumpSignatureReport() error = %v", err)
+	}
+
+	s := report.String()
+	for _, want := range []string{"65 bytes", "20 5a 94", "32 (P2PKH (compressed))", "recovery id: 1", "low s:       true"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("String() = %q, want it to contain %q", s, want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+)
+
+// ParseSignature decodes a 65-byte compact signature given in base64, as
+// wallets emit it, or in hex, as some libraries log it. The header byte must
+// be in the BIP-0137 ranges and R and S must be valid scalars.
+func ParseSignature(s string) ([]byte, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, ErrEmptySignature
+	}
+
+	var sigBytes []byte
+	var err error
+	if len(s) == 2*compactSignatureLength {
+		sigBytes, err = hex.DecodeString(s)
+	} else {
+		sigBytes, err = base64.StdEncoding.DecodeString(s)
+	}
+	if err != nil {
+		return nil, newVerifyError(ErrMalformedSignature, "signature is neither base64 nor hex: %v", err)
+	}
+	if err := checkCompactSignature(sigBytes); err != nil {
+		return nil, err
+	}
+	return sigBytes, nil
+}
+
+// EncodeSignatureBase64 encodes a compact signature in base64, the form the
+// verification functions take
+func EncodeSignatureBase64(sigBytes []byte) (string, error) {
+	if err := checkCompactSignature(sigBytes); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sigBytes), nil
+}
+
+// EncodeSignatureHex encodes a compact signature in lower-case hex
+func EncodeSignatureHex(sigBytes []byte) (string, error) {
+	if err := checkCompactSignature(sigBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(sigBytes), nil
+}
+
+// CompactToDER converts a compact signature to the DER encoding used by
+// transaction signatures and most ECDSA libraries. The header byte is
+// dropped; S is kept as is, even when it is high.
+func CompactToDER(sigBytes []byte) ([]byte, error) {
+	if err := checkCompactSignature(sigBytes); err != nil {
+		return nil, err
+	}
+
+	r, s := derInteger(sigBytes[1:33]), derInteger(sigBytes[33:])
+	der := make([]byte, 0, 6+len(r)+len(s))
+	der = append(der, 0x30, byte(4+len(r)+len(s)))
+	der = append(der, 0x02, byte(len(r)))
+	der = append(der, r...)
+	der = append(der, 0x02, byte(len(s)))
+	return append(der, s...), nil
+}
+
+// DERToCompact converts a DER signature of the message by the public key,
+// such as one produced by an HSM, into a compact signature for an address
+// of the given type. DER signatures carry no recovery ID, so it is found by
+// recovering the public key from each candidate. P2PKH signatures use the
+// header bytes of compressed keys.
+func DERToCompact(der []byte, message string, pubKey *btcec.PublicKey, addrType AddressType) ([]byte, error) {
+	if pubKey == nil {
+		return nil, ErrEmptyPublicKey
+	}
+
+	var header byte
+	switch addrType {
+	case AddressTypeP2PKH:
+		header = headerP2PKHCompressed
+	case AddressTypeP2SHP2WPKH:
+		header = headerP2SHP2WPKH
+	case AddressTypeP2WPKH:
+		header = headerP2WPKH
+	default:
+		return nil, newVerifyError(ErrUnsupportedAddressType, "no compact signature header for %q addresses", addrType)
+	}
+
+	sig, err := ecdsa.ParseDERSignature(der)
+	if err != nil {
+		return nil, newVerifyError(ErrMalformedSignature, "invalid DER signature: %v", err)
+	}
+	r, s := sig.R(), sig.S()
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+
+	digest := magicHash(message)
+	compact := make([]byte, compactSignatureLength)
+	copy(compact[1:33], rBytes[:])
+	copy(compact[33:], sBytes[:])
+	for recoveryID := byte(0); recoveryID < 4; recoveryID++ {
+		compact[0] = header + recoveryID
+		recovered, _, err := recoverPubKey(compact, digest[:])
+		if err == nil && recovered.IsEqual(pubKey) {
+			return compact, nil
+		}
+	}
+	return nil, newVerifyError(ErrInvalidSignature, "DER signature is not a signature of the message by the public key")
+}
+
+// checkCompactSignature checks the length, header byte and scalars of a
+// compact signature
+func checkCompactSignature(sigBytes []byte) error {
+	if len(sigBytes) != compactSignatureLength {
+		return newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d", len(sigBytes), compactSignatureLength)
+	}
+	if headerAddressType(sigBytes[0]) == "" {
+		return newVerifyError(ErrInvalidHeaderByte, "0x%02x", sigBytes[0])
+	}
+
+	var r, s btcec.ModNScalar
+	if overflow := r.SetByteSlice(sigBytes[1:33]); overflow || r.IsZero() {
+		return newVerifyError(ErrMalformedSignature, "R is not in the range [1, N-1]")
+	}
+	if overflow := s.SetByteSlice(sigBytes[33:]); overflow || s.IsZero() {
+		return newVerifyError(ErrMalformedSignature, "S is not in the range [1, N-1]")
+	}
+	return nil
+}
+
+// derInteger returns the minimal DER encoding of a big-endian unsigned
+// integer: leading zeros are dropped and a zero byte is prepended when the
+// high bit is set, so it isn't read as negative
+func derInteger(b []byte) []byte {
+	for len(b) > 1 && b[0] == 0 {
+		b = b[1:]
+	}
+	if b[0]&0x80 != 0 {
+		return append([]byte{0x00}, b...)
+	}
+	return b
+}
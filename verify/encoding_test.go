@@ -0,0 +1,114 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+)
+
+func TestParseSignature(t *testing.T) {
+	canonical := walletTestVectors[2].msg.Signature
+	sigBytes, _ := base64.StdEncoding.DecodeString(canonical)
+
+	zeroR := append([]byte{}, sigBytes...)
+	copy(zeroR[1:33], make([]byte, 32))
+
+	tests := []struct {
+		name    string
+		sig     string
+		wantErr error
+	}{
+		{name: "Base64", sig: canonical},
+		{name: "Hex", sig: hex.EncodeToString(sigBytes)},
+		{name: "Upper case hex", sig: strings.ToUpper(hex.EncodeToString(sigBytes))},
+		{name: "Empty", sig: " ", wantErr: ErrEmptySignature},
+		{name: "Neither base64 nor hex", sig: "not a signature!", wantErr: ErrMalformedSignature},
+		{name: "Short", sig: base64.StdEncoding.EncodeToString(sigBytes[:64]), wantErr: ErrMalformedSignature},
+		{name: "Invalid header byte", sig: base64.StdEncoding.EncodeToString(append([]byte{0x11}, sigBytes[1:]...)), wantErr: ErrInvalidHeaderByte},
+		{name: "Zero R", sig: base64.StdEncoding.EncodeToString(zeroR), wantErr: ErrMalformedSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSignature(tt.sig)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseSignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && !bytes.Equal(got, sigBytes) {
+				t.Errorf("ParseSignature() = %x, want %x", got, sigBytes)
+			}
+		})
+	}
+}
+
+func TestEncodeSignature(t *testing.T) {
+	sigBytes, _ := base64.StdEncoding.DecodeString(walletTestVectors[2].msg.Signature)
+
+	if got, err := EncodeSignatureBase64(sigBytes); err != nil || got != walletTestVectors[2].msg.Signature {
+		t.Errorf("EncodeSignatureBase64() = %q, %v, want %q", got, err, walletTestVectors[2].msg.Signature)
+	}
+	if got, err := EncodeSignatureHex(sigBytes); err != nil || got != hex.EncodeToString(sigBytes) {
+		t.Errorf("EncodeSignatureHex() = %q, %v, want %q", got, err, hex.EncodeToString(sigBytes))
+	}
+	if _, err := EncodeSignatureHex(sigBytes[:64]); !errors.Is(err, ErrMalformedSignature) {
+		t.Errorf("EncodeSignatureHex() error = %v, want %v", err, ErrMalformedSignature)
+	}
+}
+
+func TestDERRoundTrip(t *testing.T) {
+	privKey := testPrivKey(testKeySeed)
+	msg := signTestMessage(t, testKeySeed, nil, "DER round trip")
+	lowS, _ := base64.StdEncoding.DecodeString(msg.Signature)
+	highS, _ := base64.StdEncoding.DecodeString(highSVariant(t, msg.Signature))
+
+	for _, tt := range []struct {
+		name     string
+		sigBytes []byte
+	}{
+		{name: "Low S", sigBytes: lowS},
+		{name: "High S", sigBytes: highS},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			der, err := CompactToDER(tt.sigBytes)
+			if err != nil {
+				t.Fatalf("CompactToDER() error = %v", err)
+			}
+			sig, err := ecdsa.ParseDERSignature(der)
+			if err != nil {
+				t.Fatalf("ecdsa.ParseDERSignature() error = %v", err)
+			}
+			digest := MessageHash(msg.Message)
+			if !sig.Verify(digest[:], privKey.PubKey()) {
+				t.Errorf("DER signature %x doesn't verify", der)
+			}
+
+			compact, err := DERToCompact(der, msg.Message, privKey.PubKey(), AddressTypeP2PKH)
+			if err != nil {
+				t.Fatalf("DERToCompact() error = %v", err)
+			}
+			if !bytes.Equal(compact, tt.sigBytes) {
+				t.Errorf("DERToCompact() = %x, want %x", compact, tt.sigBytes)
+			}
+		})
+	}
+
+	der, _ := CompactToDER(lowS)
+	compact, err := DERToCompact(der, msg.Message, privKey.PubKey(), AddressTypeP2WPKH)
+	if err != nil || compact[0] < headerP2WPKH {
+		t.Errorf("DERToCompact() = %x, %v, want a P2WPKH header byte", compact, err)
+	}
+	if _, err := DERToCompact(der, "other message", privKey.PubKey(), AddressTypeP2PKH); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("DERToCompact() error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := DERToCompact(der, msg.Message, privKey.PubKey(), AddressTypeP2TR); !errors.Is(err, ErrUnsupportedAddressType) {
+		t.Errorf("DERToCompact() error = %v, want %v", err, ErrUnsupportedAddressType)
+	}
+	if _, err := DERToCompact(der[:len(der)-1], msg.Message, privKey.PubKey(), AddressTypeP2PKH); !errors.Is(err, ErrMalformedSignature) {
+		t.Errorf("DERToCompact() error = %v, want %v", err, ErrMalformedSignature)
+	}
+}
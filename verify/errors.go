@@ -0,0 +1,265 @@
+package verify
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Common errors that can occur during signature verification
+var (
+	ErrVerificationTimeout        = errors.New("signature verification timed out")
+	ErrInvalidSignature           = errors.New("invalid signature")
+	ErrEmptyAddress               = errors.New("empty bitcoin address")
+	ErrEmptyMessage               = errors.New("empty message")
+	ErrEmptySignature             = errors.New("empty signature")
+	ErrInvalidAddress             = errors.New("invalid bitcoin address")
+	ErrUnsupportedAddressType     = errors.New("unsupported address type")
+	ErrAddressMismatch            = errors.New("address mismatch")
+	ErrInvalidHeaderByte          = errors.New("invalid signature header byte")
+	ErrMalformedSignature         = errors.New("malformed signature")
+	ErrNetworkMismatch            = errors.New("address is not valid for network")
+	ErrHighS                      = errors.New("non-canonical signature with high S value")
+	ErrInvalidArmor               = errors.New("invalid armored signed message")
+	ErrMalformedContainer         = errors.New("malformed proof container")
+	ErrMessageTooLarge            = errors.New("message too large")
+	ErrEmptyPublicKey             = errors.New("empty public key")
+	ErrContainerClosed            = errors.New("proof container is closed")
+	ErrHeaderAddressMismatch      = errors.New("signature header does not match address type")
+	ErrSelfCheckFailed            = errors.New("self-check failed")
+	ErrEngineDisagreement         = errors.New("verification engines disagree")
+	ErrMalformedJSON              = errors.New("malformed signed message JSON")
+	ErrMalformedDetachedSignature = errors.New("malformed detached signature")
+	ErrDigestMismatch             = errors.New("artifact does not match the signed digest")
+	ErrMalformedQRPayload         = errors.New("malformed QR payload")
+	ErrMalformedBundle            = errors.New("malformed proof bundle")
+	ErrConversionUnsupported      = errors.New("proof cannot be converted")
+	ErrInvalidExtendedKey         = errors.New("invalid extended public key")
+	ErrInvalidDescriptor          = errors.New("invalid output descriptor")
+	ErrInvalidDerivationPath      = errors.New("invalid derivation path")
+	ErrInvalidPublicKey           = errors.New("invalid public key")
+	ErrInvalidKeyringEntry        = errors.New("invalid keyring entry")
+	ErrUntrustedSigner            = errors.New("signer is not trusted")
+	ErrMalformedKeyring           = errors.New("malformed keyring")
+	ErrInvalidMultisigPolicy      = errors.New("invalid multisig policy")
+	ErrPolicyViolation            = errors.New("signed message violates the policy")
+	ErrAddressBlocked             = errors.New("address is blocked")
+	ErrInvalidTimestamp           = errors.New("invalid message timestamp")
+	ErrMessageExpired             = errors.New("message expired")
+	ErrQueueFull                  = errors.New("verification queue is full")
+	ErrQueueStopped               = errors.New("verification queue is stopped")
+)
+
+// ErrorCode is a stable, machine-readable identifier of a verification
+// failure, suitable for API responses
+type ErrorCode string
+
+// Error codes of the verification errors
+const (
+	CodeUnknown                    ErrorCode = "unknown"
+	CodeVerificationTimeout        ErrorCode = "verification_timeout"
+	CodeInvalidSignature           ErrorCode = "invalid_signature"
+	CodeEmptyAddress               ErrorCode = "empty_address"
+	CodeEmptyMessage               ErrorCode = "empty_message"
+	CodeEmptySignature             ErrorCode = "empty_signature"
+	CodeInvalidAddress             ErrorCode = "invalid_address"
+	CodeUnsupportedAddressType     ErrorCode = "unsupported_address_type"
+	CodeAddressMismatch            ErrorCode = "address_mismatch"
+	CodeInvalidHeaderByte          ErrorCode = "invalid_header_byte"
+	CodeMalformedSignature         ErrorCode = "malformed_signature"
+	CodeNetworkMismatch            ErrorCode = "network_mismatch"
+	CodeHighS                      ErrorCode = "high_s"
+	CodeInvalidArmor               ErrorCode = "invalid_armor"
+	CodeMalformedContainer         ErrorCode = "malformed_container"
+	CodeMessageTooLarge            ErrorCode = "message_too_large"
+	CodeEmptyPublicKey             ErrorCode = "empty_public_key"
+	CodeContainerClosed            ErrorCode = "container_closed"
+	CodeHeaderAddressMismatch      ErrorCode = "header_address_mismatch"
+	CodeSelfCheckFailed            ErrorCode = "selfcheck_failed"
+	CodeEngineDisagreement         ErrorCode = "engine_disagreement"
+	CodeMalformedJSON              ErrorCode = "malformed_json"
+	CodeMalformedDetachedSignature ErrorCode = "malformed_detached_signature"
+	CodeDigestMismatch             ErrorCode = "digest_mismatch"
+	CodeMalformedQRPayload         ErrorCode = "malformed_qr_payload"
+	CodeMalformedBundle            ErrorCode = "malformed_bundle"
+	CodeConversionUnsupported      ErrorCode = "conversion_unsupported"
+	CodeInvalidExtendedKey         ErrorCode = "invalid_extended_key"
+	CodeInvalidDescriptor          ErrorCode = "invalid_descriptor"
+	CodeInvalidDerivationPath      ErrorCode = "invalid_derivation_path"
+	CodeInvalidPublicKey           ErrorCode = "invalid_public_key"
+	CodeInvalidKeyringEntry        ErrorCode = "invalid_keyring_entry"
+	CodeUntrustedSigner            ErrorCode = "untrusted_signer"
+	CodeMalformedKeyring           ErrorCode = "malformed_keyring"
+	CodeInvalidMultisigPolicy      ErrorCode = "invalid_multisig_policy"
+	CodePolicyViolation            ErrorCode = "policy_violation"
+	CodeAddressBlocked             ErrorCode = "address_blocked"
+	CodeInvalidTimestamp           ErrorCode = "invalid_timestamp"
+	CodeMessageExpired             ErrorCode = "message_expired"
+	CodeQueueFull                  ErrorCode = "queue_full"
+	CodeQueueStopped               ErrorCode = "queue_stopped"
+)
+
+// errorCodes maps each sentinel error to its code
+var errorCodes = []struct {
+	err  error
+	code ErrorCode
+}{
+	{ErrVerificationTimeout, CodeVerificationTimeout},
+	{ErrInvalidSignature, CodeInvalidSignature},
+	{ErrEmptyAddress, CodeEmptyAddress},
+	{ErrEmptyMessage, CodeEmptyMessage},
+	{ErrEmptySignature, CodeEmptySignature},
+	{ErrInvalidAddress, CodeInvalidAddress},
+	{ErrUnsupportedAddressType, CodeUnsupportedAddressType},
+	{ErrAddressMismatch, CodeAddressMismatch},
+	{ErrInvalidHeaderByte, CodeInvalidHeaderByte},
+	{ErrMalformedSignature, CodeMalformedSignature},
+	{ErrNetworkMismatch, CodeNetworkMismatch},
+	{ErrHighS, CodeHighS},
+	{ErrInvalidArmor, CodeInvalidArmor},
+	{ErrMalformedContainer, CodeMalformedContainer},
+	{ErrMessageTooLarge, CodeMessageTooLarge},
+	{ErrEmptyPublicKey, CodeEmptyPublicKey},
+	{ErrContainerClosed, CodeContainerClosed},
+	{ErrHeaderAddressMismatch, CodeHeaderAddressMismatch},
+	{ErrSelfCheckFailed, CodeSelfCheckFailed},
+	{ErrEngineDisagreement, CodeEngineDisagreement},
+	{ErrMalformedJSON, CodeMalformedJSON},
+	{ErrMalformedDetachedSignature, CodeMalformedDetachedSignature},
+	{ErrDigestMismatch, CodeDigestMismatch},
+	{ErrMalformedQRPayload, CodeMalformedQRPayload},
+	{ErrMalformedBundle, CodeMalformedBundle},
+	{ErrConversionUnsupported, CodeConversionUnsupported},
+	{ErrInvalidExtendedKey, CodeInvalidExtendedKey},
+	{ErrInvalidDescriptor, CodeInvalidDescriptor},
+	{ErrInvalidDerivationPath, CodeInvalidDerivationPath},
+	{ErrInvalidPublicKey, CodeInvalidPublicKey},
+	{ErrInvalidKeyringEntry, CodeInvalidKeyringEntry},
+	{ErrUntrustedSigner, CodeUntrustedSigner},
+	{ErrMalformedKeyring, CodeMalformedKeyring},
+	{ErrInvalidMultisigPolicy, CodeInvalidMultisigPolicy},
+	{ErrPolicyViolation, CodePolicyViolation},
+	{ErrAddressBlocked, CodeAddressBlocked},
+	{ErrInvalidTimestamp, CodeInvalidTimestamp},
+	{ErrMessageExpired, CodeMessageExpired},
+	{ErrQueueFull, CodeQueueFull},
+	{ErrQueueStopped, CodeQueueStopped},
+}
+
+// VerifyError is a verification failure carrying a stable error code. The
+// wrapped error chain contains the sentinel error matching the code, so both
+// errors.Is and errors.As can be used to branch on it.
+type VerifyError struct {
+	// Code identifies the kind of failure
+	Code ErrorCode
+
+	// Err is the underlying error, wrapping the sentinel error for Code
+	Err error
+}
+
+// Error returns the message of the underlying error
+func (e *VerifyError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *VerifyError) Unwrap() error {
+	return e.Err
+}
+
+// newVerifyError creates a VerifyError wrapping the sentinel error, with the
+// formatted details appended to its message
+func newVerifyError(sentinel error, format string, args ...interface{}) *VerifyError {
+	return &VerifyError{
+		Code: codeOfSentinel(sentinel),
+		Err:  fmt.Errorf("%w: "+format, append([]interface{}{sentinel}, args...)...),
+	}
+}
+
+// codeOfSentinel returns the code of a sentinel error
+func codeOfSentinel(sentinel error) ErrorCode {
+	for _, ec := range errorCodes {
+		if ec.err == sentinel {
+			return ec.code
+		}
+	}
+	return CodeUnknown
+}
+
+// ErrorCodeOf returns the stable error code of a verification error. Errors
+// that don't stem from verification are reported as CodeUnknown.
+func ErrorCodeOf(err error) ErrorCode {
+	var verifyErr *VerifyError
+	if errors.As(err, &verifyErr) {
+		return verifyErr.Code
+	}
+
+	for _, ec := range errorCodes {
+		if errors.Is(err, ec.err) {
+			return ec.code
+		}
+	}
+
+	return CodeUnknown
+}
+
+// knownNetworks are tried when an address doesn't decode for the requested
+// network, to tell network mismatches apart from invalid addresses
+var knownNetworks = []*chaincfg.Params{
+	&chaincfg.MainNetParams,
+	&chaincfg.TestNet3Params,
+	&chaincfg.RegressionNetParams,
+	&chaincfg.SigNetParams,
+}
+
+// decodeAddress decodes an address for the given network, classifying the
+// failure as an invalid address or a network mismatch
+func decodeAddress(address string, params *chaincfg.Params) (btcutil.Address, error) {
+	addr, err := btcutil.DecodeAddress(address, params)
+	if err != nil {
+		for _, net := range knownNetworks {
+			if net == params {
+				continue
+			}
+			if other, otherErr := btcutil.DecodeAddress(address, net); otherErr == nil && other.IsForNet(net) {
+				return nil, newVerifyError(ErrNetworkMismatch, "address '%s' belongs to network '%s', not '%s'", address, net.Name, params.Name)
+			}
+		}
+		return nil, newVerifyError(ErrInvalidAddress, "could not decode address: %v", err)
+	}
+
+	if !addr.IsForNet(params) {
+		return nil, newVerifyError(ErrNetworkMismatch, "address '%s' is not valid for network '%s'", address, params.Name)
+	}
+
+	return addr, nil
+}
+
+// classifyVerifierError wraps an error returned by the BitonicNL verifier in
+// a VerifyError. The verifier only returns plain errors, so they are
+// classified by their message.
+func classifyVerifierError(err error) error {
+	var verifyErr *VerifyError
+	if errors.As(err, &verifyErr) {
+		return err
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "does not match expected address"):
+		return &VerifyError{Code: CodeAddressMismatch, Err: fmt.Errorf("%w: %w", ErrAddressMismatch, err)}
+	case strings.Contains(msg, "invalid recovery flag"), strings.Contains(msg, "cannot use"):
+		return &VerifyError{Code: CodeInvalidHeaderByte, Err: fmt.Errorf("%w: %w", ErrInvalidHeaderByte, err)}
+	case strings.Contains(msg, "unsupported address type"):
+		return &VerifyError{Code: CodeUnsupportedAddressType, Err: fmt.Errorf("%w: %w", ErrUnsupportedAddressType, err)}
+	case strings.Contains(msg, "wrong signature length"), strings.Contains(msg, "could not decode signature"):
+		return &VerifyError{Code: CodeMalformedSignature, Err: fmt.Errorf("%w: %w", ErrMalformedSignature, err)}
+	case strings.Contains(msg, "could not decode address"):
+		return &VerifyError{Code: CodeInvalidAddress, Err: fmt.Errorf("%w: %w", ErrInvalidAddress, err)}
+	default:
+		return &VerifyError{Code: CodeInvalidSignature, Err: fmt.Errorf("%w: %w", ErrInvalidSignature, err)}
+	}
+}
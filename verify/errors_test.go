@@ -0,0 +1,507 @@
+package verify
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestErrorCodeOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ErrorCode
+	}{
+		{name: "Nil error", err: nil, want: CodeUnknown},
+		{name: "Unrelated error", err: errors.New("boom"), want: CodeUnknown},
+		{name: "Bare sentinel", err: ErrEmptyMessage, want: CodeEmptyMessage},
+		{name: "Wrapped sentinel", err: fmt.Errorf("context: %w", ErrEmptySignature), want: CodeEmptySignature},
+		{name: "VerifyError", err: newVerifyError(ErrAddressMismatch, "details"), want: CodeAddressMismatch},
+		{
+			name: "Wrapped VerifyError",
+			err:  fmt.Errorf("signature verification error: %w", newVerifyError(ErrNetworkMismatch, "details")),
+			want: CodeNetworkMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCodeOf(tt.err); got != tt.want {
+				t.Errorf("ErrorCodeOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyErrorTaxonomy(t *testing.T) {
+	tests := []struct {
+		name      string
+		address   string
+		signature string
+		params    *chaincfg.Params
+		wantErr   error
+	}{
+		{
+			name:      "Address mismatch",
+			address:   "14wPe34dikRzK4tMYvtwMMJCEZbJ7ar35V",
+			signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			params:    &chaincfg.MainNetParams,
+			wantErr:   ErrAddressMismatch,
+		},
+		{
+			name:      "Network mismatch",
+			address:   "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+			signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			params:    &chaincfg.MainNetParams,
+			wantErr:   ErrNetworkMismatch,
+		},
+		{
+			name:      "Base58 network mismatch",
+			address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			params:    &chaincfg.TestNet3Params,
+			wantErr:   ErrNetworkMismatch,
+		},
+		{
+			name:      "Invalid address",
+			address:   "INVALID",
+			signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			params:    &chaincfg.MainNetParams,
+			wantErr:   ErrInvalidAddress,
+		},
+		{
+			name:      "Invalid base64",
+			address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			signature: "not base64",
+			params:    &chaincfg.MainNetParams,
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "Short signature",
+			address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			signature: "VGhpcyBpcyBub3QgdmFsaWQ=",
+			params:    &chaincfg.MainNetParams,
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "Invalid header byte",
+			address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			signature: "zPOBbkXzwDgGVU3Gxk0noVuLq8P1pGfQUxnS0nzuxEN3qR/U/s63P81io7LV04ZxN88gVX/Qw0rzLFBR8q4IkUc=",
+			params:    &chaincfg.MainNetParams,
+			wantErr:   ErrInvalidHeaderByte,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyBip137SignatureWithParams(tt.address, "test message", tt.signature, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyBip137SignatureWithParams() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var verifyErr *VerifyError
+			if !errors.As(err, &verifyErr) {
+				t.Fatalf("VerifyBip137SignatureWithParams() error = %v, want a *VerifyError", err)
+			}
+			if verifyErr.Code != codeOfSentinel(tt.wantErr) {
+				t.Errorf("VerifyError.Code = %v, want %v", verifyErr.Code, codeOfSentinel(tt.wantErr))
+			}
+
+			// The native engine classifies failures the same way
+			_, err = verifyBatchItem(spanScope{}, SignedMessage{Address: tt.address, Message: "test message", Signature: tt.signature}, newHashCache(magicHash, 0), tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("verifyBatchItem() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSentinelCoverage(t *testing.T) {
+	tv := walletTestVectors[2].msg
+	pubKey := mustParsePubKey(t, "034fafbb0673368ea3dcc7003a753c51bf240471c3a1b811491ba9f3480091e23c")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{
+			name:    "Empty address",
+			call:    func() error { _, err := VerifyBip137Signature("", tv.Message, tv.Signature); return err },
+			wantErr: ErrEmptyAddress,
+		},
+		{
+			name:    "Empty message",
+			call:    func() error { _, err := VerifyBip137Signature(tv.Address, "", tv.Signature); return err },
+			wantErr: ErrEmptyMessage,
+		},
+		{
+			name:    "Empty signature",
+			call:    func() error { _, err := VerifyBip137Signature(tv.Address, tv.Message, ""); return err },
+			wantErr: ErrEmptySignature,
+		},
+		{
+			name: "Empty address with context",
+			call: func() error {
+				_, err := VerifyBip137SignatureWithContext(context.Background(), SignedMessage{Message: tv.Message, Signature: tv.Signature})
+				return err
+			},
+			wantErr: ErrEmptyAddress,
+		},
+		{
+			name:    "Empty public key",
+			call:    func() error { _, err := VerifyBip137SignatureWithPubKey(nil, tv.Message, tv.Signature); return err },
+			wantErr: ErrEmptyPublicKey,
+		},
+		{
+			name: "Empty public key with params",
+			call: func() error {
+				_, err := VerifyBip137SignatureWithPubKeyAndParams(nil, tv.Message, tv.Signature, &chaincfg.MainNetParams)
+				return err
+			},
+			wantErr: ErrEmptyPublicKey,
+		},
+		{
+			name:    "Empty message with public key",
+			call:    func() error { _, err := VerifyBip137SignatureWithPubKey(pubKey, "", tv.Signature); return err },
+			wantErr: ErrEmptyMessage,
+		},
+		{
+			name:    "Empty signature with public key",
+			call:    func() error { _, err := VerifyBip137SignatureWithPubKey(pubKey, tv.Message, ""); return err },
+			wantErr: ErrEmptySignature,
+		},
+		{
+			name: "Invalid base64 with public key and params",
+			call: func() error {
+				_, err := VerifyBip137SignatureWithPubKeyAndParams(pubKey, tv.Message, "not base64", &chaincfg.MainNetParams)
+				return err
+			},
+			wantErr: ErrMalformedSignature,
+		},
+		{
+			name:    "Invalid address",
+			call:    func() error { _, err := VerifyBip137Signature("INVALID", tv.Message, tv.Signature); return err },
+			wantErr: ErrInvalidAddress,
+		},
+		{
+			name: "Network mismatch",
+			call: func() error {
+				_, err := VerifyBip137SignatureWithParams(tv.Address, tv.Message, tv.Signature, &chaincfg.TestNet3Params)
+				return err
+			},
+			wantErr: ErrNetworkMismatch,
+		},
+		{
+			name: "Address mismatch",
+			call: func() error {
+				_, err := VerifyBip137Signature(walletTestVectors[0].msg.Address, tv.Message, tv.Signature)
+				return err
+			},
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			name:    "Malformed signature",
+			call:    func() error { _, err := VerifyBip137Signature(tv.Address, tv.Message, "not base64"); return err },
+			wantErr: ErrMalformedSignature,
+		},
+		{
+			name: "Invalid header byte",
+			call: func() error {
+				return Explain(tv.Address, tv.Message, "zPOBbkXzwDgGVU3Gxk0noVuLq8P1pGfQUxnS0nzuxEN3qR/U/s63P81io7LV04ZxN88gVX/Qw0rzLFBR8q4IkUc=").Err
+			},
+			wantErr: ErrInvalidHeaderByte,
+		},
+		{
+			name: "Invalid signature",
+			call: func() error {
+				// R and S of zero can't be used to recover a public key
+				sig := base64.StdEncoding.EncodeToString(append([]byte{headerP2PKHCompressed}, make([]byte, 64)...))
+				return Explain(tv.Address, tv.Message, sig).Err
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name: "Unsupported address type",
+			call: func() error {
+				// P2WSH addresses can't sign messages with BIP-0137
+				return Explain("bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", tv.Message, tv.Signature).Err
+			},
+			wantErr: ErrUnsupportedAddressType,
+		},
+		{
+			name: "High S",
+			call: func() error {
+				msg := tv
+				msg.Signature = highSVariant(t, tv.Signature)
+				_, err := NewVerifier(WithRequireLowS()).Verify(msg)
+				return err
+			},
+			wantErr: ErrHighS,
+		},
+		{
+			name: "Header address mismatch",
+			call: func() error {
+				_, err := NewVerifier(WithStrictHeader()).Verify(walletTestVectors[6].msg)
+				return err
+			},
+			wantErr: ErrHeaderAddressMismatch,
+		},
+		{
+			name: "Message too large",
+			call: func() error {
+				_, err := NewVerifier(WithMaxMessageSize(1)).Verify(tv)
+				return err
+			},
+			wantErr: ErrMessageTooLarge,
+		},
+		{
+			name: "Verification timeout",
+			call: func() error {
+				_, err := VerifyBatch(cancelled, []SignedMessage{tv})
+				return err
+			},
+			wantErr: ErrVerificationTimeout,
+		},
+		{
+			name:    "Invalid armor",
+			call:    func() error { _, err := ParseArmored("not armored"); return err },
+			wantErr: ErrInvalidArmor,
+		},
+		{
+			name: "Malformed container",
+			call: func() error {
+				_, err := NewContainerReader(strings.NewReader("not a container\n"))
+				return err
+			},
+			wantErr: ErrMalformedContainer,
+		},
+		{
+			name: "Container closed",
+			call: func() error {
+				cw, err := NewContainerWriter(io.Discard)
+				if err != nil {
+					return err
+				}
+				cw.Close()
+				return cw.Write(tv)
+			},
+			wantErr: ErrContainerClosed,
+		},
+		{
+			name: "Self-check failed",
+			call: func() error {
+				return runSelfCheck([]selfCheckVector{{Name: "flipped", Network: "mainnet", Address: tv.Address, Message: tv.Message, Signature: tv.Signature}})
+			},
+			wantErr: ErrSelfCheckFailed,
+		},
+		{
+			name: "Engine disagreement",
+			call: func() error {
+				// The BitonicNL verifier only trims the message on mainnet
+				msg := SignedMessage{Address: "msgXyBvhCMiwsopsDU8D8KyUinbzp5AzHw", Message: "test message\n", Signature: walletTestVectors[2].msg.Signature}
+				_, err := NewVerifier(WithParams(&chaincfg.TestNet3Params), WithCrossCheck()).Verify(msg)
+				return err
+			},
+			wantErr: ErrEngineDisagreement,
+		},
+		{
+			name:    "Malformed JSON",
+			call:    func() error { _, err := ParseSignedMessageJSON([]byte("{")); return err },
+			wantErr: ErrMalformedJSON,
+		},
+		{
+			name: "Malformed detached signature",
+			call: func() error {
+				_, err := ReadDetachedSignature(strings.NewReader("not a signature"))
+				return err
+			},
+			wantErr: ErrMalformedDetachedSignature,
+		},
+		{
+			name: "Digest mismatch",
+			call: func() error {
+				sig := DetachedSignature{Address: tv.Address, Signature: tv.Signature, Digest: "sha256:" + strings.Repeat("00", 32)}
+				_, err := VerifyDetached(strings.NewReader(tv.Message), sig, &chaincfg.MainNetParams)
+				return err
+			},
+			wantErr: ErrDigestMismatch,
+		},
+		{
+			name: "Malformed QR payload",
+			call: func() error {
+				_, err := ParseQRPayload("bitcoin:" + tv.Address)
+				return err
+			},
+			wantErr: ErrMalformedQRPayload,
+		},
+		{
+			name: "Malformed bundle",
+			call: func() error {
+				_, err := ReadBundle(strings.NewReader("{}"))
+				return err
+			},
+			wantErr: ErrMalformedBundle,
+		},
+		{
+			name: "Conversion unsupported",
+			call: func() error {
+				_, err := ConvertToBIP322(walletTestVectors[3].msg, &chaincfg.MainNetParams)
+				return err
+			},
+			wantErr: ErrConversionUnsupported,
+		},
+		{
+			name: "Invalid extended key",
+			call: func() error {
+				_, _, err := VerifyAgainstXpub("xpub123", tv.Message, tv.Signature, 0)
+				return err
+			},
+			wantErr: ErrInvalidExtendedKey,
+		},
+		{
+			name: "Invalid descriptor",
+			call: func() error {
+				_, err := ParseDescriptor("wsh(pk(02))", &chaincfg.MainNetParams)
+				return err
+			},
+			wantErr: ErrInvalidDescriptor,
+		},
+		{
+			name: "Invalid derivation path",
+			call: func() error {
+				_, err := ParseDerivationPath("m/x")
+				return err
+			},
+			wantErr: ErrInvalidDerivationPath,
+		},
+		{
+			name: "Invalid public key",
+			call: func() error {
+				_, err := VerifyBip137SignatureWithXOnlyPubKey(make([]byte, 33), tv.Message, tv.Signature)
+				return err
+			},
+			wantErr: ErrInvalidPublicKey,
+		},
+		{
+			name: "Invalid keyring entry",
+			call: func() error {
+				return NewKeyring(&chaincfg.MainNetParams).Add(KeyringEntry{Address: tv.Address})
+			},
+			wantErr: ErrInvalidKeyringEntry,
+		},
+		{
+			name: "Untrusted signer",
+			call: func() error {
+				_, _, err := NewKeyring(&chaincfg.MainNetParams).Verify(tv.Message, tv.Signature)
+				return err
+			},
+			wantErr: ErrUntrustedSigner,
+		},
+		{
+			name: "Malformed keyring",
+			call: func() error {
+				_, err := ReadKeyring(strings.NewReader(`{"version":1,"network":"litecoin"}`))
+				return err
+			},
+			wantErr: ErrMalformedKeyring,
+		},
+		{
+			name: "Invalid multisig policy",
+			call: func() error {
+				_, err := VerifyMultisig(tv.Message, []string{tv.Signature}, []string{tv.Address}, 2, &chaincfg.MainNetParams)
+				return err
+			},
+			wantErr: ErrInvalidMultisigPolicy,
+		},
+		{
+			name: "Policy violation",
+			call: func() error {
+				_, err := NewVerifier(WithPolicy(Policy{AllowedNetworks: []string{"signet"}})).Verify(tv)
+				return err
+			},
+			wantErr: ErrPolicyViolation,
+		},
+		{
+			name: "Address blocked",
+			call: func() error {
+				_, err := NewVerifier(WithAddressFilter(AddressFilter{Deny: []string{tv.Address}})).Verify(tv)
+				return err
+			},
+			wantErr: ErrAddressBlocked,
+		},
+		{
+			name: "Invalid timestamp",
+			call: func() error {
+				_, err := MessageTimestamp("Timestamp: yesterday")
+				return err
+			},
+			wantErr: ErrInvalidTimestamp,
+		},
+		{
+			name: "Message expired",
+			call: func() error {
+				msg := tv
+				msg.Message = TimestampMessage(tv.Message, time.Now().Add(-time.Hour))
+				_, err := NewVerifier(WithMaxMessageAge(time.Minute)).Verify(msg)
+				return err
+			},
+			wantErr: ErrMessageExpired,
+		},
+		{
+			name: "Queue full",
+			call: func() error {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				q := NewQueue(WithQueueCapacity(1), WithQueueWorkers(1), WithQueueVerifier(blockingVerifier{}))
+				defer q.Stop(ctx)
+				var err error
+				for err == nil {
+					_, err = q.TrySubmit(tv)
+				}
+				return err
+			},
+			wantErr: ErrQueueFull,
+		},
+		{
+			name: "Queue stopped",
+			call: func() error {
+				q := NewQueue()
+				q.Stop(context.Background())
+				_, err := q.Submit(context.Background(), tv)
+				return err
+			},
+			wantErr: ErrQueueStopped,
+		},
+	}
+
+	covered := make(map[error]bool)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+			if code := ErrorCodeOf(err); code != codeOfSentinel(tt.wantErr) {
+				t.Errorf("ErrorCodeOf() = %v, want %v", code, codeOfSentinel(tt.wantErr))
+			}
+			covered[tt.wantErr] = true
+		})
+	}
+
+	// Every sentinel must be reachable from at least one failure path
+	for _, ec := range errorCodes {
+		if !covered[ec.err] {
+			t.Errorf("no failure path covers %v (%s)", ec.err, ec.code)
+		}
+	}
+}
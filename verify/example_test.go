@@ -30,7 +30,7 @@ func ExampleVerifyBip137Signature() {
 // using a public key directly.
 func ExampleVerifyBip137SignatureWithPubKey() {
 	// Public key in hex format
-	pubKeyHex := "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"
+	pubKeyHex := "034fafbb0673368ea3dcc7003a753c51bf240471c3a1b811491ba9f3480091e23c"
 	message := "Hello, Bitcoin testing!"
 	signature := "IOeVH/0KqgmS3XKwqCJiwlcHonwxKMQN6fbOW5UsXSDZB4EGCVTXx6c+ZU/Ae5qO94MSBZn2aPOiUsupRIwBaAU="
 
@@ -0,0 +1,130 @@
+package verify
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// FailureStage identifies the verification stage at which a signature failed
+type FailureStage string
+
+// Verification stages, in the order they are run
+const (
+	// StageNone means the signature passed every stage
+	StageNone FailureStage = ""
+
+	// StageInput covers missing address, message or signature
+	StageInput FailureStage = "input"
+
+	// StageDecoding covers decoding the address and the base64 signature
+	StageDecoding FailureStage = "decoding"
+
+	// StageRecovery covers recovering the public key from the signature
+	StageRecovery FailureStage = "recovery"
+
+	// StageDerivation covers deriving an address from the recovered key
+	StageDerivation FailureStage = "derivation"
+
+	// StageAddressMismatch means the derived address differs from the
+	// expected one: the signature is valid, but made by another key
+	StageAddressMismatch FailureStage = "address_mismatch"
+)
+
+// FailureReport explains the outcome of a signature verification, pointing
+// at the stage where it failed and at what was recovered up to that point
+type FailureReport struct {
+	// Valid reports whether the signature is valid
+	Valid bool
+
+	// Stage is the stage at which verification failed, StageNone if valid
+	Stage FailureStage
+
+	// Err is the error of the failing stage
+	Err error
+
+	// Code is the stable code of Err
+	Code ErrorCode
+
+	// ExpectedAddress is the address the signature was checked against
+	ExpectedAddress string
+
+	// HeaderByte is the signature header byte, if the signature decoded
+	HeaderByte byte
+
+	// RecoveredPubKey is the hex-encoded public key recovered from the
+	// signature, in the serialization the header byte asks for
+	RecoveredPubKey string
+
+	// Compressed reports whether the recovered public key is compressed
+	Compressed bool
+
+	// RecoveredAddress is the address of the expected type derived from the
+	// recovered public key
+	RecoveredAddress string
+}
+
+// String returns a human-readable summary of the report
+func (r FailureReport) String() string {
+	if r.Valid {
+		return fmt.Sprintf("signature is valid for %s", r.ExpectedAddress)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "verification failed at stage %q (%s): %v", r.Stage, r.Code, r.Err)
+	if r.RecoveredPubKey != "" {
+		fmt.Fprintf(&sb, "; recovered public key %s", r.RecoveredPubKey)
+	}
+	if r.RecoveredAddress != "" {
+		fmt.Fprintf(&sb, "; signed by %s instead of %s", r.RecoveredAddress, r.ExpectedAddress)
+	}
+	return sb.String()
+}
+
+// Explain verifies a signature using the Bitcoin mainnet parameters and
+// reports the exact stage at which it failed, including the address that
+// actually signed the message when the signature is valid for another key.
+func Explain(address, message, signatureBase64 string) FailureReport {
+	return ExplainWithParams(address, message, signatureBase64, &chaincfg.MainNetParams)
+}
+
+// ExplainWithParams is like Explain, using the provided network parameters.
+func ExplainWithParams(address, message, signatureBase64 string, params *chaincfg.Params) FailureReport {
+	report := FailureReport{ExpectedAddress: address}
+
+	switch {
+	case address == "":
+		return report.fail(StageInput, ErrEmptyAddress)
+	case message == "":
+		return report.fail(StageInput, ErrEmptyMessage)
+	case signatureBase64 == "":
+		return report.fail(StageInput, ErrEmptySignature)
+	}
+
+	if err := checkMessageSize(eventLogger{}, message, MaxMessageSize()); err != nil {
+		return report.fail(StageInput, err)
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return report.fail(StageDecoding, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err))
+	}
+
+	digest := magicHash(message)
+	report = explainDigest(eventLogger{}, spanScope{}, address, digest[:], sigBytes, params, TaprootKeyInternal)
+	if !report.Valid {
+		logEvent(LogLevelDebug, "Explained verification failure", "address", address, "network", params.Name,
+			"stage", report.Stage, "header_byte", report.HeaderByte, "recovered_address", report.RecoveredAddress, "error", report.Err)
+	}
+	return report
+}
+
+// fail records the failing stage and its error in the report
+func (r FailureReport) fail(stage FailureStage, err error) FailureReport {
+	r.Stage = stage
+	r.Err = err
+	r.Code = ErrorCodeOf(err)
+	return r
+}
@@ -0,0 +1,104 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	tests := []struct {
+		name             string
+		address          string
+		message          string
+		signature        string
+		wantStage        FailureStage
+		wantErr          error
+		wantRecoveredKey bool
+		wantRecovered    string
+	}{
+		{
+			name:             "Valid signature",
+			address:          "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			message:          "test message",
+			signature:        "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			wantStage:        StageNone,
+			wantRecoveredKey: true,
+			wantRecovered:    "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+		},
+		{
+			name:      "Empty message",
+			address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			wantStage: StageInput,
+			wantErr:   ErrEmptyMessage,
+		},
+		{
+			name:      "Invalid base64",
+			address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			message:   "test message",
+			signature: "not base64",
+			wantStage: StageDecoding,
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "Testnet address",
+			address:   "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+			message:   "test message",
+			signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			wantStage: StageDecoding,
+			wantErr:   ErrNetworkMismatch,
+		},
+		{
+			name:      "Invalid header byte",
+			address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			message:   "test message",
+			signature: "zPOBbkXzwDgGVU3Gxk0noVuLq8P1pGfQUxnS0nzuxEN3qR/U/s63P81io7LV04ZxN88gVX/Qw0rzLFBR8q4IkUc=",
+			wantStage: StageRecovery,
+			wantErr:   ErrInvalidHeaderByte,
+		},
+		{
+			name:             "SegWit header with P2PKH address",
+			address:          "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			message:          "test message",
+			signature:        "KFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			wantStage:        StageDerivation,
+			wantErr:          ErrInvalidHeaderByte,
+			wantRecoveredKey: true,
+		},
+		{
+			name:             "Signed by another address",
+			address:          "14wPe34dikRzK4tMYvtwMMJCEZbJ7ar35V",
+			message:          "test message",
+			signature:        "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			wantStage:        StageAddressMismatch,
+			wantErr:          ErrAddressMismatch,
+			wantRecoveredKey: true,
+			wantRecovered:    "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := Explain(tt.address, tt.message, tt.signature)
+
+			if report.Valid != (tt.wantStage == StageNone) {
+				t.Errorf("Explain().Valid = %v, want %v", report.Valid, tt.wantStage == StageNone)
+			}
+			if report.Stage != tt.wantStage {
+				t.Errorf("Explain().Stage = %q, want %q", report.Stage, tt.wantStage)
+			}
+			if tt.wantErr != nil && !errors.Is(report.Err, tt.wantErr) {
+				t.Errorf("Explain().Err = %v, want %v", report.Err, tt.wantErr)
+			}
+			if tt.wantErr != nil && report.Code != ErrorCodeOf(tt.wantErr) {
+				t.Errorf("Explain().Code = %v, want %v", report.Code, ErrorCodeOf(tt.wantErr))
+			}
+			if (report.RecoveredPubKey != "") != tt.wantRecoveredKey {
+				t.Errorf("Explain().RecoveredPubKey = %q, want recovered %v", report.RecoveredPubKey, tt.wantRecoveredKey)
+			}
+			if report.RecoveredAddress != tt.wantRecovered {
+				t.Errorf("Explain().RecoveredAddress = %q, want %q", report.RecoveredAddress, tt.wantRecovered)
+			}
+		})
+	}
+}
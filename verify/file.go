@@ -0,0 +1,92 @@
+package verify
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// FileMessage returns the message attesting to a file: its digest, as
+// returned by ArtifactDigest, followed by its base name, such as
+// "sha256:5f0a... backup.tar.gz". Signing the name too means a signature of
+// one file can't be passed off as a signature of a renamed copy.
+func FileMessage(digest, path string) string {
+	return digest + " " + filepath.Base(path)
+}
+
+// SignFile signs the message attesting to the file at path with the private
+// key, for an address of the given type. P2PKH, P2SH-P2WPKH and P2WPKH
+// addresses are supported; the public key is used in compressed form.
+// Nonces are derived per RFC 6979, so the signature of a file is always the
+// same.
+func SignFile(path string, privKey *btcec.PrivateKey, addrType AddressType, params *chaincfg.Params) (SignedMessage, error) {
+	message, err := fileMessage(path)
+	if err != nil {
+		return SignedMessage{}, err
+	}
+	return signMessage(privKey, message, addrType, params)
+}
+
+// VerifyFile verifies a signature of the message attesting to the file at
+// path, as produced by SignFile or by signing FileMessage with a wallet.
+func VerifyFile(path, address, signatureBase64 string, params *chaincfg.Params) (bool, error) {
+	message, err := fileMessage(path)
+	if err != nil {
+		logEvent(LogLevelError, "Failed to read file", "path", path, "error", err)
+		return false, err
+	}
+	return VerifyBip137SignatureWithParams(address, message, signatureBase64, params)
+}
+
+// fileMessage hashes the file at path into its attestation message
+func fileMessage(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	digest, err := ArtifactDigest(f)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return FileMessage(digest, path), nil
+}
+
+// signMessage produces a BIP-0137 signature of the message with the header
+// byte of the address type, and the address it verifies against
+func signMessage(privKey *btcec.PrivateKey, message string, addrType AddressType, params *chaincfg.Params) (SignedMessage, error) {
+	var header byte
+	switch addrType {
+	case AddressTypeP2PKH:
+		header = headerP2PKHCompressed
+	case AddressTypeP2SHP2WPKH:
+		header = headerP2SHP2WPKH
+	case AddressTypeP2WPKH:
+		header = headerP2WPKH
+	default:
+		return SignedMessage{}, newVerifyError(ErrUnsupportedAddressType, "cannot sign for %q addresses", addrType)
+	}
+	addr, err := pubKeyHashAddress(addrType, btcutil.Hash160(privKey.PubKey().SerializeCompressed()), params)
+	if err != nil {
+		return SignedMessage{}, err
+	}
+
+	digest := magicHash(message)
+	sig := ecdsa.SignCompact(privKey, digest[:], true)
+	// SignCompact sets the P2PKH compressed header, moved to the range of
+	// the address type keeping the recovery ID
+	sig[0] += header - headerP2PKHCompressed
+
+	return SignedMessage{
+		Address:   addr.EncodeAddress(),
+		Message:   message,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}, nil
+}
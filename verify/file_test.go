@@ -0,0 +1,86 @@
+package verify
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestFileMessage(t *testing.T) {
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	want := digest + " backup.tar.gz"
+	if got := FileMessage(digest, filepath.Join("var", "backups", "backup.tar.gz")); got != want {
+		t.Errorf("FileMessage() = %q, want %q", got, want)
+	}
+}
+
+func TestSignVerifyFile(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	privKey := testPrivKey("file test key")
+
+	dir := t.TempDir()
+	path := writeTestFile(t, dir, "backup.tar.gz", "backup content")
+
+	for _, addrType := range []AddressType{AddressTypeP2PKH, AddressTypeP2SHP2WPKH, AddressTypeP2WPKH} {
+		t.Run(string(addrType), func(t *testing.T) {
+			msg, err := SignFile(path, privKey, addrType, &chaincfg.MainNetParams)
+			if err != nil {
+				t.Fatalf("SignFile() error = %v", err)
+			}
+			if !strings.HasPrefix(msg.Message, "sha256:") || !strings.HasSuffix(msg.Message, " backup.tar.gz") {
+				t.Errorf("SignFile() message = %q, want the digest and file name", msg.Message)
+			}
+
+			valid, err := VerifyFile(path, msg.Address, msg.Signature, &chaincfg.MainNetParams)
+			if err != nil || !valid {
+				t.Errorf("VerifyFile() = %v, %v, want true, nil", valid, err)
+			}
+
+			_, info, err := VerifyBip137SignatureExWithParams(msg.Address, msg.Message, msg.Signature, &chaincfg.MainNetParams)
+			if err != nil || info.AddressType != addrType {
+				t.Errorf("VerifyBip137SignatureExWithParams() address type = %q, %v, want %q", info.AddressType, err, addrType)
+			}
+		})
+	}
+
+	msg, err := SignFile(path, privKey, AddressTypeP2WPKH, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		path    string
+		wantErr error
+	}{
+		{name: "Modified content", path: writeTestFile(t, t.TempDir(), "backup.tar.gz", "tampered content"), wantErr: ErrAddressMismatch},
+		{name: "Renamed copy", path: writeTestFile(t, dir, "other.tar.gz", "backup content"), wantErr: ErrAddressMismatch},
+		{name: "Missing file", path: filepath.Join(dir, "missing.tar.gz"), wantErr: os.ErrNotExist},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifyFile(tt.path, msg.Address, msg.Signature, &chaincfg.MainNetParams)
+			if valid || !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyFile() = %v, %v, want false, %v", valid, err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := SignFile(path, privKey, AddressTypeP2TR, &chaincfg.MainNetParams); !errors.Is(err, ErrUnsupportedAddressType) {
+		t.Errorf("SignFile() error = %v, want %v", err, ErrUnsupportedAddressType)
+	}
+}
+
+// Helper function to write a file with the given content, returning its path
+func writeTestFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,290 @@
+// Package verifygrpc exposes a verify.Verifier as a gRPC service, defined in
+// verify.proto, for microservices that prefer gRPC over the REST API of the
+// httpserver package:
+//
+//	s := grpc.NewServer()
+//	verifygrpc.RegisterVerificationServer(s, verifygrpc.NewServer())
+//
+// Clients are created with NewVerificationClient.
+package verifygrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative verify.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"runtime"
+	"sync"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/internal/drain"
+	"github.com/sero/btc/verify/ratelimit"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultMaxBatchSize is the default maximum number of messages of a
+// VerifyBatch request
+const DefaultMaxBatchSize = 1000
+
+// Server implements VerificationServer with a verify.Verifier
+type Server struct {
+	UnimplementedVerificationServer
+
+	verifier     *verify.Verifier
+	maxBatchSize int
+	ipLimit      *ratelimit.Limiter
+	addressLimit *ratelimit.Limiter
+
+	// inflight tracks the calls being served
+	inflight   drain.Group
+	onShutdown []func(context.Context) error
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithVerifier sets the verifier requests are verified with, by default a
+// verifier for mainnet created with verify.NewVerifier. Recover derives
+// addresses for the network of the verifier and applies its message size
+// limit and hash scheme.
+func WithVerifier(v *verify.Verifier) Option {
+	return func(s *Server) {
+		s.verifier = v
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of messages of a VerifyBatch
+// request. Larger batches are rejected with codes.InvalidArgument.
+func WithMaxBatchSize(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxBatchSize = n
+		}
+	}
+}
+
+// WithIPRateLimit limits the verifications and recoveries per client IP. A
+// batch counts one verification per message, so batches larger than the
+// burst of the limiter fail with codes.InvalidArgument. Calls over the limit
+// fail with codes.ResourceExhausted.
+func WithIPRateLimit(l *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.ipLimit = l
+	}
+}
+
+// WithAddressRateLimit limits the verifications per address to verify.
+// Verify calls over the limit fail with codes.ResourceExhausted; messages of
+// a batch over the limit get a result with the code rate_limited.
+func WithAddressRateLimit(l *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.addressLimit = l
+	}
+}
+
+// WithShutdownHook adds a function Shutdown calls once the calls in flight
+// have completed, to flush metrics or audit buffers fed by the verifier.
+// Hooks are called in the order they were added.
+func WithShutdownHook(f func(ctx context.Context) error) Option {
+	return func(s *Server) {
+		s.onShutdown = append(s.onShutdown, f)
+	}
+}
+
+// NewServer creates a Server
+func NewServer(opts ...Option) *Server {
+	s := &Server{maxBatchSize: DefaultMaxBatchSize}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.verifier == nil {
+		s.verifier = verify.NewVerifier()
+	}
+	return s
+}
+
+// Shutdown shuts the service down gracefully, for rolling restarts: calls
+// arriving from now on fail with codes.Unavailable, so clients retry them on
+// another instance. When every call in flight has completed, the shutdown
+// hooks are called.
+//
+// If ctx is done before the calls in flight complete, Shutdown returns the
+// error of the context without calling the hooks. Call it before
+// grpc.Server.GracefulStop, which closes the listeners and connections.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if err := s.inflight.Wait(ctx); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range s.onShutdown {
+		if err := f(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// begin starts serving a call, failing with codes.Unavailable once the
+// service is shutting down. Calls begun must end with s.inflight.End.
+func (s *Server) begin() error {
+	if !s.inflight.Begin() {
+		return status.Error(codes.Unavailable, "server is shutting down")
+	}
+	return nil
+}
+
+// Verify verifies a signed message
+func (s *Server) Verify(ctx context.Context, req *VerifyRequest) (*VerifyResponse, error) {
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+	defer s.inflight.End()
+
+	if !s.ipLimit.Allow(clientIP(ctx)) {
+		return nil, status.Error(codes.ResourceExhausted, "too many requests from this client")
+	}
+	if !s.addressLimit.Allow(req.GetAddress()) {
+		return nil, status.Error(codes.ResourceExhausted, "too many requests for this address")
+	}
+	return s.verify(ctx, req), nil
+}
+
+// VerifyBatch verifies the messages of a batch concurrently
+func (s *Server) VerifyBatch(ctx context.Context, req *VerifyBatchRequest) (*VerifyBatchResponse, error) {
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+	defer s.inflight.End()
+
+	if len(req.GetMessages()) > s.maxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "batch has %d messages, the limit is %d", len(req.GetMessages()), s.maxBatchSize)
+	}
+	if s.ipLimit != nil && len(req.GetMessages()) > s.ipLimit.Burst() {
+		return nil, status.Errorf(codes.InvalidArgument, "batch has %d messages, the rate limit allows %d at once", len(req.GetMessages()), s.ipLimit.Burst())
+	}
+	if !s.ipLimit.AllowN(clientIP(ctx), len(req.GetMessages())) {
+		return nil, status.Error(codes.ResourceExhausted, "too many requests from this client")
+	}
+
+	resp := &VerifyBatchResponse{Results: make([]*VerifyResponse, len(req.GetMessages()))}
+	workers := min(runtime.GOMAXPROCS(0), len(req.GetMessages()))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				msg := req.GetMessages()[i]
+				if !s.addressLimit.Allow(msg.GetAddress()) {
+					resp.Results[i] = &VerifyResponse{Code: string(ratelimit.CodeRateLimited), Error: "too many requests for this address"}
+					continue
+				}
+				resp.Results[i] = s.verify(ctx, msg)
+			}
+		}()
+	}
+	for i := range req.GetMessages() {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, result := range resp.Results {
+		if result.Valid {
+			resp.Valid++
+		} else {
+			resp.Invalid++
+		}
+	}
+	return resp, nil
+}
+
+// Recover recovers the public key and address that signed a message
+func (s *Server) Recover(ctx context.Context, req *RecoverRequest) (*RecoverResponse, error) {
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+	defer s.inflight.End()
+
+	if !s.ipLimit.Allow(clientIP(ctx)) {
+		return nil, status.Error(codes.ResourceExhausted, "too many requests from this client")
+	}
+
+	rec, err := s.verifier.Recover(req.GetMessage(), req.GetSignature())
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &RecoverResponse{
+		Pubkey:      rec.PubKey,
+		Compressed:  rec.Compressed,
+		AddressType: string(rec.AddressType),
+		Address:     rec.Address,
+	}, nil
+}
+
+// Inspect decodes a compact signature. Signatures with a header byte outside
+// the BIP-0137 ranges are returned with an empty address type.
+func (s *Server) Inspect(ctx context.Context, req *InspectRequest) (*InspectResponse, error) {
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+	defer s.inflight.End()
+
+	sig, err := verify.DecodeCompactSignature(req.GetSignature())
+	if err != nil && !errors.Is(err, verify.ErrInvalidHeaderByte) {
+		return nil, statusOf(err)
+	}
+	return &InspectResponse{
+		HeaderByte:  uint32(sig.HeaderByte),
+		RecoveryId:  uint32(sig.RecoveryID),
+		Compressed:  sig.Compressed,
+		AddressType: string(sig.AddressType),
+		R:           sig.R,
+		S:           sig.S,
+		LowS:        sig.LowS,
+	}, nil
+}
+
+// verify verifies a signed message, reporting failures in the response
+func (s *Server) verify(ctx context.Context, req *VerifyRequest) *VerifyResponse {
+	result, err := s.verifier.VerifyContext(ctx, verify.SignedMessage{
+		Address:   req.GetAddress(),
+		Message:   req.GetMessage(),
+		Signature: req.GetSignature(),
+	})
+
+	resp := &VerifyResponse{Valid: err == nil && result.Valid}
+	switch {
+	case err != nil:
+		resp.Code = string(verify.ErrorCodeOf(err))
+		resp.Error = err.Error()
+	case !result.Valid:
+		resp.Code = string(verify.CodeInvalidSignature)
+	}
+	return resp
+}
+
+// clientIP returns the IP address of the client of a call
+func clientIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// statusOf converts a verification error to a gRPC status carrying its
+// error code
+func statusOf(err error) error {
+	return status.Error(codes.InvalidArgument, fmt.Sprintf("%s: %v", verify.ErrorCodeOf(err), err))
+}
@@ -0,0 +1,233 @@
+package verifygrpc
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/ratelimit"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testAddress   = "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"
+	testSignature = "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="
+)
+
+func TestVerify(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	tests := []struct {
+		name      string
+		req       *VerifyRequest
+		wantValid bool
+		wantCode  verify.ErrorCode
+	}{
+		{
+			name:      "Valid signature",
+			req:       &VerifyRequest{Address: testAddress, Message: "test message", Signature: testSignature},
+			wantValid: true,
+		},
+		{
+			name:     "Invalid signature",
+			req:      &VerifyRequest{Address: testAddress, Message: "other message", Signature: testSignature},
+			wantCode: verify.CodeAddressMismatch,
+		},
+		{
+			name:     "Missing signature",
+			req:      &VerifyRequest{Address: testAddress, Message: "test message"},
+			wantCode: verify.CodeEmptySignature,
+		},
+	}
+
+	client := newTestClient(t, NewServer())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := client.Verify(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if got.Valid != tt.wantValid || got.Code != string(tt.wantCode) {
+				t.Errorf("Verify() = %v, want valid %v and code %q", got, tt.wantValid, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	client := newTestClient(t, NewServer(WithMaxBatchSize(2)))
+
+	got, err := client.VerifyBatch(context.Background(), &VerifyBatchRequest{Messages: []*VerifyRequest{
+		{Address: testAddress, Message: "test message", Signature: testSignature},
+		{Address: testAddress, Message: "other message", Signature: testSignature},
+	}})
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if got.Valid != 1 || got.Invalid != 1 || len(got.Results) != 2 {
+		t.Errorf("VerifyBatch() = %v, want 1 valid and 1 invalid result", got)
+	}
+	if !got.Results[0].Valid || got.Results[1].Valid {
+		t.Errorf("VerifyBatch() results = %v, want results in request order", got.Results)
+	}
+
+	_, err = client.VerifyBatch(context.Background(), &VerifyBatchRequest{Messages: make([]*VerifyRequest, 3)})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("VerifyBatch() with too many messages error = %v, want %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	client := newTestClient(t, NewServer(WithIPRateLimit(ratelimit.PerMinute(3)), WithAddressRateLimit(ratelimit.PerMinute(1))))
+	req := &VerifyRequest{Address: testAddress, Message: "test message", Signature: testSignature}
+
+	// A batch over the burst fails without using up the limit
+	if _, err := client.VerifyBatch(context.Background(), &VerifyBatchRequest{Messages: []*VerifyRequest{req, req, req, req}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("VerifyBatch() over the burst error = %v, want %v", err, codes.InvalidArgument)
+	}
+
+	got, err := client.VerifyBatch(context.Background(), &VerifyBatchRequest{Messages: []*VerifyRequest{req, req}})
+	if err != nil {
+		t.Fatalf("VerifyBatch() error = %v", err)
+	}
+	if got.Valid != 1 || got.Results[0].Code != string(ratelimit.CodeRateLimited) && got.Results[1].Code != string(ratelimit.CodeRateLimited) {
+		t.Errorf("VerifyBatch() = %v, want the repeated address rate limited", got)
+	}
+
+	if _, err := client.Verify(context.Background(), req); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Verify() over the address limit error = %v, want %v", err, codes.ResourceExhausted)
+	}
+	if _, err := client.Verify(context.Background(), &VerifyRequest{Address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("Verify() over the IP limit error = %v, want %v", err, codes.ResourceExhausted)
+	}
+}
+
+func TestRecover(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	client := newTestClient(t, NewServer())
+
+	got, err := client.Recover(context.Background(), &RecoverRequest{Message: "test message", Signature: testSignature})
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if got.Address != testAddress || got.AddressType != string(verify.AddressTypeP2PKH) {
+		t.Errorf("Recover() = %v, want address %s", got, testAddress)
+	}
+
+	_, err = client.Recover(context.Background(), &RecoverRequest{Message: "test message", Signature: "not base64"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Recover() with malformed signature error = %v, want %v", err, codes.InvalidArgument)
+	}
+
+	limited := newTestClient(t, NewServer(WithVerifier(verify.NewVerifier(verify.WithMaxMessageSize(4)))))
+	_, err = limited.Recover(context.Background(), &RecoverRequest{Message: "test message", Signature: testSignature})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(status.Convert(err).Message(), string(verify.CodeMessageTooLarge)) {
+		t.Errorf("Recover() over the verifier's size limit error = %v, want %v with code %s", err, codes.InvalidArgument, verify.CodeMessageTooLarge)
+	}
+}
+
+func TestInspect(t *testing.T) {
+	client := newTestClient(t, NewServer())
+
+	got, err := client.Inspect(context.Background(), &InspectRequest{Signature: testSignature})
+	if err != nil {
+		t.Fatalf("Inspect() error = %v", err)
+	}
+	if got.HeaderByte != 32 || got.RecoveryId != 1 || !got.Compressed || got.AddressType != string(verify.AddressTypeP2PKH) {
+		t.Errorf("Inspect() = %v, want compressed P2PKH header 32", got)
+	}
+
+	_, err = client.Inspect(context.Background(), &InspectRequest{Signature: "AAAA"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Inspect() with short signature error = %v, want %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	v := verify.NewVerifier(verify.WithHooks(verify.Hooks{
+		OnStart: func(ctx context.Context, msg verify.SignedMessage) {
+			close(started)
+			<-release
+		},
+	}))
+	var flushed atomic.Int32
+	s := NewServer(WithVerifier(v), WithShutdownHook(func(ctx context.Context) error {
+		flushed.Add(1)
+		return nil
+	}))
+	client := newTestClient(t, s)
+
+	inflight := make(chan error)
+	go func() {
+		_, err := client.Verify(context.Background(), &VerifyRequest{Address: testAddress, Message: "test message", Signature: testSignature})
+		inflight <- err
+	}()
+	<-started
+
+	shutdown := make(chan error)
+	go func() {
+		shutdown <- s.Shutdown(context.Background())
+	}()
+	for !s.inflight.Closed() {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := client.Inspect(context.Background(), &InspectRequest{Signature: testSignature})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Inspect() during shutdown error = %v, want %v", err, codes.Unavailable)
+	}
+	if flushed.Load() != 0 {
+		t.Error("shutdown hook called before the calls in flight completed")
+	}
+
+	close(release)
+	if err := <-inflight; err != nil {
+		t.Errorf("Verify() in flight error = %v", err)
+	}
+	if err := <-shutdown; err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if n := flushed.Load(); n != 1 {
+		t.Errorf("shutdown hook called %d times, want once", n)
+	}
+}
+
+// Helper function to serve s over an in-memory listener and connect a client
+func newTestClient(t *testing.T, s *Server) VerificationClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	RegisterVerificationServer(srv, s)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("grpc.NewClient() error = %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewVerificationClient(conn)
+}
@@ -0,0 +1,625 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: verify.proto
+
+package verifygrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type VerifyRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Address string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Message string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Base64-encoded signature.
+	Signature     string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyRequest) Reset() {
+	*x = VerifyRequest{}
+	mi := &file_verify_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyRequest) ProtoMessage() {}
+
+func (x *VerifyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyRequest.ProtoReflect.Descriptor instead.
+func (*VerifyRequest) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *VerifyRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *VerifyRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type VerifyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Valid bool                   `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+	// Error code of an invalid signature, such as "address_mismatch".
+	Code          string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Error         string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyResponse) Reset() {
+	*x = VerifyResponse{}
+	mi := &file_verify_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyResponse) ProtoMessage() {}
+
+func (x *VerifyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyResponse.ProtoReflect.Descriptor instead.
+func (*VerifyResponse) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *VerifyResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+func (x *VerifyResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *VerifyResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type VerifyBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Messages      []*VerifyRequest       `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyBatchRequest) Reset() {
+	*x = VerifyBatchRequest{}
+	mi := &file_verify_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBatchRequest) ProtoMessage() {}
+
+func (x *VerifyBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBatchRequest.ProtoReflect.Descriptor instead.
+func (*VerifyBatchRequest) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifyBatchRequest) GetMessages() []*VerifyRequest {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+type VerifyBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*VerifyResponse      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	Valid         int32                  `protobuf:"varint,2,opt,name=valid,proto3" json:"valid,omitempty"`
+	Invalid       int32                  `protobuf:"varint,3,opt,name=invalid,proto3" json:"invalid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyBatchResponse) Reset() {
+	*x = VerifyBatchResponse{}
+	mi := &file_verify_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyBatchResponse) ProtoMessage() {}
+
+func (x *VerifyBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyBatchResponse.ProtoReflect.Descriptor instead.
+func (*VerifyBatchResponse) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *VerifyBatchResponse) GetResults() []*VerifyResponse {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *VerifyBatchResponse) GetValid() int32 {
+	if x != nil {
+		return x.Valid
+	}
+	return 0
+}
+
+func (x *VerifyBatchResponse) GetInvalid() int32 {
+	if x != nil {
+		return x.Invalid
+	}
+	return 0
+}
+
+type RecoverRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// Base64-encoded 65-byte compact signature.
+	Signature     string `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecoverRequest) Reset() {
+	*x = RecoverRequest{}
+	mi := &file_verify_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecoverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecoverRequest) ProtoMessage() {}
+
+func (x *RecoverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecoverRequest.ProtoReflect.Descriptor instead.
+func (*RecoverRequest) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RecoverRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *RecoverRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type RecoverResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Hex-encoded public key, in the serialization the header byte asks for.
+	Pubkey     string `protobuf:"bytes,1,opt,name=pubkey,proto3" json:"pubkey,omitempty"`
+	Compressed bool   `protobuf:"varint,2,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	// Address type the header byte stands for, such as "p2wpkh".
+	AddressType   string `protobuf:"bytes,3,opt,name=address_type,json=addressType,proto3" json:"address_type,omitempty"`
+	Address       string `protobuf:"bytes,4,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecoverResponse) Reset() {
+	*x = RecoverResponse{}
+	mi := &file_verify_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecoverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecoverResponse) ProtoMessage() {}
+
+func (x *RecoverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecoverResponse.ProtoReflect.Descriptor instead.
+func (*RecoverResponse) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RecoverResponse) GetPubkey() string {
+	if x != nil {
+		return x.Pubkey
+	}
+	return ""
+}
+
+func (x *RecoverResponse) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+func (x *RecoverResponse) GetAddressType() string {
+	if x != nil {
+		return x.AddressType
+	}
+	return ""
+}
+
+func (x *RecoverResponse) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type InspectRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Base64-encoded 65-byte compact signature.
+	Signature     string `protobuf:"bytes,1,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectRequest) Reset() {
+	*x = InspectRequest{}
+	mi := &file_verify_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectRequest) ProtoMessage() {}
+
+func (x *InspectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectRequest.ProtoReflect.Descriptor instead.
+func (*InspectRequest) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *InspectRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type InspectResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	HeaderByte uint32                 `protobuf:"varint,1,opt,name=header_byte,json=headerByte,proto3" json:"header_byte,omitempty"`
+	RecoveryId uint32                 `protobuf:"varint,2,opt,name=recovery_id,json=recoveryId,proto3" json:"recovery_id,omitempty"`
+	Compressed bool                   `protobuf:"varint,3,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	// Address type the header byte stands for, empty for header bytes outside
+	// the BIP-0137 ranges.
+	AddressType string `protobuf:"bytes,4,opt,name=address_type,json=addressType,proto3" json:"address_type,omitempty"`
+	// Hex-encoded R and S values.
+	R             string `protobuf:"bytes,5,opt,name=r,proto3" json:"r,omitempty"`
+	S             string `protobuf:"bytes,6,opt,name=s,proto3" json:"s,omitempty"`
+	LowS          bool   `protobuf:"varint,7,opt,name=low_s,json=lowS,proto3" json:"low_s,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectResponse) Reset() {
+	*x = InspectResponse{}
+	mi := &file_verify_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectResponse) ProtoMessage() {}
+
+func (x *InspectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_verify_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectResponse.ProtoReflect.Descriptor instead.
+func (*InspectResponse) Descriptor() ([]byte, []int) {
+	return file_verify_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *InspectResponse) GetHeaderByte() uint32 {
+	if x != nil {
+		return x.HeaderByte
+	}
+	return 0
+}
+
+func (x *InspectResponse) GetRecoveryId() uint32 {
+	if x != nil {
+		return x.RecoveryId
+	}
+	return 0
+}
+
+func (x *InspectResponse) GetCompressed() bool {
+	if x != nil {
+		return x.Compressed
+	}
+	return false
+}
+
+func (x *InspectResponse) GetAddressType() string {
+	if x != nil {
+		return x.AddressType
+	}
+	return ""
+}
+
+func (x *InspectResponse) GetR() string {
+	if x != nil {
+		return x.R
+	}
+	return ""
+}
+
+func (x *InspectResponse) GetS() string {
+	if x != nil {
+		return x.S
+	}
+	return ""
+}
+
+func (x *InspectResponse) GetLowS() bool {
+	if x != nil {
+		return x.LowS
+	}
+	return false
+}
+
+var File_verify_proto protoreflect.FileDescriptor
+
+const file_verify_proto_rawDesc = "" +
+	"\n" +
+	"\fverify.proto\x12\tbip137.v1\"a\n" +
+	"\rVerifyRequest\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\tsignature\x18\x03 \x01(\tR\tsignature\"P\n" +
+	"\x0eVerifyResponse\x12\x14\n" +
+	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"J\n" +
+	"\x12VerifyBatchRequest\x124\n" +
+	"\bmessages\x18\x01 \x03(\v2\x18.bip137.v1.VerifyRequestR\bmessages\"z\n" +
+	"\x13VerifyBatchResponse\x123\n" +
+	"\aresults\x18\x01 \x03(\v2\x19.bip137.v1.VerifyResponseR\aresults\x12\x14\n" +
+	"\x05valid\x18\x02 \x01(\x05R\x05valid\x12\x18\n" +
+	"\ainvalid\x18\x03 \x01(\x05R\ainvalid\"H\n" +
+	"\x0eRecoverRequest\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12\x1c\n" +
+	"\tsignature\x18\x02 \x01(\tR\tsignature\"\x86\x01\n" +
+	"\x0fRecoverResponse\x12\x16\n" +
+	"\x06pubkey\x18\x01 \x01(\tR\x06pubkey\x12\x1e\n" +
+	"\n" +
+	"compressed\x18\x02 \x01(\bR\n" +
+	"compressed\x12!\n" +
+	"\faddress_type\x18\x03 \x01(\tR\vaddressType\x12\x18\n" +
+	"\aaddress\x18\x04 \x01(\tR\aaddress\".\n" +
+	"\x0eInspectRequest\x12\x1c\n" +
+	"\tsignature\x18\x01 \x01(\tR\tsignature\"\xc7\x01\n" +
+	"\x0fInspectResponse\x12\x1f\n" +
+	"\vheader_byte\x18\x01 \x01(\rR\n" +
+	"headerByte\x12\x1f\n" +
+	"\vrecovery_id\x18\x02 \x01(\rR\n" +
+	"recoveryId\x12\x1e\n" +
+	"\n" +
+	"compressed\x18\x03 \x01(\bR\n" +
+	"compressed\x12!\n" +
+	"\faddress_type\x18\x04 \x01(\tR\vaddressType\x12\f\n" +
+	"\x01r\x18\x05 \x01(\tR\x01r\x12\f\n" +
+	"\x01s\x18\x06 \x01(\tR\x01s\x12\x13\n" +
+	"\x05low_s\x18\a \x01(\bR\x04lowS2\x9f\x02\n" +
+	"\fVerification\x12=\n" +
+	"\x06Verify\x12\x18.bip137.v1.VerifyRequest\x1a\x19.bip137.v1.VerifyResponse\x12L\n" +
+	"\vVerifyBatch\x12\x1d.bip137.v1.VerifyBatchRequest\x1a\x1e.bip137.v1.VerifyBatchResponse\x12@\n" +
+	"\aRecover\x12\x19.bip137.v1.RecoverRequest\x1a\x1a.bip137.v1.RecoverResponse\x12@\n" +
+	"\aInspect\x12\x19.bip137.v1.InspectRequest\x1a\x1a.bip137.v1.InspectResponseB,Z*github.com/sero/btc/verify/grpc;verifygrpcb\x06proto3"
+
+var (
+	file_verify_proto_rawDescOnce sync.Once
+	file_verify_proto_rawDescData []byte
+)
+
+func file_verify_proto_rawDescGZIP() []byte {
+	file_verify_proto_rawDescOnce.Do(func() {
+		file_verify_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_verify_proto_rawDesc), len(file_verify_proto_rawDesc)))
+	})
+	return file_verify_proto_rawDescData
+}
+
+var file_verify_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_verify_proto_goTypes = []any{
+	(*VerifyRequest)(nil),       // 0: bip137.v1.VerifyRequest
+	(*VerifyResponse)(nil),      // 1: bip137.v1.VerifyResponse
+	(*VerifyBatchRequest)(nil),  // 2: bip137.v1.VerifyBatchRequest
+	(*VerifyBatchResponse)(nil), // 3: bip137.v1.VerifyBatchResponse
+	(*RecoverRequest)(nil),      // 4: bip137.v1.RecoverRequest
+	(*RecoverResponse)(nil),     // 5: bip137.v1.RecoverResponse
+	(*InspectRequest)(nil),      // 6: bip137.v1.InspectRequest
+	(*InspectResponse)(nil),     // 7: bip137.v1.InspectResponse
+}
+var file_verify_proto_depIdxs = []int32{
+	0, // 0: bip137.v1.VerifyBatchRequest.messages:type_name -> bip137.v1.VerifyRequest
+	1, // 1: bip137.v1.VerifyBatchResponse.results:type_name -> bip137.v1.VerifyResponse
+	0, // 2: bip137.v1.Verification.Verify:input_type -> bip137.v1.VerifyRequest
+	2, // 3: bip137.v1.Verification.VerifyBatch:input_type -> bip137.v1.VerifyBatchRequest
+	4, // 4: bip137.v1.Verification.Recover:input_type -> bip137.v1.RecoverRequest
+	6, // 5: bip137.v1.Verification.Inspect:input_type -> bip137.v1.InspectRequest
+	1, // 6: bip137.v1.Verification.Verify:output_type -> bip137.v1.VerifyResponse
+	3, // 7: bip137.v1.Verification.VerifyBatch:output_type -> bip137.v1.VerifyBatchResponse
+	5, // 8: bip137.v1.Verification.Recover:output_type -> bip137.v1.RecoverResponse
+	7, // 9: bip137.v1.Verification.Inspect:output_type -> bip137.v1.InspectResponse
+	6, // [6:10] is the sub-list for method output_type
+	2, // [2:6] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_verify_proto_init() }
+func file_verify_proto_init() {
+	if File_verify_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_verify_proto_rawDesc), len(file_verify_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_verify_proto_goTypes,
+		DependencyIndexes: file_verify_proto_depIdxs,
+		MessageInfos:      file_verify_proto_msgTypes,
+	}.Build()
+	File_verify_proto = out.File
+	file_verify_proto_goTypes = nil
+	file_verify_proto_depIdxs = nil
+}
@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: verify.proto
+
+package verifygrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Verification_Verify_FullMethodName      = "/bip137.v1.Verification/Verify"
+	Verification_VerifyBatch_FullMethodName = "/bip137.v1.Verification/VerifyBatch"
+	Verification_Recover_FullMethodName     = "/bip137.v1.Verification/Recover"
+	Verification_Inspect_FullMethodName     = "/bip137.v1.Verification/Inspect"
+)
+
+// VerificationClient is the client API for Verification service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Verification verifies and decodes signed messages. Verdicts, invalid
+// signatures included, are returned in the response with an error code;
+// RPC errors are reserved for requests that can't be processed at all.
+type VerificationClient interface {
+	// Verify verifies a signed message.
+	Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error)
+	// VerifyBatch verifies several signed messages, returning one result per
+	// message in request order.
+	VerifyBatch(ctx context.Context, in *VerifyBatchRequest, opts ...grpc.CallOption) (*VerifyBatchResponse, error)
+	// Recover recovers the public key and address that signed a message with
+	// a compact signature.
+	Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*RecoverResponse, error)
+	// Inspect decodes a compact signature without verifying it.
+	Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error)
+}
+
+type verificationClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewVerificationClient(cc grpc.ClientConnInterface) VerificationClient {
+	return &verificationClient{cc}
+}
+
+func (c *verificationClient) Verify(ctx context.Context, in *VerifyRequest, opts ...grpc.CallOption) (*VerifyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyResponse)
+	err := c.cc.Invoke(ctx, Verification_Verify_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationClient) VerifyBatch(ctx context.Context, in *VerifyBatchRequest, opts ...grpc.CallOption) (*VerifyBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyBatchResponse)
+	err := c.cc.Invoke(ctx, Verification_VerifyBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationClient) Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*RecoverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RecoverResponse)
+	err := c.cc.Invoke(ctx, Verification_Recover_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *verificationClient) Inspect(ctx context.Context, in *InspectRequest, opts ...grpc.CallOption) (*InspectResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InspectResponse)
+	err := c.cc.Invoke(ctx, Verification_Inspect_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// VerificationServer is the server API for Verification service.
+// All implementations must embed UnimplementedVerificationServer
+// for forward compatibility.
+//
+// Verification verifies and decodes signed messages. Verdicts, invalid
+// signatures included, are returned in the response with an error code;
+// RPC errors are reserved for requests that can't be processed at all.
+type VerificationServer interface {
+	// Verify verifies a signed message.
+	Verify(context.Context, *VerifyRequest) (*VerifyResponse, error)
+	// VerifyBatch verifies several signed messages, returning one result per
+	// message in request order.
+	VerifyBatch(context.Context, *VerifyBatchRequest) (*VerifyBatchResponse, error)
+	// Recover recovers the public key and address that signed a message with
+	// a compact signature.
+	Recover(context.Context, *RecoverRequest) (*RecoverResponse, error)
+	// Inspect decodes a compact signature without verifying it.
+	Inspect(context.Context, *InspectRequest) (*InspectResponse, error)
+	mustEmbedUnimplementedVerificationServer()
+}
+
+// UnimplementedVerificationServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedVerificationServer struct{}
+
+func (UnimplementedVerificationServer) Verify(context.Context, *VerifyRequest) (*VerifyResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedVerificationServer) VerifyBatch(context.Context, *VerifyBatchRequest) (*VerifyBatchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method VerifyBatch not implemented")
+}
+func (UnimplementedVerificationServer) Recover(context.Context, *RecoverRequest) (*RecoverResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Recover not implemented")
+}
+func (UnimplementedVerificationServer) Inspect(context.Context, *InspectRequest) (*InspectResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Inspect not implemented")
+}
+func (UnimplementedVerificationServer) mustEmbedUnimplementedVerificationServer() {}
+func (UnimplementedVerificationServer) testEmbeddedByValue()                      {}
+
+// UnsafeVerificationServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to VerificationServer will
+// result in compilation errors.
+type UnsafeVerificationServer interface {
+	mustEmbedUnimplementedVerificationServer()
+}
+
+func RegisterVerificationServer(s grpc.ServiceRegistrar, srv VerificationServer) {
+	// If the following call panics, it indicates UnimplementedVerificationServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Verification_ServiceDesc, srv)
+}
+
+func _Verification_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Verification_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServer).Verify(ctx, req.(*VerifyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Verification_VerifyBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServer).VerifyBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Verification_VerifyBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServer).VerifyBatch(ctx, req.(*VerifyBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Verification_Recover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServer).Recover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Verification_Recover_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServer).Recover(ctx, req.(*RecoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Verification_Inspect_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VerificationServer).Inspect(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Verification_Inspect_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VerificationServer).Inspect(ctx, req.(*InspectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Verification_ServiceDesc is the grpc.ServiceDesc for Verification service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Verification_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "bip137.v1.Verification",
+	HandlerType: (*VerificationServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Verify",
+			Handler:    _Verification_Verify_Handler,
+		},
+		{
+			MethodName: "VerifyBatch",
+			Handler:    _Verification_VerifyBatch_Handler,
+		},
+		{
+			MethodName: "Recover",
+			Handler:    _Verification_Recover_Handler,
+		},
+		{
+			MethodName: "Inspect",
+			Handler:    _Verification_Inspect_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "verify.proto",
+}
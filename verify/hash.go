@@ -0,0 +1,101 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"sync"
+)
+
+// bitcoinMessagePrefix is the magic prefix prepended to every signed message
+const bitcoinMessagePrefix = "Bitcoin Signed Message:\n"
+
+// maxPooledBufferSize caps the capacity of buffers returned to the pool, so a
+// single huge message doesn't pin a large allocation for the process lifetime.
+const maxPooledBufferSize = 64 * 1024
+
+var (
+	// Reusable buffers for formatting messages before hashing
+	messageBufferPool = sync.Pool{
+		New: func() interface{} {
+			buf := make([]byte, 0, 256)
+			return &buf
+		},
+	}
+
+	// Reusable SHA-256 states
+	sha256Pool = sync.Pool{
+		New: func() interface{} {
+			return &sha256State{h: sha256.New()}
+		},
+	}
+)
+
+// sha256State is a pooled hash state with scratch space for its digest, so
+// summing doesn't allocate
+type sha256State struct {
+	h   hash.Hash
+	sum [sha256.Size]byte
+}
+
+// appendMagicMessage appends the message in the Bitcoin signed message format
+// (compact size prefixed magic prefix, followed by the compact size prefixed
+// message) to b.
+func appendMagicMessage(b []byte, message string) []byte {
+	return appendPrefixedMessage(b, bitcoinMessagePrefix, message)
+}
+
+// appendPrefixedMessage appends the message in the Bitcoin signed message
+// format with the given magic prefix to b
+func appendPrefixedMessage(b []byte, prefix, message string) []byte {
+	b = appendCompactSize(b, uint64(len(prefix)))
+	b = append(b, prefix...)
+	b = appendCompactSize(b, uint64(len(message)))
+	return append(b, message...)
+}
+
+// magicHash returns the double SHA-256 digest of the message in the Bitcoin
+// signed message format. Formatting buffers and hash states are taken from
+// pools to keep allocations flat under high-throughput workloads.
+func magicHash(message string) [32]byte {
+	return magicHashWithEvents(eventLogger{}, message)
+}
+
+// magicHashWithEvents is like magicHash, logging the formatted message to
+// events
+func magicHashWithEvents(events eventLogger, message string) [32]byte {
+	bufPtr := messageBufferPool.Get().(*[]byte)
+	buf := appendMagicMessage((*bufPtr)[:0], message)
+
+	if events.enabled(LogLevelTrace) {
+		events.log(LogLevelTrace, "Formatted Bitcoin message", "message_hex", hex.EncodeToString(buf))
+	}
+
+	digest := doubleSHA256(buf)
+
+	if cap(buf) <= maxPooledBufferSize {
+		*bufPtr = buf[:0]
+		messageBufferPool.Put(bufPtr)
+	}
+
+	return digest
+}
+
+// MessageHash returns the digest a BIP-0137 signature of the message signs:
+// the double SHA-256 of the message in the Bitcoin signed message format.
+// Signing tools need it to produce signatures the package verifies.
+func MessageHash(message string) [32]byte {
+	return magicHash(message)
+}
+
+// doubleSHA256 returns SHA-256(SHA-256(data)) using a pooled hash state
+func doubleSHA256(data []byte) [32]byte {
+	state := sha256Pool.Get().(*sha256State)
+	defer sha256Pool.Put(state)
+
+	state.h.Reset()
+	state.h.Write(data)
+	state.h.Sum(state.sum[:0])
+
+	return sha256.Sum256(state.sum[:])
+}
@@ -0,0 +1,87 @@
+package verify
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+func TestMagicHash(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{name: "Empty message", message: ""},
+		{name: "Short message", message: "Hello, Bitcoin testing!"},
+		{name: "Compact size boundary", message: strings.Repeat("a", 253)},
+		{name: "Larger than pooled buffer", message: strings.Repeat("b", maxPooledBufferSize+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first := sha256.Sum256(appendMagicMessage(nil, tt.message))
+			want := sha256.Sum256(first[:])
+
+			// Hash twice to exercise reuse of pooled buffers
+			for i := 0; i < 2; i++ {
+				if got := magicHash(tt.message); !bytes.Equal(got[:], want[:]) {
+					t.Errorf("magicHash() = %x, want %x", got, want)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkMagicHash(b *testing.B) {
+	message := strings.Repeat("Hello, Bitcoin testing! ", 16)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		magicHash(message)
+	}
+}
+
+func BenchmarkMagicHashUnpooled(b *testing.B) {
+	message := strings.Repeat("Hello, Bitcoin testing! ", 16)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		first := sha256.Sum256(appendMagicMessage(nil, message))
+		sha256.Sum256(first[:])
+	}
+}
+
+func BenchmarkVerifySignatureDirectly(b *testing.B) {
+	SetLogLevel(LogLevelNone)
+	defer SetLogLevel(LogLevelInfo)
+
+	pubKey := mustParsePubKey(b, "034fafbb0673368ea3dcc7003a753c51bf240471c3a1b811491ba9f3480091e23c")
+	message := "Hello, Bitcoin testing!"
+	signature := "IOeVH/0KqgmS3XKwqCJiwlcHonwxKMQN6fbOW5UsXSDZB4EGCVTXx6c+ZU/Ae5qO94MSBZn2aPOiUsupRIwBaAU="
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := verifySignatureDirectly(eventLogger{}, pubKey, message, signature); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Helper function to parse a hex-encoded public key in tests and benchmarks
+func mustParsePubKey(tb testing.TB, pubKeyHex string) *btcec.PublicKey {
+	tb.Helper()
+
+	pubKeyBytes, err := hex.DecodeString(pubKeyHex)
+	if err != nil {
+		tb.Fatalf("hex.DecodeString() error = %v", err)
+	}
+	pubKey, err := btcec.ParsePubKey(pubKeyBytes)
+	if err != nil {
+		tb.Fatalf("btcec.ParsePubKey() error = %v", err)
+	}
+	return pubKey
+}
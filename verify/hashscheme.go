@@ -0,0 +1,35 @@
+package verify
+
+// HashScheme computes the digest a signature of a message signs. The
+// default scheme is the Bitcoin signed message format of MessageHash; forks
+// and non-standard wallets that sign with another prefix or hash function
+// can be verified by passing their scheme to WithHashScheme.
+type HashScheme interface {
+	// Hash returns the 32-byte digest signed for the message
+	Hash(message string) [32]byte
+}
+
+// HashFunc adapts a function to the HashScheme interface
+type HashFunc func(message string) [32]byte
+
+// Hash calls f(message)
+func (f HashFunc) Hash(message string) [32]byte {
+	return f(message)
+}
+
+// prefixScheme is the Bitcoin signed message format with another magic
+// prefix
+type prefixScheme string
+
+// MessagePrefix returns the scheme of the Bitcoin signed message format
+// with another magic prefix, as used by forks such as Litecoin with
+// "Litecoin Signed Message:\n". The prefix and message are each preceded by
+// their compact size length and the result is double SHA-256 hashed.
+func MessagePrefix(prefix string) HashScheme {
+	return prefixScheme(prefix)
+}
+
+// Hash returns the double SHA-256 of the message formatted with the prefix
+func (p prefixScheme) Hash(message string) [32]byte {
+	return doubleSHA256(appendPrefixedMessage(nil, string(p), message))
+}
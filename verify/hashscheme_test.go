@@ -0,0 +1,61 @@
+package verify
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+)
+
+func TestWithHashScheme(t *testing.T) {
+	const message = "signed by a fork"
+	litecoin := MessagePrefix("Litecoin Signed Message:\n")
+	singleSHA256 := HashFunc(func(message string) [32]byte {
+		return sha256.Sum256([]byte(message))
+	})
+
+	tests := []struct {
+		name      string
+		signedFor HashScheme
+		scheme    HashScheme
+		signature string
+		wantErr   error
+	}{
+		{name: "Prefix scheme", signedFor: litecoin, scheme: litecoin},
+		{name: "Hash function", signedFor: singleSHA256, scheme: singleSHA256},
+		{name: "Default scheme", signedFor: MessagePrefix(bitcoinMessagePrefix)},
+		{name: "Fork signature with the default scheme", signedFor: litecoin, wantErr: ErrAddressMismatch},
+		{name: "Bitcoin signature with a fork scheme", signedFor: MessagePrefix(bitcoinMessagePrefix), scheme: litecoin, wantErr: ErrAddressMismatch},
+		{name: "Not a compact signature", signedFor: litecoin, scheme: litecoin, signature: "AAAA", wantErr: ErrMalformedSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := signTestMessage(t, testKeySeed, tt.signedFor.Hash, message)
+			if tt.signature != "" {
+				msg.Signature = tt.signature
+			}
+
+			var opts []Option
+			if tt.scheme != nil {
+				opts = append(opts, WithHashScheme(tt.scheme))
+			}
+			result, err := NewVerifier(opts...).Verify(msg)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if result.Valid != (tt.wantErr == nil) {
+				t.Errorf("Verify() valid = %v, want %v", result.Valid, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestMessagePrefix(t *testing.T) {
+	const message = "line one\nline two"
+	if got, want := MessagePrefix(bitcoinMessagePrefix).Hash(message), MessageHash(message); got != want {
+		t.Errorf("MessagePrefix(bitcoin).Hash() = %x, want %x", got, want)
+	}
+	if MessagePrefix("Litecoin Signed Message:\n").Hash(message) == MessageHash(message) {
+		t.Error("MessagePrefix(litecoin).Hash() = MessageHash(), want a different digest")
+	}
+}
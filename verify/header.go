@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// SignatureHeaderInfo is what the header byte of a BIP-0137 signature says
+// about the key that made it
+type SignatureHeaderInfo struct {
+	// HeaderByte is the first byte of the signature
+	HeaderByte byte
+
+	// AddressType is the address type the header byte stands for, empty when
+	// the header byte is outside the BIP-0137 ranges
+	AddressType AddressType
+
+	// Compressed reports whether the header byte asks for the compressed
+	// serialization of the public key
+	Compressed bool
+
+	// RecoveryID selects which of the candidate public keys signed
+	RecoveryID int
+}
+
+// Valid reports whether the header byte is within the BIP-0137 ranges
+func (h SignatureHeaderInfo) Valid() bool {
+	return h.AddressType != ""
+}
+
+// String describes the address type and key serialization of the header
+// byte, e.g. "P2PKH (compressed)"
+func (h SignatureHeaderInfo) String() string {
+	switch h.AddressType {
+	case AddressTypeP2PKH:
+		if h.Compressed {
+			return "P2PKH (compressed)"
+		}
+		return "P2PKH (uncompressed)"
+	case AddressTypeP2SHP2WPKH:
+		return "P2SH-P2WPKH (SegWit over P2SH)"
+	case AddressTypeP2WPKH:
+		return "P2WPKH (native SegWit)"
+	default:
+		return fmt.Sprintf("Unknown (0x%02x)", h.HeaderByte)
+	}
+}
+
+// InspectSignature decodes a base64-encoded 65-byte signature and analyzes
+// its header byte, without verifying it. Like DecodeCompactSignature, the
+// analysis is returned along with ErrInvalidHeaderByte when the header byte
+// is outside the BIP-0137 ranges.
+func InspectSignature(signatureBase64 string) (SignatureHeaderInfo, error) {
+	if signatureBase64 == "" {
+		return SignatureHeaderInfo{}, ErrEmptySignature
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return SignatureHeaderInfo{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	if len(sigBytes) != compactSignatureLength {
+		return SignatureHeaderInfo{}, newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d", len(sigBytes), compactSignatureLength)
+	}
+
+	info := inspectHeader(sigBytes[0])
+	if !info.Valid() {
+		return info, newVerifyError(ErrInvalidHeaderByte, "0x%02x", info.HeaderByte)
+	}
+	return info, nil
+}
+
+// inspectHeader analyzes a signature header byte. Header bytes outside the
+// BIP-0137 ranges have no address type and aren't compressed.
+func inspectHeader(header byte) SignatureHeaderInfo {
+	info := SignatureHeaderInfo{
+		HeaderByte:  header,
+		AddressType: headerAddressType(header),
+		RecoveryID:  int(header-headerP2PKHUncompressed) & 0x03,
+	}
+	info.Compressed = info.Valid() && header >= headerP2PKHCompressed
+	return info
+}
+
+// logHeader logs the analysis of the header byte of a decoded signature,
+// warning about header bytes outside the BIP-0137 ranges
+func logHeader(events eventLogger, sigBytes []byte) {
+	if len(sigBytes) == 0 {
+		return
+	}
+
+	info := inspectHeader(sigBytes[0])
+	if !info.Valid() {
+		events.log(LogLevelWarning, "Unknown signature header byte", "header_byte", info.HeaderByte)
+	}
+	events.log(LogLevelDebug, "Signature header",
+		"header_byte", info.HeaderByte, "address_type", info.String(), "compressed", info.Compressed, "rec_id", byte(info.RecoveryID))
+}
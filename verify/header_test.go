@@ -0,0 +1,72 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestInspectSignature(t *testing.T) {
+	sigBytes, _ := base64.StdEncoding.DecodeString(walletTestVectors[2].msg.Signature)
+	sigBytes[0] = 0xff
+	badHeader := base64.StdEncoding.EncodeToString(sigBytes)
+
+	tests := []struct {
+		name       string
+		signature  string
+		want       SignatureHeaderInfo
+		wantString string
+		wantErr    error
+	}{
+		{
+			name:       "Uncompressed P2PKH",
+			signature:  walletTestVectors[1].msg.Signature,
+			want:       SignatureHeaderInfo{HeaderByte: 28, AddressType: AddressTypeP2PKH, RecoveryID: 1},
+			wantString: "P2PKH (uncompressed)",
+		},
+		{
+			name:       "Compressed P2PKH",
+			signature:  walletTestVectors[2].msg.Signature,
+			want:       SignatureHeaderInfo{HeaderByte: 32, AddressType: AddressTypeP2PKH, Compressed: true, RecoveryID: 1},
+			wantString: "P2PKH (compressed)",
+		},
+		{
+			name:       "P2SH-P2WPKH",
+			signature:  walletTestVectors[4].msg.Signature,
+			want:       SignatureHeaderInfo{HeaderByte: 35, AddressType: AddressTypeP2SHP2WPKH, Compressed: true},
+			wantString: "P2SH-P2WPKH (SegWit over P2SH)",
+		},
+		{
+			name:       "Header out of range",
+			signature:  badHeader,
+			want:       SignatureHeaderInfo{HeaderByte: 0xff},
+			wantString: "Unknown (0xff)",
+			wantErr:    ErrInvalidHeaderByte,
+		},
+		{
+			name:      "Wrong length",
+			signature: "AAAA",
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "Empty signature",
+			signature: "",
+			wantErr:   ErrEmptySignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := InspectSignature(tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("InspectSignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("InspectSignature() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantString != "" && got.String() != tt.wantString {
+				t.Errorf("String() = %q, want %q", got.String(), tt.wantString)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package verify
+
+import (
+	"context"
+	"time"
+)
+
+// Hooks are callbacks a Verifier calls around each verification, so
+// applications can audit or alert on verifications without parsing logs.
+// Any of them may be nil. They are called synchronously by the verifying
+// goroutine and must be safe for concurrent use when the Verifier is shared.
+type Hooks struct {
+	// OnStart is called before the message is verified
+	OnStart func(ctx context.Context, msg SignedMessage)
+
+	// OnSuccess is called when the signature is valid
+	OnSuccess func(ctx context.Context, msg SignedMessage, result *Result, duration time.Duration)
+
+	// OnFailure is called when the signature is invalid or couldn't be
+	// verified. err is ErrInvalidSignature when the verifier rejected the
+	// signature without a more specific reason.
+	OnFailure func(ctx context.Context, msg SignedMessage, err error, duration time.Duration)
+}
+
+// WithHooks sets the callbacks the verifier calls around each verification
+func WithHooks(h Hooks) Option {
+	return func(v *Verifier) {
+		v.hooks = h
+	}
+}
+
+// start calls OnStart, if set
+func (h Hooks) start(ctx context.Context, msg SignedMessage) {
+	if h.OnStart != nil {
+		h.OnStart(ctx, msg)
+	}
+}
+
+// finish calls OnSuccess or OnFailure, if set, depending on the outcome of a
+// verification that started at start
+func (h Hooks) finish(ctx context.Context, msg SignedMessage, start time.Time, result *Result, err error) {
+	switch {
+	case err == nil && result.Valid:
+		if h.OnSuccess != nil {
+			h.OnSuccess(ctx, msg, result, time.Since(start))
+		}
+	case h.OnFailure != nil:
+		if err == nil {
+			err = ErrInvalidSignature
+		}
+		h.OnFailure(ctx, msg, err, time.Since(start))
+	}
+}
@@ -0,0 +1,95 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVerifierHooks(t *testing.T) {
+	tv := walletTestVectors[2].msg
+	mismatch := tv
+	mismatch.Address = walletTestVectors[0].msg.Address
+	empty := tv
+	empty.Signature = ""
+
+	tests := []struct {
+		name    string
+		msg     SignedMessage
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "Valid signature",
+			msg:  tv,
+			want: []string{"start", "success"},
+		},
+		{
+			name:    "Address mismatch",
+			msg:     mismatch,
+			want:    []string{"start", "failure"},
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			name:    "Empty signature",
+			msg:     empty,
+			want:    []string{"start", "failure"},
+			wantErr: ErrEmptySignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			var calls []string
+			var gotErr error
+			record := func(call string) {
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, call)
+			}
+
+			v := NewVerifier(WithHooks(Hooks{
+				OnStart: func(ctx context.Context, msg SignedMessage) {
+					record("start")
+				},
+				OnSuccess: func(ctx context.Context, msg SignedMessage, result *Result, duration time.Duration) {
+					record("success")
+				},
+				OnFailure: func(ctx context.Context, msg SignedMessage, err error, duration time.Duration) {
+					record("failure")
+					gotErr = err
+				},
+			}))
+			v.Verify(tt.msg)
+
+			if len(calls) != len(tt.want) {
+				t.Fatalf("hooks called = %v, want %v", calls, tt.want)
+			}
+			for i := range calls {
+				if calls[i] != tt.want[i] {
+					t.Errorf("hooks called = %v, want %v", calls, tt.want)
+				}
+			}
+			if !errors.Is(gotErr, tt.wantErr) {
+				t.Errorf("OnFailure error = %v, want %v", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifierHooksPartial(t *testing.T) {
+	var started int
+	v := NewVerifier(WithHooks(Hooks{
+		OnStart: func(ctx context.Context, msg SignedMessage) { started++ },
+	}))
+
+	if _, err := v.Verify(walletTestVectors[2].msg); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if started != 1 {
+		t.Errorf("OnStart called %d times, want 1", started)
+	}
+}
@@ -0,0 +1,258 @@
+// Package httpauth provides net/http middleware that authenticates requests
+// with a Bitcoin signed message, a drop-in "Sign in with Bitcoin" guard:
+//
+//	mux.Handle("/account", httpauth.Middleware()(accountHandler))
+//
+// Handlers read the authenticated address with AddressFromContext.
+//
+// The signed message is taken from the X-Bitcoin-Address, X-Bitcoin-Message
+// and X-Bitcoin-Signature headers, the message being base64-encoded since
+// header values can't hold newlines, or from an Authorization bearer token
+// holding the unpadded base64url encoding of a Credentials JSON object.
+//
+// A signature stays valid forever, so a captured one can be replayed.
+// Applications should sign a message that expires, check it with
+// WithMessageCheck, and reject messages used before with
+// WithReplayProtection.
+package httpauth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/auth"
+)
+
+// Headers carrying the signed message
+const (
+	HeaderAddress   = "X-Bitcoin-Address"
+	HeaderMessage   = "X-Bitcoin-Message"
+	HeaderSignature = "X-Bitcoin-Signature"
+)
+
+// Error codes of requests that aren't authenticated, next to the
+// verify.ErrorCode of invalid signatures
+const (
+	CodeMissingCredentials verify.ErrorCode = "missing_credentials"
+	CodeInvalidCredentials verify.ErrorCode = "invalid_credentials"
+	CodeRejectedMessage    verify.ErrorCode = "rejected_message"
+	CodeReplayedMessage    verify.ErrorCode = "replayed_message"
+)
+
+// Credentials is the JSON content of a bearer token
+type Credentials struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// Token encodes credentials as a bearer token
+func (c Credentials) Token() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// ErrorResponse is the body of requests that failed authentication
+type ErrorResponse struct {
+	Code  verify.ErrorCode `json:"code"`
+	Error string           `json:"error"`
+}
+
+// MessageCheck validates a signed message before its signature is verified,
+// returning an error to reject the request
+type MessageCheck func(r *http.Request, address, message string) error
+
+// Option configures the middleware
+type Option func(*config)
+
+// config holds the settings of the middleware
+type config struct {
+	verifier verify.MessageVerifier
+	check    MessageCheck
+	nonces   auth.NonceStore
+	ttl      time.Duration
+}
+
+// WithVerifier sets the verifier signatures are verified with, by default a
+// verifier for mainnet created with verify.NewVerifier
+func WithVerifier(v verify.MessageVerifier) Option {
+	return func(c *config) {
+		c.verifier = v
+	}
+}
+
+// WithMessageCheck rejects requests whose signed message fails the check,
+// for instance because it holds an expired timestamp or a nonce that was
+// already used
+func WithMessageCheck(check MessageCheck) Option {
+	return func(c *config) {
+		c.check = check
+	}
+}
+
+// WithReplayProtection records each accepted signed message in the store for
+// ttl, rejecting requests that reuse it. The message check should reject
+// messages older than ttl, which the store no longer remembers. Middleware
+// panics if ttl isn't positive, as the store would remember nothing.
+func WithReplayProtection(store auth.NonceStore, ttl time.Duration) Option {
+	return func(c *config) {
+		c.nonces = store
+		c.ttl = ttl
+	}
+}
+
+// contextKey is the key of the authenticated address in a request context
+type contextKey struct{}
+
+// AddressFromContext returns the address authenticated by the middleware
+func AddressFromContext(ctx context.Context) (string, bool) {
+	address, ok := ctx.Value(contextKey{}).(string)
+	return address, ok
+}
+
+// Middleware returns middleware that passes requests with a valid signed
+// message on to the next handler, with the signing address in their
+// context. Other requests get status 401 and an ErrorResponse.
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	cfg := config{}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.verifier == nil {
+		cfg.verifier = verify.NewVerifier()
+	}
+	if cfg.nonces != nil && cfg.ttl <= 0 {
+		panic(fmt.Sprintf("httpauth: replay protection ttl is %s, want a positive duration", cfg.ttl))
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			creds, err := credentialsOf(r)
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+
+			if cfg.check != nil {
+				if err := cfg.check(r, creds.Address, creds.Message); err != nil {
+					unauthorized(w, &authError{code: CodeRejectedMessage, err: err})
+					return
+				}
+			}
+
+			result, err := cfg.verifier.VerifyContext(r.Context(), verify.SignedMessage{
+				Address:   creds.Address,
+				Message:   creds.Message,
+				Signature: creds.Signature,
+			})
+			if err == nil && !result.Valid {
+				err = verify.ErrInvalidSignature
+			}
+			if err != nil {
+				unauthorized(w, &authError{code: verify.ErrorCodeOf(err), err: err})
+				return
+			}
+
+			if cfg.nonces != nil {
+				fresh, err := cfg.nonces.Put(r.Context(), replayKey(creds.Address, creds.Message), nil, cfg.ttl)
+				if err != nil {
+					writeError(w, http.StatusInternalServerError, ErrorResponse{
+						Code:  verify.CodeUnknown,
+						Error: fmt.Sprintf("recording signed message: %v", err),
+					})
+					return
+				}
+				if !fresh {
+					unauthorized(w, &authError{code: CodeReplayedMessage, err: errors.New("signed message was already used")})
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), contextKey{}, creds.Address)))
+		})
+	}
+}
+
+// replayKey returns the nonce key of a signed message. It keys on the
+// message rather than the signature, which has several valid encodings.
+// Verification also accepts the message with surrounding whitespace trimmed
+// and, depending on the verifier, with other line endings, so the key is
+// computed over the message with both normalized: otherwise one captured
+// credential could be replayed once per spelling.
+func replayKey(address, message string) string {
+	message = strings.ReplaceAll(strings.ReplaceAll(message, "\r\n", "\n"), "\r", "\n")
+	key := sha256.Sum256([]byte(address + "\x00" + strings.TrimSpace(message)))
+	return hex.EncodeToString(key[:])
+}
+
+// credentialsOf extracts the signed message from the bearer token or the
+// headers of a request
+func credentialsOf(r *http.Request) (Credentials, error) {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if !ok {
+			return Credentials{}, &authError{code: CodeInvalidCredentials, err: errors.New("authorization is not a bearer token")}
+		}
+		data, err := base64.RawURLEncoding.DecodeString(strings.TrimSpace(token))
+		if err != nil {
+			return Credentials{}, &authError{code: CodeInvalidCredentials, err: fmt.Errorf("invalid bearer token: %v", err)}
+		}
+		var creds Credentials
+		if err := json.Unmarshal(data, &creds); err != nil {
+			return Credentials{}, &authError{code: CodeInvalidCredentials, err: fmt.Errorf("invalid bearer token: %v", err)}
+		}
+		return creds, nil
+	}
+
+	address := r.Header.Get(HeaderAddress)
+	if address == "" {
+		return Credentials{}, &authError{code: CodeMissingCredentials, err: errors.New("no bearer token or signed message headers")}
+	}
+	message, err := base64.StdEncoding.DecodeString(r.Header.Get(HeaderMessage))
+	if err != nil {
+		return Credentials{}, &authError{code: CodeInvalidCredentials, err: fmt.Errorf("invalid %s header: %v", HeaderMessage, err)}
+	}
+	return Credentials{
+		Address:   address,
+		Message:   string(message),
+		Signature: r.Header.Get(HeaderSignature),
+	}, nil
+}
+
+// authError is an authentication failure with its error code
+type authError struct {
+	code verify.ErrorCode
+	err  error
+}
+
+func (e *authError) Error() string {
+	return e.err.Error()
+}
+
+// unauthorized writes the 401 response of a failed authentication
+func unauthorized(w http.ResponseWriter, err error) {
+	resp := ErrorResponse{Code: verify.CodeUnknown, Error: err.Error()}
+	var authErr *authError
+	if errors.As(err, &authErr) {
+		resp.Code = authErr.code
+	}
+
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeError(w, http.StatusUnauthorized, resp)
+}
+
+// writeError writes an ErrorResponse with the status code
+func writeError(w http.ResponseWriter, status int, resp ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
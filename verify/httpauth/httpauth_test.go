@@ -0,0 +1,181 @@
+package httpauth
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/auth"
+)
+
+const (
+	testAddress   = "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"
+	testMessage   = "test message"
+	testSignature = "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="
+)
+
+func TestReplayProtection(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	tests := []struct {
+		name  string
+		creds Credentials
+	}{
+		{
+			name:  "Compact signature",
+			creds: Credentials{Address: testAddress, Message: testMessage, Signature: testSignature},
+		},
+		{
+			// BIP-322 test vector #0
+			name: "BIP-322 signature",
+			creds: Credentials{
+				Address:   "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l",
+				Message:   "Hello World",
+				Signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := Middleware(WithReplayProtection(auth.NewMemoryNonceStore(), time.Minute))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+			// Verification also accepts the message with surrounding
+			// whitespace, so replays with it are rejected as well
+			message := tt.creds.Message
+			for i, replay := range []struct {
+				message string
+				want    int
+			}{
+				{message, http.StatusOK},
+				{message, http.StatusUnauthorized},
+				{message + " ", http.StatusUnauthorized},
+				{" " + message, http.StatusUnauthorized},
+				{message + "\n", http.StatusUnauthorized},
+				{message + "\r\n", http.StatusUnauthorized},
+			} {
+				creds := tt.creds
+				creds.Message = replay.message
+				req := httptest.NewRequest(http.MethodGet, "/", nil)
+				req.Header.Set("Authorization", "Bearer "+creds.Token())
+				rec := httptest.NewRecorder()
+				handler.ServeHTTP(rec, req)
+
+				if rec.Code != replay.want {
+					t.Errorf("request %d with message %q status = %d, want %d", i, replay.message, rec.Code, replay.want)
+				}
+			}
+		})
+	}
+}
+
+func TestReplayProtectionInvalidTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("Middleware() with ttl %s didn't panic", ttl)
+				}
+			}()
+			Middleware(WithReplayProtection(auth.NewMemoryNonceStore(), ttl))
+		}()
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	valid := Credentials{Address: testAddress, Message: testMessage, Signature: testSignature}
+	forged := Credentials{Address: testAddress, Message: "other message", Signature: testSignature}
+
+	tests := []struct {
+		name     string
+		header   http.Header
+		check    MessageCheck
+		wantCode verify.ErrorCode
+	}{
+		{
+			name:   "Valid bearer token",
+			header: http.Header{"Authorization": {"Bearer " + valid.Token()}},
+		},
+		{
+			name: "Valid headers",
+			header: http.Header{
+				HeaderAddress:   {testAddress},
+				HeaderMessage:   {base64.StdEncoding.EncodeToString([]byte(testMessage))},
+				HeaderSignature: {testSignature},
+			},
+		},
+		{
+			name:     "Invalid signature",
+			header:   http.Header{"Authorization": {"Bearer " + forged.Token()}},
+			wantCode: verify.CodeAddressMismatch,
+		},
+		{
+			name:     "No credentials",
+			header:   http.Header{},
+			wantCode: CodeMissingCredentials,
+		},
+		{
+			name:     "Basic authorization",
+			header:   http.Header{"Authorization": {"Basic dXNlcjpwYXNz"}},
+			wantCode: CodeInvalidCredentials,
+		},
+		{
+			name:     "Malformed token",
+			header:   http.Header{"Authorization": {"Bearer !!!"}},
+			wantCode: CodeInvalidCredentials,
+		},
+		{
+			name:     "Message not base64",
+			header:   http.Header{HeaderAddress: {testAddress}, HeaderMessage: {testMessage}, HeaderSignature: {testSignature}},
+			wantCode: CodeInvalidCredentials,
+		},
+		{
+			name:   "Rejected message",
+			header: http.Header{"Authorization": {"Bearer " + valid.Token()}},
+			check: func(r *http.Request, address, message string) error {
+				return errors.New("expired")
+			},
+			wantCode: CodeRejectedMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAddress string
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAddress, _ = AddressFromContext(r.Context())
+			})
+			handler := Middleware(WithMessageCheck(tt.check))(next)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header = tt.header
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tt.wantCode == "" {
+				if rec.Code != http.StatusOK || gotAddress != testAddress {
+					t.Errorf("status = %d, address = %q, want %d and %q", rec.Code, gotAddress, http.StatusOK, testAddress)
+				}
+				return
+			}
+
+			var resp ErrorResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("response isn't JSON: %v", err)
+			}
+			if rec.Code != http.StatusUnauthorized || resp.Code != tt.wantCode {
+				t.Errorf("status = %d, code = %q, want %d and %q", rec.Code, resp.Code, http.StatusUnauthorized, tt.wantCode)
+			}
+			if gotAddress != "" {
+				t.Errorf("next handler called with address %q", gotAddress)
+			}
+		})
+	}
+}
@@ -0,0 +1,433 @@
+// Package httpserver exposes a verify.Verifier over HTTP with JSON requests
+// and responses, so services written in other languages can verify
+// signatures:
+//
+//	POST /v1/verify        verifies one signed message (VerifyRequest)
+//	POST /v1/verify/batch  verifies up to the batch limit at once (BatchRequest)
+//	GET  /v1/verify/stream verifies a WebSocket stream of StreamRequest frames
+//	GET  /healthz          reports that the server is up
+//	GET  /openapi.json     returns the OpenAPI document of the API
+//
+// Verdicts are returned with status 200, invalid signatures included; their
+// error code tells why they failed. Requests that can't be verified at all
+// get status 400 or 413 and an ErrorResponse, or a VerifyResponse with the
+// error code of the malformed field. Requests over the rate limits get
+// status 429, and requests arriving once Shutdown was called get status 503.
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/internal/drain"
+	"github.com/sero/btc/verify/ratelimit"
+	"golang.org/x/net/websocket"
+)
+
+// Default limits of a Server
+const (
+	DefaultMaxBodySize  = 1 << 20
+	DefaultMaxBatchSize = 1000
+)
+
+// Error codes of requests that aren't processed at all
+const (
+	CodeInvalidRequest  verify.ErrorCode = "invalid_request"
+	CodeRequestTooLarge verify.ErrorCode = "request_too_large"
+	CodeBatchTooLarge   verify.ErrorCode = "batch_too_large"
+	CodeShuttingDown    verify.ErrorCode = "shutting_down"
+
+	// CodeBatchExceedsRateLimit rejects batches larger than the burst of the
+	// IP rate limit, which could never be allowed
+	CodeBatchExceedsRateLimit verify.ErrorCode = "batch_exceeds_rate_limit"
+)
+
+// VerifyRequest is a signed message to verify
+type VerifyRequest struct {
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+}
+
+// VerifyResponse is the outcome of verifying a signed message. Code and
+// Error are set when the signature is invalid.
+type VerifyResponse struct {
+	Valid bool             `json:"valid"`
+	Code  verify.ErrorCode `json:"code,omitempty"`
+	Error string           `json:"error,omitempty"`
+}
+
+// BatchRequest is a batch of signed messages to verify
+type BatchRequest struct {
+	Messages []VerifyRequest `json:"messages"`
+}
+
+// BatchResponse holds one result per message of a batch, in request order
+type BatchResponse struct {
+	Results []VerifyResponse `json:"results"`
+	Valid   int              `json:"valid"`
+	Invalid int              `json:"invalid"`
+}
+
+// ErrorResponse is the body of requests that weren't processed
+type ErrorResponse struct {
+	Code  verify.ErrorCode `json:"code"`
+	Error string           `json:"error"`
+}
+
+// Server is an http.Handler serving verification requests. It is safe for
+// concurrent use.
+type Server struct {
+	verifier     verify.MessageVerifier
+	maxBodySize  int64
+	maxBatchSize int
+	ipLimit      *ratelimit.Limiter
+	addressLimit *ratelimit.Limiter
+	mux          *http.ServeMux
+
+	// inflight tracks the requests being served, streams included
+	inflight   drain.Group
+	onShutdown []func(context.Context) error
+
+	// streams are the open WebSocket streams, ended by Shutdown
+	mu      sync.Mutex
+	streams map[*websocket.Conn]struct{}
+}
+
+// Option configures a Server
+type Option func(*Server)
+
+// WithVerifier sets the verifier requests are verified with, by default a
+// verifier for mainnet created with verify.NewVerifier
+func WithVerifier(v verify.MessageVerifier) Option {
+	return func(s *Server) {
+		s.verifier = v
+	}
+}
+
+// WithMaxBodySize sets the maximum size of a request body in bytes. Larger
+// requests are rejected with status 413.
+func WithMaxBodySize(n int64) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxBodySize = n
+		}
+	}
+}
+
+// WithMaxBatchSize sets the maximum number of messages of a batch request.
+// Larger batches are rejected with status 413.
+func WithMaxBatchSize(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.maxBatchSize = n
+		}
+	}
+}
+
+// WithIPRateLimit limits the verifications per client IP, taken from the
+// remote address of the connection. A batch counts one verification per
+// message, so batches larger than the burst of the limiter are rejected
+// with status 400 and the code batch_too_large. Requests over the limit are
+// rejected with status 429.
+func WithIPRateLimit(l *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.ipLimit = l
+	}
+}
+
+// WithAddressRateLimit limits the verifications per address to verify.
+// Single requests over the limit are rejected with status 429; messages of
+// a batch or stream over the limit get a result with the code
+// rate_limited.
+func WithAddressRateLimit(l *ratelimit.Limiter) Option {
+	return func(s *Server) {
+		s.addressLimit = l
+	}
+}
+
+// WithShutdownHook adds a function Shutdown calls once the requests in
+// flight have completed, to flush metrics or audit buffers fed by the
+// verifier. Hooks are called in the order they were added.
+func WithShutdownHook(f func(ctx context.Context) error) Option {
+	return func(s *Server) {
+		s.onShutdown = append(s.onShutdown, f)
+	}
+}
+
+// New creates a Server
+func New(opts ...Option) *Server {
+	s := &Server{
+		maxBodySize:  DefaultMaxBodySize,
+		maxBatchSize: DefaultMaxBatchSize,
+		streams:      make(map[*websocket.Conn]struct{}),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.verifier == nil {
+		s.verifier = verify.NewVerifier()
+	}
+
+	s.mux = http.NewServeMux()
+	s.mux.HandleFunc("POST /v1/verify", s.handleVerify)
+	s.mux.HandleFunc("POST /v1/verify/batch", s.handleBatch)
+	// websocket.Server skips the Origin check of websocket.Handler, so
+	// non-browser clients can connect
+	s.mux.Handle("GET /v1/verify/stream", websocket.Server{Handler: s.handleStream})
+	s.mux.HandleFunc("GET /openapi.json", s.handleOpenAPI)
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return s
+}
+
+// ServeHTTP serves a request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.inflight.Begin() {
+		w.Header().Set("Connection", "close")
+		writeJSON(w, http.StatusServiceUnavailable, ErrorResponse{Code: CodeShuttingDown, Error: "server is shutting down"})
+		return
+	}
+	defer s.inflight.End()
+	s.mux.ServeHTTP(w, r)
+}
+
+// Shutdown shuts the server down gracefully, for rolling restarts: requests
+// arriving from now on get status 503, health checks included, so load
+// balancers stop routing to the server. Open streams stop reading frames and
+// close once the frames they received are answered. When every request in
+// flight has completed, the shutdown hooks are called.
+//
+// If ctx is done before the requests in flight complete, Shutdown returns
+// the error of the context without calling the hooks. Shutdown doesn't close
+// listeners or idle connections; call it before http.Server.Shutdown.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.inflight.Close()
+
+	// Wake the streams waiting for a frame; the deadline makes them stop
+	// reading and wind down
+	s.mu.Lock()
+	for ws := range s.streams {
+		ws.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	if err := s.inflight.Wait(ctx); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, f := range s.onShutdown {
+		if err := f(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// trackStream registers an open stream for Shutdown to end, returning false
+// when the server is already shutting down
+func (s *Server) trackStream(ws *websocket.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.inflight.Closed() {
+		return false
+	}
+	s.streams[ws] = struct{}{}
+	return true
+}
+
+// untrackStream unregisters a stream once it's closed
+func (s *Server) untrackStream(ws *websocket.Conn) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.streams, ws)
+}
+
+// handleVerify serves POST /v1/verify
+func (s *Server) handleVerify(w http.ResponseWriter, r *http.Request) {
+	if !s.ipLimit.Allow(clientIP(r)) {
+		writeRateLimited(w, "too many requests from this client")
+		return
+	}
+
+	var req VerifyRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	if !s.addressLimit.Allow(req.Address) {
+		writeRateLimited(w, "too many requests for this address")
+		return
+	}
+
+	resp, err := s.verify(r, req)
+	writeJSON(w, statusOf(err), resp)
+}
+
+// handleBatch serves POST /v1/verify/batch
+func (s *Server) handleBatch(w http.ResponseWriter, r *http.Request) {
+	// Charge the request before decoding it, so limited clients can't make
+	// the server parse large bodies; the other messages are charged below
+	if !s.ipLimit.Allow(clientIP(r)) {
+		writeRateLimited(w, "too many requests from this client")
+		return
+	}
+
+	var req BatchRequest
+	if !s.decode(w, r, &req) {
+		return
+	}
+	if len(req.Messages) > s.maxBatchSize {
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+			Code:  CodeBatchTooLarge,
+			Error: fmt.Sprintf("batch has %d messages, the limit is %d", len(req.Messages), s.maxBatchSize),
+		})
+		return
+	}
+	if s.ipLimit != nil && len(req.Messages) > s.ipLimit.Burst() {
+		// The limiter would never allow the batch, however long the client
+		// waits
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Code:  CodeBatchExceedsRateLimit,
+			Error: fmt.Sprintf("batch has %d messages, the rate limit allows %d at once", len(req.Messages), s.ipLimit.Burst()),
+		})
+		return
+	}
+	if len(req.Messages) > 1 && !s.ipLimit.AllowN(clientIP(r), len(req.Messages)-1) {
+		writeRateLimited(w, "too many requests from this client")
+		return
+	}
+
+	resp := BatchResponse{Results: make([]VerifyResponse, len(req.Messages))}
+	workers := min(runtime.GOMAXPROCS(0), len(req.Messages))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				resp.Results[i] = s.verifyLimited(r, req.Messages[i])
+			}
+		}()
+	}
+	for i := range req.Messages {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+
+	for _, result := range resp.Results {
+		if result.Valid {
+			resp.Valid++
+		} else {
+			resp.Invalid++
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// verify verifies a signed message, returning the response along with the
+// verification error
+func (s *Server) verify(r *http.Request, req VerifyRequest) (VerifyResponse, error) {
+	result, err := s.verifier.VerifyContext(r.Context(), verify.SignedMessage{
+		Address:   req.Address,
+		Message:   req.Message,
+		Signature: req.Signature,
+	})
+
+	resp := VerifyResponse{Valid: err == nil && result.Valid}
+	switch {
+	case err != nil:
+		resp.Code = verify.ErrorCodeOf(err)
+		resp.Error = err.Error()
+	case !result.Valid:
+		resp.Code = verify.CodeInvalidSignature
+	}
+	return resp, err
+}
+
+// verifyLimited verifies a signed message of a batch or stream, reporting
+// messages over the address rate limit in the response
+func (s *Server) verifyLimited(r *http.Request, req VerifyRequest) VerifyResponse {
+	if !s.addressLimit.Allow(req.Address) {
+		return VerifyResponse{Code: ratelimit.CodeRateLimited, Error: "too many requests for this address"}
+	}
+	resp, _ := s.verify(r, req)
+	return resp
+}
+
+// decode decodes the JSON body of r into v, writing an error response and
+// returning false if it can't
+func (s *Server) decode(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	err := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBodySize)).Decode(v)
+	if err == nil {
+		return true
+	}
+
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		writeJSON(w, http.StatusRequestEntityTooLarge, ErrorResponse{
+			Code:  CodeRequestTooLarge,
+			Error: fmt.Sprintf("request body exceeds %d bytes", tooLarge.Limit),
+		})
+		return false
+	}
+	writeJSON(w, http.StatusBadRequest, ErrorResponse{
+		Code:  CodeInvalidRequest,
+		Error: fmt.Sprintf("invalid request body: %v", err),
+	})
+	return false
+}
+
+// statusOf returns the status code of a single verification: 400 for
+// requests with a missing or malformed field, 403 for blocked addresses, 500
+// for failures unrelated to the request and 200 for verdicts
+func statusOf(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	switch verify.ErrorCodeOf(err) {
+	case verify.CodeEmptyAddress, verify.CodeEmptyMessage, verify.CodeEmptySignature,
+		verify.CodeInvalidAddress, verify.CodeMalformedSignature:
+		return http.StatusBadRequest
+	case verify.CodeMessageTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case verify.CodeAddressBlocked:
+		return http.StatusForbidden
+	case verify.CodeUnknown, verify.CodeEngineDisagreement:
+		return http.StatusInternalServerError
+	default:
+		return http.StatusOK
+	}
+}
+
+// clientIP returns the IP address of the client of a request
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// writeRateLimited writes the 429 response of a rate limited request
+func writeRateLimited(w http.ResponseWriter, msg string) {
+	writeJSON(w, http.StatusTooManyRequests, ErrorResponse{Code: ratelimit.CodeRateLimited, Error: msg})
+}
+
+// writeJSON writes v as the JSON body of a response with the status code
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,299 @@
+package httpserver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/ratelimit"
+	"github.com/sero/btc/verify/verifytest"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	testAddress   = "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"
+	testSignature = "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="
+)
+
+func TestVerify(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantValid  bool
+		wantCode   verify.ErrorCode
+	}{
+		{
+			name:       "Valid signature",
+			body:       `{"address":"` + testAddress + `","message":"test message","signature":"` + testSignature + `"}`,
+			wantStatus: http.StatusOK,
+			wantValid:  true,
+		},
+		{
+			name:       "Invalid signature",
+			body:       `{"address":"` + testAddress + `","message":"other message","signature":"` + testSignature + `"}`,
+			wantStatus: http.StatusOK,
+			wantCode:   verify.CodeAddressMismatch,
+		},
+		{
+			name:       "Missing signature",
+			body:       `{"address":"` + testAddress + `","message":"test message"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   verify.CodeEmptySignature,
+		},
+		{
+			name:       "Invalid body",
+			body:       `{"address":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeInvalidRequest,
+		},
+		{
+			name:       "Body too large",
+			body:       `{"address":"` + testAddress + `","message":"` + strings.Repeat("a", 300) + `","signature":"` + testSignature + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   CodeRequestTooLarge,
+		},
+	}
+
+	srv := httptest.NewServer(New(WithMaxBodySize(256)))
+	defer srv.Close()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got VerifyResponse
+			status := post(t, srv.URL+"/v1/verify", tt.body, &got)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if got.Valid != tt.wantValid || got.Code != tt.wantCode {
+				t.Errorf("response = %+v, want valid %v and code %q", got, tt.wantValid, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestVerifyAddressFilter(t *testing.T) {
+	v := verify.NewVerifier(verify.WithAddressFilter(verify.AddressFilter{Deny: []string{testAddress}}))
+	srv := httptest.NewServer(New(WithVerifier(v)))
+	defer srv.Close()
+
+	var got VerifyResponse
+	body := `{"address":"` + testAddress + `","message":"test message","signature":"` + testSignature + `"}`
+	if status := post(t, srv.URL+"/v1/verify", body, &got); status != http.StatusForbidden || got.Code != verify.CodeAddressBlocked {
+		t.Errorf("status = %d, code = %q, want %d and %q", status, got.Code, http.StatusForbidden, verify.CodeAddressBlocked)
+	}
+}
+
+func TestVerifyBatch(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	srv := httptest.NewServer(New(WithMaxBatchSize(3)))
+	defer srv.Close()
+
+	valid := `{"address":"` + testAddress + `","message":"test message","signature":"` + testSignature + `"}`
+	invalid := `{"address":"` + testAddress + `","message":"other message","signature":"` + testSignature + `"}`
+	empty := `{"address":"` + testAddress + `","message":"test message"}`
+
+	var got BatchResponse
+	if status := post(t, srv.URL+"/v1/verify/batch", `{"messages":[`+valid+`,`+invalid+`,`+empty+`]}`, &got); status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", status, http.StatusOK)
+	}
+	want := []VerifyResponse{
+		{Valid: true},
+		{Code: verify.CodeAddressMismatch},
+		{Code: verify.CodeEmptySignature},
+	}
+	if len(got.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(got.Results), len(want))
+	}
+	for i := range want {
+		if got.Results[i].Valid != want[i].Valid || got.Results[i].Code != want[i].Code {
+			t.Errorf("result %d = %+v, want %+v", i, got.Results[i], want[i])
+		}
+	}
+	if got.Valid != 1 || got.Invalid != 2 {
+		t.Errorf("valid = %d, invalid = %d, want 1 and 2", got.Valid, got.Invalid)
+	}
+
+	var tooLarge ErrorResponse
+	body := `{"messages":[` + strings.Repeat(valid+",", 3) + valid + `]}`
+	if status := post(t, srv.URL+"/v1/verify/batch", body, &tooLarge); status != http.StatusRequestEntityTooLarge || tooLarge.Code != CodeBatchTooLarge {
+		t.Errorf("oversized batch: status = %d, code = %q, want %d and %q", status, tooLarge.Code, http.StatusRequestEntityTooLarge, CodeBatchTooLarge)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	valid := `{"address":"` + testAddress + `","message":"test message","signature":"` + testSignature + `"}`
+	other := `{"address":"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2","message":"test message","signature":"` + testSignature + `"}`
+
+	t.Run("Per IP", func(t *testing.T) {
+		srv := httptest.NewServer(New(WithIPRateLimit(ratelimit.PerMinute(4))))
+		defer srv.Close()
+
+		// A batch over the burst is rejected, only charged as one request
+		var tooLarge ErrorResponse
+		body := `{"messages":[` + strings.Repeat(valid+",", 4) + valid + `]}`
+		if status := post(t, srv.URL+"/v1/verify/batch", body, &tooLarge); status != http.StatusBadRequest || tooLarge.Code != CodeBatchExceedsRateLimit {
+			t.Errorf("batch over the burst: status = %d, code = %q, want %d and %q", status, tooLarge.Code, http.StatusBadRequest, CodeBatchExceedsRateLimit)
+		}
+
+		var got BatchResponse
+		if status := post(t, srv.URL+"/v1/verify/batch", `{"messages":[`+valid+`,`+valid+`]}`, &got); status != http.StatusOK {
+			t.Errorf("batch status = %d, want %d", status, http.StatusOK)
+		}
+		var single VerifyResponse
+		if status := post(t, srv.URL+"/v1/verify", valid, &single); status != http.StatusOK {
+			t.Errorf("status = %d, want %d", status, http.StatusOK)
+		}
+		var limited ErrorResponse
+		if status := post(t, srv.URL+"/v1/verify", valid, &limited); status != http.StatusTooManyRequests || limited.Code != ratelimit.CodeRateLimited {
+			t.Errorf("status = %d, code = %q, want %d and %q", status, limited.Code, http.StatusTooManyRequests, ratelimit.CodeRateLimited)
+		}
+
+		// Limited clients are turned away before their batch is decoded
+		if status := post(t, srv.URL+"/v1/verify/batch", `{"messages":`, &limited); status != http.StatusTooManyRequests || limited.Code != ratelimit.CodeRateLimited {
+			t.Errorf("malformed batch: status = %d, code = %q, want %d and %q", status, limited.Code, http.StatusTooManyRequests, ratelimit.CodeRateLimited)
+		}
+	})
+
+	t.Run("Per address", func(t *testing.T) {
+		srv := httptest.NewServer(New(WithAddressRateLimit(ratelimit.PerMinute(1))))
+		defer srv.Close()
+
+		var got BatchResponse
+		if status := post(t, srv.URL+"/v1/verify/batch", `{"messages":[`+valid+`,`+other+`,`+valid+`]}`, &got); status != http.StatusOK {
+			t.Fatalf("batch status = %d, want %d", status, http.StatusOK)
+		}
+		if len(got.Results) != 3 || got.Results[1].Code == ratelimit.CodeRateLimited {
+			t.Fatalf("batch results = %+v, want the other address verified", got.Results)
+		}
+		limited := 0
+		for _, result := range []VerifyResponse{got.Results[0], got.Results[2]} {
+			if result.Code == ratelimit.CodeRateLimited {
+				limited++
+			}
+		}
+		if limited != 1 {
+			t.Errorf("batch results = %+v, want one of the repeated address rate limited", got.Results)
+		}
+
+		var resp ErrorResponse
+		if status := post(t, srv.URL+"/v1/verify", valid, &resp); status != http.StatusTooManyRequests {
+			t.Errorf("status = %d, want %d", status, http.StatusTooManyRequests)
+		}
+	})
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/v1/verify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET /v1/verify status = %d, want %d", resp.StatusCode, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mock := &verifytest.MockVerifier{Fallback: func(ctx context.Context, msg verify.SignedMessage) (*verify.Result, error) {
+		close(started)
+		<-release
+		return &verify.Result{Valid: true}, nil
+	}}
+	var flushed atomic.Int32
+	s := New(WithVerifier(mock), WithShutdownHook(func(ctx context.Context) error {
+		flushed.Add(1)
+		return nil
+	}))
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/v1/verify/stream", "", "http://localhost/")
+	if err != nil {
+		t.Fatalf("websocket.Dial() error = %v", err)
+	}
+	defer ws.Close()
+
+	inflight := make(chan int)
+	go func() {
+		resp, err := http.Post(srv.URL+"/v1/verify", "application/json",
+			strings.NewReader(`{"address":"`+testAddress+`","message":"test message","signature":"`+testSignature+`"}`))
+		if err != nil {
+			inflight <- 0
+			return
+		}
+		resp.Body.Close()
+		inflight <- resp.StatusCode
+	}()
+	<-started
+
+	// A deadline passing while the request is in flight skips the hooks
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() with a request in flight error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if n := flushed.Load(); n != 0 {
+		t.Errorf("shutdown hook called %d times before the requests completed", n)
+	}
+
+	// The stream was ended, and new requests are turned away
+	var frame string
+	if err := websocket.Message.Receive(ws, &frame); err == nil {
+		t.Errorf("stream received %q after Shutdown(), want it closed", frame)
+	}
+	resp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("GET /healthz during shutdown status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	close(release)
+	if err := s.Shutdown(context.Background()); err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+	if status := <-inflight; status != http.StatusOK {
+		t.Errorf("request in flight status = %d, want %d", status, http.StatusOK)
+	}
+	if n := flushed.Load(); n != 1 {
+		t.Errorf("shutdown hook called %d times, want once", n)
+	}
+}
+
+// Helper function to post a JSON body and decode the JSON response into v
+func post(t *testing.T, url, body string, v interface{}) int {
+	t.Helper()
+
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatalf("response isn't JSON: %v", err)
+	}
+	return resp.StatusCode
+}
@@ -0,0 +1,203 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// schemaTypes are the request and response types described in the OpenAPI
+// document, under their Go names
+var schemaTypes = []reflect.Type{
+	reflect.TypeOf(VerifyRequest{}),
+	reflect.TypeOf(VerifyResponse{}),
+	reflect.TypeOf(BatchRequest{}),
+	reflect.TypeOf(BatchResponse{}),
+	reflect.TypeOf(StreamRequest{}),
+	reflect.TypeOf(StreamResponse{}),
+	reflect.TypeOf(ErrorResponse{}),
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  []byte
+)
+
+// OpenAPI returns the OpenAPI 3 document of the server's REST API in JSON.
+// The schemas are generated from the request and response types, so the
+// document can't drift from the server. The server also serves it at
+// GET /openapi.json.
+func OpenAPI() []byte {
+	openAPIOnce.Do(func() {
+		openAPIDoc, _ = json.MarshalIndent(openAPIDocument(), "", "  ")
+	})
+	return openAPIDoc
+}
+
+// handleOpenAPI serves GET /openapi.json
+func (s *Server) handleOpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(OpenAPI())
+}
+
+// openAPIDocument builds the OpenAPI document
+func openAPIDocument() map[string]interface{} {
+	schemas := make(map[string]interface{})
+	for _, t := range schemaTypes {
+		schemas[t.Name()] = structSchema(t)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":       "BIP-0137 signature verification",
+			"description": "Verifies Bitcoin signed messages. Verdicts, invalid signatures included, are returned with status 200 and the error code of the failure.",
+			"version":     "1",
+		},
+		"paths": map[string]interface{}{
+			"/v1/verify": map[string]interface{}{
+				"post": operation("verify", "Verify a signed message", "VerifyRequest", map[string]interface{}{
+					"200": response("Verdict", ref("VerifyResponse")),
+					"400": response("Missing or malformed field, or invalid request body", oneOf("VerifyResponse", "ErrorResponse")),
+					"403": response("Address blocked by the server", ref("VerifyResponse")),
+					"413": response("Request body or message too large", oneOf("VerifyResponse", "ErrorResponse")),
+					"429": response("Rate limited", ref("ErrorResponse")),
+					"500": response("Internal failure", ref("VerifyResponse")),
+					"503": response("Server shutting down", ref("ErrorResponse")),
+				}),
+			},
+			"/v1/verify/batch": map[string]interface{}{
+				"post": operation("verifyBatch", "Verify a batch of signed messages", "BatchRequest", map[string]interface{}{
+					"200": response("One verdict per message, in request order", ref("BatchResponse")),
+					"400": response("Invalid request body, or batch larger than the rate limit burst with the code batch_exceeds_rate_limit", ref("ErrorResponse")),
+					"413": response("Request body or batch too large", ref("ErrorResponse")),
+					"429": response("Rate limited", ref("ErrorResponse")),
+					"503": response("Server shutting down", ref("ErrorResponse")),
+				}),
+			},
+			"/v1/verify/stream": map[string]interface{}{
+				"get": map[string]interface{}{
+					"operationId": "verifyStream",
+					"summary":     "Verify a WebSocket stream of signed messages",
+					"description": "Upgrades to a WebSocket. Each text frame holds a StreamRequest; a StreamResponse is sent for each as soon as it completes, so results may arrive out of order.",
+					"responses": map[string]interface{}{
+						"101": map[string]interface{}{"description": "Switching to the WebSocket protocol"},
+					},
+				},
+			},
+			"/healthz": map[string]interface{}{
+				"get": map[string]interface{}{
+					"operationId": "health",
+					"summary":     "Report that the server is up",
+					"responses": map[string]interface{}{
+						"200": map[string]interface{}{"description": "Server is up"},
+						"503": map[string]interface{}{"description": "Server shutting down"},
+					},
+				},
+			},
+		},
+		"components": map[string]interface{}{
+			"schemas": schemas,
+		},
+	}
+}
+
+// operation describes an operation taking a JSON body of the named schema
+func operation(id, summary, body string, responses map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"operationId": id,
+		"summary":     summary,
+		"requestBody": map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{"schema": ref(body)},
+			},
+		},
+		"responses": responses,
+	}
+}
+
+// response describes a JSON response with the schema
+func response(description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
+
+// ref refers to the named component schema
+func ref(name string) map[string]interface{} {
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// oneOf is a schema matching one of the named component schemas
+func oneOf(names ...string) map[string]interface{} {
+	refs := make([]interface{}, len(names))
+	for i, name := range names {
+		refs[i] = ref(name)
+	}
+	return map[string]interface{}{"oneOf": refs}
+}
+
+// structSchema returns the schema of a struct type from its JSON encoding.
+// Fields of embedded structs are flattened like encoding/json does, and
+// fields without omitempty are required.
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	var required []string
+
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				addFields(f.Type)
+				continue
+			}
+			name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = typeSchema(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// typeSchema returns the schema of a field type, referring to the component
+// schemas for the described struct types
+func typeSchema(t reflect.Type) map[string]interface{} {
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return ref(t.Name())
+	default:
+		return map[string]interface{}{}
+	}
+}
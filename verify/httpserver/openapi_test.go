@@ -0,0 +1,74 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestOpenAPI(t *testing.T) {
+	srv := httptest.NewServer(New())
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != string(OpenAPI()) {
+		t.Fatalf("GET /openapi.json status = %d, want %d and the OpenAPI document", resp.StatusCode, http.StatusOK)
+	}
+
+	var doc struct {
+		Paths      map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]interface{} `json:"properties"`
+				Required   []string               `json:"required"`
+			} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		t.Fatalf("OpenAPI document isn't JSON: %v", err)
+	}
+
+	for _, path := range []string{"/v1/verify", "/v1/verify/batch", "/v1/verify/stream", "/healthz"} {
+		if _, ok := doc.Paths[path]; !ok {
+			t.Errorf("path %s not documented", path)
+		}
+	}
+
+	tests := []struct {
+		schema       string
+		wantProps    []string
+		wantRequired []string
+	}{
+		{"VerifyRequest", []string{"address", "message", "signature"}, []string{"address", "message", "signature"}},
+		{"VerifyResponse", []string{"valid", "code", "error"}, []string{"valid"}},
+		{"BatchResponse", []string{"results", "valid", "invalid"}, []string{"results", "valid", "invalid"}},
+		{"StreamRequest", []string{"id", "address", "message", "signature"}, []string{"address", "message", "signature"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.schema, func(t *testing.T) {
+			schema, ok := doc.Components.Schemas[tt.schema]
+			if !ok {
+				t.Fatalf("schema %s not documented", tt.schema)
+			}
+			for _, prop := range tt.wantProps {
+				if _, ok := schema.Properties[prop]; !ok {
+					t.Errorf("property %s missing", prop)
+				}
+			}
+			if len(schema.Properties) != len(tt.wantProps) {
+				t.Errorf("properties = %v, want %v", schema.Properties, tt.wantProps)
+			}
+			if !reflect.DeepEqual(schema.Required, tt.wantRequired) {
+				t.Errorf("required = %v, want %v", schema.Required, tt.wantRequired)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package httpserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/sero/btc/verify/ratelimit"
+	"golang.org/x/net/websocket"
+)
+
+// StreamRequest is a frame of the streaming endpoint: a signed message and
+// an optional ID echoed back in its result
+type StreamRequest struct {
+	ID string `json:"id,omitempty"`
+	VerifyRequest
+}
+
+// StreamResponse is the result of a StreamRequest frame. Frames that can't
+// be decoded get a result with the code invalid_request or
+// request_too_large.
+type StreamResponse struct {
+	ID string `json:"id,omitempty"`
+	VerifyResponse
+}
+
+// handleStream serves GET /v1/verify/stream. Each text frame holds one
+// StreamRequest; the frames are verified concurrently and their results
+// are sent as soon as they complete, so they may arrive out of order. When
+// the server shuts down, the stream stops reading frames and is closed once
+// the frames it received are answered.
+func (s *Server) handleStream(ws *websocket.Conn) {
+	defer ws.Close()
+	ws.MaxPayloadBytes = int(s.maxBodySize)
+	if !s.trackStream(ws) {
+		return
+	}
+	defer s.untrackStream(ws)
+
+	var mu sync.Mutex
+	send := func(resp StreamResponse) {
+		mu.Lock()
+		defer mu.Unlock()
+		websocket.JSON.Send(ws, resp)
+	}
+
+	r := ws.Request()
+	slots := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		var frame []byte
+		err := websocket.Message.Receive(ws, &frame)
+		if errors.Is(err, websocket.ErrFrameTooLarge) {
+			send(StreamResponse{VerifyResponse: VerifyResponse{
+				Code:  CodeRequestTooLarge,
+				Error: fmt.Sprintf("frame exceeds %d bytes", s.maxBodySize),
+			}})
+			continue
+		}
+		if err != nil {
+			// The client closed the stream, or the server is shutting
+			// down
+			return
+		}
+
+		var req StreamRequest
+		if err := json.Unmarshal(frame, &req); err != nil {
+			send(StreamResponse{VerifyResponse: VerifyResponse{
+				Code:  CodeInvalidRequest,
+				Error: fmt.Sprintf("invalid frame: %v", err),
+			}})
+			continue
+		}
+
+		if !s.ipLimit.Allow(clientIP(r)) {
+			send(StreamResponse{ID: req.ID, VerifyResponse: VerifyResponse{
+				Code:  ratelimit.CodeRateLimited,
+				Error: "too many requests from this client",
+			}})
+			continue
+		}
+
+		// Stop reading frames while every worker is busy
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			send(StreamResponse{ID: req.ID, VerifyResponse: s.verifyLimited(r, req.VerifyRequest)})
+		}()
+	}
+}
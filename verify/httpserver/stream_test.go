@@ -0,0 +1,61 @@
+package httpserver
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sero/btc/verify"
+	"golang.org/x/net/websocket"
+)
+
+func TestStream(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	srv := httptest.NewServer(New(WithMaxBodySize(512)))
+	defer srv.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/v1/verify/stream", "", "http://localhost/")
+	if err != nil {
+		t.Fatalf("websocket.Dial() error = %v", err)
+	}
+	defer ws.Close()
+
+	frames := map[string]string{
+		"valid":     `{"id":"valid","address":"` + testAddress + `","message":"test message","signature":"` + testSignature + `"}`,
+		"invalid":   `{"id":"invalid","address":"` + testAddress + `","message":"other message","signature":"` + testSignature + `"}`,
+		"empty":     `{"id":"empty","address":"` + testAddress + `","message":"test message"}`,
+		"malformed": `{"id":`,
+		"too large": `{"id":"too large","message":"` + strings.Repeat("a", 600) + `"}`,
+	}
+	want := map[string]StreamResponse{
+		"valid":     {ID: "valid", VerifyResponse: VerifyResponse{Valid: true}},
+		"invalid":   {ID: "invalid", VerifyResponse: VerifyResponse{Code: verify.CodeAddressMismatch}},
+		"empty":     {ID: "empty", VerifyResponse: VerifyResponse{Code: verify.CodeEmptySignature}},
+		"malformed": {VerifyResponse: VerifyResponse{Code: CodeInvalidRequest}},
+		"too large": {VerifyResponse: VerifyResponse{Code: CodeRequestTooLarge}},
+	}
+
+	for _, frame := range frames {
+		if err := websocket.Message.Send(ws, frame); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	// Results arrive in completion order; frames that couldn't be decoded
+	// have no ID, so they are matched by code
+	got := make(map[verify.ErrorCode]StreamResponse)
+	for range frames {
+		var resp StreamResponse
+		if err := websocket.JSON.Receive(ws, &resp); err != nil {
+			t.Fatalf("Receive() error = %v", err)
+		}
+		got[resp.Code] = resp
+	}
+	for name, w := range want {
+		g, ok := got[w.Code]
+		if !ok || g.ID != w.ID || g.Valid != w.Valid {
+			t.Errorf("result for %s frame = %+v, want %+v", name, g, w)
+		}
+	}
+}
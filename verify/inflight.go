@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultInFlightWarningThreshold is the number of concurrent context-based
+// verifications above which a warning is logged.
+const DefaultInFlightWarningThreshold = 1000
+
+var (
+	// Number of context-based verifications currently running
+	inFlightVerifications atomic.Int64
+
+	// Gauge level above which a warning is logged, 0 disables the warning
+	inFlightWarningThreshold atomic.Int64
+)
+
+func init() {
+	inFlightWarningThreshold.Store(DefaultInFlightWarningThreshold)
+}
+
+// InFlightVerifications returns the number of context-based verifications
+// that are currently running.
+func InFlightVerifications() int64 {
+	return inFlightVerifications.Load()
+}
+
+// StrandedVerifications returns the number of verifications still running
+// even though their context was done and the caller has returned.
+//
+// Deprecated: verifications run on the caller's goroutine and stop at the
+// next stage once their context is done, so none are ever stranded and this
+// is always 0.
+func StrandedVerifications() int64 {
+	return 0
+}
+
+// SetInFlightWarningThreshold sets the number of in-flight verifications above
+// which a warning is logged. A threshold of 0 disables the warning.
+func SetInFlightWarningThreshold(threshold int64) {
+	inFlightWarningThreshold.Store(threshold)
+}
+
+// runWithContext runs verifyFn on the caller's goroutine, tracked by the
+// in-flight gauge. verifyFn checks the context between its stages, so a
+// context that is done stops the work rather than leaving it running in the
+// background.
+func runWithContext(ctx context.Context, verifyFn func(ctx context.Context) (bool, error)) (bool, error) {
+	events := eventLoggerFromContext(ctx)
+	startTime := time.Now()
+
+	inFlight := inFlightVerifications.Add(1)
+	defer inFlightVerifications.Add(-1)
+	if threshold := inFlightWarningThreshold.Load(); threshold > 0 && inFlight > threshold {
+		events.log(LogLevelWarning, "Verifications in flight exceed threshold", "in_flight", inFlight, "threshold", threshold)
+	}
+
+	valid, err := func() (bool, error) {
+		if err := checkContext(ctx); err != nil {
+			return false, err
+		}
+		return verifyFn(ctx)
+	}()
+	switch {
+	case errors.Is(err, ErrVerificationTimeout):
+		events.log(LogLevelError, "Context cancelled or timed out", "duration", time.Since(startTime), "error", err)
+		return false, err
+	case err != nil:
+		events.log(LogLevelError, "Signature verification error", "error", err)
+		return false, fmt.Errorf("signature verification error: %w", err)
+	}
+	events.log(LogLevelInfo, "Context-based verification result", "valid", valid)
+	return valid, nil
+}
+
+// checkContext returns an error wrapping both ErrVerificationTimeout and the
+// error of ctx once ctx is done
+func checkContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return newVerifyError(ErrVerificationTimeout, "%w", err)
+	}
+	return nil
+}
+
+// logContextDeadline logs the deadline of the context of a verification to
+// events
+func logContextDeadline(ctx context.Context, events eventLogger) {
+	if deadline, ok := ctx.Deadline(); ok {
+		events.log(LogLevelDebug, "Context has deadline", "deadline", deadline.Format(time.RFC3339), "timeout", time.Until(deadline))
+	} else {
+		events.log(LogLevelDebug, "Context has no deadline")
+	}
+}
@@ -0,0 +1,103 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRunWithContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	called := false
+	_, err := runWithContext(ctx, func(ctx context.Context) (bool, error) {
+		called = true
+		return true, nil
+	})
+	if !errors.Is(err, ErrVerificationTimeout) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("runWithContext() error = %v, want %v wrapping %v", err, ErrVerificationTimeout, context.Canceled)
+	}
+	if called {
+		t.Error("runWithContext() ran the verification of a cancelled context")
+	}
+}
+
+func TestRunWithContextInFlight(t *testing.T) {
+	before := InFlightVerifications()
+
+	var during int64
+	runWithContext(context.Background(), func(ctx context.Context) (bool, error) {
+		during = InFlightVerifications() - before
+		return true, nil
+	})
+	if during != 1 {
+		t.Errorf("InFlightVerifications() delta during verification = %d, want 1", during)
+	}
+	if got := InFlightVerifications() - before; got != 0 {
+		t.Errorf("InFlightVerifications() delta after verification = %d, want 0", got)
+	}
+	if got := StrandedVerifications(); got != 0 {
+		t.Errorf("StrandedVerifications() = %d, want 0", got)
+	}
+}
+
+func TestRunWithContextResult(t *testing.T) {
+	valid, err := runWithContext(context.Background(), func(ctx context.Context) (bool, error) {
+		return true, nil
+	})
+	if err != nil || !valid {
+		t.Errorf("runWithContext() = %v, %v, want true, nil", valid, err)
+	}
+
+	_, err = runWithContext(context.Background(), func(ctx context.Context) (bool, error) {
+		return false, ErrInvalidSignature
+	})
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("runWithContext() error = %v, want %v", err, ErrInvalidSignature)
+	}
+}
+
+func TestVerifyWithContextStopsBetweenStages(t *testing.T) {
+	defer SetTracer(GetTracer())
+	tv := walletTestVectors[2].msg
+
+	for _, stage := range []string{SpanDecode, SpanRecover, SpanDerive} {
+		t.Run(stage, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			rec := &cancellingTracer{at: stage, cancel: cancel}
+			SetTracer(rec)
+
+			valid, err := VerifyBip137SignatureWithContext(ctx, tv)
+			if valid || !errors.Is(err, ErrVerificationTimeout) {
+				t.Fatalf("VerifyBip137SignatureWithContext() = %v, %v, want %v", valid, err, ErrVerificationTimeout)
+			}
+			for _, name := range rec.started {
+				if name == SpanCompare {
+					t.Errorf("spans %v: verification went on after the %s stage", rec.started, stage)
+				}
+			}
+		})
+	}
+}
+
+// Helper tracer cancelling the verification when the span of a stage starts,
+// after skipping skip of them
+type cancellingTracer struct {
+	at      string
+	skip    int
+	cancel  context.CancelFunc
+	started []string
+}
+
+func (c *cancellingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	c.started = append(c.started, name)
+	if name == c.at {
+		if c.skip == 0 {
+			c.cancel()
+		}
+		c.skip--
+	}
+	return ctx, noopSpan{}
+}
@@ -0,0 +1,73 @@
+// Package drain tracks the work in flight of a server, so it can stop
+// taking new work and wait for the work in flight to complete when shutting
+// down.
+package drain
+
+import (
+	"context"
+	"sync"
+)
+
+// Group counts work in flight. The zero value is ready to use and takes
+// work until Close is called. A Group is safe for concurrent use.
+type Group struct {
+	mu      sync.Mutex
+	active  int
+	closed  bool
+	drained chan struct{}
+}
+
+// Begin starts a unit of work, returning false once the group is closed.
+// Every successful Begin must be followed by End.
+func (g *Group) Begin() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return false
+	}
+	g.active++
+	return true
+}
+
+// End completes a unit of work started with Begin
+func (g *Group) End() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.active--
+	if g.closed && g.active == 0 {
+		close(g.drained)
+	}
+}
+
+// Close stops the group from taking new work
+func (g *Group) Close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return
+	}
+	g.closed = true
+	g.drained = make(chan struct{})
+	if g.active == 0 {
+		close(g.drained)
+	}
+}
+
+// Closed reports whether the group was closed
+func (g *Group) Closed() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.closed
+}
+
+// Wait closes the group and waits for the work in flight to complete,
+// returning the error of ctx if it's done first
+func (g *Group) Wait(ctx context.Context) error {
+	g.Close()
+	select {
+	case <-g.drained:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,30 @@
+package drain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGroup(t *testing.T) {
+	var g Group
+	if !g.Begin() || !g.Begin() {
+		t.Fatal("Begin() = false before Close()")
+	}
+	g.End()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := g.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() with work in flight error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if g.Begin() || !g.Closed() {
+		t.Error("Begin() = true after Close()")
+	}
+
+	g.End()
+	if err := g.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() once drained error = %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// ParseSignedMessageJSON parses the canonical JSON form of a signed message,
+// as produced by encoding/json:
+//
+//	{"address": "...", "message": "...", "signature": "...", "network": "mainnet", "type": "p2pkh"}
+//
+// The network and type fields are optional. Unknown fields, missing fields,
+// malformed signatures and addresses that don't match the stated network or
+// type are rejected, so a parsed message is well-formed, though its
+// signature isn't verified. Without a network, the address must belong to
+// one of the known networks.
+func ParseSignedMessageJSON(data []byte) (SignedMessage, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+
+	var msg SignedMessage
+	if err := dec.Decode(&msg); err != nil {
+		return SignedMessage{}, newVerifyError(ErrMalformedJSON, "%v", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return SignedMessage{}, newVerifyError(ErrMalformedJSON, "unexpected data after the signed message")
+	}
+
+	switch {
+	case msg.Address == "":
+		return SignedMessage{}, ErrEmptyAddress
+	case msg.Message == "":
+		return SignedMessage{}, ErrEmptyMessage
+	case msg.Signature == "":
+		return SignedMessage{}, ErrEmptySignature
+	}
+	if err := checkMessageSize(eventLogger{}, msg.Message, MaxMessageSize()); err != nil {
+		return SignedMessage{}, err
+	}
+	if _, err := base64.StdEncoding.DecodeString(msg.Signature); err != nil {
+		return SignedMessage{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+
+	addr, err := decodeStatedAddress(msg.Address, msg.Network)
+	if err != nil {
+		return SignedMessage{}, err
+	}
+	if msg.Type != "" && msg.Type != addressTypeOf(addr) {
+		return SignedMessage{}, newVerifyError(ErrMalformedJSON, "type %q does not match %s address '%s'", msg.Type, addressTypeOf(addr), msg.Address)
+	}
+
+	return msg, nil
+}
+
+// decodeStatedAddress decodes an address for the named network, or for the
+// first known network it belongs to when no network is named
+func decodeStatedAddress(address, network string) (btcutil.Address, error) {
+	if network != "" {
+		params := networkByName(network)
+		if params == nil {
+			return nil, newVerifyError(ErrMalformedJSON, "unknown network %q", network)
+		}
+		return decodeAddress(address, params)
+	}
+
+	for _, params := range knownNetworks {
+		if addr, err := btcutil.DecodeAddress(address, params); err == nil && addr.IsForNet(params) {
+			return addr, nil
+		}
+	}
+	return nil, newVerifyError(ErrInvalidAddress, "could not decode address '%s' for any known network", address)
+}
+
+// networkByName returns the known network with the name, accepting
+// "testnet" for testnet3, or nil if there is none
+func networkByName(name string) *chaincfg.Params {
+	if name == "testnet" {
+		name = chaincfg.TestNet3Params.Name
+	}
+	for _, params := range knownNetworks {
+		if params.Name == name {
+			return params
+		}
+	}
+	return nil
+}
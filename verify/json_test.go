@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestSignedMessageJSON(t *testing.T) {
+	msg := SignedMessage{
+		Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+		Message:   "test message",
+		Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"address":"1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5","message":"test message","signature":"IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+
+	msg.Network = "mainnet"
+	msg.Type = AddressTypeP2PKH
+	data, _ = json.Marshal(msg)
+	got, err := ParseSignedMessageJSON(data)
+	if err != nil {
+		t.Fatalf("ParseSignedMessageJSON() error = %v", err)
+	}
+	if got != msg {
+		t.Errorf("ParseSignedMessageJSON() = %+v, want %+v", got, msg)
+	}
+}
+
+func TestParseSignedMessageJSON(t *testing.T) {
+	const (
+		address   = `"address":"1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"`
+		message   = `"message":"test message"`
+		signature = `"signature":"IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA="`
+	)
+
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "Without network", data: `{` + address + `,` + message + `,` + signature + `}`},
+		{name: "Testnet alias", data: `{"address":"msgXyBvhCMiwsopsDU8D8KyUinbzp5AzHw",` + message + `,` + signature + `,"network":"testnet"}`},
+		{name: "Missing signature", data: `{` + address + `,` + message + `}`, wantErr: ErrEmptySignature},
+		{name: "Not JSON", data: `address=1DAag8`, wantErr: ErrMalformedJSON},
+		{name: "Unknown field", data: `{` + address + `,` + message + `,` + signature + `,"pubkey":"02"}`, wantErr: ErrMalformedJSON},
+		{name: "Trailing data", data: `{` + address + `,` + message + `,` + signature + `} {}`, wantErr: ErrMalformedJSON},
+		{name: "Unknown network", data: `{` + address + `,` + message + `,` + signature + `,"network":"litecoin"}`, wantErr: ErrMalformedJSON},
+		{name: "Wrong network", data: `{` + address + `,` + message + `,` + signature + `,"network":"testnet3"}`, wantErr: ErrNetworkMismatch},
+		{name: "Wrong type", data: `{` + address + `,` + message + `,` + signature + `,"type":"p2wpkh"}`, wantErr: ErrMalformedJSON},
+		{name: "Invalid address", data: `{"address":"1DAag8qiPLHh6hMFVu9qJQm9ro1Htwuy00",` + message + `,` + signature + `}`, wantErr: ErrInvalidAddress},
+		{name: "Malformed signature", data: `{` + address + `,` + message + `,"signature":"not base64!"}`, wantErr: ErrMalformedSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSignedMessageJSON([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseSignedMessageJSON() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
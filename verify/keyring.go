@@ -0,0 +1,278 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// keyringVersion is the version of the keyring file format
+const keyringVersion = 1
+
+// KeyringEntry is a trusted identity of a Keyring: a label naming the signer
+// and either the address or the public key it signs with
+type KeyringEntry struct {
+	// Label names the signer and is unique within the keyring
+	Label string `json:"label"`
+
+	// Address is the address the signer signs with, empty for public key
+	// entries
+	Address string `json:"address,omitempty"`
+
+	// PubKey is the hex-encoded public key the signer signs with, in
+	// compressed, uncompressed or 32-byte x-only form, empty for address
+	// entries. A public key entry matches signatures for any address type.
+	PubKey string `json:"pubkey,omitempty"`
+
+	// RevokedAt is when the entry was revoked, nil while it is trusted
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// keyringFile is the JSON form of a keyring
+type keyringFile struct {
+	Version int            `json:"version"`
+	Network string         `json:"network"`
+	Entries []KeyringEntry `json:"entries"`
+}
+
+// Keyring holds the trusted identities of a network and tells which of them,
+// if any, signed a message. A Keyring is safe for concurrent use.
+type Keyring struct {
+	params *chaincfg.Params
+
+	mu      sync.RWMutex
+	entries []KeyringEntry
+}
+
+// NewKeyring creates an empty keyring for the network
+func NewKeyring(params *chaincfg.Params) *Keyring {
+	return &Keyring{params: params}
+}
+
+// Add adds an identity to the keyring. The label must be new, and exactly
+// one of the address, which must be for the network of the keyring, and the
+// public key must be set. Entries with RevokedAt set are added revoked.
+func (k *Keyring) Add(entry KeyringEntry) error {
+	if entry.Label == "" {
+		return newVerifyError(ErrInvalidKeyringEntry, "empty label")
+	}
+	switch {
+	case entry.Address == "" && entry.PubKey == "":
+		return newVerifyError(ErrInvalidKeyringEntry, "entry %q has neither an address nor a public key", entry.Label)
+	case entry.Address != "" && entry.PubKey != "":
+		return newVerifyError(ErrInvalidKeyringEntry, "entry %q has both an address and a public key", entry.Label)
+	case entry.Address != "":
+		if _, err := decodeAddress(entry.Address, k.params); err != nil {
+			return err
+		}
+	default:
+		if _, err := parseKeyringPubKey(entry.PubKey); err != nil {
+			return newVerifyError(ErrInvalidKeyringEntry, "entry %q: %v", entry.Label, err)
+		}
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, e := range k.entries {
+		if e.Label == entry.Label {
+			return newVerifyError(ErrInvalidKeyringEntry, "duplicate label %q", entry.Label)
+		}
+	}
+	k.entries = append(k.entries, entry)
+	return nil
+}
+
+// Entries returns the entries of the keyring in the order they were added
+func (k *Keyring) Entries() []KeyringEntry {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return append([]KeyringEntry(nil), k.entries...)
+}
+
+// Revoke marks the entry with the label as revoked at the given time.
+// Signatures by a revoked entry still verify, but are reported as untrusted.
+// Revoking a revoked entry keeps its first revocation time.
+func (k *Keyring) Revoke(label string, at time.Time) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for i, e := range k.entries {
+		if e.Label != label {
+			continue
+		}
+		if e.RevokedAt == nil {
+			at = at.UTC()
+			k.entries[i].RevokedAt = &at
+		}
+		return nil
+	}
+	return newVerifyError(ErrInvalidKeyringEntry, "no entry labeled %q", label)
+}
+
+// Verify identifies the trusted identity that signed a message with a
+// 65-byte compact signature. The public key is recovered from the signature
+// and compared with each entry in turn; address entries also check that the
+// header byte suits the address, as Verify does. A signature by none of the
+// entries, or only by revoked entries, fails with ErrUntrustedSigner; the
+// revoked entry is returned so callers can tell who signed.
+func (k *Keyring) Verify(message, signatureBase64 string) (bool, KeyringEntry, error) {
+	// Recovery validates the inputs and finds the one key to look for
+	rec, err := Recover(message, signatureBase64, k.params)
+	if err != nil {
+		return false, KeyringEntry{}, err
+	}
+	sigBytes, _ := base64.StdEncoding.DecodeString(signatureBase64)
+	recovered, _ := hex.DecodeString(rec.PubKey)
+	signer, err := btcec.ParsePubKey(recovered)
+	if err != nil {
+		return false, KeyringEntry{}, newVerifyError(ErrInvalidSignature, "could not parse recovered pubkey: %v", err)
+	}
+
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	var revoked []KeyringEntry
+	for _, entry := range k.entries {
+		if !k.signedBy(entry, signer, rec.Compressed, sigBytes[0]) {
+			continue
+		}
+		if entry.RevokedAt != nil {
+			revoked = append(revoked, entry)
+			continue
+		}
+		logEvent(LogLevelInfo, "Signature matches keyring entry", "label", entry.Label)
+		return true, entry, nil
+	}
+
+	if len(revoked) > 0 {
+		entry := revoked[0]
+		logEvent(LogLevelError, "Signature matches revoked keyring entry", "label", entry.Label, "revoked_at", *entry.RevokedAt)
+		return false, entry, newVerifyError(ErrUntrustedSigner, "signed by %q, revoked at %s", entry.Label, entry.RevokedAt.Format(time.RFC3339))
+	}
+
+	logEvent(LogLevelError, "Signer not in keyring", "recovered_pubkey", rec.PubKey)
+	return false, KeyringEntry{}, newVerifyError(ErrUntrustedSigner, "signer %s matches none of the %d keyring entries", rec.PubKey, len(k.entries))
+}
+
+// VerifyAgainstKeyring identifies the trusted identity of the keyring that
+// signed a message. See Keyring.Verify.
+func VerifyAgainstKeyring(keyring *Keyring, message, signatureBase64 string) (bool, KeyringEntry, error) {
+	return keyring.Verify(message, signatureBase64)
+}
+
+// ReadKeyring reads a keyring written by WriteKeyring. The network and
+// every entry are checked as Add does.
+func ReadKeyring(r io.Reader) (*Keyring, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var f keyringFile
+	if err := dec.Decode(&f); err != nil {
+		return nil, newVerifyError(ErrMalformedKeyring, "%v", err)
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, newVerifyError(ErrMalformedKeyring, "unexpected data after the keyring")
+	}
+	if f.Version != keyringVersion {
+		return nil, newVerifyError(ErrMalformedKeyring, "unsupported version %d", f.Version)
+	}
+	params := networkByName(f.Network)
+	if params == nil {
+		return nil, newVerifyError(ErrMalformedKeyring, "unknown network %q", f.Network)
+	}
+
+	k := NewKeyring(params)
+	for _, entry := range f.Entries {
+		if err := k.Add(entry); err != nil {
+			return nil, err
+		}
+	}
+	return k, nil
+}
+
+// WriteKeyring writes the keyring as indented JSON, with its network and
+// the revocation times of its entries
+func WriteKeyring(w io.Writer, k *Keyring) error {
+	f := keyringFile{Version: keyringVersion, Network: k.params.Name, Entries: k.Entries()}
+	if f.Entries == nil {
+		f.Entries = []KeyringEntry{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// LoadKeyring reads the keyring file at path. See ReadKeyring.
+func LoadKeyring(path string) (*Keyring, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadKeyring(f)
+}
+
+// SaveKeyring writes the keyring to the file at path. The file is replaced
+// atomically, so readers never see a partly written keyring.
+func SaveKeyring(path string, k *Keyring) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := WriteKeyring(tmp, k); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// signedBy reports whether the recovered public key and header byte of a
+// signature belong to the keyring entry
+func (k *Keyring) signedBy(entry KeyringEntry, signer *btcec.PublicKey, compressed bool, header byte) bool {
+	if entry.PubKey != "" {
+		pubKey, err := hex.DecodeString(entry.PubKey)
+		if err != nil {
+			return false
+		}
+		if len(pubKey) == schnorr.PubKeyBytesLen {
+			return bytes.Equal(schnorr.SerializePubKey(signer), pubKey)
+		}
+		trusted, err := btcec.ParsePubKey(pubKey)
+		return err == nil && trusted.IsEqual(signer)
+	}
+
+	addr, err := decodeAddress(entry.Address, k.params)
+	if err != nil {
+		return false
+	}
+	derived, err := deriveAddressForHeader(signer, compressed, header, addr, k.params, TaprootKeyInternal)
+	return err == nil && derived == addr.EncodeAddress()
+}
+
+// parseKeyringPubKey parses the hex-encoded public key of a keyring entry
+func parseKeyringPubKey(pubKeyHex string) ([]byte, error) {
+	pubKey, err := hex.DecodeString(pubKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKey) == schnorr.PubKeyBytesLen {
+		_, err = schnorr.ParsePubKey(pubKey)
+	} else {
+		_, err = btcec.ParsePubKey(pubKey)
+	}
+	return pubKey, err
+}
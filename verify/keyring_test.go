@@ -0,0 +1,223 @@
+package verify
+
+import (
+	"encoding/hex"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestKeyringVerify(t *testing.T) {
+	privKey := testPrivKey(testKeySeed)
+
+	tests := []struct {
+		name      string
+		entries   []KeyringEntry
+		msg       SignedMessage
+		wantLabel string
+		wantErr   error
+	}{
+		{
+			name:      "Address entry",
+			entries:   []KeyringEntry{{Label: "segwit", Address: walletTestVectors[5].msg.Address}, {Label: "bms", Address: walletTestVectors[2].msg.Address}},
+			msg:       walletTestVectors[2].msg,
+			wantLabel: "bms",
+		},
+		{
+			name:      "Public key entry",
+			entries:   []KeyringEntry{{Label: "test key", PubKey: hex.EncodeToString(privKey.PubKey().SerializeCompressed())}},
+			msg:       signTestMessage(t, testKeySeed, nil, "keyring test"),
+			wantLabel: "test key",
+		},
+		{
+			name:      "Uncompressed public key entry",
+			entries:   []KeyringEntry{{Label: "test key", PubKey: hex.EncodeToString(privKey.PubKey().SerializeUncompressed())}},
+			msg:       signTestMessage(t, testKeySeed, nil, "keyring test"),
+			wantLabel: "test key",
+		},
+		{
+			name:      "X-only public key entry",
+			entries:   []KeyringEntry{{Label: "test key", PubKey: hex.EncodeToString(schnorr.SerializePubKey(privKey.PubKey()))}},
+			msg:       signTestMessage(t, testKeySeed, nil, "keyring test"),
+			wantLabel: "test key",
+		},
+		{
+			name:    "Untrusted signer",
+			entries: []KeyringEntry{{Label: "bms", Address: walletTestVectors[2].msg.Address}},
+			msg:     walletTestVectors[5].msg,
+			wantErr: ErrUntrustedSigner,
+		},
+		{
+			name:    "Empty keyring",
+			msg:     walletTestVectors[2].msg,
+			wantErr: ErrUntrustedSigner,
+		},
+		{
+			name:    "Malformed signature",
+			entries: []KeyringEntry{{Label: "bms", Address: walletTestVectors[2].msg.Address}},
+			msg:     SignedMessage{Message: "keyring test", Signature: "not base64"},
+			wantErr: ErrMalformedSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring := NewKeyring(&chaincfg.MainNetParams)
+			for _, entry := range tt.entries {
+				if err := keyring.Add(entry); err != nil {
+					t.Fatalf("Add(%q) error = %v", entry.Label, err)
+				}
+			}
+
+			valid, entry, err := VerifyAgainstKeyring(keyring, tt.msg.Message, tt.msg.Signature)
+			if tt.wantErr != nil {
+				if valid || !errors.Is(err, tt.wantErr) {
+					t.Errorf("VerifyAgainstKeyring() = %v, %v, want error %v", valid, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !valid {
+				t.Fatalf("VerifyAgainstKeyring() = %v, %v, want true", valid, err)
+			}
+			if entry.Label != tt.wantLabel {
+				t.Errorf("VerifyAgainstKeyring() label = %q, want %q", entry.Label, tt.wantLabel)
+			}
+		})
+	}
+}
+
+func TestKeyringAdd(t *testing.T) {
+	address := walletTestVectors[2].msg.Address
+
+	tests := []struct {
+		name    string
+		entry   KeyringEntry
+		wantErr error
+	}{
+		{name: "Address", entry: KeyringEntry{Label: "new", Address: address}},
+		{name: "Duplicate label", entry: KeyringEntry{Label: "existing", Address: address}, wantErr: ErrInvalidKeyringEntry},
+		{name: "Empty label", entry: KeyringEntry{Address: address}, wantErr: ErrInvalidKeyringEntry},
+		{name: "Neither address nor public key", entry: KeyringEntry{Label: "new"}, wantErr: ErrInvalidKeyringEntry},
+		{name: "Both address and public key", entry: KeyringEntry{Label: "new", Address: address, PubKey: "02"}, wantErr: ErrInvalidKeyringEntry},
+		{name: "Invalid public key", entry: KeyringEntry{Label: "new", PubKey: "02abcd"}, wantErr: ErrInvalidKeyringEntry},
+		{name: "Address for other network", entry: KeyringEntry{Label: "new", Address: "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"}, wantErr: ErrNetworkMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyring := NewKeyring(&chaincfg.MainNetParams)
+			if err := keyring.Add(KeyringEntry{Label: "existing", Address: address}); err != nil {
+				t.Fatalf("Add() error = %v", err)
+			}
+
+			err := keyring.Add(tt.entry)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("Add() error = %v, want %v", err, tt.wantErr)
+			}
+			wantLen := 1
+			if tt.wantErr == nil {
+				wantLen = 2
+			}
+			if got := len(keyring.Entries()); got != wantLen {
+				t.Errorf("len(Entries()) = %d, want %d", got, wantLen)
+			}
+		})
+	}
+}
+
+func TestKeyringRevoke(t *testing.T) {
+	msg := walletTestVectors[2].msg
+	keyring := NewKeyring(&chaincfg.MainNetParams)
+	if err := keyring.Add(KeyringEntry{Label: "bms", Address: msg.Address}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	revokedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := keyring.Revoke("bms", revokedAt); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	// Revoking again keeps the first revocation time
+	if err := keyring.Revoke("bms", revokedAt.Add(time.Hour)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	if err := keyring.Revoke("unknown", revokedAt); !errors.Is(err, ErrInvalidKeyringEntry) {
+		t.Errorf("Revoke(unknown) error = %v, want %v", err, ErrInvalidKeyringEntry)
+	}
+
+	valid, entry, err := keyring.Verify(msg.Message, msg.Signature)
+	if valid || !errors.Is(err, ErrUntrustedSigner) {
+		t.Errorf("Verify() = %v, %v, want error %v", valid, err, ErrUntrustedSigner)
+	}
+	if entry.Label != "bms" || entry.RevokedAt == nil || !entry.RevokedAt.Equal(revokedAt) {
+		t.Errorf("Verify() entry = %+v, want bms revoked at %v", entry, revokedAt)
+	}
+
+	// A trusted entry for the same signer takes precedence
+	if err := keyring.Add(KeyringEntry{Label: "bms again", Address: msg.Address}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if valid, entry, err := keyring.Verify(msg.Message, msg.Signature); !valid || entry.Label != "bms again" {
+		t.Errorf("Verify() = %v, %q, %v, want true, %q", valid, entry.Label, err, "bms again")
+	}
+}
+
+func TestKeyringSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	keyring := NewKeyring(&chaincfg.TestNet3Params)
+	for _, entry := range []KeyringEntry{
+		{Label: "alice", Address: "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"},
+		{Label: "bob", PubKey: "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798"},
+	} {
+		if err := keyring.Add(entry); err != nil {
+			t.Fatalf("Add(%q) error = %v", entry.Label, err)
+		}
+	}
+	if err := keyring.Revoke("bob", time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	if err := SaveKeyring(path, keyring); err != nil {
+		t.Fatalf("SaveKeyring() error = %v", err)
+	}
+	loaded, err := LoadKeyring(path)
+	if err != nil {
+		t.Fatalf("LoadKeyring() error = %v", err)
+	}
+	if loaded.params != &chaincfg.TestNet3Params {
+		t.Errorf("LoadKeyring() network = %s, want %s", loaded.params.Name, chaincfg.TestNet3Params.Name)
+	}
+	if got, want := loaded.Entries(), keyring.Entries(); !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadKeyring() entries = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadKeyring(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr error
+	}{
+		{name: "Empty keyring", input: `{"version":1,"network":"mainnet","entries":[]}`},
+		{name: "Unsupported version", input: `{"version":2,"network":"mainnet","entries":[]}`, wantErr: ErrMalformedKeyring},
+		{name: "Unknown network", input: `{"version":1,"network":"litecoin","entries":[]}`, wantErr: ErrMalformedKeyring},
+		{name: "Unknown field", input: `{"version":1,"network":"mainnet","entries":[],"owner":"me"}`, wantErr: ErrMalformedKeyring},
+		{name: "Trailing data", input: `{"version":1,"network":"mainnet","entries":[]} {}`, wantErr: ErrMalformedKeyring},
+		{name: "Duplicate label", input: `{"version":1,"network":"mainnet","entries":[{"label":"a","address":"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"},{"label":"a","address":"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH"}]}`, wantErr: ErrInvalidKeyringEntry},
+		{name: "Address for other network", input: `{"version":1,"network":"mainnet","entries":[{"label":"a","address":"mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r"}]}`, wantErr: ErrNetworkMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadKeyring(strings.NewReader(tt.input))
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Errorf("ReadKeyring() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
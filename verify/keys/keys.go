@@ -0,0 +1,97 @@
+// Package keys generates secp256k1 keys and derives the addresses they sign
+// for, for tests, example programs and test vector generation:
+//
+//	privKey, err := keys.Generate()
+//	info, err := keys.Describe(privKey, &chaincfg.MainNetParams)
+//
+// Keys generated from a seed are deterministic and must only be used for
+// testing.
+package keys
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// Info is a private key in wallet import format with its public key and
+// the addresses of every type it signs for
+type Info struct {
+	Network           string `json:"network"`
+	WIF               string `json:"wif"`
+	PubKey            string `json:"pubkey"`
+	P2PKH             string `json:"p2pkh"`
+	P2PKHUncompressed string `json:"p2pkh_uncompressed"`
+	P2SHP2WPKH        string `json:"p2sh_p2wpkh"`
+	P2WPKH            string `json:"p2wpkh"`
+	P2TR              string `json:"p2tr"`
+}
+
+// Generate generates a random private key
+func Generate() (*btcec.PrivateKey, error) {
+	return btcec.NewPrivateKey()
+}
+
+// FromSeed derives a private key from a seed, as the SHA-256 hash of the
+// seed. The same seed always gives the same key, so anyone knowing the seed
+// knows the key.
+func FromSeed(seed string) *btcec.PrivateKey {
+	sum := sha256.Sum256([]byte(seed))
+	privKey, _ := btcec.PrivKeyFromBytes(sum[:])
+	return privKey
+}
+
+// Describe returns the WIF, the compressed public key and the addresses of
+// the private key on the network. The WIF is for the compressed public key,
+// which every address but P2PKHUncompressed is derived from; the P2TR
+// address commits to the key without a script path, as in BIP-86.
+func Describe(privKey *btcec.PrivateKey, params *chaincfg.Params) (Info, error) {
+	wif, err := btcutil.NewWIF(privKey, params, true)
+	if err != nil {
+		return Info{}, err
+	}
+	pubKey := privKey.PubKey()
+	pubKeyHash := btcutil.Hash160(pubKey.SerializeCompressed())
+
+	p2pkh, err := btcutil.NewAddressPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		return Info{}, err
+	}
+	p2pkhUncompressed, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(pubKey.SerializeUncompressed()), params)
+	if err != nil {
+		return Info{}, err
+	}
+	witnessScript, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(pubKeyHash).Script()
+	if err != nil {
+		return Info{}, err
+	}
+	p2shP2WPKH, err := btcutil.NewAddressScriptHash(witnessScript, params)
+	if err != nil {
+		return Info{}, err
+	}
+	p2wpkh, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+	if err != nil {
+		return Info{}, err
+	}
+	outputKey := txscript.ComputeTaprootKeyNoScript(pubKey)
+	p2tr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), params)
+	if err != nil {
+		return Info{}, err
+	}
+
+	return Info{
+		Network:           params.Name,
+		WIF:               wif.String(),
+		PubKey:            hex.EncodeToString(pubKey.SerializeCompressed()),
+		P2PKH:             p2pkh.EncodeAddress(),
+		P2PKHUncompressed: p2pkhUncompressed.EncodeAddress(),
+		P2SHP2WPKH:        p2shP2WPKH.EncodeAddress(),
+		P2WPKH:            p2wpkh.EncodeAddress(),
+		P2TR:              p2tr.EncodeAddress(),
+	}, nil
+}
@@ -0,0 +1,111 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/signer"
+)
+
+func TestDescribe(t *testing.T) {
+	// Private key 1, whose public key is the generator point
+	var one [32]byte
+	one[31] = 1
+	privKey, _ := btcec.PrivKeyFromBytes(one[:])
+
+	tests := []struct {
+		name   string
+		params *chaincfg.Params
+		want   Info
+	}{
+		{
+			name:   "Mainnet",
+			params: &chaincfg.MainNetParams,
+			want: Info{
+				Network:           "mainnet",
+				WIF:               "KwDiBf89QgGbjEhKnhXJuH7LrciVrZi3qYjgd9M7rFU73sVHnoWn",
+				PubKey:            "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+				P2PKH:             "1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",
+				P2PKHUncompressed: "1EHNa6Q4Jz2uvNExL497mE43ikXhwF6kZm",
+				P2SHP2WPKH:        "3JvL6Ymt8MVWiCNHC7oWU6nLeHNJKLZGLN",
+				P2WPKH:            "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			},
+		},
+		{
+			name:   "Testnet",
+			params: &chaincfg.TestNet3Params,
+			want: Info{
+				Network:           "testnet3",
+				WIF:               "cMahea7zqjxrtgAbB7LSGbcQUr1uX1ojuat9jZodMN87JcbXMTcA",
+				PubKey:            "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+				P2PKH:             "mrCDrCybB6J1vRfbwM5hemdJz73FwDBC8r",
+				P2PKHUncompressed: "mtoKs9V381UAhUia3d7Vb9GNak8Qvmcsme",
+				P2SHP2WPKH:        "2NAUYAHhujozruyzpsFRP63mbrdaU5wnEpN",
+				P2WPKH:            "tb1qw508d6qejxtdg4y5r3zarvary0c5xw7kxpjzsx",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Describe(privKey, tt.params)
+			if err != nil {
+				t.Fatalf("Describe() error = %v", err)
+			}
+			// The P2TR address is checked against a signature below
+			got.P2TR = ""
+			if got != tt.want {
+				t.Errorf("Describe() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDescribeSigns(t *testing.T) {
+	privKey := FromSeed("keys test")
+	info, err := Describe(privKey, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("Describe() error = %v", err)
+	}
+	key, err := signer.ImportWIF(info.WIF, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("ImportWIF() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		address  string
+		signType verify.AddressType
+	}{
+		{name: "P2PKH", address: info.P2PKH, signType: verify.AddressTypeP2PKH},
+		{name: "P2SH-P2WPKH", address: info.P2SHP2WPKH, signType: verify.AddressTypeP2SHP2WPKH},
+		{name: "P2WPKH", address: info.P2WPKH, signType: verify.AddressTypeP2WPKH},
+		// Signatures for P2TR addresses use the P2PKH compressed header
+		{name: "P2TR", address: info.P2TR, signType: verify.AddressTypeP2PKH},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := key.Sign("keys test", tt.signType)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			valid, err := verify.VerifyBip137Signature(tt.address, msg.Message, msg.Signature)
+			if err != nil || !valid {
+				t.Errorf("VerifyBip137Signature(%s) = %v, %v, want true", tt.address, valid, err)
+			}
+		})
+	}
+}
+
+func TestFromSeed(t *testing.T) {
+	a, b := FromSeed("seed"), FromSeed("seed")
+	if !a.Key.Equals(&b.Key) {
+		t.Error("FromSeed() returned different keys for the same seed")
+	}
+	if c := FromSeed("other seed"); a.Key.Equals(&c.Key) {
+		t.Error("FromSeed() returned the same key for different seeds")
+	}
+}
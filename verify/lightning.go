@@ -0,0 +1,101 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// lightningMessagePrefix is prepended to messages signed by Lightning nodes
+const lightningMessagePrefix = "Lightning Signed Message:"
+
+// zbase32Alphabet is the alphabet of z-base-32, the encoding of Lightning
+// message signatures
+const zbase32Alphabet = "ybndrfg8ejkmcpqxot1uwisza345h769"
+
+// LightningMessageHash returns the digest a Lightning node signs for the
+// message: the double SHA-256 of "Lightning Signed Message:" followed by the
+// message, without the length prefixes of the Bitcoin signed message format.
+func LightningMessageHash(message string) [32]byte {
+	return doubleSHA256([]byte(lightningMessagePrefix + message))
+}
+
+// RecoverLightningNodeKey recovers the public key of the node that signed
+// the message, from a z-base-32 signature as returned by LND's signmessage
+// and c-lightning's signmessage "zbase" field.
+func RecoverLightningNodeKey(message, signatureZBase32 string) (*btcec.PublicKey, error) {
+	switch {
+	case message == "":
+		return nil, ErrEmptyMessage
+	case signatureZBase32 == "":
+		return nil, ErrEmptySignature
+	}
+	if err := checkMessageSize(eventLogger{}, message, MaxMessageSize()); err != nil {
+		return nil, err
+	}
+
+	sigBytes, err := decodeZBase32(signatureZBase32)
+	if err != nil {
+		return nil, err
+	}
+	if len(sigBytes) != compactSignatureLength {
+		return nil, newVerifyError(ErrMalformedSignature, "invalid signature length: %d, want %d", len(sigBytes), compactSignatureLength)
+	}
+	// Node signatures use the P2PKH header bytes, SegWit ones don't apply
+	if sigBytes[0] >= headerP2SHP2WPKH {
+		return nil, newVerifyError(ErrInvalidHeaderByte, "0x%02x is not a Lightning signature header", sigBytes[0])
+	}
+
+	digest := LightningMessageHash(message)
+	pubKey, _, err := recoverPubKey(sigBytes, digest[:])
+	return pubKey, err
+}
+
+// VerifyLightningMessage verifies a z-base-32 signature of the message by
+// the Lightning node with the given public key, letting operators check
+// node-key attestations made with LND's or c-lightning's signmessage.
+func VerifyLightningMessage(nodeKey *btcec.PublicKey, message, signatureZBase32 string) (bool, error) {
+	if nodeKey == nil {
+		logEvent(LogLevelError, "Empty public key provided")
+		return false, ErrEmptyPublicKey
+	}
+
+	recovered, err := RecoverLightningNodeKey(message, signatureZBase32)
+	if err != nil {
+		logEvent(LogLevelError, "Failed to recover Lightning node key", "error", err)
+		return false, err
+	}
+
+	want, got := nodeKey.SerializeCompressed(), recovered.SerializeCompressed()
+	if !bytes.Equal(got, want) {
+		logEvent(LogLevelError, "Lightning node key mismatch", "recovered", hex.EncodeToString(got), "expected", hex.EncodeToString(want))
+		return false, newVerifyError(ErrAddressMismatch, "recovered node key %x does not match %x", got, want)
+	}
+
+	logEvent(LogLevelInfo, "Lightning signature verification successful", "node_key", hex.EncodeToString(want))
+	return true, nil
+}
+
+// decodeZBase32 decodes z-base-32 text, dropping the bits of a partial
+// trailing byte
+func decodeZBase32(s string) ([]byte, error) {
+	out := make([]byte, 0, len(s)*5/8)
+	var acc uint
+	var bits uint
+	for i := 0; i < len(s); i++ {
+		v := strings.IndexByte(zbase32Alphabet, s[i])
+		if v < 0 {
+			return nil, newVerifyError(ErrMalformedSignature, "invalid z-base-32 character %q at offset %d", s[i], i)
+		}
+		acc = acc<<5 | uint(v)
+		bits += 5
+		if bits >= 8 {
+			bits -= 8
+			out = append(out, byte(acc>>bits))
+			acc &= 1<<bits - 1
+		}
+	}
+	return out, nil
+}
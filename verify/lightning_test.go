@@ -0,0 +1,148 @@
+package verify
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+)
+
+func TestVerifyLightningMessage(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	nodeKey := testPrivKey("node")
+	otherKey := testPrivKey("other node")
+	signature := signLightningTestMessage(nodeKey, "node attestation")
+	if len(signature) != 104 {
+		t.Fatalf("signature has %d characters, want 104", len(signature))
+	}
+
+	segwitSig, _ := decodeZBase32(signature)
+	segwitSig[0] += headerP2WPKH - headerP2PKHCompressed
+
+	tests := []struct {
+		name      string
+		nodeKey   *btcec.PublicKey
+		message   string
+		signature string
+		wantValid bool
+		wantErr   error
+	}{
+		{
+			name:      "Valid signature",
+			nodeKey:   nodeKey.PubKey(),
+			message:   "node attestation",
+			signature: signature,
+			wantValid: true,
+		},
+		{
+			name:      "Other node",
+			nodeKey:   otherKey.PubKey(),
+			message:   "node attestation",
+			signature: signature,
+			wantErr:   ErrAddressMismatch,
+		},
+		{
+			name:      "Modified message",
+			nodeKey:   nodeKey.PubKey(),
+			message:   "node attestation!",
+			signature: signature,
+			wantErr:   ErrAddressMismatch,
+		},
+		{
+			name:      "Base64 signature",
+			nodeKey:   nodeKey.PubKey(),
+			message:   "node attestation",
+			signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "Truncated signature",
+			nodeKey:   nodeKey.PubKey(),
+			message:   "node attestation",
+			signature: signature[:100],
+			wantErr:   ErrMalformedSignature,
+		},
+		{
+			name:      "SegWit header",
+			nodeKey:   nodeKey.PubKey(),
+			message:   "node attestation",
+			signature: encodeZBase32(segwitSig),
+			wantErr:   ErrInvalidHeaderByte,
+		},
+		{
+			name:      "Empty message",
+			nodeKey:   nodeKey.PubKey(),
+			signature: signature,
+			wantErr:   ErrEmptyMessage,
+		},
+		{
+			name:      "Missing node key",
+			message:   "node attestation",
+			signature: signature,
+			wantErr:   ErrEmptyPublicKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifyLightningMessage(tt.nodeKey, tt.message, tt.signature)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyLightningMessage() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid {
+				t.Errorf("VerifyLightningMessage() = %v, want %v", valid, tt.wantValid)
+			}
+		})
+	}
+}
+
+func TestZBase32(t *testing.T) {
+	// Vectors of the z-base-32 specification
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{data: []byte{0xf0, 0xbf, 0xc7}, want: "6n9hq"},
+		{data: []byte{0xd4, 0x7a, 0x04}, want: "4t7ye"},
+		{data: []byte{0x00}, want: "yy"},
+	}
+
+	for _, tt := range tests {
+		if got := encodeZBase32(tt.data); !strings.HasPrefix(got, tt.want) {
+			t.Errorf("encodeZBase32(%x) = %q, want %q", tt.data, got, tt.want)
+		}
+		got, err := decodeZBase32(tt.want)
+		if err != nil || string(got[:len(tt.data)]) != string(tt.data) {
+			t.Errorf("decodeZBase32(%q) = %x, %v, want %x", tt.want, got, err, tt.data)
+		}
+	}
+}
+
+// Helper function to sign a message the way LND's signmessage does
+func signLightningTestMessage(privKey *btcec.PrivateKey, message string) string {
+	digest := LightningMessageHash(message)
+	return encodeZBase32(ecdsa.SignCompact(privKey, digest[:], true))
+}
+
+// Helper function to encode data as z-base-32, padding the last character
+// with zero bits
+func encodeZBase32(data []byte) string {
+	var sb strings.Builder
+	var acc uint
+	var bits uint
+	for _, b := range data {
+		acc = acc<<8 | uint(b)
+		bits += 8
+		for bits >= 5 {
+			bits -= 5
+			sb.WriteByte(zbase32Alphabet[acc>>bits&0x1f])
+		}
+	}
+	if bits > 0 {
+		sb.WriteByte(zbase32Alphabet[acc<<(5-bits)&0x1f])
+	}
+	return sb.String()
+}
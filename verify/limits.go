@@ -0,0 +1,37 @@
+package verify
+
+import "sync/atomic"
+
+// DefaultMaxMessageSize is the default maximum length of a message in bytes.
+// Signed messages are short in practice, while hashing a huge message can
+// stall a verification endpoint.
+const DefaultMaxMessageSize = 1 << 20
+
+// Maximum message length in bytes, 0 disables the limit
+var maxMessageSize atomic.Int64
+
+func init() {
+	maxMessageSize.Store(DefaultMaxMessageSize)
+}
+
+// MaxMessageSize returns the maximum message length accepted by the
+// package-level functions.
+func MaxMessageSize() int64 {
+	return maxMessageSize.Load()
+}
+
+// SetMaxMessageSize sets the maximum message length in bytes accepted by the
+// package-level functions and by Verifiers without their own limit. A limit
+// of 0 disables the check.
+func SetMaxMessageSize(size int64) {
+	maxMessageSize.Store(size)
+}
+
+// checkMessageSize rejects messages longer than limit, unless limit is 0
+func checkMessageSize(events eventLogger, message string, limit int64) error {
+	if limit > 0 && int64(len(message)) > limit {
+		events.log(LogLevelError, "Message exceeds the size limit", "size", len(message), "limit", limit)
+		return newVerifyError(ErrMessageTooLarge, "%d bytes exceeds the limit of %d bytes", len(message), limit)
+	}
+	return nil
+}
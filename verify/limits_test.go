@@ -0,0 +1,117 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMaxMessageSize(t *testing.T) {
+	old := MaxMessageSize()
+	t.Cleanup(func() { SetMaxMessageSize(old) })
+	SetMaxMessageSize(16)
+
+	tv := walletTestVectors[2].msg
+	long := strings.Repeat("x", 17)
+	pubKey := mustParsePubKey(t, "034fafbb0673368ea3dcc7003a753c51bf240471c3a1b811491ba9f3480091e23c")
+
+	tests := []struct {
+		name   string
+		verify func() error
+	}{
+		{
+			name: "VerifyBip137Signature",
+			verify: func() error {
+				_, err := VerifyBip137Signature(tv.Address, long, tv.Signature)
+				return err
+			},
+		},
+		{
+			name: "VerifyBip137SignatureWithContext",
+			verify: func() error {
+				_, err := VerifyBip137SignatureWithContext(context.Background(), SignedMessage{Address: tv.Address, Message: long, Signature: tv.Signature})
+				return err
+			},
+		},
+		{
+			name: "VerifyBip137SignatureWithPubKey",
+			verify: func() error {
+				_, err := VerifyBip137SignatureWithPubKey(pubKey, long, tv.Signature)
+				return err
+			},
+		},
+		{
+			name: "VerifyBip137SignatureEx",
+			verify: func() error {
+				_, _, err := VerifyBip137SignatureEx(tv.Address, long, tv.Signature)
+				return err
+			},
+		},
+		{
+			name: "Explain",
+			verify: func() error {
+				return Explain(tv.Address, long, tv.Signature).Err
+			},
+		},
+		{
+			name: "VerifyBatch",
+			verify: func() error {
+				report, err := VerifyBatch(context.Background(), []SignedMessage{{Address: tv.Address, Message: long, Signature: tv.Signature}})
+				if err != nil {
+					return err
+				}
+				return report.Results[0].Err
+			},
+		},
+		{
+			name: "Verifier",
+			verify: func() error {
+				_, err := NewVerifier().Verify(SignedMessage{Address: tv.Address, Message: long, Signature: tv.Signature})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.verify(); !errors.Is(err, ErrMessageTooLarge) {
+				t.Errorf("%s() error = %v, want %v", tt.name, err, ErrMessageTooLarge)
+			}
+		})
+	}
+
+	// Messages within the limit still verify
+	if valid, err := VerifyBip137Signature(tv.Address, tv.Message, tv.Signature); err != nil || !valid {
+		t.Errorf("VerifyBip137Signature() = %v, %v, want valid", valid, err)
+	}
+}
+
+func TestVerifierMaxMessageSize(t *testing.T) {
+	tv := walletTestVectors[2].msg
+
+	tests := []struct {
+		name    string
+		limit   int64
+		wantErr error
+	}{
+		{name: "Below message length", limit: int64(len(tv.Message)) - 1, wantErr: ErrMessageTooLarge},
+		{name: "Exactly message length", limit: int64(len(tv.Message))},
+		{name: "Disabled", limit: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewVerifier(WithMaxMessageSize(tt.limit)).Verify(tv)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || ErrorCodeOf(err) != CodeMessageTooLarge {
+					t.Errorf("Verifier.Verify() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || !result.Valid {
+				t.Errorf("Verifier.Verify() = %+v, %v, want valid", result, err)
+			}
+		})
+	}
+}
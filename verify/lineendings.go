@@ -0,0 +1,78 @@
+package verify
+
+import "strings"
+
+// LineEndings selects how line endings of multi-line messages are treated.
+// Wallets differ on whether a pasted multi-line message is signed with LF or
+// CRLF line endings, so a message copied across platforms may not verify as
+// is.
+type LineEndings int
+
+const (
+	// LineEndingsExact verifies the message byte for byte
+	LineEndingsExact LineEndings = iota
+
+	// LineEndingsLF converts CRLF and CR line endings to LF before verifying
+	LineEndingsLF
+
+	// LineEndingsCRLF converts LF and CR line endings to CRLF before verifying
+	LineEndingsCRLF
+
+	// LineEndingsAny verifies the message as is, then with LF and then with
+	// CRLF line endings, accepting the first that matches
+	LineEndingsAny
+)
+
+// String returns the name of the line ending mode
+func (l LineEndings) String() string {
+	switch l {
+	case LineEndingsExact:
+		return "exact"
+	case LineEndingsLF:
+		return "lf"
+	case LineEndingsCRLF:
+		return "crlf"
+	case LineEndingsAny:
+		return "any"
+	default:
+		return "unknown"
+	}
+}
+
+// messageVariant is a message with the line endings it was converted to
+type messageVariant struct {
+	lineEndings LineEndings
+	message     string
+}
+
+// messageVariants returns the messages to verify for a line ending mode, in
+// the order they should be tried
+func messageVariants(message string, mode LineEndings) []messageVariant {
+	switch mode {
+	case LineEndingsLF:
+		return []messageVariant{{LineEndingsLF, toLF(message)}}
+	case LineEndingsCRLF:
+		return []messageVariant{{LineEndingsCRLF, toCRLF(message)}}
+	case LineEndingsAny:
+		variants := []messageVariant{{LineEndingsExact, message}}
+		if lf := toLF(message); lf != message {
+			variants = append(variants, messageVariant{LineEndingsLF, lf})
+		}
+		if crlf := toCRLF(message); crlf != message {
+			variants = append(variants, messageVariant{LineEndingsCRLF, crlf})
+		}
+		return variants
+	default:
+		return []messageVariant{{LineEndingsExact, message}}
+	}
+}
+
+// toLF converts CRLF and lone CR line endings to LF
+func toLF(message string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(message, "\r\n", "\n"), "\r", "\n")
+}
+
+// toCRLF converts all line endings to CRLF
+func toCRLF(message string) string {
+	return strings.ReplaceAll(toLF(message), "\n", "\r\n")
+}
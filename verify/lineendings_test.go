@@ -0,0 +1,89 @@
+package verify
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMessageVariants(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		mode    LineEndings
+		want    []messageVariant
+	}{
+		{
+			name:    "Exact",
+			message: "a\r\nb",
+			mode:    LineEndingsExact,
+			want:    []messageVariant{{LineEndingsExact, "a\r\nb"}},
+		},
+		{
+			name:    "LF from CRLF and CR",
+			message: "a\r\nb\rc",
+			mode:    LineEndingsLF,
+			want:    []messageVariant{{LineEndingsLF, "a\nb\nc"}},
+		},
+		{
+			name:    "CRLF from LF",
+			message: "a\nb\r\nc",
+			mode:    LineEndingsCRLF,
+			want:    []messageVariant{{LineEndingsCRLF, "a\r\nb\r\nc"}},
+		},
+		{
+			name:    "Any with LF message",
+			message: "a\nb",
+			mode:    LineEndingsAny,
+			want:    []messageVariant{{LineEndingsExact, "a\nb"}, {LineEndingsCRLF, "a\r\nb"}},
+		},
+		{
+			name:    "Any with single line message",
+			message: "a b",
+			mode:    LineEndingsAny,
+			want:    []messageVariant{{LineEndingsExact, "a b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := messageVariants(tt.message, tt.mode); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("messageVariants() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifierLineEndings(t *testing.T) {
+	// Signed with LF line endings, received with CRLF
+	msg := signTestMessage(t, testKeySeed, nil, "line one\nline two\n")
+	msg.Message = "line one\r\nline two\r\n"
+
+	tests := []struct {
+		name            string
+		mode            LineEndings
+		wantValid       bool
+		wantLineEndings LineEndings
+	}{
+		{name: "Exact", mode: LineEndingsExact, wantValid: false},
+		{name: "LF", mode: LineEndingsLF, wantValid: true, wantLineEndings: LineEndingsLF},
+		{name: "CRLF", mode: LineEndingsCRLF, wantValid: false},
+		{name: "Any", mode: LineEndingsAny, wantValid: true, wantLineEndings: LineEndingsLF},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewVerifier(WithLineEndings(tt.mode)).Verify(msg)
+
+			if result.Valid != tt.wantValid {
+				t.Fatalf("Verifier.Verify().Valid = %v, want %v (error: %v)", result.Valid, tt.wantValid, err)
+			}
+			if !tt.wantValid && !errors.Is(err, ErrAddressMismatch) {
+				t.Errorf("Verifier.Verify() error = %v, want %v", err, ErrAddressMismatch)
+			}
+			if result.LineEndings != tt.wantLineEndings {
+				t.Errorf("Verifier.Verify().LineEndings = %s, want %s", result.LineEndings, tt.wantLineEndings)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package verify
+
+import "context"
+
+// contextKey is the type of the context keys of the package
+type contextKey int
+
+const (
+	loggerContextKey contextKey = iota
+	requestIDContextKey
+)
+
+// ContextWithLogger returns a copy of ctx carrying a logger. Verifications
+// run with the context, such as VerifyBip137SignatureWithContext and
+// VerifyBatch, log to it instead of the package logger.
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey, l)
+}
+
+// ContextWithRequestID returns a copy of ctx carrying a request or
+// correlation ID. Messages logged by verifications run with the context carry
+// the ID as the "request_id" field, so the logs of concurrent requests can be
+// told apart.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or an empty
+// string if there is none.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey).(string)
+	return id
+}
+
+// eventLogger logs structured messages on behalf of a single verification,
+// adding its fields to every message. The zero value logs to the package
+// logger at the package log level.
+type eventLogger struct {
+	logger  Logger
+	keyvals []interface{}
+
+	// level overrides the package log level when set
+	level    LogLevel
+	hasLevel bool
+}
+
+// eventLoggerFromContext returns an eventLogger using the logger and request
+// ID carried by ctx
+func eventLoggerFromContext(ctx context.Context) eventLogger {
+	var events eventLogger
+	if l, ok := ctx.Value(loggerContextKey).(Logger); ok && l != nil {
+		events.logger = l
+	}
+	if id := RequestIDFromContext(ctx); id != "" {
+		events.keyvals = []interface{}{"request_id", id}
+	}
+	return events
+}
+
+// enabled reports whether messages of the level are logged, so costly
+// values are only computed when they are
+func (e eventLogger) enabled(level LogLevel) bool {
+	threshold := GetLogLevel()
+	if e.hasLevel {
+		threshold = e.level
+	}
+	return threshold.enables(level)
+}
+
+// log hands a structured message to the logger if the level is enabled
+func (e eventLogger) log(level LogLevel, msg string, keyvals ...interface{}) {
+	if !e.enabled(level) {
+		return
+	}
+
+	l := e.logger
+	if l == nil {
+		l = GetLogger()
+	}
+	if len(e.keyvals) > 0 {
+		keyvals = append(keyvals[:len(keyvals):len(keyvals)], e.keyvals...)
+	}
+	l.Log(level, msg, keyvals...)
+}
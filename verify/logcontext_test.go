@@ -0,0 +1,117 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestContextLogger(t *testing.T) {
+	defer SetLogger(GetLogger())
+	defer SetLogLevel(GetLogLevel())
+
+	global := &recordingLogger{}
+	SetLogger(global)
+	SetLogLevel(LogLevelTrace)
+
+	tv := walletTestVectors[2].msg
+	pubKey := mustParsePubKey(t, Explain(tv.Address, tv.Message, tv.Signature).RecoveredPubKey)
+
+	tests := []struct {
+		name   string
+		verify func(ctx context.Context) (bool, error)
+	}{
+		{
+			name:   "Address",
+			verify: func(ctx context.Context) (bool, error) { return VerifyBip137SignatureWithContext(ctx, tv) },
+		},
+		{
+			name: "Public key",
+			verify: func(ctx context.Context) (bool, error) {
+				return VerifyBip137SignatureWithPubKeyAndContext(ctx, pubKey, tv.Message, tv.Signature)
+			},
+		},
+		{
+			name: "Batch",
+			verify: func(ctx context.Context) (bool, error) {
+				report, err := VerifyBatch(ctx, []SignedMessage{tv})
+				return report.Valid == 1, err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Run concurrent requests, each with its own logger and ID
+			loggers := make([]*recordingLogger, 8)
+			var wg sync.WaitGroup
+			for i := range loggers {
+				loggers[i] = &recordingLogger{}
+				ctx := ContextWithLogger(context.Background(), loggers[i])
+				ctx = ContextWithRequestID(ctx, fmt.Sprintf("req-%d", i))
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if valid, err := tt.verify(ctx); !valid || err != nil {
+						t.Errorf("verification = %v, %v, want true, nil", valid, err)
+					}
+				}()
+			}
+			wg.Wait()
+
+			for i, l := range loggers {
+				if len(l.lines) == 0 {
+					t.Fatalf("request %d: nothing logged to the context logger", i)
+				}
+				want := fmt.Sprintf("req-%d", i)
+				for k, keyvals := range l.keyvals {
+					if got := requestIDOf(keyvals); got != want {
+						t.Errorf("request %d: %q logged with request_id %q, want %q", i, l.lines[k], got, want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRequestIDFromContext(t *testing.T) {
+	if got := RequestIDFromContext(context.Background()); got != "" {
+		t.Errorf("RequestIDFromContext() = %q, want empty", got)
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "abc")
+	if got := RequestIDFromContext(ctx); got != "abc" {
+		t.Errorf("RequestIDFromContext() = %q, want %q", got, "abc")
+	}
+}
+
+func TestContextRequestIDWithPackageLogger(t *testing.T) {
+	defer SetLogger(GetLogger())
+	defer SetLogLevel(GetLogLevel())
+
+	global := &recordingLogger{}
+	SetLogger(global)
+	SetLogLevel(LogLevelInfo)
+
+	ctx := ContextWithRequestID(context.Background(), "abc")
+	if _, err := VerifyBip137SignatureWithContext(ctx, walletTestVectors[2].msg); err != nil {
+		t.Fatalf("VerifyBip137SignatureWithContext() error = %v", err)
+	}
+
+	if got, _ := global.value("request_id"); got != "abc" {
+		t.Errorf("package logger got request_id %v, want %q", got, "abc")
+	}
+}
+
+// Helper function to find the request ID in logged key-value pairs
+func requestIDOf(keyvals []interface{}) string {
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		if keyvals[i] == "request_id" {
+			id, _ := keyvals[i+1].(string)
+			return id
+		}
+	}
+	return ""
+}
@@ -2,9 +2,11 @@ package verify
 
 import (
 	"fmt"
+	"io"
 	"log"
-	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
 )
 
 // LogLevel determines the verbosity of logging
@@ -16,52 +18,176 @@ const (
 	LogLevelInfo
 	LogLevelDebug
 	LogLevelTrace
+
+	// LogLevelWarning is between LogLevelError and LogLevelInfo in
+	// verbosity. It's numbered last so the other levels keep the values
+	// they had before it was added.
+	LogLevelWarning
 )
 
+// enables reports whether messages of the given level are logged when l is
+// the configured level
+func (l LogLevel) enables(level LogLevel) bool {
+	return l.verbosity() >= level.verbosity()
+}
+
+// verbosity ranks the level from quietest to most verbose, leaving room for
+// LogLevelWarning between LogLevelError and LogLevelInfo
+func (l LogLevel) verbosity() int {
+	if l == LogLevelWarning {
+		return 2*int(LogLevelError) + 1
+	}
+	return 2 * int(l)
+}
+
+// String returns the name of the level as used in log lines
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelNone:
+		return "NONE"
+	case LogLevelError:
+		return "ERROR"
+	case LogLevelWarning:
+		return "WARNING"
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelDebug:
+		return "DEBUG"
+	case LogLevelTrace:
+		return "TRACE"
+	default:
+		return fmt.Sprintf("LEVEL(%d)", int(l))
+	}
+}
+
+// Logger receives the log messages of the package. Messages are filtered by
+// the level set with SetLogLevel before they reach the Logger.
+// Implementations must be safe for concurrent use.
+type Logger interface {
+	// Log records a message. keyvals holds alternating keys and values with
+	// additional context, and may be empty.
+	Log(level LogLevel, msg string, keyvals ...interface{})
+}
+
 var (
-	// Current log level, default to info
-	currentLogLevel = LogLevelInfo
+	// Current log level, read on every log call while it may be changed
+	// concurrently
+	currentLogLevel atomic.Int32
 
-	// Logger instance
-	Logger = log.New(os.Stdout, "", log.LstdFlags)
+	// Logger the package logs to, never nil
+	logger atomic.Pointer[Logger]
 )
 
-// SetLogLevel sets the current logging level
+func init() {
+	SetLogLevel(LogLevelInfo)
+	SetLogger(defaultLogger())
+}
+
+// SetLogger replaces the logger of the package. By default messages are
+// written to standard output, or to the browser console under WebAssembly; a
+// nil logger discards them.
+func SetLogger(l Logger) {
+	if l == nil {
+		l = NewStdLogger(log.New(io.Discard, "", 0))
+	}
+	logger.Store(&l)
+}
+
+// GetLogger returns the logger of the package
+func GetLogger() Logger {
+	return *logger.Load()
+}
+
+// NewStdLogger returns a Logger writing to a standard library logger, one
+// line per message, with the key-value pairs appended as key=value. Values
+// containing spaces, quotes or equal signs are quoted.
+func NewStdLogger(l *log.Logger) Logger {
+	return stdLogger{l: l}
+}
+
+// stdLogger adapts a *log.Logger to the Logger interface
+type stdLogger struct {
+	l *log.Logger
+}
+
+// Log writes the message as a single line prefixed with the level
+func (s stdLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	s.l.Print(formatLogLine(level, msg, keyvals))
+}
+
+// formatLogLine formats a message as a single line prefixed with the level,
+// followed by the key-value pairs as key=value
+func formatLogLine(level LogLevel, msg string, keyvals []interface{}) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", level, msg)
+	for i := 0; i < len(keyvals); i += 2 {
+		if i+1 < len(keyvals) {
+			fmt.Fprintf(&b, " %v=%s", keyvals[i], formatLogValue(keyvals[i+1]))
+		} else {
+			fmt.Fprintf(&b, " %s", formatLogValue(keyvals[i]))
+		}
+	}
+	return b.String()
+}
+
+// formatLogValue formats a value of a key-value pair, quoting it when it
+// would otherwise be ambiguous
+func formatLogValue(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// SetLogLevel sets the current logging level. It is safe to call while
+// verifications are running.
 func SetLogLevel(level LogLevel) {
-	currentLogLevel = level
+	currentLogLevel.Store(int32(level))
 }
 
 // GetLogLevel returns the current logging level
 func GetLogLevel() LogLevel {
-	return currentLogLevel
+	return LogLevel(currentLogLevel.Load())
+}
+
+// logEvent hands a structured message to the logger if the level is enabled.
+// keyvals holds alternating keys and values, such as "address" and the
+// address being verified, so logs can be queried by field.
+func logEvent(level LogLevel, msg string, keyvals ...interface{}) {
+	eventLogger{}.log(level, msg, keyvals...)
+}
+
+// logf formats a message and hands it to the logger if the level is enabled
+func logf(level LogLevel, format string, args ...interface{}) {
+	if GetLogLevel().enables(level) {
+		GetLogger().Log(level, fmt.Sprintf(format, args...))
+	}
 }
 
 // LogError logs an error message
 func LogError(format string, args ...interface{}) {
-	if currentLogLevel >= LogLevelError {
-		Logger.Printf("[ERROR] "+format, args...)
-	}
+	logf(LogLevelError, format, args...)
+}
+
+// LogWarning logs a warning message
+func LogWarning(format string, args ...interface{}) {
+	logf(LogLevelWarning, format, args...)
 }
 
 // LogInfo logs an info message
 func LogInfo(format string, args ...interface{}) {
-	if currentLogLevel >= LogLevelInfo {
-		Logger.Printf("[INFO] "+format, args...)
-	}
+	logf(LogLevelInfo, format, args...)
 }
 
 // LogDebug logs a debug message
 func LogDebug(format string, args ...interface{}) {
-	if currentLogLevel >= LogLevelDebug {
-		Logger.Printf("[DEBUG] "+format, args...)
-	}
+	logf(LogLevelDebug, format, args...)
 }
 
 // LogTrace logs a trace message (most detailed)
 func LogTrace(format string, args ...interface{}) {
-	if currentLogLevel >= LogLevelTrace {
-		Logger.Printf("[TRACE] "+format, args...)
-	}
+	logf(LogLevelTrace, format, args...)
 }
 
 // DumpHex returns a hexadecimal representation of the data
@@ -0,0 +1,33 @@
+//go:build js && wasm
+
+package verify
+
+import (
+	"syscall/js"
+)
+
+// defaultLogger returns the logger the package starts with. In the browser
+// there is no standard output to speak of, so messages go to the console,
+// where they can be filtered by level.
+func defaultLogger() Logger {
+	return consoleLogger{console: js.Global().Get("console")}
+}
+
+// consoleLogger logs to the JavaScript console
+type consoleLogger struct {
+	console js.Value
+}
+
+// Log writes the message with the console method of its level
+func (c consoleLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	method := "log"
+	switch level {
+	case LogLevelError:
+		method = "error"
+	case LogLevelWarning:
+		method = "warn"
+	case LogLevelDebug, LogLevelTrace:
+		method = "debug"
+	}
+	c.console.Call(method, formatLogLine(level, msg, keyvals))
+}
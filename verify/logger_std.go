@@ -0,0 +1,14 @@
+//go:build !(js && wasm) && !tinygo
+
+package verify
+
+import (
+	"log"
+	"os"
+)
+
+// defaultLogger returns the logger the package starts with, writing to
+// standard output
+func defaultLogger() Logger {
+	return NewStdLogger(log.New(os.Stdout, "", log.LstdFlags))
+}
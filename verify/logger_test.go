@@ -0,0 +1,178 @@
+package verify
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestStdLogger(t *testing.T) {
+	tests := []struct {
+		name    string
+		level   LogLevel
+		msg     string
+		keyvals []interface{}
+		want    string
+	}{
+		{name: "Message only", level: LogLevelInfo, msg: "hello", want: "[INFO] hello\n"},
+		{name: "Key-value pairs", level: LogLevelError, msg: "failed", keyvals: []interface{}{"address", "1abc", "stage", "recovery"}, want: "[ERROR] failed address=1abc stage=recovery\n"},
+		{name: "Quoted values", level: LogLevelDebug, msg: "input", keyvals: []interface{}{"message", "test message", "empty", ""}, want: "[DEBUG] input message=\"test message\" empty=\"\"\n"},
+		{name: "Odd number of key-values", level: LogLevelWarning, msg: "odd", keyvals: []interface{}{"dangling"}, want: "[WARNING] odd dangling\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			NewStdLogger(log.New(&buf, "", 0)).Log(tt.level, tt.msg, tt.keyvals...)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Log() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetLogger(t *testing.T) {
+	defer SetLogger(GetLogger())
+	defer SetLogLevel(GetLogLevel())
+
+	rec := &recordingLogger{}
+	SetLogger(rec)
+	SetLogLevel(LogLevelWarning)
+
+	LogError("error %d", 1)
+	LogWarning("warning %d", 2)
+	LogInfo("info %d", 3)
+
+	want := []string{"ERROR error 1", "WARNING warning 2"}
+	if strings.Join(rec.lines, "|") != strings.Join(want, "|") {
+		t.Errorf("logged %q, want %q", rec.lines, want)
+	}
+
+	// A nil logger discards messages instead of panicking
+	SetLogger(nil)
+	LogError("discarded")
+}
+
+func TestLogLevelValues(t *testing.T) {
+	// Levels are stored and configured by number, which must not change
+	for level, want := range map[LogLevel]int{
+		LogLevelNone:    0,
+		LogLevelError:   1,
+		LogLevelInfo:    2,
+		LogLevelDebug:   3,
+		LogLevelTrace:   4,
+		LogLevelWarning: 5,
+	} {
+		if int(level) != want {
+			t.Errorf("%s = %d, want %d", level, int(level), want)
+		}
+	}
+
+	// From quietest to most verbose
+	levels := []LogLevel{LogLevelNone, LogLevelError, LogLevelWarning, LogLevelInfo, LogLevelDebug, LogLevelTrace}
+	for i, configured := range levels {
+		for j, level := range levels {
+			if got := configured.enables(level); got != (j <= i) {
+				t.Errorf("%s.enables(%s) = %v, want %v", configured, level, got, j <= i)
+			}
+		}
+	}
+}
+
+func TestStructuredLogFields(t *testing.T) {
+	defer SetLogger(GetLogger())
+	defer SetLogLevel(GetLogLevel())
+
+	rec := &recordingLogger{}
+	SetLogger(rec)
+	SetLogLevel(LogLevelDebug)
+
+	tv := walletTestVectors[2].msg
+	if _, err := VerifyBip137Signature(tv.Address, tv.Message, tv.Signature); err != nil {
+		t.Fatalf("VerifyBip137Signature() error = %v", err)
+	}
+
+	want := map[string]interface{}{
+		"address":     tv.Address,
+		"network":     chaincfg.MainNetParams.Name,
+		"header_byte": byte(headerP2PKHCompressed + 1),
+		"rec_id":      byte(1),
+		"valid":       true,
+	}
+	for key, value := range want {
+		got, ok := rec.value(key)
+		if !ok {
+			t.Errorf("no message logged with key %q", key)
+			continue
+		}
+		if got != value {
+			t.Errorf("logged %s = %v (%T), want %v (%T)", key, got, got, value, value)
+		}
+	}
+	if _, ok := rec.value("duration"); !ok {
+		t.Errorf("no message logged with key %q", "duration")
+	}
+}
+
+func TestSetLogLevelConcurrently(t *testing.T) {
+	defer SetLogger(GetLogger())
+	defer SetLogLevel(GetLogLevel())
+
+	SetLogger(&recordingLogger{})
+	tv := walletTestVectors[2].msg
+
+	// Run with -race to check that changing the level doesn't race with
+	// logging verifications
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := VerifyBip137Signature(tv.Address, tv.Message, tv.Signature); err != nil {
+					t.Errorf("VerifyBip137Signature() error = %v", err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				SetLogLevel(LogLevel(j % int(LogLevelTrace+1)))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// Helper type to record logged messages
+type recordingLogger struct {
+	mu      sync.Mutex
+	lines   []string
+	keyvals [][]interface{}
+}
+
+func (r *recordingLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, level.String()+" "+msg)
+	r.keyvals = append(r.keyvals, keyvals)
+}
+
+// Helper function to find the value of a key in the first message logged
+// with it
+func (r *recordingLogger) value(key string) (interface{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, keyvals := range r.keyvals {
+		for i := 0; i+1 < len(keyvals); i += 2 {
+			if keyvals[i] == key {
+				return keyvals[i+1], true
+			}
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,15 @@
+//go:build tinygo && !(js && wasm)
+
+package verify
+
+import (
+	"log"
+	"os"
+)
+
+// defaultLogger returns the logger the package starts with. Boards running
+// TinyGo rarely have their wall clock set, so lines are written to the
+// serial console without a timestamp.
+func defaultLogger() Logger {
+	return NewStdLogger(log.New(os.Stdout, "", 0))
+}
@@ -0,0 +1,145 @@
+package verify
+
+import (
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	verifier "github.com/bitonicnl/verify-signed-message/pkg"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// AddressType identifies the kind of address a signature was matched against
+type AddressType string
+
+// Address types supported for verification
+const (
+	AddressTypeP2PKH      AddressType = "p2pkh"
+	AddressTypeP2SHP2WPKH AddressType = "p2sh-p2wpkh"
+	AddressTypeP2WPKH     AddressType = "p2wpkh"
+	AddressTypeP2TR       AddressType = "p2tr"
+)
+
+// MatchInfo describes the address an accepted signature was matched against
+type MatchInfo struct {
+	// AddressType is the type of the signing address
+	AddressType AddressType
+
+	// Network is the name of the network the address belongs to
+	Network string
+
+	// Compressed reports whether the signing public key is compressed
+	Compressed bool
+}
+
+// VerifyBip137SignatureEx is like VerifyBip137Signature, also returning the
+// address type, network and key compression of an accepted signature, so
+// they can be stored with the proof.
+func VerifyBip137SignatureEx(address, message, signatureBase64 string) (bool, MatchInfo, error) {
+	return VerifyBip137SignatureExWithParams(address, message, signatureBase64, &chaincfg.MainNetParams)
+}
+
+// VerifyBip137SignatureExWithParams is like VerifyBip137SignatureEx, using the
+// provided network parameters. The MatchInfo is only filled in when the
+// signature is valid.
+func VerifyBip137SignatureExWithParams(address, message, signatureBase64 string, params *chaincfg.Params) (valid bool, info MatchInfo, err error) {
+	start := time.Now()
+	defer func() {
+		observeVerification(GetMetrics(), start, valid, err)
+	}()
+
+	logEvent(LogLevelDebug, "Verifying signature with match info", "address", address, "network", params.Name)
+
+	// Validate inputs
+	if address == "" {
+		return false, MatchInfo{}, ErrEmptyAddress
+	}
+	if message == "" {
+		return false, MatchInfo{}, ErrEmptyMessage
+	}
+	if signatureBase64 == "" {
+		return false, MatchInfo{}, ErrEmptySignature
+	}
+	if err := checkMessageSize(eventLogger{}, message, MaxMessageSize()); err != nil {
+		return false, MatchInfo{}, err
+	}
+
+	addr, err := decodeAddress(address, params)
+	if err != nil {
+		logEvent(LogLevelError, "Invalid address", "address", address, "network", params.Name, "error", err)
+		return false, MatchInfo{}, err
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		logEvent(LogLevelError, "Failed to decode base64 signature", "error", err)
+		return false, MatchInfo{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+
+	info = MatchInfo{
+		AddressType: addressTypeOf(addr),
+		Network:     params.Name,
+	}
+
+	if len(sigBytes) == compactSignatureLength {
+		digest := magicHash(message)
+		report := explainDigest(eventLogger{}, spanScope{}, address, digest[:], sigBytes, params, TaprootKeyInternal)
+		if !report.Valid {
+			logEvent(LogLevelError, "Signature verification failed", "address", address, "network", params.Name, "header_byte", report.HeaderByte, "error", report.Err)
+			return false, MatchInfo{}, report.Err
+		}
+		info.Compressed = report.Compressed
+	} else {
+		// BIP-322 signatures are only defined for SegWit and Taproot
+		// addresses, which always use compressed keys
+		valid, err := verifier.VerifyWithChain(verifier.SignedMessage{
+			Address:   address,
+			Message:   message,
+			Signature: signatureBase64,
+		}, params)
+		if err != nil {
+			logEvent(LogLevelError, "Signature verification failed", "address", address, "network", params.Name, "error", err)
+			return false, MatchInfo{}, fmt.Errorf("signature verification error: %w", classifyVerifierError(err))
+		}
+		if !valid {
+			return false, MatchInfo{}, nil
+		}
+		info.Compressed = true
+	}
+
+	logEvent(LogLevelInfo, "Signature verification successful", "address", address, "network", info.Network, "address_type", info.AddressType)
+	return true, info, nil
+}
+
+// headerAddressType returns the address type a BIP-0137 header byte stands
+// for, or an empty AddressType for header bytes outside the BIP-0137 ranges
+func headerAddressType(header byte) AddressType {
+	switch {
+	case header >= headerP2PKHUncompressed && header < headerP2SHP2WPKH:
+		return AddressTypeP2PKH
+	case header >= headerP2SHP2WPKH && header < headerP2WPKH:
+		return AddressTypeP2SHP2WPKH
+	case header >= headerP2WPKH && header <= headerMax:
+		return AddressTypeP2WPKH
+	default:
+		return ""
+	}
+}
+
+// addressTypeOf returns the AddressType of a decoded address. P2SH addresses
+// can only sign as P2SH-P2WPKH, so they are reported as such.
+func addressTypeOf(addr btcutil.Address) AddressType {
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash:
+		return AddressTypeP2PKH
+	case *btcutil.AddressScriptHash:
+		return AddressTypeP2SHP2WPKH
+	case *btcutil.AddressWitnessPubKeyHash:
+		return AddressTypeP2WPKH
+	case *btcutil.AddressTaproot:
+		return AddressTypeP2TR
+	default:
+		return AddressType(fmt.Sprintf("%T", addr))
+	}
+}
@@ -0,0 +1,82 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyBip137SignatureEx(t *testing.T) {
+	tests := []struct {
+		name     string
+		msg      SignedMessage
+		wantInfo MatchInfo
+		wantErr  error
+	}{
+		{
+			name:     "Uncompressed P2PKH",
+			msg:      walletTestVectors[1].msg,
+			wantInfo: MatchInfo{AddressType: AddressTypeP2PKH, Network: "mainnet", Compressed: false},
+		},
+		{
+			name:     "Compressed P2PKH",
+			msg:      walletTestVectors[2].msg,
+			wantInfo: MatchInfo{AddressType: AddressTypeP2PKH, Network: "mainnet", Compressed: true},
+		},
+		{
+			name:     "P2SH-P2WPKH",
+			msg:      walletTestVectors[3].msg,
+			wantInfo: MatchInfo{AddressType: AddressTypeP2SHP2WPKH, Network: "mainnet", Compressed: true},
+		},
+		{
+			name:     "P2WPKH",
+			msg:      walletTestVectors[5].msg,
+			wantInfo: MatchInfo{AddressType: AddressTypeP2WPKH, Network: "mainnet", Compressed: true},
+		},
+		{
+			name:     "P2TR",
+			msg:      walletTestVectors[7].msg,
+			wantInfo: MatchInfo{AddressType: AddressTypeP2TR, Network: "mainnet", Compressed: true},
+		},
+		{
+			name: "BIP-322 P2WPKH",
+			msg: SignedMessage{
+				Address:   "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l",
+				Message:   "Hello World",
+				Signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+			},
+			wantInfo: MatchInfo{AddressType: AddressTypeP2WPKH, Network: "mainnet", Compressed: true},
+		},
+		{
+			name: "Address mismatch",
+			msg: SignedMessage{
+				Address:   "14wPe34dikRzK4tMYvtwMMJCEZbJ7ar35V",
+				Message:   walletTestVectors[2].msg.Message,
+				Signature: walletTestVectors[2].msg.Signature,
+			},
+			wantErr: ErrAddressMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, info, err := VerifyBip137SignatureEx(tt.msg.Address, tt.msg.Message, tt.msg.Signature)
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("VerifyBip137SignatureEx() error = %v, want %v", err, tt.wantErr)
+				}
+				if valid || info != (MatchInfo{}) {
+					t.Errorf("VerifyBip137SignatureEx() = %v, %+v, want false and empty info", valid, info)
+				}
+				return
+			}
+
+			if err != nil || !valid {
+				t.Fatalf("VerifyBip137SignatureEx() = %v, %v, want valid", valid, err)
+			}
+			if info != tt.wantInfo {
+				t.Errorf("VerifyBip137SignatureEx() info = %+v, want %+v", info, tt.wantInfo)
+			}
+		})
+	}
+}
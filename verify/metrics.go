@@ -0,0 +1,57 @@
+package verify
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Metrics receives measurements of verifications, so verification services
+// can export them to their monitoring system. Implementations must be safe
+// for concurrent use.
+type Metrics interface {
+	// ObserveVerification records a completed verification. reason is empty
+	// for valid signatures and the error code of the failure otherwise.
+	ObserveVerification(valid bool, reason ErrorCode, duration time.Duration)
+
+	// ObserveHashCache records the message hash cache lookups of a batch
+	ObserveHashCache(hits, misses int)
+}
+
+// Metrics of the package, nil when none are set
+var metrics atomic.Pointer[Metrics]
+
+// SetMetrics sets the metrics that verifications report to. A nil value
+// disables reporting, which is the default.
+func SetMetrics(m Metrics) {
+	if m == nil {
+		metrics.Store(nil)
+		return
+	}
+	metrics.Store(&m)
+}
+
+// GetMetrics returns the metrics of the package, or nil if none are set
+func GetMetrics() Metrics {
+	if m := metrics.Load(); m != nil {
+		return *m
+	}
+	return nil
+}
+
+// observeVerification reports a verification that started at start to m, if
+// set. Signatures that are invalid without an error are reported as
+// CodeInvalidSignature.
+func observeVerification(m Metrics, start time.Time, valid bool, err error) {
+	if m == nil {
+		return
+	}
+
+	var reason ErrorCode
+	switch {
+	case err != nil:
+		reason = ErrorCodeOf(err)
+	case !valid:
+		reason = CodeInvalidSignature
+	}
+	m.ObserveVerification(valid, reason, time.Since(start))
+}
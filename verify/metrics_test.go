@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestObserveVerification(t *testing.T) {
+	defer SetMetrics(GetMetrics())
+
+	tv := walletTestVectors[2].msg
+	mismatch := tv
+	mismatch.Address = walletTestVectors[0].msg.Address
+
+	tests := []struct {
+		name       string
+		verify     func()
+		wantValid  bool
+		wantReason ErrorCode
+	}{
+		{
+			name:      "Valid signature",
+			verify:    func() { VerifyBip137Signature(tv.Address, tv.Message, tv.Signature) },
+			wantValid: true,
+		},
+		{
+			name:       "Address mismatch",
+			verify:     func() { VerifyBip137Signature(mismatch.Address, mismatch.Message, mismatch.Signature) },
+			wantReason: CodeAddressMismatch,
+		},
+		{
+			name:       "Empty message with match info",
+			verify:     func() { VerifyBip137SignatureEx(tv.Address, "", tv.Signature) },
+			wantReason: CodeEmptyMessage,
+		},
+		{
+			name: "Public key",
+			verify: func() {
+				pubKey := mustParsePubKey(t, Explain(tv.Address, tv.Message, tv.Signature).RecoveredPubKey)
+				VerifyBip137SignatureWithPubKeyAndParams(pubKey, tv.Message, tv.Signature, &chaincfg.MainNetParams)
+			},
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingMetrics{}
+			SetMetrics(rec)
+			tt.verify()
+
+			if len(rec.observations) != 1 {
+				t.Fatalf("recorded %d verifications, want 1", len(rec.observations))
+			}
+			got := rec.observations[0]
+			if got.valid != tt.wantValid || got.reason != tt.wantReason {
+				t.Errorf("observed valid = %v, reason = %q, want %v, %q", got.valid, got.reason, tt.wantValid, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestVerifierMetrics(t *testing.T) {
+	defer SetMetrics(GetMetrics())
+
+	global := &recordingMetrics{}
+	SetMetrics(global)
+
+	own := &recordingMetrics{}
+	NewVerifier(WithMetrics(own)).Verify(walletTestVectors[2].msg)
+
+	if len(own.observations) != 1 || len(global.observations) != 0 {
+		t.Errorf("verifier recorded %d, package recorded %d, want 1 and 0", len(own.observations), len(global.observations))
+	}
+}
+
+// Helper type to record observed verifications
+type recordingMetrics struct {
+	mu           sync.Mutex
+	observations []struct {
+		valid  bool
+		reason ErrorCode
+	}
+}
+
+func (r *recordingMetrics) ObserveVerification(valid bool, reason ErrorCode, duration time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observations = append(r.observations, struct {
+		valid  bool
+		reason ErrorCode
+	}{valid, reason})
+}
+
+func (r *recordingMetrics) ObserveHashCache(hits, misses int) {}
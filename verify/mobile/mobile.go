@@ -0,0 +1,153 @@
+// Package mobile exposes the verifier to iOS and Android apps through
+// gomobile. Its API only uses strings, byte slices, booleans and structs of
+// those, which gomobile can bind:
+//
+//	gomobile bind -target=android github.com/sero/btc/verify/mobile
+//	gomobile bind -target=ios github.com/sero/btc/verify/mobile
+//
+// Networks are named mainnet, testnet, regtest or signet; an empty name
+// stands for mainnet. Errors returned as Go errors, which become exceptions
+// or NSErrors, start with the error code of the failure, such as
+// "malformed_signature: ...", as exceptions can't carry it otherwise.
+package mobile
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/signer"
+)
+
+// VerifyResult is the outcome of a verification. Failed verifications are
+// reported here rather than as errors, so apps can branch on the code.
+type VerifyResult struct {
+	// Valid reports whether the signature is valid
+	Valid bool
+
+	// Code is the error code of a failed verification, such as
+	// address_mismatch, and empty when the signature is valid
+	Code string
+
+	// Error describes why verification failed
+	Error string
+}
+
+// SignedMessage is a message signed for an address
+type SignedMessage struct {
+	Address   string
+	Message   string
+	Signature string
+}
+
+// Recovery is the public key and address that signed a message
+type Recovery struct {
+	// PubKey is the hex-encoded public key
+	PubKey string
+
+	// Compressed reports whether PubKey is compressed
+	Compressed bool
+
+	// AddressType is the address type the signature's header byte stands
+	// for, such as p2wpkh
+	AddressType string
+
+	// Address is the address of that type for PubKey
+	Address string
+}
+
+// Verify verifies a BIP-0137 or BIP-322 signature of the message by the
+// address, on the named network
+func Verify(address, message, signature, network string) *VerifyResult {
+	params, err := networkParams(network)
+	if err != nil {
+		return failed(err)
+	}
+	result, err := verify.NewVerifier(verify.WithParams(params)).Verify(verify.SignedMessage{
+		Address:   address,
+		Message:   message,
+		Signature: signature,
+	})
+	if err != nil {
+		return failed(err)
+	}
+	return &VerifyResult{Valid: result.Valid}
+}
+
+// VerifyWithPubKey verifies a compact signature of the message by the public
+// key, given in its 33- or 65-byte serialization
+func VerifyWithPubKey(pubKey []byte, message, signature string) *VerifyResult {
+	key, err := btcec.ParsePubKey(pubKey)
+	if err != nil {
+		return failed(fmt.Errorf("%w: %v", verify.ErrInvalidPublicKey, err))
+	}
+	valid, err := verify.VerifyBip137SignatureWithPubKey(key, message, signature)
+	if err != nil {
+		return failed(err)
+	}
+	return &VerifyResult{Valid: valid}
+}
+
+// Recover recovers the public key and address that signed the message with a
+// compact signature, on the named network
+func Recover(message, signature, network string) (*Recovery, error) {
+	params, err := networkParams(network)
+	if err != nil {
+		return nil, withCode(err)
+	}
+	rec, err := verify.Recover(message, signature, params)
+	if err != nil {
+		return nil, withCode(err)
+	}
+	return &Recovery{
+		PubKey:      rec.PubKey,
+		Compressed:  rec.Compressed,
+		AddressType: string(rec.AddressType),
+		Address:     rec.Address,
+	}, nil
+}
+
+// Sign signs the message with a private key in wallet import format, for the
+// address of the given type: p2pkh, p2sh-p2wpkh or p2wpkh
+func Sign(wif, message, addressType, network string) (*SignedMessage, error) {
+	params, err := networkParams(network)
+	if err != nil {
+		return nil, withCode(err)
+	}
+	key, err := signer.ImportWIF(wif, params)
+	if err != nil {
+		return nil, withCode(err)
+	}
+	msg, err := key.Sign(message, verify.AddressType(addressType))
+	if err != nil {
+		return nil, withCode(err)
+	}
+	return &SignedMessage{Address: msg.Address, Message: msg.Message, Signature: msg.Signature}, nil
+}
+
+// networkParams returns the parameters of the named network
+func networkParams(name string) (*chaincfg.Params, error) {
+	switch name {
+	case "", "mainnet":
+		return &chaincfg.MainNetParams, nil
+	case "testnet", "testnet3":
+		return &chaincfg.TestNet3Params, nil
+	case "regtest":
+		return &chaincfg.RegressionNetParams, nil
+	case "signet":
+		return &chaincfg.SigNetParams, nil
+	default:
+		return nil, fmt.Errorf("unknown network %q", name)
+	}
+}
+
+// failed returns the result of a verification that failed with err
+func failed(err error) *VerifyResult {
+	return &VerifyResult{Code: string(verify.ErrorCodeOf(err)), Error: err.Error()}
+}
+
+// withCode prefixes err with its error code
+func withCode(err error) error {
+	return fmt.Errorf("%s: %w", verify.ErrorCodeOf(err), err)
+}
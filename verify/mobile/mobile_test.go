@@ -0,0 +1,89 @@
+package mobile
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+// Compressed private key of the Bitcoin wiki WIF example
+const testWIF = "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"
+
+func TestSignVerifyRecover(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	msg, err := Sign(testWIF, "hello from a phone", "p2wpkh", "")
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	if result := Verify(msg.Address, msg.Message, msg.Signature, "mainnet"); !result.Valid || result.Code != "" {
+		t.Errorf("Verify() = %+v, want valid", result)
+	}
+	result := Verify(msg.Address, "edited", msg.Signature, "mainnet")
+	if result.Valid || result.Code != string(verify.CodeAddressMismatch) {
+		t.Errorf("Verify() of another message = %+v, want code %q", result, verify.CodeAddressMismatch)
+	}
+
+	rec, err := Recover(msg.Message, msg.Signature, "mainnet")
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	if rec.Address != msg.Address || rec.AddressType != "p2wpkh" || !rec.Compressed {
+		t.Errorf("Recover() = %+v, want %s", rec, msg.Address)
+	}
+
+	pubKey, _ := hex.DecodeString(rec.PubKey)
+	if result := VerifyWithPubKey(pubKey, msg.Message, msg.Signature); !result.Valid {
+		t.Errorf("VerifyWithPubKey() = %+v, want valid", result)
+	}
+	if result := VerifyWithPubKey([]byte{0x02}, msg.Message, msg.Signature); result.Code != string(verify.CodeInvalidPublicKey) {
+		t.Errorf("VerifyWithPubKey() of a truncated key = %+v, want code %q", result, verify.CodeInvalidPublicKey)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	tests := []struct {
+		name     string
+		call     func() error
+		wantCode string
+	}{
+		{
+			name:     "Malformed signature",
+			call:     func() error { _, err := Recover("message", "not base64", ""); return err },
+			wantCode: string(verify.CodeMalformedSignature),
+		},
+		{
+			name:     "Key of another network",
+			call:     func() error { _, err := Sign(testWIF, "message", "p2wpkh", "testnet"); return err },
+			wantCode: string(verify.CodeNetworkMismatch),
+		},
+		{
+			name:     "Unsupported address type",
+			call:     func() error { _, err := Sign(testWIF, "message", "p2tr", ""); return err },
+			wantCode: string(verify.CodeUnsupportedAddressType),
+		},
+		{
+			name:     "Unknown network",
+			call:     func() error { _, err := Sign(testWIF, "message", "p2wpkh", "moon"); return err },
+			wantCode: string(verify.CodeUnknown),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantCode+": ") {
+				t.Errorf("error = %v, want one starting with %q", err, tt.wantCode)
+			}
+		})
+	}
+
+	if result := Verify("1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", "message", "c2ln", "moon"); result.Valid || result.Error == "" {
+		t.Errorf("Verify() on an unknown network = %+v, want an error", result)
+	}
+}
@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// MultisigResult reports which members of an m-of-n set of addresses signed
+// a message
+type MultisigResult struct {
+	// Signers are the addresses that signed the message, in the order of the
+	// set
+	Signers []string
+
+	// Unmatched are the indexes of the signatures that aren't valid
+	// signatures by a member of the set, or repeat a member or key that
+	// already signed
+	Unmatched []int
+
+	// Threshold is the number of members whose signatures are required
+	Threshold int
+
+	// ThresholdMet reports whether at least Threshold members signed
+	ThresholdMet bool
+}
+
+// VerifyMultisig verifies that at least threshold of the addresses each
+// signed the same message, as in treasury or DAO attestations. The
+// signatures are 65-byte compact signatures in any order; each is matched to
+// the member that made it by recovering its public key, like Keyring.Verify.
+// Signatures by other keys don't count towards the threshold but don't fail
+// verification either; an unmet threshold is reported in the result, not as
+// an error. Each key counts once, so a set holding several addresses of one
+// key, such as its P2PKH and P2WPKH addresses, can't be met by that key
+// alone. Sets with duplicate or invalid addresses, or a threshold outside
+// 1..len(addresses), fail with ErrInvalidMultisigPolicy.
+func VerifyMultisig(message string, signatures, addresses []string, threshold int, params *chaincfg.Params) (MultisigResult, error) {
+	if threshold < 1 || threshold > len(addresses) {
+		return MultisigResult{}, newVerifyError(ErrInvalidMultisigPolicy, "threshold %d of %d addresses", threshold, len(addresses))
+	}
+	if message == "" {
+		return MultisigResult{}, ErrEmptyMessage
+	}
+
+	members := NewKeyring(params)
+	for _, address := range addresses {
+		if err := members.Add(KeyringEntry{Label: address, Address: address}); err != nil {
+			return MultisigResult{}, newVerifyError(ErrInvalidMultisigPolicy, "address %q: %w", address, err)
+		}
+	}
+
+	signed := make(map[string]bool)
+	signedKeys := make(map[string]bool)
+	result := MultisigResult{Threshold: threshold}
+	for i, signature := range signatures {
+		valid, entry, err := members.Verify(message, signature)
+		if err != nil || !valid || signed[entry.Address] {
+			result.Unmatched = append(result.Unmatched, i)
+			continue
+		}
+		key, err := signingKey(message, signature, params)
+		if err != nil || signedKeys[key] {
+			result.Unmatched = append(result.Unmatched, i)
+			continue
+		}
+		signed[entry.Address] = true
+		signedKeys[key] = true
+	}
+	for _, address := range addresses {
+		if signed[address] {
+			result.Signers = append(result.Signers, address)
+		}
+	}
+	result.ThresholdMet = len(result.Signers) >= threshold
+
+	logEvent(LogLevelInfo, "Verified multisig attestation", "signers", len(result.Signers), "threshold", threshold, "members", len(addresses), "threshold_met", result.ThresholdMet)
+	return result, nil
+}
+
+// signingKey returns the compressed serialization of the public key that
+// made a compact signature of the message, which identifies the signer
+// whatever address type and key serialization it signed for
+func signingKey(message, signatureBase64 string, params *chaincfg.Params) (string, error) {
+	rec, err := Recover(message, signatureBase64, params)
+	if err != nil {
+		return "", err
+	}
+	serialized, _ := hex.DecodeString(rec.PubKey)
+	pubKey, err := btcec.ParsePubKey(serialized)
+	if err != nil {
+		return "", newVerifyError(ErrInvalidSignature, "could not parse recovered pubkey: %v", err)
+	}
+	return hex.EncodeToString(pubKey.SerializeCompressed()), nil
+}
@@ -0,0 +1,138 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestVerifyMultisig(t *testing.T) {
+	const message = "treasury transfer #42 approved"
+	alice := signTestMessage(t, "alice", nil, message)
+	bob := signTestMessage(t, "bob", nil, message)
+	carol := signTestMessage(t, "carol", nil, message)
+	mallory := signTestMessage(t, "mallory", nil, message)
+	members := []string{alice.Address, bob.Address, carol.Address}
+
+	tests := []struct {
+		name          string
+		signatures    []string
+		threshold     int
+		wantSigners   []string
+		wantUnmatched []int
+		wantMet       bool
+		wantErr       error
+	}{
+		{
+			name:        "Threshold met",
+			signatures:  []string{carol.Signature, alice.Signature},
+			threshold:   2,
+			wantSigners: []string{alice.Address, carol.Address},
+			wantMet:     true,
+		},
+		{
+			name:          "Threshold not met",
+			signatures:    []string{bob.Signature, mallory.Signature},
+			threshold:     2,
+			wantSigners:   []string{bob.Address},
+			wantUnmatched: []int{1},
+		},
+		{
+			name:          "Repeated signer counts once",
+			signatures:    []string{bob.Signature, bob.Signature},
+			threshold:     2,
+			wantSigners:   []string{bob.Address},
+			wantUnmatched: []int{1},
+		},
+		{
+			name:          "Malformed signature",
+			signatures:    []string{"not base64", alice.Signature, bob.Signature, carol.Signature},
+			threshold:     3,
+			wantSigners:   members,
+			wantUnmatched: []int{0},
+			wantMet:       true,
+		},
+		{
+			name:          "Signature of another message",
+			signatures:    []string{signTestMessage(t, "alice", nil, "other message").Signature},
+			threshold:     1,
+			wantUnmatched: []int{0},
+		},
+		{
+			name:       "Threshold above members",
+			signatures: []string{alice.Signature},
+			threshold:  4,
+			wantErr:    ErrInvalidMultisigPolicy,
+		},
+		{
+			name:       "Zero threshold",
+			signatures: []string{alice.Signature},
+			threshold:  0,
+			wantErr:    ErrInvalidMultisigPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := VerifyMultisig(message, tt.signatures, members, tt.threshold, &chaincfg.MainNetParams)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("VerifyMultisig() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyMultisig() error = %v", err)
+			}
+			if !reflect.DeepEqual(result.Signers, tt.wantSigners) {
+				t.Errorf("VerifyMultisig() signers = %v, want %v", result.Signers, tt.wantSigners)
+			}
+			if !reflect.DeepEqual(result.Unmatched, tt.wantUnmatched) {
+				t.Errorf("VerifyMultisig() unmatched = %v, want %v", result.Unmatched, tt.wantUnmatched)
+			}
+			if result.ThresholdMet != tt.wantMet {
+				t.Errorf("VerifyMultisig() threshold met = %v, want %v", result.ThresholdMet, tt.wantMet)
+			}
+		})
+	}
+}
+
+func TestVerifyMultisigDuplicateMember(t *testing.T) {
+	alice := signTestMessage(t, "alice", nil, "message")
+	_, err := VerifyMultisig("message", []string{alice.Signature}, []string{alice.Address, alice.Address}, 1, &chaincfg.MainNetParams)
+	if !errors.Is(err, ErrInvalidMultisigPolicy) {
+		t.Errorf("VerifyMultisig() error = %v, want %v", err, ErrInvalidMultisigPolicy)
+	}
+}
+
+func TestVerifyMultisigSameKeyAddresses(t *testing.T) {
+	const message = "treasury transfer #43 approved"
+	alice := signTestMessage(t, "alice", nil, message)
+	bob := signTestMessage(t, "bob", nil, message)
+
+	// The P2WPKH address of alice's key, and her signature for it
+	privKey := testPrivKey("alice")
+	segwit, err := btcutil.NewAddressWitnessPubKeyHash(btcutil.Hash160(privKey.PubKey().SerializeCompressed()), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("btcutil.NewAddressWitnessPubKeyHash() error = %v", err)
+	}
+	sigBytes, _ := base64.StdEncoding.DecodeString(alice.Signature)
+	sigBytes[0] += headerP2WPKH - headerP2PKHCompressed
+	segwitSignature := base64.StdEncoding.EncodeToString(sigBytes)
+
+	members := []string{alice.Address, segwit.EncodeAddress(), bob.Address}
+	result, err := VerifyMultisig(message, []string{alice.Signature, segwitSignature}, members, 2, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("VerifyMultisig() error = %v", err)
+	}
+	if result.ThresholdMet {
+		t.Errorf("VerifyMultisig() threshold met by one key with signers %v", result.Signers)
+	}
+	if !reflect.DeepEqual(result.Signers, []string{alice.Address}) || !reflect.DeepEqual(result.Unmatched, []int{1}) {
+		t.Errorf("VerifyMultisig() = %+v, want signer %s and signature 1 unmatched", result, alice.Address)
+	}
+}
@@ -0,0 +1,147 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// AggregateMuSig2Keys aggregates the public keys of the participants of a
+// MuSig2 group into the group key, sorting them first as BIP-327 KeySort
+// does, so the order they are given in doesn't matter. The result is the
+// untweaked aggregate key, the internal key of the group's Taproot address.
+func AggregateMuSig2Keys(pubKeys []*btcec.PublicKey) (*btcec.PublicKey, error) {
+	if len(pubKeys) == 0 {
+		return nil, ErrEmptyPublicKey
+	}
+	for _, pubKey := range pubKeys {
+		if pubKey == nil {
+			return nil, ErrEmptyPublicKey
+		}
+	}
+	agg, _, _, err := musig2.AggregateKeys(pubKeys, true)
+	if err != nil {
+		return nil, newVerifyError(ErrInvalidPublicKey, "aggregating keys: %v", err)
+	}
+	return agg.PreTweakedKey, nil
+}
+
+// VerifyMuSig2 verifies a base64-encoded 64-byte MuSig2 aggregate Schnorr
+// signature of the message digest, as returned by MessageHash, by the group
+// of participant keys. The signature may be made with the aggregate key of
+// the group or with its BIP-86 tweaked output key, which groups use to sign
+// for their Taproot address.
+func VerifyMuSig2(pubKeys []*btcec.PublicKey, message, signatureBase64 string) (bool, error) {
+	agg, err := AggregateMuSig2Keys(pubKeys)
+	if err != nil {
+		logEvent(LogLevelError, "Failed to aggregate MuSig2 keys", "error", err)
+		return false, err
+	}
+	sig, digest, err := decodeSchnorrProof(message, signatureBase64)
+	if err != nil {
+		return false, err
+	}
+
+	keys := []*btcec.PublicKey{agg}
+	if tweaked := musig2Bip86Key(pubKeys); tweaked != nil {
+		keys = append(keys, tweaked)
+	}
+	for _, key := range keys {
+		if sig.Verify(digest[:], key) {
+			logEvent(LogLevelInfo, "MuSig2 signature verification successful", "aggregate_key", hex.EncodeToString(schnorr.SerializePubKey(agg)), "signers", len(pubKeys))
+			return true, nil
+		}
+	}
+	logEvent(LogLevelError, "MuSig2 signature verification failed", "aggregate_key", hex.EncodeToString(schnorr.SerializePubKey(agg)))
+	return false, newVerifyError(ErrInvalidSignature, "schnorr signature does not verify against the aggregate key %x or its taproot output key", schnorr.SerializePubKey(agg))
+}
+
+// VerifyMuSig2WithAggregateKey is like VerifyMuSig2 for a group known by its
+// 32-byte x-only key, which the signature must verify against as is.
+func VerifyMuSig2WithAggregateKey(xOnlyKey []byte, message, signatureBase64 string) (bool, error) {
+	if len(xOnlyKey) == 0 {
+		return false, ErrEmptyPublicKey
+	}
+	key, err := schnorr.ParsePubKey(xOnlyKey)
+	if err != nil {
+		return false, newVerifyError(ErrInvalidPublicKey, "%v", err)
+	}
+	return verifySchnorrProof(key, message, signatureBase64)
+}
+
+// VerifyMuSig2WithAddress is like VerifyMuSig2 for a group known by its P2TR
+// address, whose output key the signature must verify against. Since only
+// the output key is checked, this verifies a Schnorr signature by whoever
+// controls the key path of the address, MuSig2 group or not.
+func VerifyMuSig2WithAddress(address, message, signatureBase64 string, params *chaincfg.Params) (bool, error) {
+	if address == "" {
+		return false, ErrEmptyAddress
+	}
+	addr, err := decodeAddress(address, params)
+	if err != nil {
+		return false, err
+	}
+	taproot, ok := addr.(*btcutil.AddressTaproot)
+	if !ok {
+		return false, newVerifyError(ErrUnsupportedAddressType, "MuSig2 signatures need a P2TR address, not %s", addressTypeOf(addr))
+	}
+	key, err := schnorr.ParsePubKey(taproot.WitnessProgram())
+	if err != nil {
+		return false, newVerifyError(ErrInvalidAddress, "output key: %v", err)
+	}
+	return verifySchnorrProof(key, message, signatureBase64)
+}
+
+// verifySchnorrProof verifies a Schnorr signature of the message digest by
+// a single key
+func verifySchnorrProof(key *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
+	sig, digest, err := decodeSchnorrProof(message, signatureBase64)
+	if err != nil {
+		return false, err
+	}
+	if !sig.Verify(digest[:], key) {
+		logEvent(LogLevelError, "Schnorr signature verification failed", "key", hex.EncodeToString(schnorr.SerializePubKey(key)))
+		return false, newVerifyError(ErrInvalidSignature, "schnorr signature does not verify against %x", schnorr.SerializePubKey(key))
+	}
+	logEvent(LogLevelInfo, "Schnorr signature verification successful", "key", hex.EncodeToString(schnorr.SerializePubKey(key)))
+	return true, nil
+}
+
+// decodeSchnorrProof checks the message and decodes the 64-byte Schnorr
+// signature of a MuSig2 proof, returning it with the digest it signs
+func decodeSchnorrProof(message, signatureBase64 string) (*schnorr.Signature, [32]byte, error) {
+	switch {
+	case message == "":
+		return nil, [32]byte{}, ErrEmptyMessage
+	case signatureBase64 == "":
+		return nil, [32]byte{}, ErrEmptySignature
+	}
+	if err := checkMessageSize(eventLogger{}, message, MaxMessageSize()); err != nil {
+		return nil, [32]byte{}, err
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return nil, [32]byte{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	sig, err := schnorr.ParseSignature(sigBytes)
+	if err != nil {
+		return nil, [32]byte{}, newVerifyError(ErrMalformedSignature, "invalid schnorr signature: %v", err)
+	}
+	return sig, magicHash(message), nil
+}
+
+// musig2Bip86Key returns the BIP-86 tweaked aggregate key of the group, the
+// output key of its Taproot address
+func musig2Bip86Key(pubKeys []*btcec.PublicKey) *btcec.PublicKey {
+	agg, _, _, err := musig2.AggregateKeys(pubKeys, true, musig2.WithBIP86KeyTweak())
+	if err != nil {
+		return nil
+	}
+	return agg.FinalKey
+}
@@ -0,0 +1,132 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr/musig2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+func TestVerifyMuSig2(t *testing.T) {
+	const message = "collaborative custody proof"
+
+	var privKeys []*btcec.PrivateKey
+	var pubKeys []*btcec.PublicKey
+	for _, seed := range []string{"alice", "bob", "carol"} {
+		privKey := testPrivKey(seed)
+		privKeys = append(privKeys, privKey)
+		pubKeys = append(pubKeys, privKey.PubKey())
+	}
+	reordered := []*btcec.PublicKey{pubKeys[2], pubKeys[0], pubKeys[1]}
+
+	sig := signMuSig2(t, privKeys, message, false)
+	tweakedSig := signMuSig2(t, privKeys, message, true)
+	otherSig := signMuSig2(t, privKeys, "another message", false)
+
+	tests := []struct {
+		name      string
+		pubKeys   []*btcec.PublicKey
+		signature string
+		wantErr   error
+	}{
+		{name: "Aggregate key signature", pubKeys: pubKeys, signature: sig},
+		{name: "Keys in another order", pubKeys: reordered, signature: sig},
+		{name: "Taproot output key signature", pubKeys: pubKeys, signature: tweakedSig},
+		{name: "Signature of another message", pubKeys: pubKeys, signature: otherSig, wantErr: ErrInvalidSignature},
+		{name: "Subset of the signers", pubKeys: pubKeys[:2], signature: sig, wantErr: ErrInvalidSignature},
+		{name: "Compact signature", pubKeys: pubKeys, signature: walletTestVectors[2].msg.Signature, wantErr: ErrMalformedSignature},
+		{name: "No keys", signature: sig, wantErr: ErrEmptyPublicKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, err := VerifyMuSig2(tt.pubKeys, message, tt.signature)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("VerifyMuSig2() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != (tt.wantErr == nil) {
+				t.Errorf("VerifyMuSig2() = %v, want %v", valid, tt.wantErr == nil)
+			}
+		})
+	}
+
+	agg, err := AggregateMuSig2Keys(pubKeys)
+	if err != nil {
+		t.Fatalf("AggregateMuSig2Keys() error = %v", err)
+	}
+	if valid, err := VerifyMuSig2WithAggregateKey(schnorr.SerializePubKey(agg), message, sig); err != nil || !valid {
+		t.Errorf("VerifyMuSig2WithAggregateKey() = %v, %v, want true", valid, err)
+	}
+
+	outputKey := txscript.ComputeTaprootKeyNoScript(agg)
+	addr, err := btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("btcutil.NewAddressTaproot() error = %v", err)
+	}
+	if valid, err := VerifyMuSig2WithAddress(addr.EncodeAddress(), message, tweakedSig, &chaincfg.MainNetParams); err != nil || !valid {
+		t.Errorf("VerifyMuSig2WithAddress() = %v, %v, want true", valid, err)
+	}
+	if _, err := VerifyMuSig2WithAddress(addr.EncodeAddress(), message, sig, &chaincfg.MainNetParams); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("VerifyMuSig2WithAddress() with the untweaked signature error = %v, want %v", err, ErrInvalidSignature)
+	}
+	if _, err := VerifyMuSig2WithAddress(walletTestVectors[2].msg.Address, message, tweakedSig, &chaincfg.MainNetParams); !errors.Is(err, ErrUnsupportedAddressType) {
+		t.Errorf("VerifyMuSig2WithAddress() with a P2PKH address error = %v, want %v", err, ErrUnsupportedAddressType)
+	}
+}
+
+// Helper function to produce a MuSig2 signature of the message digest by
+// the keys, for their aggregate key or its BIP-86 tweaked output key
+func signMuSig2(t *testing.T, privKeys []*btcec.PrivateKey, message string, bip86 bool) string {
+	t.Helper()
+
+	var pubKeys []*btcec.PublicKey
+	for _, privKey := range privKeys {
+		pubKeys = append(pubKeys, privKey.PubKey())
+	}
+	opts := []musig2.ContextOption{musig2.WithKnownSigners(pubKeys)}
+	if bip86 {
+		opts = append(opts, musig2.WithBip86TweakCtx())
+	}
+
+	sessions := make([]*musig2.Session, len(privKeys))
+	for i, privKey := range privKeys {
+		ctx, err := musig2.NewContext(privKey, true, opts...)
+		if err != nil {
+			t.Fatalf("musig2.NewContext() error = %v", err)
+		}
+		if sessions[i], err = ctx.NewSession(); err != nil {
+			t.Fatalf("NewSession() error = %v", err)
+		}
+	}
+	for i, session := range sessions {
+		for j, other := range sessions {
+			if i == j {
+				continue
+			}
+			if _, err := session.RegisterPubNonce(other.PublicNonce()); err != nil {
+				t.Fatalf("RegisterPubNonce() error = %v", err)
+			}
+		}
+	}
+
+	digest := magicHash(message)
+	partials := make([]*musig2.PartialSignature, len(sessions))
+	for i, session := range sessions {
+		var err error
+		if partials[i], err = session.Sign(digest); err != nil {
+			t.Fatalf("Sign() error = %v", err)
+		}
+	}
+	for i := 1; i < len(partials); i++ {
+		if _, err := sessions[0].CombineSig(partials[i]); err != nil {
+			t.Fatalf("CombineSig() error = %v", err)
+		}
+	}
+	return base64.StdEncoding.EncodeToString(sessions[0].FinalSig().Serialize())
+}
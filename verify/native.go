@@ -0,0 +1,186 @@
+package verify
+
+import (
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// compactSignatureLength is the length of a decoded BIP-0137 signature: one
+// header byte followed by the 32-byte R and S values
+const compactSignatureLength = 65
+
+// Header byte ranges defined by BIP-0137
+const (
+	headerP2PKHUncompressed = 27
+	headerP2PKHCompressed   = 31
+	headerP2SHP2WPKH        = 35
+	headerP2WPKH            = 39
+	headerMax               = 42
+)
+
+// verifyDigest verifies a decoded BIP-0137 signature over a precomputed
+// message digest natively: the public key is recovered from the signature and
+// the address derived from it is compared with the expected address.
+func verifyDigest(spans spanScope, address string, digest, sigBytes []byte, params *chaincfg.Params) (bool, error) {
+	report := explainDigest(eventLogger{}, spans, address, digest, sigBytes, params, TaprootKeyInternal)
+	return report.Valid, report.Err
+}
+
+// explainDigest runs the native verification stages on a decoded signature,
+// recording what was recovered and where verification failed. Each stage runs
+// in its own span, and the context of spans is checked after each of them, so
+// a done context stops verification with ErrVerificationTimeout. For P2TR
+// addresses, taprootKey selects which key of the address the recovered key
+// is.
+func explainDigest(events eventLogger, spans spanScope, address string, digest, sigBytes []byte, params *chaincfg.Params, taprootKey TaprootKey) FailureReport {
+	report := FailureReport{ExpectedAddress: address}
+
+	_, span := spans.start(SpanDecode)
+	span.SetAttribute("signature_length", len(sigBytes))
+	addr, err := decodeAddress(address, params)
+	if err == nil {
+		err = spans.err()
+	}
+	if err == nil && len(sigBytes) != compactSignatureLength {
+		err = newVerifyError(ErrMalformedSignature, "wrong signature length: %d instead of %d", len(sigBytes), compactSignatureLength)
+	}
+	span.End(err)
+	if err != nil {
+		return report.fail(StageDecoding, err)
+	}
+	report.HeaderByte = sigBytes[0]
+
+	_, span = spans.start(SpanRecover)
+	span.SetAttribute("header_byte", int(sigBytes[0]))
+	pubKey, compressed, err := recoverPubKey(sigBytes, digest)
+	if err == nil {
+		err = spans.err()
+	}
+	if err == nil {
+		span.SetAttribute("compressed", compressed)
+	}
+	span.End(err)
+	if err != nil {
+		return report.fail(StageRecovery, err)
+	}
+	events.log(LogLevelTrace, "Recovered public key", "pubkey", hex.EncodeToString(pubKey.SerializeCompressed()), "compressed", compressed, "header_byte", sigBytes[0])
+
+	report.Compressed = compressed
+	if compressed {
+		report.RecoveredPubKey = hex.EncodeToString(pubKey.SerializeCompressed())
+	} else {
+		report.RecoveredPubKey = hex.EncodeToString(pubKey.SerializeUncompressed())
+	}
+
+	_, span = spans.start(SpanDerive)
+	derived, err := deriveAddressForHeader(pubKey, compressed, sigBytes[0], addr, params, taprootKey)
+	if err == nil {
+		err = spans.err()
+	}
+	if err == nil {
+		span.SetAttribute("derived_address", derived)
+	}
+	span.End(err)
+	if err != nil {
+		return report.fail(StageDerivation, err)
+	}
+	report.RecoveredAddress = derived
+
+	_, span = spans.start(SpanCompare)
+	if derived != addr.EncodeAddress() {
+		err = newVerifyError(ErrAddressMismatch, "generated address '%s' does not match expected address '%s'", derived, addr.EncodeAddress())
+	}
+	span.End(err)
+	if err != nil {
+		return report.fail(StageAddressMismatch, err)
+	}
+
+	report.Valid = true
+	return report
+}
+
+// recoverPubKey recovers the public key from a 65-byte BIP-0137 signature and
+// the message digest. SegWit header bytes (35-42) are mapped onto the
+// compressed P2PKH range, which is all the recovery itself cares about.
+func recoverPubKey(sigBytes, digest []byte) (*btcec.PublicKey, bool, error) {
+	header := sigBytes[0]
+	if header < headerP2PKHUncompressed || header > headerMax {
+		return nil, false, newVerifyError(ErrInvalidHeaderByte, "0x%02x", header)
+	}
+
+	compact := sigBytes
+	if header >= headerP2SHP2WPKH {
+		compact = make([]byte, compactSignatureLength)
+		copy(compact, sigBytes)
+		compact[0] = headerP2PKHCompressed + (header-headerP2PKHUncompressed)&0x03
+	}
+
+	pubKey, compressed, err := ecdsa.RecoverCompact(compact, digest)
+	if err != nil {
+		return nil, false, newVerifyError(ErrInvalidSignature, "could not recover pubkey: %v", err)
+	}
+
+	return pubKey, compressed, nil
+}
+
+// deriveAddressForHeader derives the address of the same type as addr from
+// the recovered public key, rejecting header bytes that can't be used with
+// that address type.
+func deriveAddressForHeader(pubKey *btcec.PublicKey, compressed bool, header byte, addr btcutil.Address, params *chaincfg.Params, taprootKey TaprootKey) (string, error) {
+	var serialized []byte
+	if compressed {
+		serialized = pubKey.SerializeCompressed()
+	} else {
+		serialized = pubKey.SerializeUncompressed()
+	}
+	pubKeyHash := btcutil.Hash160(serialized)
+
+	isSegWitHeader := header >= headerP2SHP2WPKH
+
+	var derived btcutil.Address
+	var err error
+
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash:
+		if isSegWitHeader {
+			return "", newVerifyError(ErrInvalidHeaderByte, "cannot use P2PKH address with SegWit header byte 0x%02x", header)
+		}
+		derived, err = btcutil.NewAddressPubKeyHash(pubKeyHash, params)
+
+	case *btcutil.AddressScriptHash:
+		if !compressed || header >= headerP2WPKH {
+			return "", newVerifyError(ErrInvalidHeaderByte, "cannot use P2SH-P2WPKH address with header byte 0x%02x", header)
+		}
+		var witnessProgram []byte
+		witnessProgram, err = txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(pubKeyHash).Script()
+		if err == nil {
+			derived, err = btcutil.NewAddressScriptHash(witnessProgram, params)
+		}
+
+	case *btcutil.AddressWitnessPubKeyHash:
+		if !compressed {
+			return "", newVerifyError(ErrInvalidHeaderByte, "cannot use P2WPKH address with header byte 0x%02x", header)
+		}
+		derived, err = btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+
+	case *btcutil.AddressTaproot:
+		if isSegWitHeader {
+			return "", newVerifyError(ErrInvalidHeaderByte, "cannot use P2TR address with SegWit header byte 0x%02x", header)
+		}
+		derived, err = taprootAddress(pubKey, taprootKey, params)
+
+	default:
+		return "", newVerifyError(ErrUnsupportedAddressType, "'%T'", addr)
+	}
+
+	if err != nil {
+		return "", newVerifyError(ErrInvalidAddress, "failed to derive address from public key: %v", err)
+	}
+
+	return derived.EncodeAddress(), nil
+}
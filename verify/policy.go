@@ -0,0 +1,136 @@
+package verify
+
+import (
+	"encoding/base64"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// PolicyRule identifies a rule of a Policy
+type PolicyRule string
+
+// Rules a Policy can enforce
+const (
+	PolicyRuleNetwork      PolicyRule = "network"
+	PolicyRuleAddressType  PolicyRule = "address_type"
+	PolicyRuleLowS         PolicyRule = "low_s"
+	PolicyRuleStrictHeader PolicyRule = "strict_header"
+	PolicyRuleMessageAge   PolicyRule = "message_age"
+)
+
+// PolicyViolation is a rule of a Policy that a signed message breaks
+type PolicyViolation struct {
+	// Rule is the broken rule
+	Rule PolicyRule `json:"rule"`
+
+	// Detail explains how the message breaks the rule
+	Detail string `json:"detail"`
+}
+
+// String returns the rule followed by the detail
+func (v PolicyViolation) String() string {
+	return fmt.Sprintf("%s: %s", v.Rule, v.Detail)
+}
+
+// Policy describes which signed messages an application accepts, beyond
+// them carrying a valid signature. The zero Policy accepts everything.
+type Policy struct {
+	// AllowedNetworks are the names of the networks proofs may be made on,
+	// such as mainnet or signet; "testnet" stands for testnet3. Empty allows
+	// any network.
+	AllowedNetworks []string
+
+	// AllowedAddressTypes are the address types proofs may be made with.
+	// Empty allows any type.
+	AllowedAddressTypes []AddressType
+
+	// RequireLowS rejects compact signatures with a high S value, like
+	// WithRequireLowS
+	RequireLowS bool
+
+	// RequireStrictHeader rejects compact signatures whose header byte is
+	// for another address type, like WithStrictHeader
+	RequireStrictHeader bool
+
+	// MaxMessageAge rejects messages signed longer ago than this, and
+	// messages timestamped in the future by more than a minute of clock
+	// skew, like WithMaxMessageAge. Zero disables the rule.
+	MaxMessageAge time.Duration
+
+	// MessageTime extracts the time a message was signed at from the
+	// message, MessageTimestamp when nil. MaxMessageAge rejects every message
+	// it fails for.
+	MessageTime func(message string) (time.Time, error)
+}
+
+// Check evaluates the policy for a signed message verified with params at
+// time now, returning the rules it breaks. It doesn't verify the signature.
+// The signature rules only apply to 65-byte compact signatures, and a
+// message whose address doesn't decode breaks no address rule, as
+// verification rejects both anyway.
+func (p *Policy) Check(msg SignedMessage, params *chaincfg.Params, now time.Time) []PolicyViolation {
+	sigBytes, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		sigBytes = nil
+	}
+	return p.check(msg, sigBytes, params, now)
+}
+
+// check implements Check with the decoded signature
+func (p *Policy) check(msg SignedMessage, sigBytes []byte, params *chaincfg.Params, now time.Time) []PolicyViolation {
+	var violations []PolicyViolation
+	violate := func(rule PolicyRule, format string, args ...any) {
+		violations = append(violations, PolicyViolation{Rule: rule, Detail: fmt.Sprintf(format, args...)})
+	}
+
+	if len(p.AllowedNetworks) > 0 && !slices.ContainsFunc(p.AllowedNetworks, func(name string) bool {
+		allowed := networkByName(name)
+		return allowed != nil && allowed.Name == params.Name
+	}) {
+		violate(PolicyRuleNetwork, "network %s is not allowed", params.Name)
+	}
+
+	addr, err := decodeAddress(msg.Address, params)
+	if err == nil {
+		addrType := addressTypeOf(addr)
+		if len(p.AllowedAddressTypes) > 0 && !slices.Contains(p.AllowedAddressTypes, addrType) {
+			violate(PolicyRuleAddressType, "%s addresses are not allowed", addrType)
+		}
+		if p.RequireStrictHeader && len(sigBytes) == compactSignatureLength {
+			if got := headerAddressType(sigBytes[0]); got != addrType {
+				violate(PolicyRuleStrictHeader, "header byte 0x%02x is not for %s addresses", sigBytes[0], addrType)
+			}
+		}
+	}
+
+	if p.RequireLowS && len(sigBytes) == compactSignatureLength && !isLowS(sigBytes) {
+		violate(PolicyRuleLowS, "S value is in the upper half of the curve order")
+	}
+
+	if p.MaxMessageAge > 0 {
+		messageTime := p.MessageTime
+		if messageTime == nil {
+			messageTime = MessageTimestamp
+		}
+		if signedAt, err := messageTime(msg.Message); err != nil {
+			violate(PolicyRuleMessageAge, "the time the message was signed at is unknown: %v", err)
+		} else if err := checkSignedAt(signedAt, p.MaxMessageAge, now); err != nil {
+			violate(PolicyRuleMessageAge, "%v", err)
+		}
+	}
+
+	return violations
+}
+
+// policyError returns the error reporting the violations
+func policyError(violations []PolicyViolation) error {
+	details := make([]string, len(violations))
+	for i, violation := range violations {
+		details[i] = violation.String()
+	}
+	return newVerifyError(ErrPolicyViolation, "%s", strings.Join(details, "; "))
+}
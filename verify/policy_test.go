@@ -0,0 +1,114 @@
+package verify
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	fresh := signTestMessage(t, "alice", nil, "login at 2026-01-01T11:59:00Z")
+	stale := signTestMessage(t, "alice", nil, "login at 2025-12-31T12:00:00Z")
+	untimed := signTestMessage(t, "alice", nil, "login")
+	future := signTestMessage(t, "alice", nil, "login at 2026-01-02T12:00:00Z")
+	skewed := signTestMessage(t, "alice", nil, "login at 2026-01-01T12:00:30Z")
+	timestamped := signTestMessage(t, "alice", nil, TimestampMessage("login", now.Add(-time.Minute)))
+	highS := fresh
+	highS.Signature = highSVariant(t, fresh.Signature)
+
+	messageTime := func(message string) (time.Time, error) {
+		_, at, ok := strings.Cut(message, " at ")
+		if !ok {
+			return time.Time{}, errors.New("no timestamp")
+		}
+		return time.Parse(time.RFC3339, at)
+	}
+
+	tests := []struct {
+		name   string
+		policy Policy
+		msg    SignedMessage
+		params *chaincfg.Params
+		want   []PolicyRule
+	}{
+		{name: "Zero policy", msg: highS},
+		{name: "Allowed network", policy: Policy{AllowedNetworks: []string{"testnet", "mainnet"}}, msg: fresh},
+		{name: "Disallowed network", policy: Policy{AllowedNetworks: []string{"testnet"}}, msg: fresh, want: []PolicyRule{PolicyRuleNetwork}},
+		{name: "Testnet alias", policy: Policy{AllowedNetworks: []string{"testnet"}}, msg: fresh, params: &chaincfg.TestNet3Params},
+		{name: "Allowed address type", policy: Policy{AllowedAddressTypes: []AddressType{AddressTypeP2PKH}}, msg: fresh},
+		{name: "Disallowed address type", policy: Policy{AllowedAddressTypes: []AddressType{AddressTypeP2WPKH, AddressTypeP2TR}}, msg: fresh, want: []PolicyRule{PolicyRuleAddressType}},
+		{name: "High S", policy: Policy{RequireLowS: true}, msg: highS, want: []PolicyRule{PolicyRuleLowS}},
+		{name: "Matching header", policy: Policy{RequireStrictHeader: true}, msg: walletTestVectors[5].msg},
+		{name: "Mismatched header", policy: Policy{RequireStrictHeader: true}, msg: walletTestVectors[6].msg, want: []PolicyRule{PolicyRuleStrictHeader}},
+		{name: "Fresh message", policy: Policy{MaxMessageAge: time.Hour, MessageTime: messageTime}, msg: fresh},
+		{name: "Stale message", policy: Policy{MaxMessageAge: time.Hour, MessageTime: messageTime}, msg: stale, want: []PolicyRule{PolicyRuleMessageAge}},
+		{name: "Future message", policy: Policy{MaxMessageAge: time.Hour, MessageTime: messageTime}, msg: future, want: []PolicyRule{PolicyRuleMessageAge}},
+		{name: "Message within clock skew", policy: Policy{MaxMessageAge: time.Hour, MessageTime: messageTime}, msg: skewed},
+		{name: "Message without timestamp", policy: Policy{MaxMessageAge: time.Hour, MessageTime: messageTime}, msg: untimed, want: []PolicyRule{PolicyRuleMessageAge}},
+		{name: "Default extractor", policy: Policy{MaxMessageAge: time.Hour}, msg: timestamped},
+		{name: "Default extractor without timestamp line", policy: Policy{MaxMessageAge: time.Hour}, msg: fresh, want: []PolicyRule{PolicyRuleMessageAge}},
+		{
+			name:   "Several violations",
+			policy: Policy{AllowedAddressTypes: []AddressType{AddressTypeP2TR}, RequireLowS: true, MaxMessageAge: time.Hour, MessageTime: messageTime},
+			msg:    highS,
+			want:   []PolicyRule{PolicyRuleAddressType, PolicyRuleLowS},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+			if params == nil {
+				params = &chaincfg.MainNetParams
+			}
+			var got []PolicyRule
+			for _, violation := range tt.policy.Check(tt.msg, params, now) {
+				got = append(got, violation.Rule)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Policy.Check() rules = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifierWithPolicy(t *testing.T) {
+	valid := walletTestVectors[2].msg
+	forged := valid
+	forged.Message += " (edited)"
+	segwitOnly := Policy{AllowedAddressTypes: []AddressType{AddressTypeP2WPKH}}
+
+	tests := []struct {
+		name           string
+		policy         Policy
+		msg            SignedMessage
+		wantValid      bool
+		wantViolations int
+		wantErr        error
+	}{
+		{name: "Valid signature within policy", msg: valid, wantValid: true},
+		{name: "Valid signature violating policy", policy: segwitOnly, msg: valid, wantViolations: 1, wantErr: ErrPolicyViolation},
+		{name: "Invalid signature within policy", msg: forged, wantErr: ErrAddressMismatch},
+		{name: "Invalid signature violating policy", policy: segwitOnly, msg: forged, wantViolations: 1, wantErr: ErrPolicyViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewVerifier(WithPolicy(tt.policy)).Verify(tt.msg)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Verifier.Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("Verifier.Verify().Valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+			if len(result.Violations) != tt.wantViolations {
+				t.Errorf("Verifier.Verify().Violations = %v, want %d violations", result.Violations, tt.wantViolations)
+			}
+		})
+	}
+}
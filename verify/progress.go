@@ -0,0 +1,147 @@
+package verify
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// defaultProgressInterval is how often progress is reported when
+// WithProgress is given no interval
+const defaultProgressInterval = time.Second
+
+// BatchProgress is a snapshot of a running batch verification
+type BatchProgress struct {
+	// Done is the number of messages verified so far
+	Done int
+
+	// Total is the number of messages to verify, the size of the sample
+	// when only a sample is verified
+	Total int
+
+	// Valid is the number of messages with a valid signature so far
+	Valid int
+
+	// Invalid is the number of messages that failed verification so far
+	Invalid int
+
+	// Cancelled is the number of messages left unverified because the
+	// context was done, only known in the final snapshot
+	Cancelled int
+
+	// Elapsed is the time since the batch started
+	Elapsed time.Duration
+
+	// ETA estimates the time until the batch completes from the rate so
+	// far; it's zero until a message has been verified and once the batch
+	// has completed
+	ETA time.Duration
+
+	// Final reports the last snapshot, taken when the batch has completed
+	Final bool
+}
+
+// WithProgress calls report with a snapshot of the batch every interval,
+// one second when interval isn't positive, and once more when the batch
+// completes, so CLIs and dashboards can show the progress of long runs.
+// Snapshots are reported from a single goroutine, in order; a slow callback
+// delays the next snapshot but not the verification.
+func WithProgress(interval time.Duration, report func(BatchProgress)) BatchOption {
+	return func(c *batchConfig) {
+		if interval <= 0 {
+			interval = defaultProgressInterval
+		}
+		c.progressInterval = interval
+		c.progress = report
+	}
+}
+
+// progressTracker counts the verified messages of a batch and reports
+// snapshots of them. A nil tracker counts nothing.
+type progressTracker struct {
+	report func(BatchProgress)
+	total  int
+	start  time.Time
+
+	valid   atomic.Int64
+	invalid atomic.Int64
+
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+// newProgressTracker starts reporting the progress of a batch of total
+// messages every interval, or returns nil when no callback is configured
+func newProgressTracker(cfg batchConfig, total int) *progressTracker {
+	if cfg.progress == nil {
+		return nil
+	}
+
+	p := &progressTracker{
+		report:  cfg.progress,
+		total:   total,
+		start:   time.Now(),
+		stop:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go func() {
+		defer close(p.stopped)
+		ticker := time.NewTicker(cfg.progressInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-p.stop:
+				return
+			case <-ticker.C:
+				p.report(p.snapshot())
+			}
+		}
+	}()
+	return p
+}
+
+// record counts the outcome of a verified message
+func (p *progressTracker) record(result *BatchResult) {
+	switch {
+	case p == nil, result.Cancelled:
+	case result.Valid:
+		p.valid.Add(1)
+	default:
+		p.invalid.Add(1)
+	}
+}
+
+// snapshot returns the progress so far
+func (p *progressTracker) snapshot() BatchProgress {
+	progress := BatchProgress{
+		Total:   p.total,
+		Valid:   int(p.valid.Load()),
+		Invalid: int(p.invalid.Load()),
+		Elapsed: time.Since(p.start),
+	}
+	progress.Done = progress.Valid + progress.Invalid
+	if progress.Done > 0 {
+		perMessage := progress.Elapsed / time.Duration(progress.Done)
+		progress.ETA = perMessage * time.Duration(p.total-progress.Done)
+	}
+	return progress
+}
+
+// finish stops the periodic reports and reports the final counts of the
+// batch
+func (p *progressTracker) finish(report *BatchReport) {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	<-p.stopped
+
+	p.report(BatchProgress{
+		Done:      report.Valid + report.Invalid,
+		Total:     p.total,
+		Valid:     report.Valid,
+		Invalid:   report.Invalid,
+		Cancelled: report.Cancelled,
+		Elapsed:   time.Since(p.start),
+		Final:     true,
+	})
+}
@@ -3,7 +3,7 @@ package verify
 import (
 	"context"
 	"encoding/base64"
-	"fmt"
+	"encoding/hex"
 	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
@@ -24,162 +24,109 @@ import (
 // - bool: true if the signature is valid, false otherwise
 // - error: an error if the verification process fails
 func VerifyBip137SignatureWithPubKey(pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
-	LogInfo("Starting BIP-0137 signature verification with public key")
-	LogDebug("Message: %s", message)
-	LogDebug("Signature (Base64): %s", signatureBase64)
-
-	if pubKey != nil {
-		LogDebug("Public Key (compressed): %x", pubKey.SerializeCompressed())
-	} else {
-		LogError("Empty public key provided")
-		return false, fmt.Errorf("empty public key")
+	start := time.Now()
+	valid, err := verifyWithPubKey(eventLogger{}, pubKey, message, signatureBase64)
+	observeVerification(GetMetrics(), start, valid, err)
+	return valid, err
+}
+
+// verifyWithPubKey implements VerifyBip137SignatureWithPubKey, logging to
+// events
+func verifyWithPubKey(events eventLogger, pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
+	events.log(LogLevelInfo, "Starting BIP-0137 signature verification with public key")
+	events.log(LogLevelDebug, "Verification input", "message", message, "signature", signatureBase64)
+
+	if pubKey == nil {
+		events.log(LogLevelError, "Empty public key provided")
+		return false, ErrEmptyPublicKey
 	}
+	events.log(LogLevelDebug, "Public key", "pubkey", hex.EncodeToString(pubKey.SerializeCompressed()))
 
 	startTime := time.Now()
 	defer func() {
-		LogDebug("Verification completed in %s", time.Since(startTime))
+		events.log(LogLevelDebug, "Verification completed", "duration", time.Since(startTime))
 	}()
 
 	// Use the enhanced implementation with fallback to address-based verification
-	return EnhancedVerifyBip137SignatureWithPubKey(pubKey, message, signatureBase64)
+	return enhancedVerifyWithPubKey(events, pubKey, message, signatureBase64)
 }
 
 // VerifyBip137SignatureWithPubKeyAndParams verifies a BIP-0137 signature using the provided
 // public key and network parameters (mainnet, testnet, etc.).
-func VerifyBip137SignatureWithPubKeyAndParams(pubKey *btcec.PublicKey, message, signatureBase64 string, params *chaincfg.Params) (bool, error) {
-	LogDebug("Verifying signature with network parameters: %s", params.Name)
-
-	// Log inputs
-	if GetLogLevel() >= LogLevelTrace {
-		LogTrace("Detailed verification parameters:")
-		LogTrace("Network: %s", params.Name)
-		LogTrace("P2PKH Prefix: %x", params.PubKeyHashAddrID)
-		LogTrace("P2SH Prefix: %x", params.ScriptHashAddrID)
-		if pubKey != nil {
-			LogTrace("Public Key (hex): %x", pubKey.SerializeCompressed())
-		}
-	}
+func VerifyBip137SignatureWithPubKeyAndParams(pubKey *btcec.PublicKey, message, signatureBase64 string, params *chaincfg.Params) (valid bool, err error) {
+	start := time.Now()
+	defer func() {
+		observeVerification(GetMetrics(), start, valid, err)
+	}()
+
+	logEvent(LogLevelDebug, "Verifying signature with network parameters", "network", params.Name)
+	logEvent(LogLevelTrace, "Network parameters",
+		"network", params.Name, "p2pkh_prefix", params.PubKeyHashAddrID, "p2sh_prefix", params.ScriptHashAddrID)
 
 	// Validate inputs
 	if pubKey == nil {
-		LogError("Empty public key provided")
-		return false, fmt.Errorf("empty public key")
+		logEvent(LogLevelError, "Empty public key provided")
+		return false, ErrEmptyPublicKey
 	}
 	if message == "" {
-		LogError("Empty message provided")
+		logEvent(LogLevelError, "Empty message provided")
 		return false, ErrEmptyMessage
 	}
 	if signatureBase64 == "" {
-		LogError("Empty signature provided")
+		logEvent(LogLevelError, "Empty signature provided")
 		return false, ErrEmptySignature
 	}
+	if err := checkMessageSize(eventLogger{}, message, MaxMessageSize()); err != nil {
+		return false, err
+	}
 
 	// Attempt to decode the signature to validate it's correct base64
 	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
 	if err != nil {
-		LogError("Failed to decode base64 signature: %v", err)
-		return false, fmt.Errorf("invalid base64 signature: %w", err)
+		logEvent(LogLevelError, "Failed to decode base64 signature", "error", err)
+		return false, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
 	}
 
 	// Log the decoded signature bytes
-	LogTrace("Decoded signature (hex): %s", DumpHex(sigBytes))
-
-	// Check the signature header byte
-	if len(sigBytes) > 0 {
-		headerByte := sigBytes[0]
-		LogDebug("Signature header byte: 0x%02x", headerByte)
-
-		// Analyze the header byte based on BIP-0137
-		recID := headerByte & 0x03
-		isCompressed := false
-		addrType := "Unknown"
-
-		switch {
-		case headerByte >= 27 && headerByte <= 30:
-			addrType = "P2PKH (uncompressed)"
-			isCompressed = false
-		case headerByte >= 31 && headerByte <= 34:
-			addrType = "P2PKH (compressed)"
-			isCompressed = true
-		case headerByte >= 35 && headerByte <= 38:
-			addrType = "P2SH-P2WPKH (SegWit over P2SH)"
-			isCompressed = true
-		case headerByte >= 39 && headerByte <= 42:
-			addrType = "P2WPKH (native SegWit)"
-			isCompressed = true
-		default:
-			LogWarning("Unknown signature header byte: 0x%02x", headerByte)
-		}
-
-		LogDebug("Signature details from header:")
-		LogDebug("  Address type: %s", addrType)
-		LogDebug("  Compressed public key: %t", isCompressed)
-		LogDebug("  Recovery ID: %d", recID)
-	}
+	logEvent(LogLevelTrace, "Decoded signature", "signature_hex", DumpHex(sigBytes))
+
+	logHeader(eventLogger{}, sigBytes)
 
 	// Derive address and verify using the address-based method with the appropriate network parameters
 	// First derive the address from the public key
 	pubKeyHash := btcutil.Hash160(pubKey.SerializeCompressed())
 	addr, err := btcutil.NewAddressPubKeyHash(pubKeyHash, params)
 	if err != nil {
-		LogError("Failed to derive address from public key: %v", err)
-		return false, fmt.Errorf("failed to derive address from public key: %w", err)
+		logEvent(LogLevelError, "Failed to derive address from public key", "network", params.Name, "error", err)
+		return false, newVerifyError(ErrInvalidAddress, "failed to derive address from public key: %v", err)
 	}
 
 	derivedAddress := addr.EncodeAddress()
-	LogInfo("Derived address from public key: %s", derivedAddress)
+	logEvent(LogLevelInfo, "Derived address from public key", "address", derivedAddress, "network", params.Name)
 
 	// Use the address-based verification with the specified network parameters
-	return VerifyBip137SignatureWithParams(derivedAddress, message, signatureBase64, params)
+	return verifyWithParams(eventLogger{}, derivedAddress, message, signatureBase64, params)
 }
 
 // VerifyBip137SignatureWithPubKeyAndContext verifies a BIP-0137 signature with a public key
 // and context support for timeout and cancellation.
-func VerifyBip137SignatureWithPubKeyAndContext(ctx context.Context, pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
-	LogInfo("Starting context-based signature verification with public key")
-
-	// Check if context has a deadline
-	if deadline, ok := ctx.Deadline(); ok {
-		LogDebug("Context has deadline: %s (timeout in %s)",
-			deadline.Format(time.RFC3339), time.Until(deadline))
-	} else {
-		LogDebug("Context has no deadline")
-	}
-
-	// Create a channel to receive the verification result
-	resultCh := make(chan struct {
-		valid bool
-		err   error
-	}, 1)
-
-	// Run verification in a goroutine
-	startTime := time.Now()
-	go func() {
-		LogDebug("Starting verification goroutine")
-		valid, err := VerifyBip137SignatureWithPubKey(pubKey, message, signatureBase64)
-		duration := time.Since(startTime)
-		LogDebug("Verification completed in goroutine after %s", duration)
-
-		resultCh <- struct {
-			valid bool
-			err   error
-		}{valid, err}
+func VerifyBip137SignatureWithPubKeyAndContext(ctx context.Context, pubKey *btcec.PublicKey, message, signatureBase64 string) (valid bool, err error) {
+	start := time.Now()
+	defer func() {
+		observeVerification(GetMetrics(), start, valid, err)
 	}()
 
-	// Wait for either the context to be done or the verification to complete
-	select {
-	case <-ctx.Done():
-		ctxErr := ctx.Err()
-		LogError("Context cancelled or timed out: %v", ctxErr)
-		return false, fmt.Errorf("%w: %v", ErrVerificationTimeout, ctxErr)
-	case result := <-resultCh:
-		if result.err != nil {
-			LogError("Signature verification error: %v", result.err)
-			return false, fmt.Errorf("signature verification error: %w", result.err)
-		}
-		LogInfo("Context-based verification result: %t", result.valid)
-		return result.valid, nil
+	events := eventLoggerFromContext(ctx)
+	events.log(LogLevelInfo, "Starting context-based signature verification with public key")
+	logContextDeadline(ctx, events)
+
+	if err := checkMessageSize(events, message, MaxMessageSize()); err != nil {
+		return false, err
 	}
+
+	return runWithContext(ctx, func(ctx context.Context) (bool, error) {
+		return verifyWithPubKey(events, pubKey, message, signatureBase64)
+	})
 }
 
 // formatBitcoinMessage adds the Bitcoin message prefix and formats the message
@@ -199,6 +146,6 @@ func formatBitcoinMessage(message string) []byte {
 	// you would need to encode the prefix and message with proper Bitcoin
 	// varint encoding for the lengths
 
-	LogTrace("Formatted Bitcoin message with standard prefix")
+	logEvent(LogLevelTrace, "Formatted Bitcoin message with standard prefix")
 	return []byte(message) // Placeholder return
 }
@@ -0,0 +1,128 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// PubKeyResult is the detailed outcome of verifying a signature against a
+// public key
+type PubKeyResult struct {
+	// Valid reports whether the signature was made by the public key
+	Valid bool
+
+	// AddressType is the address type the header byte stands for
+	AddressType AddressType
+
+	// DerivedAddress is the address of that type for the given public key,
+	// in the serialization the header byte asks for
+	DerivedAddress string
+
+	// RecoveredPubKey is the hex-encoded public key recovered from the
+	// signature, empty when the signature is cryptographically invalid
+	RecoveredPubKey string
+
+	// RecoveredAddress is the address of the same type for the recovered
+	// public key
+	RecoveredAddress string
+
+	// KeyMismatch reports that the signature recovered a public key other
+	// than the given one. Any well-formed signature recovers some key, so a
+	// signature of another message is a mismatch too.
+	KeyMismatch bool
+}
+
+// VerifyDetailed verifies a 65-byte BIP-0137 signature against a public key
+// like VerifyBip137SignatureWithPubKeyAndParams, but tells apart the ways it
+// fails instead of collapsing them into false. A signature that recovers
+// another key fails with ErrAddressMismatch and sets KeyMismatch, with the
+// recovered key and address filled in; a signature no key can be recovered
+// from fails with ErrInvalidSignature. DerivedAddress is set as soon as the
+// header byte is known, so callers can show which address was checked.
+func VerifyDetailed(pubKey *btcec.PublicKey, message, signatureBase64 string, params *chaincfg.Params) (result PubKeyResult, err error) {
+	start := time.Now()
+	defer func() {
+		observeVerification(GetMetrics(), start, result.Valid, err)
+	}()
+
+	switch {
+	case pubKey == nil:
+		return result, ErrEmptyPublicKey
+	case message == "":
+		return result, ErrEmptyMessage
+	case signatureBase64 == "":
+		return result, ErrEmptySignature
+	}
+	if err := checkMessageSize(eventLogger{}, message, MaxMessageSize()); err != nil {
+		return result, err
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return result, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	if len(sigBytes) != compactSignatureLength {
+		return result, newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d", len(sigBytes), compactSignatureLength)
+	}
+
+	header := inspectHeader(sigBytes[0])
+	if !header.Valid() {
+		return result, newVerifyError(ErrInvalidHeaderByte, "0x%02x", header.HeaderByte)
+	}
+	result.AddressType = header.AddressType
+	compressed := header.Compressed
+
+	result.DerivedAddress, err = keyAddress(pubKey, compressed, result.AddressType, params)
+	if err != nil {
+		return result, err
+	}
+	logEvent(LogLevelDebug, "Derived address from public key", "address", result.DerivedAddress, "network", params.Name)
+
+	digest := magicHash(message)
+	recovered, _, err := recoverPubKey(sigBytes, digest[:])
+	if err != nil {
+		logEvent(LogLevelDebug, "Signature is cryptographically invalid", "error", err)
+		return result, err
+	}
+
+	if compressed {
+		result.RecoveredPubKey = hex.EncodeToString(recovered.SerializeCompressed())
+	} else {
+		result.RecoveredPubKey = hex.EncodeToString(recovered.SerializeUncompressed())
+	}
+	result.RecoveredAddress, err = keyAddress(recovered, compressed, result.AddressType, params)
+	if err != nil {
+		return result, err
+	}
+
+	if !recovered.IsEqual(pubKey) {
+		result.KeyMismatch = true
+		logEvent(LogLevelDebug, "Recovered public key doesn't match", "recovered", result.RecoveredPubKey)
+		return result, newVerifyError(ErrAddressMismatch, "signature recovers key of %s, want %s",
+			result.RecoveredAddress, result.DerivedAddress)
+	}
+
+	result.Valid = true
+	return result, nil
+}
+
+// keyAddress returns the key hash address of the given type for a public key
+// in the given serialization
+func keyAddress(pubKey *btcec.PublicKey, compressed bool, addrType AddressType, params *chaincfg.Params) (string, error) {
+	var serialized []byte
+	if compressed {
+		serialized = pubKey.SerializeCompressed()
+	} else {
+		serialized = pubKey.SerializeUncompressed()
+	}
+	addr, err := pubKeyHashAddress(addrType, btcutil.Hash160(serialized), params)
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
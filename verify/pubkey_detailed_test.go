@@ -0,0 +1,102 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestVerifyDetailed(t *testing.T) {
+	pubKey := mustParsePubKey(t, "024da006f958beba78ec54443df4a3f52237253f7ae8cbdb17dccf3feaa57f3126")
+	other := mustParsePubKey(t, "0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	compressed := walletTestVectors[2].msg
+
+	sigBytes, _ := base64.StdEncoding.DecodeString(compressed.Signature)
+	clear(sigBytes[1:33])
+	zeroR := base64.StdEncoding.EncodeToString(sigBytes)
+
+	tests := []struct {
+		name             string
+		msg              SignedMessage
+		otherKey         bool
+		wantValid        bool
+		wantDerived      string
+		wantMismatch     bool
+		wantRecoveredKey bool
+		wantErr          error
+	}{
+		{
+			name:             "Compressed",
+			msg:              compressed,
+			wantValid:        true,
+			wantDerived:      compressed.Address,
+			wantRecoveredKey: true,
+		},
+		{
+			name:             "Uncompressed",
+			msg:              walletTestVectors[1].msg,
+			wantValid:        true,
+			wantDerived:      walletTestVectors[1].msg.Address,
+			wantRecoveredKey: true,
+		},
+		{
+			name:             "Another key",
+			msg:              SignedMessage{Message: compressed.Message, Signature: compressed.Signature},
+			otherKey:         true,
+			wantMismatch:     true,
+			wantRecoveredKey: true,
+			wantErr:          ErrAddressMismatch,
+		},
+		{
+			name:             "Altered message",
+			msg:              SignedMessage{Address: compressed.Address, Message: compressed.Message + ".", Signature: compressed.Signature},
+			wantDerived:      compressed.Address,
+			wantMismatch:     true,
+			wantRecoveredKey: true,
+			wantErr:          ErrAddressMismatch,
+		},
+		{
+			name:        "Zero R",
+			msg:         SignedMessage{Address: compressed.Address, Message: compressed.Message, Signature: zeroR},
+			wantDerived: compressed.Address,
+			wantErr:     ErrInvalidSignature,
+		},
+		{
+			name:    "Not a compact signature",
+			msg:     SignedMessage{Message: compressed.Message, Signature: "AAAA"},
+			wantErr: ErrMalformedSignature,
+		},
+		{
+			name:    "Empty message",
+			msg:     SignedMessage{Signature: compressed.Signature},
+			wantErr: ErrEmptyMessage,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := pubKey
+			if tt.otherKey {
+				key = other
+			}
+			got, err := VerifyDetailed(key, tt.msg.Message, tt.msg.Signature, &chaincfg.MainNetParams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyDetailed() error = %v, want %v", err, tt.wantErr)
+			}
+			if got.Valid != tt.wantValid || got.KeyMismatch != tt.wantMismatch {
+				t.Errorf("VerifyDetailed() = %+v, want valid %v, mismatch %v", got, tt.wantValid, tt.wantMismatch)
+			}
+			if tt.wantDerived != "" && got.DerivedAddress != tt.wantDerived {
+				t.Errorf("DerivedAddress = %s, want %s", got.DerivedAddress, tt.wantDerived)
+			}
+			if (got.RecoveredPubKey != "") != tt.wantRecoveredKey {
+				t.Errorf("RecoveredPubKey = %q, want recovered %v", got.RecoveredPubKey, tt.wantRecoveredKey)
+			}
+			if got.Valid && got.RecoveredAddress != got.DerivedAddress {
+				t.Errorf("RecoveredAddress = %s, want %s", got.RecoveredAddress, got.DerivedAddress)
+			}
+		})
+	}
+}
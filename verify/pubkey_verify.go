@@ -1,9 +1,9 @@
 package verify
 
 import (
-	"crypto/sha256"
 	"encoding/base64"
-	"fmt"
+	"encoding/hex"
+	"time"
 
 	"github.com/btcsuite/btcd/btcec/v2"
 	"github.com/btcsuite/btcd/btcutil"
@@ -37,75 +37,83 @@ import (
 // - bool: true if the signature is valid, false otherwise
 // - error: an error if the verification process fails
 func EnhancedVerifyBip137SignatureWithPubKey(pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
-	LogInfo("Starting enhanced BIP-0137 signature verification with public key")
-	LogDebug("Message: %s", message)
-	LogDebug("Signature (Base64): %s", signatureBase64)
+	start := time.Now()
+	valid, err := enhancedVerifyWithPubKey(eventLogger{}, pubKey, message, signatureBase64)
+	observeVerification(GetMetrics(), start, valid, err)
+	return valid, err
+}
+
+// enhancedVerifyWithPubKey implements EnhancedVerifyBip137SignatureWithPubKey,
+// logging to events
+func enhancedVerifyWithPubKey(events eventLogger, pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
+	events.log(LogLevelInfo, "Starting enhanced BIP-0137 signature verification with public key")
+	events.log(LogLevelDebug, "Verification input", "message", message, "signature", signatureBase64)
 
 	if pubKey == nil {
-		LogError("Empty public key provided")
-		return false, fmt.Errorf("empty public key")
+		events.log(LogLevelError, "Empty public key provided")
+		return false, ErrEmptyPublicKey
+	}
+	if message == "" {
+		events.log(LogLevelError, "Empty message provided")
+		return false, ErrEmptyMessage
+	}
+	if signatureBase64 == "" {
+		events.log(LogLevelError, "Empty signature provided")
+		return false, ErrEmptySignature
+	}
+	if err := checkMessageSize(events, message, MaxMessageSize()); err != nil {
+		return false, err
 	}
 
-	LogDebug("Public Key (compressed): %x", pubKey.SerializeCompressed())
+	events.log(LogLevelDebug, "Public key", "pubkey", hex.EncodeToString(pubKey.SerializeCompressed()))
 
 	// First attempt: Direct verification with public key
-	valid, err := verifySignatureDirectly(pubKey, message, signatureBase64)
+	valid, err := verifySignatureDirectly(events, pubKey, message, signatureBase64)
 	if err == nil {
-		LogInfo("Direct signature verification result: %t", valid)
+		events.log(LogLevelInfo, "Direct signature verification result", "valid", valid)
 		return valid, nil
 	}
 
-	LogDebug("Direct verification failed, falling back to address-based verification: %v", err)
+	events.log(LogLevelDebug, "Direct verification failed, falling back to address-based verification", "error", err)
 
 	// Second attempt: Derive address and use address-based verification
-	return verifyWithDerivedAddress(pubKey, message, signatureBase64)
+	return verifyWithDerivedAddress(events, pubKey, message, signatureBase64)
 }
 
 // verifySignatureDirectly attempts to verify a Bitcoin message signature directly
 // using the provided public key.
-func verifySignatureDirectly(pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
-	LogDebug("Attempting direct signature verification with public key")
+func verifySignatureDirectly(events eventLogger, pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
+	events.log(LogLevelDebug, "Attempting direct signature verification with public key")
 
 	// Decode signature from base64
 	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
 	if err != nil {
-		return false, fmt.Errorf("invalid base64 signature: %w", err)
+		return false, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
 	}
 
-	if len(sigBytes) < 65 {
-		return false, fmt.Errorf("signature too short (expected at least 65 bytes)")
+	if len(sigBytes) < compactSignatureLength {
+		return false, newVerifyError(ErrMalformedSignature, "signature too short (expected at least %d bytes)", compactSignatureLength)
 	}
 
-	// Extract recovery ID and signature components
-	headerByte := sigBytes[0]
-	LogDebug("Signature header byte: 0x%02x", headerByte)
-
-	// Verify header byte is valid per BIP-137
-	recoveryID := int(headerByte-27) % 4
-	isCompressed := headerByte >= 31 // 31-34 = compressed, 27-30 = uncompressed
-
 	// Check that the header byte is within valid ranges for a standard Bitcoin signature
-	if (headerByte < 27 || headerByte > 34) &&
-		(headerByte < 35 || headerByte > 42) {
-		LogError("Invalid header byte: 0x%02x", headerByte)
-		return false, fmt.Errorf("invalid signature header byte: 0x%02x", headerByte)
+	header := inspectHeader(sigBytes[0])
+	if !header.Valid() {
+		events.log(LogLevelError, "Invalid header byte", "header_byte", header.HeaderByte)
+		return false, newVerifyError(ErrInvalidHeaderByte, "0x%02x", header.HeaderByte)
 	}
 
-	LogDebug("Recovery ID: %d, Compressed: %t", recoveryID, isCompressed)
-
-	// Format the message according to Bitcoin signed message format and hash it
-	formattedMsg := formatBitcoinMessageForVerification(message)
+	events.log(LogLevelDebug, "Signature header",
+		"header_byte", header.HeaderByte, "compressed", header.Compressed, "rec_id", header.RecoveryID)
 
-	// Double SHA-256 hash the formatted message
-	messageHash := sha256.Sum256(formattedMsg)
-	messageHash = sha256.Sum256(messageHash[:])
+	// Format the message according to Bitcoin signed message format and
+	// double SHA-256 hash it
+	messageHash := magicHash(message)
 
 	// Extract the R and S components (bytes 1-33 and 33-65)
 	rBytes := sigBytes[1:33]
 	sBytes := sigBytes[33:65]
 
-	LogDebug("Signature R component: %x", rBytes)
-	LogDebug("Signature S component: %x", sBytes)
+	events.log(LogLevelDebug, "Signature components", "r", hex.EncodeToString(rBytes), "s", hex.EncodeToString(sBytes))
 
 	// Create a DER signature from R and S components
 	// Standard DER format:
@@ -149,49 +157,49 @@ func verifySignatureDirectly(pubKey *btcec.PublicKey, message, signatureBase64 s
 	der[5+rLen] = byte(sLen)   // Length of S
 	copy(der[6+rLen:], sBytes) // S value
 
-	LogDebug("Created DER signature: %x", der)
+	events.log(LogLevelDebug, "Created DER signature", "der", hex.EncodeToString(der))
 
 	// Parse the DER signature
 	signature, err := ecdsa.ParseDERSignature(der)
 	if err != nil {
-		LogError("Error parsing DER signature: %v", err)
-		return false, fmt.Errorf("error parsing signature: %w", err)
+		events.log(LogLevelError, "Error parsing DER signature", "error", err)
+		return false, newVerifyError(ErrInvalidSignature, "error parsing signature: %v", err)
 	}
 
 	// Verify the signature against the message hash and public key
 	valid := signature.Verify(messageHash[:], pubKey)
 
-	LogDebug("Direct verification result: %v", valid)
+	events.log(LogLevelDebug, "Direct verification result", "valid", valid)
 	return valid, nil
 }
 
 // verifyWithDerivedAddress derives a Bitcoin address from the public key and uses
 // address-based verification as a fallback.
-func verifyWithDerivedAddress(pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
-	LogDebug("Deriving Bitcoin address from public key for verification")
+func verifyWithDerivedAddress(events eventLogger, pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
+	events.log(LogLevelDebug, "Deriving Bitcoin address from public key for verification")
 
 	// Determine if the signature uses a compressed or uncompressed key
 	// from the signature header byte
 	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
 	if err != nil {
-		return false, fmt.Errorf("invalid base64 signature: %w", err)
+		return false, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
 	}
 
 	if len(sigBytes) < 1 {
-		return false, fmt.Errorf("signature too short")
+		return false, newVerifyError(ErrMalformedSignature, "signature too short")
 	}
 
 	// Derive the address from the public key
 	derivedAddress, err := deriveAddressFromPubKey(pubKey, &chaincfg.MainNetParams)
 	if err != nil {
-		return false, fmt.Errorf("failed to derive address from public key: %w", err)
+		return false, err
 	}
 
-	LogInfo("Derived address from public key: %s", derivedAddress)
+	events.log(LogLevelInfo, "Derived address from public key", "address", derivedAddress)
 
 	// Use the address-based verification method
-	LogDebug("Falling back to address-based verification")
-	return VerifyBip137Signature(derivedAddress, message, signatureBase64)
+	events.log(LogLevelDebug, "Falling back to address-based verification", "address", derivedAddress)
+	return verifyWithParams(events, derivedAddress, message, signatureBase64, &chaincfg.MainNetParams)
 }
 
 // deriveAddressFromPubKey derives a Bitcoin address from a public key
@@ -200,7 +208,7 @@ func deriveAddressFromPubKey(pubKey *btcec.PublicKey, params *chaincfg.Params) (
 	pubKeyHash := btcutil.Hash160(pubKey.SerializeCompressed())
 	addr, err := btcutil.NewAddressPubKeyHash(pubKeyHash, params)
 	if err != nil {
-		return "", fmt.Errorf("error creating address from public key: %w", err)
+		return "", newVerifyError(ErrInvalidAddress, "error creating address from public key: %v", err)
 	}
 
 	// Return the address string
@@ -213,32 +221,6 @@ func DeriveAddressFromPubKey(pubKey *btcec.PublicKey) (string, error) {
 	return deriveAddressFromPubKey(pubKey, &chaincfg.MainNetParams)
 }
 
-// formatBitcoinMessageForVerification formats a message according to the Bitcoin
-// signed message format: "Bitcoin Signed Message:\n" + message
-func formatBitcoinMessageForVerification(message string) []byte {
-	prefix := "Bitcoin Signed Message:\n"
-
-	// Bitcoin's message format uses a compact size encoding for the lengths
-	// Prefix: "Bitcoin Signed Message:\n"
-	// Message: the actual message being signed
-
-	// First, we create a temporary buffer to hold our formatted message
-	var result []byte
-
-	// Add the prefix with varint length
-	prefixBytes := []byte(prefix)
-	result = appendCompactSize(result, uint64(len(prefixBytes)))
-	result = append(result, prefixBytes...)
-
-	// Add the message with varint length
-	messageBytes := []byte(message)
-	result = appendCompactSize(result, uint64(len(messageBytes)))
-	result = append(result, messageBytes...)
-
-	LogTrace("Formatted Bitcoin message (hex): %x", result)
-	return result
-}
-
 // appendCompactSize appends a compact size uint to a byte slice in Bitcoin's format
 func appendCompactSize(b []byte, n uint64) []byte {
 	if n < 253 {
@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"encoding/base64"
+	"net/url"
+	"strings"
+)
+
+// qrScheme is the URI scheme of QR payloads
+const qrScheme = "bitcoinsig"
+
+// EncodeQRPayload encodes a signed message as a compact URI suited to QR
+// codes:
+//
+//	bitcoinsig:1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5?m=test+message&s=IFqUo4...
+//
+// The signature is re-encoded as unpadded URL-safe base64, so it needs no
+// percent-encoding, and the network is added as n when set.
+func EncodeQRPayload(msg SignedMessage) (string, error) {
+	switch {
+	case msg.Address == "":
+		return "", ErrEmptyAddress
+	case msg.Message == "":
+		return "", ErrEmptyMessage
+	case msg.Signature == "":
+		return "", ErrEmptySignature
+	}
+	sigBytes, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return "", newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+
+	query := url.Values{}
+	query.Set("m", msg.Message)
+	query.Set("s", base64.RawURLEncoding.EncodeToString(sigBytes))
+	if msg.Network != "" {
+		query.Set("n", msg.Network)
+	}
+	return qrScheme + ":" + url.PathEscape(msg.Address) + "?" + query.Encode(), nil
+}
+
+// ParseQRPayload parses a payload produced by EncodeQRPayload. The scheme is
+// matched case-insensitively, as scanners may return it in upper case. Like
+// BIP-21 URIs, unknown parameters are ignored unless prefixed with "req-".
+// The signature is returned in standard base64 and the address is checked
+// against the network, but the signature isn't verified.
+func ParseQRPayload(payload string) (SignedMessage, error) {
+	scheme, rest, ok := strings.Cut(strings.TrimSpace(payload), ":")
+	if !ok || !strings.EqualFold(scheme, qrScheme) {
+		return SignedMessage{}, newVerifyError(ErrMalformedQRPayload, "payload is not a %s: URI", qrScheme)
+	}
+	rawAddress, rawQuery, _ := strings.Cut(rest, "?")
+
+	address, err := url.PathUnescape(rawAddress)
+	if err != nil {
+		return SignedMessage{}, newVerifyError(ErrMalformedQRPayload, "invalid address: %v", err)
+	}
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return SignedMessage{}, newVerifyError(ErrMalformedQRPayload, "invalid query: %v", err)
+	}
+	for key, values := range query {
+		if strings.HasPrefix(key, "req-") {
+			return SignedMessage{}, newVerifyError(ErrMalformedQRPayload, "unsupported required parameter %q", key)
+		}
+		if len(values) > 1 {
+			return SignedMessage{}, newVerifyError(ErrMalformedQRPayload, "duplicate parameter %q", key)
+		}
+	}
+
+	msg := SignedMessage{Address: address, Message: query.Get("m"), Network: query.Get("n")}
+	switch {
+	case msg.Address == "":
+		return SignedMessage{}, ErrEmptyAddress
+	case msg.Message == "":
+		return SignedMessage{}, ErrEmptyMessage
+	case query.Get("s") == "":
+		return SignedMessage{}, ErrEmptySignature
+	}
+	if err := checkMessageSize(eventLogger{}, msg.Message, MaxMessageSize()); err != nil {
+		return SignedMessage{}, err
+	}
+
+	sigBytes, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(query.Get("s"), "="))
+	if err != nil {
+		return SignedMessage{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	msg.Signature = base64.StdEncoding.EncodeToString(sigBytes)
+
+	if msg.Network != "" && networkByName(msg.Network) == nil {
+		return SignedMessage{}, newVerifyError(ErrMalformedQRPayload, "unknown network %q", msg.Network)
+	}
+	if _, err := decodeStatedAddress(msg.Address, msg.Network); err != nil {
+		return SignedMessage{}, err
+	}
+
+	return msg, nil
+}
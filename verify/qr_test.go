@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQRPayloadRoundTrip(t *testing.T) {
+	for _, tv := range walletTestVectors {
+		t.Run(tv.name, func(t *testing.T) {
+			payload, err := EncodeQRPayload(tv.msg)
+			if err != nil {
+				t.Fatalf("EncodeQRPayload() error = %v", err)
+			}
+			got, err := ParseQRPayload(payload)
+			if err != nil {
+				t.Fatalf("ParseQRPayload(%q) error = %v", payload, err)
+			}
+			if got != tv.msg {
+				t.Errorf("ParseQRPayload() = %+v, want %+v", got, tv.msg)
+			}
+		})
+	}
+
+	msg := SignedMessage{
+		Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+		Message:   "test message",
+		Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+		Network:   "mainnet",
+	}
+	want := "bitcoinsig:1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5?m=test+message&n=mainnet&s=IFqUo4_sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA"
+	if got, err := EncodeQRPayload(msg); err != nil || got != want {
+		t.Errorf("EncodeQRPayload() = %q, %v, want %q", got, err, want)
+	}
+}
+
+func TestParseQRPayload(t *testing.T) {
+	const (
+		address   = "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5"
+		message   = "m=test+message"
+		signature = "s=IFqUo4_sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA"
+	)
+
+	tests := []struct {
+		name    string
+		payload string
+		wantErr error
+	}{
+		{name: "Upper case scheme", payload: "BITCOINSIG:" + address + "?" + message + "&" + signature},
+		{name: "Padded signature", payload: "bitcoinsig:" + address + "?" + message + "&" + signature + "="},
+		{name: "Unknown parameter", payload: "bitcoinsig:" + address + "?" + message + "&" + signature + "&label=shop"},
+		{name: "Wrong scheme", payload: "bitcoin:" + address + "?" + message + "&" + signature, wantErr: ErrMalformedQRPayload},
+		{name: "Required parameter", payload: "bitcoinsig:" + address + "?" + message + "&" + signature + "&req-expiry=1", wantErr: ErrMalformedQRPayload},
+		{name: "Duplicate parameter", payload: "bitcoinsig:" + address + "?" + message + "&" + message + "&" + signature, wantErr: ErrMalformedQRPayload},
+		{name: "Unknown network", payload: "bitcoinsig:" + address + "?" + message + "&" + signature + "&n=litecoin", wantErr: ErrMalformedQRPayload},
+		{name: "Wrong network", payload: "bitcoinsig:" + address + "?" + message + "&" + signature + "&n=testnet", wantErr: ErrNetworkMismatch},
+		{name: "Missing message", payload: "bitcoinsig:" + address + "?" + signature, wantErr: ErrEmptyMessage},
+		{name: "Missing address", payload: "bitcoinsig:?" + message + "&" + signature, wantErr: ErrEmptyAddress},
+		{name: "Malformed signature", payload: "bitcoinsig:" + address + "?" + message + "&s=not+base64", wantErr: ErrMalformedSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQRPayload(tt.payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ParseQRPayload() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
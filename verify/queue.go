@@ -0,0 +1,231 @@
+package verify
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// defaultQueueCapacity is the number of messages a queue holds by default
+const defaultQueueCapacity = 1024
+
+// QueueResult is the outcome of verifying a message submitted to a Queue
+type QueueResult struct {
+	// ID is the identifier Submit returned for the message
+	ID uint64
+
+	// Message is the message that was verified
+	Message SignedMessage
+
+	// Result is the result of the verification, as returned by the
+	// verifier of the queue
+	Result *Result
+
+	// Err is the error that occurred while verifying the message, if any
+	Err error
+}
+
+// Queue verifies submitted messages in the background, for services that
+// accept proofs in request handlers and consume the results asynchronously.
+// Workers take messages in submission order and deliver one QueueResult per
+// message on Results; results of concurrent workers may arrive out of order,
+// so they carry the ID Submit returned.
+//
+// The queue holds a bounded number of messages. When it's full, Submit blocks
+// until a worker takes a message and TrySubmit fails with ErrQueueFull, so a
+// backlog pushes back on producers instead of growing without bound. Workers
+// wait for Results to be read, so a stalled consumer fills the queue too.
+type Queue struct {
+	verifier MessageVerifier
+	workers  int
+	capacity int
+
+	jobs    chan queueJob
+	results chan QueueResult
+	nextID  atomic.Uint64
+
+	// mu guards stopped; submissions hold it for reading while they wait
+	// for room, so jobs is only closed once no submission can send on it
+	mu       sync.RWMutex
+	stopped  bool
+	stopping chan struct{}
+	stopOnce sync.Once
+
+	// ctx is the context of the verifications, cancelled when Stop gives up
+	// waiting for the queue to drain
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// queueJob is a message waiting in a queue
+type queueJob struct {
+	id  uint64
+	msg SignedMessage
+}
+
+// QueueOption configures a Queue
+type QueueOption func(*Queue)
+
+// WithQueueVerifier sets the verifier of the queued messages. A Verifier
+// with default settings is used by default; WithDefaultTimeout on it bounds
+// the time spent on each message.
+func WithQueueVerifier(v MessageVerifier) QueueOption {
+	return func(q *Queue) {
+		q.verifier = v
+	}
+}
+
+// WithQueueCapacity sets the number of messages the queue holds before
+// submissions block. It defaults to 1024.
+func WithQueueCapacity(n int) QueueOption {
+	return func(q *Queue) {
+		if n > 0 {
+			q.capacity = n
+		}
+	}
+}
+
+// WithQueueWorkers sets the number of messages verified concurrently. It
+// defaults to GOMAXPROCS.
+func WithQueueWorkers(n int) QueueOption {
+	return func(q *Queue) {
+		if n > 0 {
+			q.workers = n
+		}
+	}
+}
+
+// NewQueue creates a queue and starts its workers. Callers must read Results
+// until it's closed and call Stop once they're done submitting.
+func NewQueue(opts ...QueueOption) *Queue {
+	q := &Queue{
+		workers:  runtime.GOMAXPROCS(0),
+		capacity: defaultQueueCapacity,
+		stopping: make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	if q.verifier == nil {
+		q.verifier = NewVerifier()
+	}
+
+	q.jobs = make(chan queueJob, q.capacity)
+	q.results = make(chan QueueResult, q.capacity)
+	q.ctx, q.cancel = context.WithCancel(context.Background())
+
+	var wg sync.WaitGroup
+	for range q.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			q.work()
+		}()
+	}
+	go func() {
+		wg.Wait()
+		q.cancel()
+		close(q.results)
+		close(q.done)
+	}()
+
+	return q
+}
+
+// Submit queues the message for verification and returns the ID of its
+// result. When the queue is full it waits for room until ctx is done,
+// failing with ErrVerificationTimeout. The context only bounds the wait: the
+// message is verified after the request that submitted it has ended. Once
+// the queue is stopping, Submit fails with ErrQueueStopped.
+func (q *Queue) Submit(ctx context.Context, msg SignedMessage) (uint64, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.stopped {
+		return 0, newVerifyError(ErrQueueStopped, "queue is stopped")
+	}
+
+	job := queueJob{id: q.nextID.Add(1), msg: msg}
+	select {
+	case q.jobs <- job:
+		return job.id, nil
+	case <-q.stopping:
+		return 0, newVerifyError(ErrQueueStopped, "queue is stopping")
+	case <-ctx.Done():
+		return 0, checkContext(ctx)
+	}
+}
+
+// TrySubmit queues the message for verification without waiting, failing
+// with ErrQueueFull when the queue has no room, so request handlers can shed
+// load instead of blocking. Once the queue is stopping, TrySubmit fails with
+// ErrQueueStopped.
+func (q *Queue) TrySubmit(msg SignedMessage) (uint64, error) {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.stopped {
+		return 0, newVerifyError(ErrQueueStopped, "queue is stopped")
+	}
+
+	job := queueJob{id: q.nextID.Add(1), msg: msg}
+	select {
+	case q.jobs <- job:
+		return job.id, nil
+	default:
+		return 0, newVerifyError(ErrQueueFull, "queue holds %d messages", q.capacity)
+	}
+}
+
+// Results returns the channel the results of the submitted messages are
+// delivered on. It's closed once the queue has stopped.
+func (q *Queue) Results() <-chan QueueResult {
+	return q.results
+}
+
+// Len returns the number of messages waiting to be verified
+func (q *Queue) Len() int {
+	return len(q.jobs)
+}
+
+// Stop stops accepting messages and waits for the workers to verify the
+// queued messages and deliver their results, then closes Results. If ctx is
+// done first, the verifications still running are cancelled, the remaining
+// results are dropped and Stop returns an error wrapping
+// ErrVerificationTimeout. Stop may be called more than once.
+func (q *Queue) Stop(ctx context.Context) error {
+	q.stopOnce.Do(func() {
+		// Wake the submissions waiting for room before taking the lock
+		// they hold
+		close(q.stopping)
+		q.mu.Lock()
+		q.stopped = true
+		q.mu.Unlock()
+		close(q.jobs)
+		logEvent(LogLevelDebug, "Stopping verification queue", "queued", len(q.jobs))
+	})
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		q.cancel()
+		<-q.done
+		err := checkContext(ctx)
+		logEvent(LogLevelWarning, "Verification queue stopped before draining", "error", err)
+		return err
+	}
+}
+
+// work verifies queued messages until the queue is stopped and drained
+func (q *Queue) work() {
+	for job := range q.jobs {
+		result, err := q.verifier.VerifyContext(q.ctx, job.msg)
+		select {
+		case q.results <- QueueResult{ID: job.id, Message: job.msg, Result: result, Err: err}:
+		case <-q.ctx.Done():
+			// Stop gave up, and nobody may be reading the results
+		}
+	}
+}
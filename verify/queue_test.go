@@ -0,0 +1,98 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQueue(t *testing.T) {
+	q := NewQueue(WithQueueWorkers(3), WithQueueCapacity(2))
+
+	want := make(map[uint64]SignedMessage)
+	go func() {
+		for _, tv := range walletTestVectors {
+			id, err := q.Submit(context.Background(), tv.msg)
+			if err != nil {
+				t.Errorf("Submit() error = %v", err)
+				continue
+			}
+			want[id] = tv.msg
+		}
+		if err := q.Stop(context.Background()); err != nil {
+			t.Errorf("Stop() error = %v", err)
+		}
+	}()
+
+	var results []QueueResult
+	for result := range q.Results() {
+		results = append(results, result)
+	}
+
+	if len(results) != len(walletTestVectors) {
+		t.Fatalf("Results() delivered %d results, want %d", len(results), len(walletTestVectors))
+	}
+	for _, result := range results {
+		if result.Message != want[result.ID] {
+			t.Errorf("result %d is for %v, want %v", result.ID, result.Message, want[result.ID])
+		}
+		if result.Err != nil || !result.Result.Valid {
+			t.Errorf("result %d = %+v, %v, want valid", result.ID, result.Result, result.Err)
+		}
+	}
+}
+
+func TestQueueBackpressure(t *testing.T) {
+	msg := walletTestVectors[2].msg
+	q := NewQueue(WithQueueWorkers(1), WithQueueCapacity(1), WithQueueVerifier(blockingVerifier{}))
+
+	// The worker blocks on the first message and the second fills the queue
+	if _, err := q.Submit(context.Background(), msg); err != nil {
+		t.Fatalf("Submit() error = %v", err)
+	}
+	for q.Len() > 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := q.TrySubmit(msg); err != nil {
+		t.Fatalf("TrySubmit() error = %v", err)
+	}
+
+	if _, err := q.TrySubmit(msg); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("TrySubmit() on a full queue error = %v, want %v", err, ErrQueueFull)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Submit(ctx, msg); !errors.Is(err, ErrVerificationTimeout) {
+		t.Errorf("Submit() on a full queue error = %v, want %v", err, ErrVerificationTimeout)
+	}
+
+	// A submission waiting for room is woken by Stop
+	submitted := make(chan error)
+	go func() {
+		_, err := q.Submit(context.Background(), msg)
+		submitted <- err
+	}()
+
+	stopCtx, stopCancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer stopCancel()
+	if err := q.Stop(stopCtx); !errors.Is(err, ErrVerificationTimeout) {
+		t.Errorf("Stop() error = %v, want %v", err, ErrVerificationTimeout)
+	}
+	if err := <-submitted; !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("Submit() during Stop() error = %v, want %v", err, ErrQueueStopped)
+	}
+	for range q.Results() {
+	}
+	if _, err := q.TrySubmit(msg); !errors.Is(err, ErrQueueStopped) {
+		t.Errorf("TrySubmit() after Stop() error = %v, want %v", err, ErrQueueStopped)
+	}
+}
+
+// Helper verifier blocking until the context of the verification is done
+type blockingVerifier struct{}
+
+func (blockingVerifier) VerifyContext(ctx context.Context, msg SignedMessage) (*Result, error) {
+	<-ctx.Done()
+	return &Result{}, checkContext(ctx)
+}
@@ -0,0 +1,96 @@
+package verify
+
+import (
+	"context"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// AddressSignature is the signature of one signer of a message verified by
+// VerifyQuorum
+type AddressSignature struct {
+	Address   string `json:"address"`
+	Signature string `json:"signature"`
+}
+
+// QuorumReport summarizes the signatures of a message by many signers
+type QuorumReport struct {
+	*BatchReport
+
+	// Failures are the results of the signatures that failed verification,
+	// in input order, each with the error saying why
+	Failures []BatchResult
+
+	// Signers are the distinct signers with a valid signature, by the
+	// address of their first signature, in input order
+	Signers []string
+
+	// Quorum is the number of distinct signers required
+	Quorum int
+
+	// QuorumReached reports whether at least Quorum distinct addresses
+	// signed. A sampled batch never reaches the quorum, as the signatures
+	// that weren't verified can't be counted.
+	QuorumReached bool
+}
+
+// VerifyQuorum verifies the signatures of one message by many addresses with
+// VerifyBatch, which the options configure, and reports whether at least
+// quorum distinct addresses signed it. Unlike VerifyMultisig the signers
+// aren't known in advance: any address with a valid signature counts, so
+// callers checking membership should compare report.Signers with their
+// list. Signers are told apart by the key recovered from their signature,
+// or by their address in canonical encoding when it isn't a compact
+// signature, so neither another address of the same key nor another spelling
+// of the same address, such as an uppercase bech32 address, counts twice. A
+// quorum below 1 fails with ErrInvalidMultisigPolicy. Like
+// VerifyBatch, the report is returned alongside the context error when the
+// context is done.
+func VerifyQuorum(ctx context.Context, message string, signatures []AddressSignature, quorum int, opts ...BatchOption) (*QuorumReport, error) {
+	if quorum < 1 {
+		return nil, newVerifyError(ErrInvalidMultisigPolicy, "quorum %d", quorum)
+	}
+
+	msgs := make([]SignedMessage, len(signatures))
+	for i, s := range signatures {
+		msgs[i] = SignedMessage{Address: s.Address, Message: message, Signature: s.Signature}
+	}
+	batch, err := VerifyBatch(ctx, msgs, opts...)
+
+	cfg := batchConfig{params: &chaincfg.MainNetParams}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	report := &QuorumReport{BatchReport: batch, Quorum: quorum}
+	seen := make(map[string]bool)
+	for _, result := range batch.Results {
+		if !result.Valid {
+			report.Failures = append(report.Failures, result)
+			continue
+		}
+		signer := quorumSigner(result.Message, cfg.params)
+		if !seen[signer] {
+			seen[signer] = true
+			report.Signers = append(report.Signers, result.Message.Address)
+		}
+	}
+	report.QuorumReached = batch.Sample == nil && len(report.Signers) >= quorum
+
+	logEvent(LogLevelInfo, "Verified quorum", "signers", len(report.Signers), "quorum", quorum, "failures", len(report.Failures), "quorum_reached", report.QuorumReached)
+	return report, err
+}
+
+// quorumSigner identifies the signer of a valid signature by its key, or by
+// its address in canonical encoding when no key can be recovered
+func quorumSigner(msg SignedMessage, params *chaincfg.Params) string {
+	if key, err := signingKey(msg.Message, msg.Signature, params); err == nil {
+		return "key:" + key
+	}
+	addr, err := btcutil.DecodeAddress(msg.Address, params)
+	if err != nil {
+		return "address:" + msg.Address
+	}
+	return "address:" + addr.EncodeAddress()
+}
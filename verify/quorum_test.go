@@ -0,0 +1,110 @@
+package verify
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestVerifyQuorum(t *testing.T) {
+	const message = "release v2.0.0 sha256:5f0a"
+	alice := signTestMessage(t, "alice", nil, message)
+	bob := signTestMessage(t, "bob", nil, message)
+	stale := signTestMessage(t, "carol", nil, "release v1.9.0")
+
+	// Alice's signature for the P2WPKH address of her key
+	sigBytes, _ := base64.StdEncoding.DecodeString(alice.Signature)
+	sigBytes[0] += headerP2WPKH - headerP2PKHCompressed
+	segwitSignature := base64.StdEncoding.EncodeToString(sigBytes)
+	rec, err := Recover(message, segwitSignature, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	segwit := rec.Address
+
+	tests := []struct {
+		name         string
+		signatures   []AddressSignature
+		quorum       int
+		wantSigners  []string
+		wantFailures []error
+		wantReached  bool
+		wantErr      error
+	}{
+		{
+			name:        "Quorum reached",
+			signatures:  []AddressSignature{{alice.Address, alice.Signature}, {bob.Address, bob.Signature}},
+			quorum:      2,
+			wantSigners: []string{alice.Address, bob.Address},
+			wantReached: true,
+		},
+		{
+			name: "Invalid signatures reported",
+			signatures: []AddressSignature{
+				{alice.Address, alice.Signature},
+				{stale.Address, stale.Signature},
+				{bob.Address, "not base64"},
+			},
+			quorum:       2,
+			wantSigners:  []string{alice.Address},
+			wantFailures: []error{ErrAddressMismatch, ErrMalformedSignature},
+		},
+		{
+			name:        "Repeated signer counts once",
+			signatures:  []AddressSignature{{alice.Address, alice.Signature}, {alice.Address, alice.Signature}},
+			quorum:      2,
+			wantSigners: []string{alice.Address},
+		},
+		{
+			name:        "Address spellings count once",
+			signatures:  []AddressSignature{{segwit, segwitSignature}, {strings.ToUpper(segwit), segwitSignature}},
+			quorum:      2,
+			wantSigners: []string{segwit},
+		},
+		{
+			name:        "Addresses of one key count once",
+			signatures:  []AddressSignature{{alice.Address, alice.Signature}, {segwit, segwitSignature}},
+			quorum:      2,
+			wantSigners: []string{alice.Address},
+		},
+		{
+			name:    "Zero quorum",
+			quorum:  0,
+			wantErr: ErrInvalidMultisigPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := VerifyQuorum(context.Background(), message, tt.signatures, tt.quorum)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("VerifyQuorum() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyQuorum() error = %v", err)
+			}
+			if !reflect.DeepEqual(report.Signers, tt.wantSigners) {
+				t.Errorf("VerifyQuorum() signers = %v, want %v", report.Signers, tt.wantSigners)
+			}
+			if len(report.Failures) != len(tt.wantFailures) {
+				t.Fatalf("VerifyQuorum() failures = %+v, want %d", report.Failures, len(tt.wantFailures))
+			}
+			for i, failure := range report.Failures {
+				if !errors.Is(failure.Err, tt.wantFailures[i]) {
+					t.Errorf("VerifyQuorum() failure %d error = %v, want %v", i, failure.Err, tt.wantFailures[i])
+				}
+			}
+			if report.QuorumReached != tt.wantReached {
+				t.Errorf("VerifyQuorum() quorum reached = %v, want %v", report.QuorumReached, tt.wantReached)
+			}
+		})
+	}
+}
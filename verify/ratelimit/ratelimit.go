@@ -0,0 +1,115 @@
+// Package ratelimit limits how often clients can verify signatures, which
+// is CPU-bound and trivially abusable. The servers of the httpserver and
+// verify/grpc packages take one Limiter keyed by client IP and one keyed by
+// the address to verify:
+//
+//	srv := httpserver.New(
+//		httpserver.WithIPRateLimit(ratelimit.PerMinute(600)),
+//		httpserver.WithAddressRateLimit(ratelimit.PerMinute(30)),
+//	)
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sero/btc/verify"
+	"golang.org/x/time/rate"
+)
+
+// CodeRateLimited is the error code of requests rejected by a Limiter
+const CodeRateLimited verify.ErrorCode = "rate_limited"
+
+// pruneInterval is the minimum time between two prunes of idle keys
+const pruneInterval = time.Minute
+
+// Limiter is a set of token buckets, one per key. It is safe for concurrent
+// use. A nil Limiter allows everything.
+type Limiter struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastPrune time.Time
+}
+
+// bucket is the token bucket of a key
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New creates a Limiter allowing each key r events per second, with bursts
+// of up to burst events
+func New(r rate.Limit, burst int) *Limiter {
+	return &Limiter{
+		limit:   r,
+		burst:   burst,
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// PerMinute creates a Limiter allowing each key n events per minute, all of
+// which may happen at once
+func PerMinute(n int) *Limiter {
+	return New(rate.Limit(float64(n)/60), n)
+}
+
+// Allow reports whether an event for the key may happen now
+func (l *Limiter) Allow(key string) bool {
+	return l.AllowN(key, 1)
+}
+
+// Burst returns the most events a key may have at once, zero for a nil
+// Limiter
+func (l *Limiter) Burst() int {
+	if l == nil {
+		return 0
+	}
+	return l.burst
+}
+
+// AllowN reports whether n events for the key may happen now. More events
+// than the burst are never allowed.
+func (l *Limiter) AllowN(key string, n int) bool {
+	if l == nil {
+		return true
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.prune(now)
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.lastSeen = now
+	return b.limiter.AllowN(now, n)
+}
+
+// prune drops the buckets of keys idle long enough for their bucket to be
+// full again, which behave like new buckets, at most once per
+// pruneInterval. The caller must hold the lock.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+
+	refill := pruneInterval
+	if l.limit > 0 {
+		refill = max(refill, time.Duration(float64(l.burst)/float64(l.limit)*float64(time.Second)))
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > refill {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
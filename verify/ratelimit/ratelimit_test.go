@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := PerMinute(2)
+	l.now = func() time.Time { return now }
+
+	for i, want := range []bool{true, true, false} {
+		if got := l.Allow("a"); got != want {
+			t.Errorf("Allow() call %d = %v, want %v", i, got, want)
+		}
+	}
+	if !l.Allow("b") {
+		t.Errorf("Allow() for another key = false, want true")
+	}
+
+	now = now.Add(30 * time.Second)
+	if !l.Allow("a") {
+		t.Errorf("Allow() after refill = false, want true")
+	}
+
+	if l.AllowN("c", 3) {
+		t.Errorf("AllowN() over the burst = true, want false")
+	}
+
+	var unlimited *Limiter
+	if !unlimited.AllowN("a", 1000) {
+		t.Errorf("AllowN() on a nil Limiter = false, want true")
+	}
+	if l.Burst() != 2 || unlimited.Burst() != 0 {
+		t.Errorf("Burst() = %d, %d on a nil Limiter, want 2 and 0", l.Burst(), unlimited.Burst())
+	}
+}
+
+func TestLimiterPrune(t *testing.T) {
+	now := time.Now()
+	l := PerMinute(60)
+	l.now = func() time.Time { return now }
+
+	l.Allow("a")
+	l.Allow("b")
+	now = now.Add(2 * time.Minute)
+	l.Allow("c")
+
+	if len(l.buckets) != 1 {
+		t.Errorf("%d buckets kept, want only the active one", len(l.buckets))
+	}
+}
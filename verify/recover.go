@@ -0,0 +1,116 @@
+package verify
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// Recovery is the public key that made a compact signature and the address
+// its header byte stands for
+type Recovery struct {
+	// PubKey is the hex-encoded public key, in the serialization the header
+	// byte asks for
+	PubKey string
+
+	// Compressed reports whether PubKey is compressed
+	Compressed bool
+
+	// AddressType is the address type the header byte stands for
+	AddressType AddressType
+
+	// Address is the address of that type for PubKey
+	Address string
+}
+
+// Recover recovers the public key and address that signed a message with a
+// 65-byte compact signature, without an address to verify against. Any
+// well-formed signature recovers some key, so the result only identifies the
+// signer when the address is known to be expected.
+func Recover(message, signatureBase64 string, params *chaincfg.Params) (Recovery, error) {
+	return recoverSigner(eventLogger{}, message, signatureBase64, params, MaxMessageSize(), magicHash)
+}
+
+// Recover recovers the public key and address that signed a message like
+// the package-level Recover, with the network, message size limit, logger
+// and hash scheme of the verifier.
+func (v *Verifier) Recover(message, signatureBase64 string) (Recovery, error) {
+	return recoverSigner(v.events, message, signatureBase64, v.params, v.messageSizeLimit(), v.messageHash)
+}
+
+// recoverSigner implements Recover, rejecting messages longer than limit and
+// hashing them with hash
+func recoverSigner(events eventLogger, message, signatureBase64 string, params *chaincfg.Params, limit int64, hash func(string) [32]byte) (Recovery, error) {
+	switch {
+	case message == "":
+		return Recovery{}, ErrEmptyMessage
+	case signatureBase64 == "":
+		return Recovery{}, ErrEmptySignature
+	}
+	if err := checkMessageSize(events, message, limit); err != nil {
+		return Recovery{}, err
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return Recovery{}, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
+	}
+	if len(sigBytes) != compactSignatureLength {
+		return Recovery{}, newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d", len(sigBytes), compactSignatureLength)
+	}
+
+	digest := hash(message)
+	pubKey, compressed, err := recoverPubKey(sigBytes, digest[:])
+	if err != nil {
+		events.log(LogLevelDebug, "Failed to recover public key", "error", err)
+		return Recovery{}, err
+	}
+
+	rec := Recovery{
+		Compressed:  compressed,
+		AddressType: headerAddressType(sigBytes[0]),
+	}
+	var serialized []byte
+	if compressed {
+		serialized = pubKey.SerializeCompressed()
+	} else {
+		serialized = pubKey.SerializeUncompressed()
+	}
+	rec.PubKey = hex.EncodeToString(serialized)
+
+	addr, err := pubKeyHashAddress(rec.AddressType, btcutil.Hash160(serialized), params)
+	if err != nil {
+		return Recovery{}, err
+	}
+	rec.Address = addr.EncodeAddress()
+	events.log(LogLevelDebug, "Recovered signer", "pubkey", rec.PubKey, "address", rec.Address)
+	return rec, nil
+}
+
+// pubKeyHashAddress returns the address of the given type for a public key
+// hash. Only the key hash address types of BIP-0137 are supported.
+func pubKeyHashAddress(addrType AddressType, pubKeyHash []byte, params *chaincfg.Params) (btcutil.Address, error) {
+	var addr btcutil.Address
+	var err error
+	switch addrType {
+	case AddressTypeP2PKH:
+		addr, err = btcutil.NewAddressPubKeyHash(pubKeyHash, params)
+	case AddressTypeP2SHP2WPKH:
+		var witnessProgram []byte
+		witnessProgram, err = txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(pubKeyHash).Script()
+		if err == nil {
+			addr, err = btcutil.NewAddressScriptHash(witnessProgram, params)
+		}
+	case AddressTypeP2WPKH:
+		addr, err = btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+	default:
+		return nil, newVerifyError(ErrUnsupportedAddressType, "no key hash address for %q", addrType)
+	}
+	if err != nil {
+		return nil, newVerifyError(ErrInvalidAddress, "failed to derive address from public key: %v", err)
+	}
+	return addr, nil
+}
@@ -0,0 +1,84 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestRecover(t *testing.T) {
+	tests := []struct {
+		name            string
+		msg             SignedMessage
+		wantAddressType AddressType
+		wantCompressed  bool
+		wantErr         error
+	}{
+		{
+			name:            "Uncompressed P2PKH",
+			msg:             walletTestVectors[1].msg,
+			wantAddressType: AddressTypeP2PKH,
+		},
+		{
+			name:            "Compressed P2PKH",
+			msg:             walletTestVectors[2].msg,
+			wantAddressType: AddressTypeP2PKH,
+			wantCompressed:  true,
+		},
+		{
+			name:            "P2SH-P2WPKH",
+			msg:             walletTestVectors[4].msg,
+			wantAddressType: AddressTypeP2SHP2WPKH,
+			wantCompressed:  true,
+		},
+		{
+			name:            "P2WPKH",
+			msg:             walletTestVectors[5].msg,
+			wantAddressType: AddressTypeP2WPKH,
+			wantCompressed:  true,
+		},
+		{
+			name:    "Empty message",
+			msg:     SignedMessage{Signature: walletTestVectors[2].msg.Signature},
+			wantErr: ErrEmptyMessage,
+		},
+		{
+			name:    "Not a compact signature",
+			msg:     SignedMessage{Message: "test message", Signature: "AAAA"},
+			wantErr: ErrMalformedSignature,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Recover(tt.msg.Message, tt.msg.Signature, &chaincfg.MainNetParams)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Recover() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if got.Address != tt.msg.Address || got.AddressType != tt.wantAddressType || got.Compressed != tt.wantCompressed {
+				t.Errorf("Recover() = %+v, want address %s of type %s, compressed %v", got, tt.msg.Address, tt.wantAddressType, tt.wantCompressed)
+			}
+		})
+	}
+}
+
+func TestVerifierRecover(t *testing.T) {
+	msg := walletTestVectors[2].msg
+
+	rec, err := NewVerifier(WithParams(&chaincfg.TestNet3Params)).Recover(msg.Message, msg.Signature)
+	if err != nil {
+		t.Fatalf("Recover() error = %v", err)
+	}
+	want, _ := Recover(msg.Message, msg.Signature, &chaincfg.TestNet3Params)
+	if rec != want {
+		t.Errorf("Recover() = %+v, want %+v", rec, want)
+	}
+
+	if _, err := NewVerifier(WithMaxMessageSize(4)).Recover(msg.Message, msg.Signature); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Recover() with a 4-byte limit error = %v, want %v", err, ErrMessageTooLarge)
+	}
+}
@@ -0,0 +1,101 @@
+package verify
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// sampleConfidenceLevel is the confidence level of the reported interval
+const sampleConfidenceLevel = 0.95
+
+// sampleZScore is the standard normal quantile for sampleConfidenceLevel
+const sampleZScore = 1.959963984540054
+
+// SampleStats describes a random sample of a batch and the statistical
+// confidence of the valid rate estimated from it
+type SampleStats struct {
+	// Seed is the seed the sample was drawn with
+	Seed int64
+
+	// Fraction is the requested fraction of the batch
+	Fraction float64
+
+	// Size is the number of sampled messages
+	Size int
+
+	// Population is the number of messages in the whole batch
+	Population int
+
+	// ValidRate is the fraction of sampled messages with a valid signature
+	ValidRate float64
+
+	// ValidRateLow and ValidRateHigh bound the valid rate of the whole batch
+	// at the confidence level
+	ValidRateLow  float64
+	ValidRateHigh float64
+
+	// ConfidenceLevel is the confidence level of the interval, e.g. 0.95
+	ConfidenceLevel float64
+}
+
+// selectBatchIndexes returns the sorted indexes of the messages to verify:
+// all of them, or a random sample drawn deterministically from the seed
+func selectBatchIndexes(total int, cfg batchConfig) []int {
+	size := total
+	if cfg.sampleFraction > 0 && cfg.sampleFraction < 1 {
+		size = int(math.Ceil(cfg.sampleFraction * float64(total)))
+	}
+
+	if size == total {
+		indexes := make([]int, total)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes
+	}
+
+	rng := rand.New(rand.NewSource(cfg.sampleSeed))
+	indexes := rng.Perm(total)[:size]
+	sort.Ints(indexes)
+	return indexes
+}
+
+// newSampleStats estimates the valid rate of the population from the sample
+// using the Wilson score interval, narrowed by the finite population
+// correction since samples are drawn without replacement.
+func newSampleStats(cfg batchConfig, population, valid, size int) *SampleStats {
+	stats := &SampleStats{
+		Seed:            cfg.sampleSeed,
+		Fraction:        cfg.sampleFraction,
+		Size:            size,
+		Population:      population,
+		ConfidenceLevel: sampleConfidenceLevel,
+	}
+	if size == 0 {
+		stats.ValidRateHigh = 1
+		return stats
+	}
+
+	n := float64(size)
+	p := float64(valid) / n
+	z2 := sampleZScore * sampleZScore
+
+	denominator := 1 + z2/n
+	center := (p + z2/(2*n)) / denominator
+	halfWidth := sampleZScore * math.Sqrt(p*(1-p)/n+z2/(4*n*n)) / denominator
+	low := math.Max(0, center-halfWidth)
+	high := math.Min(1, center+halfWidth)
+
+	// Shrink the interval towards the observed rate; sampling the whole
+	// population leaves no uncertainty
+	fpc := 0.0
+	if population > 1 {
+		fpc = math.Sqrt(float64(population-size) / float64(population-1))
+	}
+
+	stats.ValidRate = p
+	stats.ValidRateLow = p - (p-low)*fpc
+	stats.ValidRateHigh = p + (high-p)*fpc
+	return stats
+}
@@ -0,0 +1,101 @@
+package verify
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// selfCheckVectors holds the known-answer vectors run by SelfCheck
+//
+//go:embed selfcheck.json
+var selfCheckVectors []byte
+
+// selfCheckVector is a known-answer verification vector
+type selfCheckVector struct {
+	Name      string    `json:"name"`
+	Network   string    `json:"network"`
+	Address   string    `json:"address"`
+	Message   string    `json:"message"`
+	Signature string    `json:"signature"`
+	Valid     bool      `json:"valid"`
+	Code      ErrorCode `json:"code,omitempty"`
+}
+
+// SelfCheck runs the embedded known-answer vectors through both verification
+// engines and returns an error wrapping ErrSelfCheckFailed if any of them
+// gives an unexpected answer. Binaries should run it at startup and refuse to
+// verify real proofs when it fails, as that points at a miscompiled binary or
+// a broken platform.
+func SelfCheck() error {
+	var vectors []selfCheckVector
+	if err := json.Unmarshal(selfCheckVectors, &vectors); err != nil {
+		return newVerifyError(ErrSelfCheckFailed, "invalid embedded vectors: %v", err)
+	}
+	return runSelfCheck(vectors)
+}
+
+// runSelfCheck checks every vector against the BitonicNL verifier and the
+// native engine
+func runSelfCheck(vectors []selfCheckVector) error {
+	logEvent(LogLevelInfo, "Running self-check", "vectors", len(vectors))
+
+	var failures []string
+	for _, vec := range vectors {
+		params := networkParams(vec.Network)
+		if params == nil {
+			failures = append(failures, fmt.Sprintf("%s: unknown network %q", vec.Name, vec.Network))
+			continue
+		}
+
+		valid, err := VerifyBip137SignatureWithParams(vec.Address, vec.Message, vec.Signature, params)
+		if msg := checkSelfCheckAnswer(vec, valid, err); msg != "" {
+			failures = append(failures, fmt.Sprintf("%s (bitonicnl): %s", vec.Name, msg))
+		}
+
+		result, err := NewVerifier(WithParams(params)).Verify(SignedMessage{
+			Address:   vec.Address,
+			Message:   vec.Message,
+			Signature: vec.Signature,
+		})
+		if msg := checkSelfCheckAnswer(vec, result.Valid, err); msg != "" {
+			failures = append(failures, fmt.Sprintf("%s (native): %s", vec.Name, msg))
+		}
+	}
+
+	if len(failures) > 0 {
+		logEvent(LogLevelError, "Self-check failed", "failures", len(failures), "vectors", len(vectors), "error", strings.Join(failures, "; "))
+		return newVerifyError(ErrSelfCheckFailed, "%d of %d vectors: %s", len(failures), len(vectors), strings.Join(failures, "; "))
+	}
+
+	logEvent(LogLevelInfo, "Self-check passed", "vectors", len(vectors))
+	return nil
+}
+
+// checkSelfCheckAnswer compares a verification outcome with the expected
+// answer of the vector, describing the difference
+func checkSelfCheckAnswer(vec selfCheckVector, valid bool, err error) string {
+	if valid != vec.Valid {
+		return fmt.Sprintf("valid = %t, want %t (error: %v)", valid, vec.Valid, err)
+	}
+	if vec.Valid && err != nil {
+		return fmt.Sprintf("unexpected error: %v", err)
+	}
+	if !vec.Valid && vec.Code != "" && ErrorCodeOf(err) != vec.Code {
+		return fmt.Sprintf("error code = %s, want %s (error: %v)", ErrorCodeOf(err), vec.Code, err)
+	}
+	return ""
+}
+
+// networkParams returns the parameters of a known network by name
+func networkParams(name string) *chaincfg.Params {
+	for _, params := range knownNetworks {
+		if params.Name == name {
+			return params
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelfCheck(t *testing.T) {
+	if err := SelfCheck(); err != nil {
+		t.Fatalf("SelfCheck() error = %v", err)
+	}
+}
+
+func TestRunSelfCheckDetectsWrongAnswers(t *testing.T) {
+	tv := walletTestVectors[2].msg
+
+	tests := []struct {
+		name   string
+		vector selfCheckVector
+	}{
+		{
+			name:   "Valid signature expected to fail",
+			vector: selfCheckVector{Name: "flipped", Network: "mainnet", Address: tv.Address, Message: tv.Message, Signature: tv.Signature, Valid: false},
+		},
+		{
+			name:   "Wrong error code",
+			vector: selfCheckVector{Name: "code", Network: "mainnet", Address: tv.Address, Message: "tampered", Signature: tv.Signature, Code: CodeHighS},
+		},
+		{
+			name:   "Unknown network",
+			vector: selfCheckVector{Name: "network", Network: "moonnet", Address: tv.Address, Message: tv.Message, Signature: tv.Signature, Valid: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := runSelfCheck([]selfCheckVector{tt.vector})
+			if !errors.Is(err, ErrSelfCheckFailed) {
+				t.Errorf("runSelfCheck() error = %v, want %v", err, ErrSelfCheckFailed)
+			}
+		})
+	}
+}
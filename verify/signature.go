@@ -3,47 +3,45 @@ package verify
 import (
 	"context"
 	"encoding/base64"
-	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	verifier "github.com/bitonicnl/verify-signed-message/pkg"
 	"github.com/btcsuite/btcd/chaincfg"
 )
 
-// Common errors that can occur during signature verification
-var (
-	ErrVerificationTimeout = errors.New("signature verification timed out")
-	ErrInvalidSignature    = errors.New("invalid signature")
-	ErrEmptyAddress        = errors.New("empty bitcoin address")
-	ErrEmptyMessage        = errors.New("empty message")
-	ErrEmptySignature      = errors.New("empty signature")
-)
-
 // SignedMessage represents a message that has been signed with a Bitcoin private key
 type SignedMessage struct {
 	// Address is the Bitcoin address that allegedly signed the message
-	Address string
+	Address string `json:"address"`
 
 	// Message is the content that was signed
-	Message string
+	Message string `json:"message"`
 
 	// Signature is the base64-encoded signature
-	Signature string
+	Signature string `json:"signature"`
+
+	// Network optionally names the network of the address, such as mainnet
+	// or testnet3. It is descriptive: verification uses the network
+	// parameters it is given.
+	Network string `json:"network,omitempty"`
+
+	// Type optionally states the type of the address. It is descriptive,
+	// like Network.
+	Type AddressType `json:"type,omitempty"`
 }
 
 // VerifyBip137Signature verifies if a message was signed by the private key
 // associated with the provided Bitcoin address according to BIP-0137.
 // It uses the Bitcoin mainnet parameters by default.
 func VerifyBip137Signature(address, message, signatureBase64 string) (bool, error) {
-	LogInfo("Starting BIP-0137 signature verification")
-	LogDebug("Address: %s", address)
-	LogDebug("Message: %s", message)
-	LogDebug("Signature (Base64): %s", signatureBase64)
+	logEvent(LogLevelInfo, "Starting BIP-0137 signature verification", "address", address)
+	logEvent(LogLevelDebug, "Verification input", "address", address, "message", message, "signature", signatureBase64)
 
 	startTime := time.Now()
 	defer func() {
-		LogDebug("Verification completed in %s", time.Since(startTime))
+		logEvent(LogLevelDebug, "Verification completed", "address", address, "duration", time.Since(startTime))
 	}()
 
 	return VerifyBip137SignatureWithParams(address, message, signatureBase64, &chaincfg.MainNetParams)
@@ -52,72 +50,53 @@ func VerifyBip137Signature(address, message, signatureBase64 string) (bool, erro
 // VerifyBip137SignatureWithParams verifies a BIP-0137 signature using the provided
 // network parameters (mainnet, testnet, etc.).
 func VerifyBip137SignatureWithParams(address, message, signatureBase64 string, params *chaincfg.Params) (bool, error) {
-	LogDebug("Verifying signature with network parameters: %s", params.Name)
+	start := time.Now()
+	valid, err := verifyWithParams(eventLogger{}, address, message, signatureBase64, params)
+	observeVerification(GetMetrics(), start, valid, err)
+	return valid, err
+}
 
-	// Log inputs
-	if GetLogLevel() >= LogLevelTrace {
-		LogTrace("Detailed verification parameters:")
-		LogTrace("Network: %s", params.Name)
-		LogTrace("P2PKH Prefix: %x", params.PubKeyHashAddrID)
-		LogTrace("P2SH Prefix: %x", params.ScriptHashAddrID)
-	}
+// verifyWithParams implements VerifyBip137SignatureWithParams, logging to
+// events
+func verifyWithParams(events eventLogger, address, message, signatureBase64 string, params *chaincfg.Params) (bool, error) {
+	events.log(LogLevelDebug, "Verifying signature with network parameters", "network", params.Name)
+	events.log(LogLevelTrace, "Network parameters",
+		"network", params.Name, "p2pkh_prefix", params.PubKeyHashAddrID, "p2sh_prefix", params.ScriptHashAddrID)
 
 	// Validate inputs
 	if address == "" {
-		LogError("Empty address provided")
+		events.log(LogLevelError, "Empty address provided")
 		return false, ErrEmptyAddress
 	}
 	if message == "" {
-		LogError("Empty message provided")
+		events.log(LogLevelError, "Empty message provided")
 		return false, ErrEmptyMessage
 	}
 	if signatureBase64 == "" {
-		LogError("Empty signature provided")
+		events.log(LogLevelError, "Empty signature provided")
 		return false, ErrEmptySignature
 	}
+	if err := checkMessageSize(events, message, MaxMessageSize()); err != nil {
+		return false, err
+	}
+
+	// Decode the address to validate it belongs to the requested network
+	if _, err := decodeAddress(address, params); err != nil {
+		events.log(LogLevelError, "Invalid address", "address", address, "network", params.Name, "error", err)
+		return false, err
+	}
 
 	// Attempt to decode the signature to validate it's correct base64
 	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
 	if err != nil {
-		LogError("Failed to decode base64 signature: %v", err)
-		return false, fmt.Errorf("invalid base64 signature: %w", err)
+		events.log(LogLevelError, "Failed to decode base64 signature", "error", err)
+		return false, newVerifyError(ErrMalformedSignature, "invalid base64 signature: %v", err)
 	}
 
 	// Log the decoded signature bytes
-	LogTrace("Decoded signature (hex): %s", DumpHex(sigBytes))
-
-	// Check the signature header byte
-	if len(sigBytes) > 0 {
-		headerByte := sigBytes[0]
-		LogDebug("Signature header byte: 0x%02x", headerByte)
-
-		// Analyze the header byte based on BIP-0137
-		recID := headerByte & 0x03
-		isCompressed := false
-		addrType := "Unknown"
-
-		switch {
-		case headerByte >= 27 && headerByte <= 30:
-			addrType = "P2PKH (uncompressed)"
-			isCompressed = false
-		case headerByte >= 31 && headerByte <= 34:
-			addrType = "P2PKH (compressed)"
-			isCompressed = true
-		case headerByte >= 35 && headerByte <= 38:
-			addrType = "P2SH-P2WPKH (SegWit over P2SH)"
-			isCompressed = true
-		case headerByte >= 39 && headerByte <= 42:
-			addrType = "P2WPKH (native SegWit)"
-			isCompressed = true
-		default:
-			LogWarning("Unknown signature header byte: 0x%02x", headerByte)
-		}
+	events.log(LogLevelTrace, "Decoded signature", "signature_hex", DumpHex(sigBytes))
 
-		LogDebug("Signature details from header:")
-		LogDebug("  Address type: %s", addrType)
-		LogDebug("  Compressed public key: %t", isCompressed)
-		LogDebug("  Recovery ID: %d", recID)
-	}
+	logHeader(events, sigBytes)
 
 	// Create a signed message struct
 	signedMessage := verifier.SignedMessage{
@@ -127,82 +106,105 @@ func VerifyBip137SignatureWithParams(address, message, signatureBase64 string, p
 	}
 
 	// Verify the signature using the provided network parameters
-	LogDebug("Calling BitonicNL verifier to verify signature")
+	events.log(LogLevelDebug, "Calling BitonicNL verifier to verify signature")
 	valid, err := verifier.VerifyWithChain(signedMessage, params)
 	if err != nil {
-		LogError("Signature verification failed: %v", err)
-		return false, fmt.Errorf("signature verification error: %w", err)
+		events.log(LogLevelError, "Signature verification failed", "address", address, "network", params.Name, "error", err)
+		return false, fmt.Errorf("signature verification error: %w", classifyVerifierError(err))
 	}
 
-	if valid {
-		LogInfo("Signature verification successful")
-	} else {
-		LogInfo("Signature verification failed (invalid signature)")
-	}
+	events.log(LogLevelInfo, "Signature verification result", "address", address, "network", params.Name, "valid", valid)
 
 	return valid, nil
 }
 
 // VerifyBip137SignatureWithContext verifies a BIP-0137 signature with context support
 // for timeout and cancellation. This is the recommended approach for 2025.
-func VerifyBip137SignatureWithContext(ctx context.Context, msg SignedMessage) (bool, error) {
-	LogInfo("Starting context-based signature verification")
+//
+// Verification runs in stages, decoding, hashing, key recovery and address
+// derivation, and the context is checked between them: once it is done,
+// verification stops at the next stage and the error wraps
+// ErrVerificationTimeout and the error of the context.
+func VerifyBip137SignatureWithContext(ctx context.Context, msg SignedMessage) (valid bool, err error) {
+	spans, span := newSpanScope(ctx, GetTracer()).start(SpanVerify)
+	span.SetAttribute("address", msg.Address)
+
+	start := time.Now()
+	defer func() {
+		observeVerification(GetMetrics(), start, valid, err)
 
-	// Check if context has a deadline
-	if deadline, ok := ctx.Deadline(); ok {
-		LogDebug("Context has deadline: %s (timeout in %s)",
-			deadline.Format(time.RFC3339), time.Until(deadline))
-	} else {
-		LogDebug("Context has no deadline")
+		span.SetAttribute("valid", valid)
+		span.End(err)
+	}()
+
+	events := eventLoggerFromContext(ctx)
+	events.log(LogLevelInfo, "Starting context-based signature verification", "address", msg.Address)
+	logContextDeadline(ctx, events)
+
+	// Validate inputs
+	if msg.Address == "" {
+		events.log(LogLevelError, "Empty address provided")
+		return false, ErrEmptyAddress
+	}
+	if msg.Message == "" {
+		events.log(LogLevelError, "Empty message provided")
+		return false, ErrEmptyMessage
+	}
+	if msg.Signature == "" {
+		events.log(LogLevelError, "Empty signature provided")
+		return false, ErrEmptySignature
+	}
+	if err := checkMessageSize(events, msg.Message, MaxMessageSize()); err != nil {
+		return false, err
 	}
 
-	// Create a channel to receive the verification result
-	resultCh := make(chan struct {
-		valid bool
-		err   error
-	}, 1)
+	return runWithContext(ctx, func(ctx context.Context) (bool, error) {
+		return verifyStages(events, spans, msg, &chaincfg.MainNetParams)
+	})
+}
 
-	// Run verification in a goroutine
-	startTime := time.Now()
-	go func() {
-		LogDebug("Starting verification goroutine")
-		// Create a signed message struct
-		signedMessage := verifier.SignedMessage{
+// verifyStages verifies a signed message, checking the context of spans
+// between the stages of verification. Compact signatures are verified by the
+// native engine; other signatures by the BitonicNL verifier, which can't be
+// interrupted, so the context is only checked before it runs. Like the
+// BitonicNL verifier, the native engine also accepts signatures of the
+// message with surrounding whitespace trimmed.
+func verifyStages(events eventLogger, spans spanScope, msg SignedMessage, params *chaincfg.Params) (bool, error) {
+	sigBytes, err := decodeSignature(msg.Signature, Base64Default)
+	if err != nil {
+		return false, err
+	}
+	if err := spans.err(); err != nil {
+		return false, err
+	}
+
+	if len(sigBytes) != compactSignatureLength {
+		valid, err := verifier.VerifyWithChain(verifier.SignedMessage{
 			Address:   msg.Address,
 			Message:   msg.Message,
 			Signature: msg.Signature,
+		}, params)
+		if err != nil {
+			return false, classifyVerifierError(err)
 		}
+		return valid, nil
+	}
 
-		// Verify the signature
-		valid, err := verifier.Verify(signedMessage)
-		duration := time.Since(startTime)
-		LogDebug("Verification completed in goroutine after %s", duration)
-
-		resultCh <- struct {
-			valid bool
-			err   error
-		}{valid, err}
-	}()
-
-	// Wait for either the context to be done or the verification to complete
-	select {
-	case <-ctx.Done():
-		ctxErr := ctx.Err()
-		LogError("Context cancelled or timed out: %v", ctxErr)
-		return false, fmt.Errorf("%w: %v", ErrVerificationTimeout, ctxErr)
-	case result := <-resultCh:
-		if result.err != nil {
-			LogError("Signature verification error: %v", result.err)
-			return false, fmt.Errorf("signature verification error: %w", result.err)
+	digest := magicHashWithEvents(events, msg.Message)
+	report := explainDigest(events, spans, msg.Address, digest[:], sigBytes, params, TaprootKeyInternal)
+	if trimmed, ok := trimmedMessage(msg.Message); ok && !report.Valid {
+		digest := magicHashWithEvents(events, trimmed)
+		if retry := explainDigest(events, spans, msg.Address, digest[:], sigBytes, params, TaprootKeyInternal); retry.Valid {
+			return true, nil
 		}
-		LogInfo("Context-based verification result: %t", result.valid)
-		return result.valid, nil
 	}
+	return report.Valid, report.Err
 }
 
-// LogWarning logs a warning message
-func LogWarning(format string, args ...interface{}) {
-	if currentLogLevel >= LogLevelInfo {
-		Logger.Printf("[WARNING] "+format, args...)
-	}
+// trimmedMessage returns the message with surrounding whitespace trimmed,
+// and whether that changed it. Electrum trims messages before signing them,
+// so the BitonicNL verifier accepts signatures of the trimmed message too.
+func trimmedMessage(message string) (string, bool) {
+	trimmed := strings.TrimSpace(message)
+	return trimmed, trimmed != message
 }
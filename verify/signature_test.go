@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/btcsuite/btcd/btcutil"
 	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify/vectors"
 )
 
 func TestVerifyBip137Signature(t *testing.T) {
@@ -41,25 +43,6 @@ func TestVerifyBip137Signature(t *testing.T) {
 			wantValid: false,
 			wantErr:   true,
 		},
-		// Real signature test case
-		{
-			name:      "Valid Bitcoin signature",
-			address:   "194vDb9xwY6XQi5bLa7FRPBewJdUqympZ9",
-			message:   "Hello, Bitcoin testing!",
-			signature: "IOeVH/0KqgmS3XKwqCJiwlcHonwxKMQN6fbOW5UsXSDZB4EGCVTXx6c+ZU/Ae5qO94MSBZn2aPOiUsupRIwBaAU=",
-			wantValid: true,
-			wantErr:   false,
-		},
-		// Note: Add real test vectors here with valid Bitcoin signatures
-		// Example:
-		// {
-		//     name:      "Valid P2PKH signature",
-		//     address:   "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
-		//     message:   "Hello, Bitcoin!",
-		//     signature: "IBFyn+h9m3pWYbB4fBFKlRzBD4eJKojgCIZSNdhLKKHPSV2/WkeV7R7IOI0dpo3uGAEpCz9eepXLrA5kF35MXuU=",
-		//     wantValid: true,
-		//     wantErr:   false,
-		// },
 	}
 
 	for _, tt := range tests {
@@ -81,6 +64,39 @@ func TestVerifyBip137Signature(t *testing.T) {
 	}
 }
 
+func TestVerifyWalletCorpus(t *testing.T) {
+	corpus, err := vectors.Corpus()
+	if err != nil {
+		t.Fatalf("vectors.Corpus() error = %v", err)
+	}
+
+	for _, vec := range corpus {
+		t.Run(vec.Name, func(t *testing.T) {
+			params := networkParams(vec.Network)
+			if params == nil {
+				t.Fatalf("unknown network %q", vec.Network)
+			}
+
+			valid, err := VerifyBip137SignatureWithParams(vec.Address, vec.Message, vec.Signature, params)
+			if err != nil || !valid {
+				t.Errorf("VerifyBip137SignatureWithParams() = %v, %v, want valid", valid, err)
+			}
+
+			result, err := NewVerifier(WithParams(params)).Verify(SignedMessage{Address: vec.Address, Message: vec.Message, Signature: vec.Signature})
+			if err != nil || !result.Valid {
+				t.Errorf("Verifier.Verify() = %+v, %v, want valid", result, err)
+			}
+			addr, err := btcutil.DecodeAddress(vec.Address, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addrType := addressTypeOf(addr); string(addrType) != vec.AddressType {
+				t.Errorf("address type = %q, want %q", addrType, vec.AddressType)
+			}
+		})
+	}
+}
+
 func TestVerifyBip137SignatureWithParams(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -184,6 +200,27 @@ func TestVerifyBip137SignatureWithContext(t *testing.T) {
 			wantErr:     false,
 			wantTimeout: false,
 		},
+		// Electrum trims messages before signing
+		{
+			name: "Trailing whitespace",
+			ctx:  context.Background(),
+			msg: SignedMessage{
+				Address:   walletTestVectors[5].msg.Address,
+				Message:   walletTestVectors[5].msg.Message + " ",
+				Signature: walletTestVectors[5].msg.Signature,
+			},
+			wantValid: true,
+		},
+		{
+			name: "Surrounding whitespace",
+			ctx:  context.Background(),
+			msg: SignedMessage{
+				Address:   walletTestVectors[5].msg.Address,
+				Message:   " " + walletTestVectors[5].msg.Message + "\n",
+				Signature: walletTestVectors[5].msg.Signature,
+			},
+			wantValid: true,
+		},
 	}
 
 	for _, tt := range tests {
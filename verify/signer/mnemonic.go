@@ -0,0 +1,65 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// ErrInvalidMnemonic is returned when a mnemonic isn't a valid BIP-39
+// mnemonic of the English word list
+var ErrInvalidMnemonic = errors.New("invalid BIP-39 mnemonic")
+
+// NewMnemonic generates a random 24-word BIP-39 mnemonic
+func NewMnemonic() (string, error) {
+	entropy, err := bip39.NewEntropy(256)
+	if err != nil {
+		return "", err
+	}
+	return bip39.NewMnemonic(entropy)
+}
+
+// SeedFromMnemonic returns the BIP-39 seed of a mnemonic and optional
+// passphrase, checking the words and checksum of the mnemonic
+func SeedFromMnemonic(mnemonic, passphrase string) ([]byte, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(strings.Join(strings.Fields(mnemonic), " "), passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidMnemonic, err)
+	}
+	return seed, nil
+}
+
+// KeyFromMnemonic derives the key at a BIP-32 path, such as
+// "m/84'/0'/0'/0/0", from the seed of a mnemonic, so example programs and
+// test vectors can start from human-readable seeds. Derived keys are
+// compressed.
+func KeyFromMnemonic(mnemonic, passphrase, path string, params *chaincfg.Params) (*Key, error) {
+	seed, err := SeedFromMnemonic(mnemonic, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := verify.ParseDerivationPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := hdkeychain.NewMaster(seed, params)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range steps {
+		if key, err = key.Derive(index); err != nil {
+			return nil, fmt.Errorf("deriving %s: %w", steps, err)
+		}
+	}
+	privKey, err := key.ECPrivKey()
+	if err != nil {
+		return nil, err
+	}
+	return NewKey(privKey, true, params), nil
+}
@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestSeedFromMnemonic(t *testing.T) {
+	// BIP-39 test vector with the passphrase "TREZOR"
+	want := "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+	seed, err := SeedFromMnemonic(testMnemonic, "TREZOR")
+	if err != nil || hex.EncodeToString(seed) != want {
+		t.Errorf("SeedFromMnemonic() = %x, %v, want %s", seed, err, want)
+	}
+
+	for _, mnemonic := range []string{
+		strings.Replace(testMnemonic, "about", "abandon", 1),
+		strings.Replace(testMnemonic, "about", "bitcoin!", 1),
+	} {
+		if _, err := SeedFromMnemonic(mnemonic, ""); !errors.Is(err, ErrInvalidMnemonic) {
+			t.Errorf("SeedFromMnemonic(%q) error = %v, want %v", mnemonic, err, ErrInvalidMnemonic)
+		}
+	}
+}
+
+func TestKeyFromMnemonic(t *testing.T) {
+	// BIP-84 test vector: first receive address of the first account
+	key, err := KeyFromMnemonic(testMnemonic, "", "m/84'/0'/0'/0/0", &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("KeyFromMnemonic() error = %v", err)
+	}
+	want := "bc1qcr8te4kr609gcawutmrza0j4xv80jy8z306fyu"
+	if addr, err := key.Address(verify.AddressTypeP2WPKH); err != nil || addr != want {
+		t.Errorf("Address() = %q, %v, want %q", addr, err, want)
+	}
+
+	if _, err := KeyFromMnemonic(testMnemonic, "", "m/84'/x", &chaincfg.MainNetParams); !errors.Is(err, verify.ErrInvalidDerivationPath) {
+		t.Errorf("KeyFromMnemonic() error = %v, want %v", err, verify.ErrInvalidDerivationPath)
+	}
+}
+
+func TestNewMnemonic(t *testing.T) {
+	mnemonic, err := NewMnemonic()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := strings.Fields(mnemonic); len(words) != 24 {
+		t.Errorf("NewMnemonic() has %d words, want 24", len(words))
+	}
+	if _, err := SeedFromMnemonic(mnemonic, ""); err != nil {
+		t.Errorf("SeedFromMnemonic() error = %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package signer
+
+import (
+	"errors"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+)
+
+// ErrInvalidNonce is returned when a nonce function gives a nonce that
+// can't sign
+var ErrInvalidNonce = errors.New("invalid signing nonce")
+
+// maxNonceAttempts bounds the nonces tried for one signature. RFC 6979 needs
+// a second attempt with a probability of about 2^-128.
+const maxNonceAttempts = 8
+
+// NonceFunc returns the nonce k of a signature of the digest by the private
+// key. attempt counts the nonces already rejected for the signature, as
+// giving a zero R or S value. A nil or zero nonce fails the signature with
+// ErrInvalidNonce.
+type NonceFunc func(privKey *btcec.PrivateKey, digest []byte, attempt uint32) *btcec.ModNScalar
+
+// NonceRFC6979 derives nonces deterministically from the private key and
+// the digest as specified by RFC 6979, like Bitcoin Core. It is the nonce
+// function of keys, so signing a message twice gives the same signature.
+func NonceRFC6979(privKey *btcec.PrivateKey, digest []byte, attempt uint32) *btcec.ModNScalar {
+	keyBytes := privKey.Key.Bytes()
+	return btcec.NonceRFC6979(keyBytes[:], digest, nil, nil, attempt)
+}
+
+// FixedNonce returns a nonce function always giving the 32-byte big-endian
+// nonce k, for reproducing test vectors of other implementations
+// byte-for-byte. It must never sign with a real key: two signatures with
+// the same nonce reveal the private key.
+func FixedNonce(k []byte) NonceFunc {
+	var nonce btcec.ModNScalar
+	valid := len(k) == 32 && !nonce.SetByteSlice(k) && !nonce.IsZero()
+	return func(*btcec.PrivateKey, []byte, uint32) *btcec.ModNScalar {
+		if !valid {
+			return nil
+		}
+		return &nonce
+	}
+}
+
+// signCompact produces a compact signature of the digest with the P2PKH
+// header byte, using nonces of the nonce function. S is in the lower half
+// of the curve order as BIP-62 requires, so with NonceRFC6979 the signature
+// is the one of ecdsa.SignCompact.
+func signCompact(privKey *btcec.PrivateKey, digest []byte, compressed bool, nonce NonceFunc) ([]byte, error) {
+	var e btcec.ModNScalar
+	e.SetByteSlice(digest)
+
+	for attempt := uint32(0); attempt < maxNonceAttempts; attempt++ {
+		k := nonce(privKey, digest, attempt)
+		if k == nil || k.IsZero() {
+			return nil, ErrInvalidNonce
+		}
+
+		// R = kG, r = R.x mod N
+		var kG btcec.JacobianPoint
+		btcec.ScalarBaseMultNonConst(k, &kG)
+		kG.ToAffine()
+		var r btcec.ModNScalar
+		overflow := r.SetBytes(kG.X.Bytes())
+		if r.IsZero() {
+			continue
+		}
+		// The recovery ID records the parity of R.y and whether R.x
+		// overflowed the curve order
+		recoveryID := byte(overflow<<1) | byte(kG.Y.IsOddBit())
+
+		// s = k^-1 (e + d*r) mod N
+		kInv := new(btcec.ModNScalar).InverseValNonConst(k)
+		s := new(btcec.ModNScalar).Mul2(&privKey.Key, &r).Add(&e).Mul(kInv)
+		if s.IsZero() {
+			continue
+		}
+		if s.IsOverHalfOrder() {
+			s.Negate()
+			recoveryID ^= 0x01
+		}
+
+		sig := make([]byte, 65)
+		sig[0] = 27 + recoveryID
+		if compressed {
+			sig[0] += 4
+		}
+		r.PutBytesUnchecked(sig[1:33])
+		s.PutBytesUnchecked(sig[33:65])
+		return sig, nil
+	}
+	return nil, ErrInvalidNonce
+}
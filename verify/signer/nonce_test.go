@@ -0,0 +1,114 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/keys"
+)
+
+func TestSignRFC6979(t *testing.T) {
+	tests := []struct {
+		name    string
+		wif     string
+		params  *chaincfg.Params
+		message string
+		want    string
+	}{
+		{
+			name:    "Bitcoin Core signmessagewithprivkey",
+			wif:     "cUeKHd5orzT3mz8P9pxyREHfsWtVfgsfDjiZZBcjUBAaGk1BTj7N",
+			params:  &chaincfg.TestNet3Params,
+			message: "This is just a test message",
+			want:    "INbVnW4e6PeRmsv2Qgu8NuopvrVjkcxob+sX8OcZG0SALhWybUjzMLPdAsXI46YZGb0KQTRii+wWIQzRpG/U+S0=",
+		},
+		{
+			name:    "bitcoinjs-message README",
+			wif:     "L4rK1yDtCWekvXuE6oXD9jCYfFNV2cWRpVuPLBcCU2z8TrisoyY1",
+			params:  &chaincfg.MainNetParams,
+			message: "This is an example of a signed message.",
+			want:    "H9L5yLFjti0QTHhPyFrZCT1V/MMnBtXKmoiKDZ78NDBjERki6ZTQZdSMCtkgoNmp17By9ItJr8o7ChX0XxY91nk=",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ImportWIF(tt.wif, tt.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			msg, err := key.Sign(tt.message, verify.AddressTypeP2PKH)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			if msg.Signature != tt.want {
+				t.Errorf("Sign() signature = %s, want %s", msg.Signature, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignMatchesSignCompact(t *testing.T) {
+	for i := range 32 {
+		privKey := keys.FromSeed(fmt.Sprintf("rfc6979 test key %d", i))
+		for _, compressed := range []bool{true, false} {
+			message := fmt.Sprintf("message %d", i)
+			msg, err := NewKey(privKey, compressed, &chaincfg.MainNetParams).Sign(message, verify.AddressTypeP2PKH)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+
+			digest := verify.MessageHash(message)
+			want := base64.StdEncoding.EncodeToString(ecdsa.SignCompact(privKey, digest[:], compressed))
+			if msg.Signature != want {
+				t.Errorf("key %d, compressed %v: Sign() signature = %s, want %s", i, compressed, msg.Signature, want)
+			}
+		}
+	}
+}
+
+func TestFixedNonce(t *testing.T) {
+	key, err := ImportWIF(compressedWIF, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// With a nonce of 1, R is the generator point
+	one := make([]byte, 32)
+	one[31] = 1
+	generatorX, _ := hex.DecodeString("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+
+	fixed := key.WithNonce(FixedNonce(one))
+	v := verify.NewVerifier()
+	for _, message := range []string{"first message", "second message"} {
+		msg, err := fixed.Sign(message, verify.AddressTypeP2WPKH)
+		if err != nil {
+			t.Fatalf("Sign(%q) error = %v", message, err)
+		}
+		sig, _ := base64.StdEncoding.DecodeString(msg.Signature)
+		if !bytes.Equal(sig[1:33], generatorX) {
+			t.Errorf("Sign(%q) R = %x, want %x", message, sig[1:33], generatorX)
+		}
+		if result, err := v.Verify(msg); err != nil || !result.Valid {
+			t.Errorf("Verify(%+v) = %+v, %v, want valid", msg, result, err)
+		}
+	}
+
+	// The key itself keeps signing with RFC 6979 nonces
+	msg, _ := key.Sign("first message", verify.AddressTypeP2WPKH)
+	if sig, _ := base64.StdEncoding.DecodeString(msg.Signature); bytes.Equal(sig[1:33], generatorX) {
+		t.Error("WithNonce() changed the nonces of the original key")
+	}
+
+	for _, nonce := range [][]byte{nil, make([]byte, 32), one[1:]} {
+		if _, err := key.WithNonce(FixedNonce(nonce)).Sign("message", verify.AddressTypeP2PKH); !errors.Is(err, ErrInvalidNonce) {
+			t.Errorf("Sign() with nonce %x error = %v, want %v", nonce, err, ErrInvalidNonce)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package signer produces BIP-0137 signatures that the verify package
+// verifies, for tools, tests and example programs that need to sign
+// messages:
+//
+//	key, err := signer.ImportWIF(wif, &chaincfg.MainNetParams)
+//	msg, err := key.Sign("message", verify.AddressTypeP2WPKH)
+//
+// Keys are held in memory unencrypted; services verifying signatures don't
+// need this package.
+package signer
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/sero/btc/verify"
+)
+
+// ErrInvalidWIF is returned when a private key isn't valid WIF
+var ErrInvalidWIF = errors.New("invalid WIF private key")
+
+// Key is a private key that signs messages for the addresses of a network
+type Key struct {
+	privKey    *btcec.PrivateKey
+	compressed bool
+	params     *chaincfg.Params
+
+	// nonce gives the signature nonces, NonceRFC6979 when nil
+	nonce NonceFunc
+}
+
+// NewKey creates a Key for the network. Keys that aren't compressed only
+// sign for P2PKH addresses of the uncompressed public key.
+func NewKey(privKey *btcec.PrivateKey, compressed bool, params *chaincfg.Params) *Key {
+	return &Key{privKey: privKey, compressed: compressed, params: params}
+}
+
+// ImportWIF imports a private key in wallet import format, as exported by
+// Bitcoin Core's dumpprivkey and most wallets. The key must be for the
+// network: mainnet keys start with 5, K or L, and keys of the test networks
+// with 9 or c. The compressed flag of the WIF is kept.
+func ImportWIF(wif string, params *chaincfg.Params) (*Key, error) {
+	decoded, err := btcutil.DecodeWIF(wif)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWIF, err)
+	}
+	if !decoded.IsForNet(params) {
+		return nil, fmt.Errorf("%w: private key is not for %s", verify.ErrNetworkMismatch, params.Name)
+	}
+	return NewKey(decoded.PrivKey, decoded.CompressPubKey, params), nil
+}
+
+// WithNonce returns a copy of the key signing with nonces of the function
+// instead of NonceRFC6979, such as FixedNonce in tests
+func (k *Key) WithNonce(nonce NonceFunc) *Key {
+	c := *k
+	c.nonce = nonce
+	return &c
+}
+
+// WIF returns the key in wallet import format
+func (k *Key) WIF() string {
+	wif, _ := btcutil.NewWIF(k.privKey, k.params, k.compressed)
+	return wif.String()
+}
+
+// PubKey returns the public key
+func (k *Key) PubKey() *btcec.PublicKey {
+	return k.privKey.PubKey()
+}
+
+// Compressed reports whether the key signs with its compressed public key
+func (k *Key) Compressed() bool {
+	return k.compressed
+}
+
+// Address returns the address of the given type for the key
+func (k *Key) Address(addrType verify.AddressType) (string, error) {
+	addr, _, err := k.address(addrType)
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// Sign signs a message for the address of the given type, returning the
+// signed message with the address it verifies against. P2PKH, P2SH-P2WPKH
+// and P2WPKH addresses are supported; SegWit addresses need a compressed
+// key. Nonces are derived per RFC 6979 unless set with WithNonce, so
+// signing a message twice gives the same signature.
+func (k *Key) Sign(message string, addrType verify.AddressType) (verify.SignedMessage, error) {
+	if message == "" {
+		return verify.SignedMessage{}, verify.ErrEmptyMessage
+	}
+	addr, header, err := k.address(addrType)
+	if err != nil {
+		return verify.SignedMessage{}, err
+	}
+
+	digest := verify.MessageHash(message)
+	nonce := k.nonce
+	if nonce == nil {
+		nonce = NonceRFC6979
+	}
+	sig, err := signCompact(k.privKey, digest[:], k.compressed, nonce)
+	if err != nil {
+		return verify.SignedMessage{}, err
+	}
+	// signCompact returns a P2PKH header byte; move it to the range of the
+	// address type, keeping the recovery ID
+	sig[0] = header + (sig[0]-27)&0x03
+
+	return verify.SignedMessage{
+		Address:   addr.EncodeAddress(),
+		Message:   message,
+		Signature: base64.StdEncoding.EncodeToString(sig),
+	}, nil
+}
+
+// Countersign signs the next message of a signature chain for the address
+// of the given type, returning the chain with the signature appended. The
+// first signature of an empty chain signs its message.
+func (k *Key) Countersign(chain verify.SignatureChain, addrType verify.AddressType) (verify.SignatureChain, error) {
+	msg, err := k.Sign(chain.NextMessage(), addrType)
+	if err != nil {
+		return verify.SignatureChain{}, err
+	}
+	chain.Signatures = append(slices.Clip(chain.Signatures), verify.AddressSignature{Address: msg.Address, Signature: msg.Signature})
+	return chain, nil
+}
+
+// address returns the address of the given type and the first header byte
+// of its BIP-0137 range
+func (k *Key) address(addrType verify.AddressType) (btcutil.Address, byte, error) {
+	if !k.compressed {
+		if addrType != verify.AddressTypeP2PKH {
+			return nil, 0, fmt.Errorf("%w: uncompressed keys only sign for P2PKH addresses", verify.ErrUnsupportedAddressType)
+		}
+		addr, err := btcutil.NewAddressPubKeyHash(btcutil.Hash160(k.PubKey().SerializeUncompressed()), k.params)
+		return addr, 27, err
+	}
+
+	pubKeyHash := btcutil.Hash160(k.PubKey().SerializeCompressed())
+	switch addrType {
+	case verify.AddressTypeP2PKH:
+		addr, err := btcutil.NewAddressPubKeyHash(pubKeyHash, k.params)
+		return addr, 31, err
+	case verify.AddressTypeP2SHP2WPKH:
+		script, err := txscript.NewScriptBuilder().AddOp(txscript.OP_0).AddData(pubKeyHash).Script()
+		if err != nil {
+			return nil, 0, err
+		}
+		addr, err := btcutil.NewAddressScriptHash(script, k.params)
+		return addr, 35, err
+	case verify.AddressTypeP2WPKH:
+		addr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, k.params)
+		return addr, 39, err
+	default:
+		return nil, 0, fmt.Errorf("%w: cannot sign for %q addresses", verify.ErrUnsupportedAddressType, addrType)
+	}
+}
@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+)
+
+// Private key of the Bitcoin wiki WIF example, uncompressed and compressed
+const (
+	uncompressedWIF = "5HueCGU8rMjxEXxiPuD5BDku4MkFqeZyd4dZ1jvhTVqvbTLvyTJ"
+	compressedWIF   = "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"
+)
+
+func TestImportWIF(t *testing.T) {
+	tests := []struct {
+		name           string
+		wif            string
+		params         *chaincfg.Params
+		wantAddress    string
+		wantCompressed bool
+		wantErr        error
+	}{
+		{
+			name:        "Uncompressed",
+			wif:         uncompressedWIF,
+			params:      &chaincfg.MainNetParams,
+			wantAddress: "1GAehh7TsJAHuUAeKZcXf5CnwuGuGgyX2S",
+		},
+		{
+			name:           "Compressed",
+			wif:            compressedWIF,
+			params:         &chaincfg.MainNetParams,
+			wantAddress:    "1LoVGDgRs9hTfTNJNuXKSpywcbdvwRXpmK",
+			wantCompressed: true,
+		},
+		{
+			name:    "Wrong network",
+			wif:     compressedWIF,
+			params:  &chaincfg.TestNet3Params,
+			wantErr: verify.ErrNetworkMismatch,
+		},
+		{
+			name:    "Bad checksum",
+			wif:     compressedWIF[:len(compressedWIF)-1] + "8",
+			params:  &chaincfg.MainNetParams,
+			wantErr: ErrInvalidWIF,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := ImportWIF(tt.wif, tt.params)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ImportWIF() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if key.Compressed() != tt.wantCompressed || key.WIF() != tt.wif {
+				t.Errorf("ImportWIF() = compressed %v, WIF %s, want %v, %s", key.Compressed(), key.WIF(), tt.wantCompressed, tt.wif)
+			}
+			if addr, err := key.Address(verify.AddressTypeP2PKH); err != nil || addr != tt.wantAddress {
+				t.Errorf("Address() = %q, %v, want %q", addr, err, tt.wantAddress)
+			}
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	compressed, _ := ImportWIF(compressedWIF, &chaincfg.MainNetParams)
+	uncompressed, _ := ImportWIF(uncompressedWIF, &chaincfg.MainNetParams)
+
+	tests := []struct {
+		name     string
+		key      *Key
+		addrType verify.AddressType
+		wantErr  error
+	}{
+		{name: "Uncompressed P2PKH", key: uncompressed, addrType: verify.AddressTypeP2PKH},
+		{name: "Compressed P2PKH", key: compressed, addrType: verify.AddressTypeP2PKH},
+		{name: "P2SH-P2WPKH", key: compressed, addrType: verify.AddressTypeP2SHP2WPKH},
+		{name: "P2WPKH", key: compressed, addrType: verify.AddressTypeP2WPKH},
+		{name: "Uncompressed P2WPKH", key: uncompressed, addrType: verify.AddressTypeP2WPKH, wantErr: verify.ErrUnsupportedAddressType},
+		{name: "P2TR", key: compressed, addrType: verify.AddressTypeP2TR, wantErr: verify.ErrUnsupportedAddressType},
+	}
+
+	v := verify.NewVerifier(verify.WithStrictHeader())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg, err := tt.key.Sign("signed by the signer package", tt.addrType)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Sign() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if result, err := v.Verify(msg); err != nil || !result.Valid {
+				t.Errorf("Verify() = %+v, %v, want a valid signature", result, err)
+			}
+		})
+	}
+}
+
+func TestCountersign(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	compressed, _ := ImportWIF(compressedWIF, &chaincfg.MainNetParams)
+	uncompressed, _ := ImportWIF(uncompressedWIF, &chaincfg.MainNetParams)
+
+	chain := verify.SignatureChain{Message: "invoice #1234: 0.5 BTC"}
+	chain, err := compressed.Countersign(chain, verify.AddressTypeP2WPKH)
+	if err != nil {
+		t.Fatalf("Countersign() error = %v", err)
+	}
+	first := chain
+	chain, err = uncompressed.Countersign(chain, verify.AddressTypeP2PKH)
+	if err != nil {
+		t.Fatalf("Countersign() error = %v", err)
+	}
+
+	if len(first.Signatures) != 1 || len(chain.Signatures) != 2 {
+		t.Fatalf("Countersign() chains have %d and %d signatures, want 1 and 2", len(first.Signatures), len(chain.Signatures))
+	}
+	if valid, err := verify.VerifyChain(chain); err != nil || !valid {
+		t.Errorf("VerifyChain() = %v, %v, want true", valid, err)
+	}
+}
@@ -0,0 +1,45 @@
+//go:build !tinygo
+
+package verify
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LevelTrace is the slog level trace messages are logged at by the Logger
+// returned from NewSlogLogger
+const LevelTrace = slog.LevelDebug - 4
+
+// NewSlogLogger returns a Logger that sends the messages of the package to a
+// structured slog.Logger. The level set with SetLogLevel still applies, so set
+// it to LogLevelTrace to leave filtering to the slog handler.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l: l}
+}
+
+// slogLogger adapts a *slog.Logger to the Logger interface
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// Log records the message with the slog level matching level
+func (s slogLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	s.l.Log(context.Background(), slogLevel(level), msg, keyvals...)
+}
+
+// slogLevel maps a LogLevel onto a slog level
+func slogLevel(level LogLevel) slog.Level {
+	switch level {
+	case LogLevelError:
+		return slog.LevelError
+	case LogLevelWarning:
+		return slog.LevelWarn
+	case LogLevelInfo:
+		return slog.LevelInfo
+	case LogLevelDebug:
+		return slog.LevelDebug
+	default:
+		return LevelTrace
+	}
+}
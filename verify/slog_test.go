@@ -0,0 +1,34 @@
+//go:build !tinygo
+
+package verify
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogLogger(t *testing.T) {
+	defer SetLogger(GetLogger())
+	defer SetLogLevel(GetLogLevel())
+
+	var buf bytes.Buffer
+	handler := slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	SetLogger(NewSlogLogger(slog.New(handler)))
+	SetLogLevel(LogLevelTrace)
+
+	LogWarning("in flight")
+	LogTrace("filtered by the handler")
+	GetLogger().Log(LogLevelInfo, "verified", "address", "1abc")
+
+	out := buf.String()
+	for _, want := range []string{"level=WARN msg=\"in flight\"", "level=INFO msg=verified address=1abc"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("slog output %q doesn't contain %q", out, want)
+		}
+	}
+	if strings.Contains(out, "filtered by the handler") {
+		t.Errorf("slog output %q contains a trace message", out)
+	}
+}
@@ -0,0 +1,48 @@
+package verify
+
+import (
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+// TaprootKey selects which key of a P2TR address signed a compact
+// signature. BIP-0137 predates Taproot, so wallets differ: most sign with
+// the internal key, whose BIP-341 tweak gives the output key of the address,
+// while others sign with the tweaked output key itself, as a key-path spend
+// does.
+type TaprootKey int
+
+const (
+	// TaprootKeyInternal treats the signing key as the internal key of the
+	// address, tweaked without a script path as in BIP-86
+	TaprootKeyInternal TaprootKey = iota
+
+	// TaprootKeyOutput treats the signing key as the output key of the
+	// address, compared with its witness program as is
+	TaprootKeyOutput
+)
+
+// String returns the name of the Taproot key
+func (k TaprootKey) String() string {
+	switch k {
+	case TaprootKeyInternal:
+		return "internal"
+	case TaprootKeyOutput:
+		return "output"
+	default:
+		return "unknown"
+	}
+}
+
+// taprootAddress derives the P2TR address of a public key that is the
+// internal or output key of the address
+func taprootAddress(pubKey *btcec.PublicKey, key TaprootKey, params *chaincfg.Params) (*btcutil.AddressTaproot, error) {
+	outputKey := pubKey
+	if key != TaprootKeyOutput {
+		outputKey = txscript.ComputeTaprootKeyNoScript(pubKey)
+	}
+	return btcutil.NewAddressTaproot(schnorr.SerializePubKey(outputKey), params)
+}
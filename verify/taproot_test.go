@@ -0,0 +1,55 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+)
+
+func TestWithTaprootKey(t *testing.T) {
+	const message = "taproot key-path proof"
+
+	internalKey := testPrivKey("taproot internal key")
+	outputKey := txscript.TweakTaprootPrivKey(*internalKey, nil)
+
+	addr, err := taprootAddress(internalKey.PubKey(), TaprootKeyInternal, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("taprootAddress() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		signer    *btcec.PrivateKey
+		key       TaprootKey
+		wantValid bool
+	}{
+		{name: "Internal key signature", signer: internalKey, key: TaprootKeyInternal, wantValid: true},
+		{name: "Output key signature", signer: outputKey, key: TaprootKeyOutput, wantValid: true},
+		{name: "Internal key signature verified as output key", signer: internalKey, key: TaprootKeyOutput},
+		{name: "Output key signature verified as internal key", signer: outputKey, key: TaprootKeyInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest := magicHash(message)
+			sig := ecdsa.SignCompact(tt.signer, digest[:], true)
+
+			result, err := NewVerifier(WithTaprootKey(tt.key)).Verify(SignedMessage{
+				Address:   addr.EncodeAddress(),
+				Message:   message,
+				Signature: base64.StdEncoding.EncodeToString(sig),
+			})
+			if result.Valid != tt.wantValid {
+				t.Errorf("Verify() = %v, %v, want %v", result.Valid, err, tt.wantValid)
+			}
+			if !tt.wantValid && !errors.Is(err, ErrAddressMismatch) {
+				t.Errorf("Verify() error = %v, want %v", err, ErrAddressMismatch)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package verify
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timestampLinePrefix starts the line holding the time a message was signed
+// at, which ends the message
+const timestampLinePrefix = "Timestamp: "
+
+// maxClockSkew is how far in the future a message timestamp may be, to allow
+// for the clock of the signer running ahead
+const maxClockSkew = time.Minute
+
+// TimestampMessage appends the time t to the message on a last line of its
+// own, such as "Timestamp: 2025-01-02T15:04:05Z", so the proof made by
+// signing it can expire. The time is written in UTC to the second.
+func TimestampMessage(message string, t time.Time) string {
+	return message + "\n" + timestampLinePrefix + t.UTC().Format(time.RFC3339)
+}
+
+// MessageTimestamp returns the time a message was signed at from its last
+// line, as written by TimestampMessage. Both ISO-8601 times, as in RFC 3339,
+// and Unix times in seconds are accepted, such as "Timestamp: 1735830245".
+// Messages without a valid timestamp line fail with ErrInvalidTimestamp.
+func MessageTimestamp(message string) (time.Time, error) {
+	line := message[strings.LastIndexByte(message, '\n')+1:]
+	value, ok := strings.CutPrefix(strings.TrimRight(line, "\r"), timestampLinePrefix)
+	if !ok {
+		return time.Time{}, newVerifyError(ErrInvalidTimestamp, "message doesn't end with a %q line", strings.TrimSpace(timestampLinePrefix))
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	return time.Time{}, newVerifyError(ErrInvalidTimestamp, "%q is neither an RFC 3339 nor a Unix time", value)
+}
+
+// checkMessageAge checks that the timestamp of the message is at most maxAge
+// before now, and not ahead of now by more than the clock skew allowed
+func checkMessageAge(message string, maxAge time.Duration, now time.Time) error {
+	signedAt, err := MessageTimestamp(message)
+	if err != nil {
+		return err
+	}
+	return checkSignedAt(signedAt, maxAge, now)
+}
+
+// checkSignedAt checks that a message signed at signedAt is at most maxAge
+// old, and not ahead of now by more than the clock skew allowed
+func checkSignedAt(signedAt time.Time, maxAge time.Duration, now time.Time) error {
+	if ahead := signedAt.Sub(now); ahead > maxClockSkew {
+		return newVerifyError(ErrInvalidTimestamp, "message is timestamped %s in the future", ahead.Round(time.Second))
+	}
+	if age := now.Sub(signedAt); age > maxAge {
+		return newVerifyError(ErrMessageExpired, "message was signed %s ago, more than %s", age.Round(time.Second), maxAge)
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestMessageTimestamp(t *testing.T) {
+	signedAt := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		message string
+		want    time.Time
+		wantErr error
+	}{
+		{name: "Timestamped message", message: TimestampMessage("login", signedAt), want: signedAt},
+		{name: "Local time", message: TimestampMessage("login", signedAt.In(time.FixedZone("CET", 3600))), want: signedAt},
+		{name: "Offset", message: "login\nTimestamp: 2025-01-02T16:04:05+01:00", want: signedAt},
+		{name: "Unix time", message: "login\nTimestamp: 1735830245", want: signedAt},
+		{name: "CRLF line endings", message: "login\r\nTimestamp: 2025-01-02T15:04:05Z\r", want: signedAt},
+		{name: "Only a timestamp", message: "Timestamp: 1735830245", want: signedAt},
+		{name: "No timestamp line", message: "login", wantErr: ErrInvalidTimestamp},
+		{name: "Timestamp not last", message: "Timestamp: 1735830245\nlogin", wantErr: ErrInvalidTimestamp},
+		{name: "Malformed timestamp", message: "login\nTimestamp: 2025-01-02", wantErr: ErrInvalidTimestamp},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MessageTimestamp(tt.message)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("MessageTimestamp() error = %v, want %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("MessageTimestamp() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifierWithMaxMessageAge(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name      string
+		message   string
+		wantValid bool
+		wantErr   error
+	}{
+		{name: "Recent message", message: TimestampMessage("login", now.Add(-time.Minute)), wantValid: true},
+		{name: "Expired message", message: TimestampMessage("login", now.Add(-2*time.Hour)), wantErr: ErrMessageExpired},
+		{name: "Slightly ahead", message: TimestampMessage("login", now.Add(30*time.Second)), wantValid: true},
+		{name: "Far in the future", message: TimestampMessage("login", now.Add(time.Hour)), wantErr: ErrInvalidTimestamp},
+		{name: "No timestamp", message: "login", wantErr: ErrInvalidTimestamp},
+	}
+
+	v := NewVerifier(WithMaxMessageAge(time.Hour))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := v.Verify(signTestMessage(t, "alice", nil, tt.message))
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Verifier.Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("Verifier.Verify().Valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package verify
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// Tracer starts the spans of verifications, so verification latency shows
+// up in distributed traces. Implementations must be safe for concurrent use.
+type Tracer interface {
+	// Start starts a span as a child of the span carried by ctx, returning a
+	// context carrying the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer
+type Span interface {
+	// SetAttribute sets an attribute of the span
+	SetAttribute(key string, value interface{})
+
+	// End ends the span, marking it as failed when err isn't nil
+	End(err error)
+}
+
+// Span names of the verification stages
+const (
+	SpanVerify  = "bip137.verify"
+	SpanBatch   = "bip137.batch"
+	SpanDecode  = "bip137.decode"
+	SpanRecover = "bip137.recover"
+	SpanDerive  = "bip137.derive"
+	SpanCompare = "bip137.compare"
+)
+
+// Tracer of the package, nil when none is set
+var tracer atomic.Pointer[Tracer]
+
+// SetTracer sets the tracer that context-aware verifications start spans
+// with: Verifier.VerifyContext, VerifyBatch and
+// VerifyBip137SignatureWithContext. A nil tracer disables tracing, which is
+// the default.
+func SetTracer(t Tracer) {
+	if t == nil {
+		tracer.Store(nil)
+		return
+	}
+	tracer.Store(&t)
+}
+
+// GetTracer returns the tracer of the package, or nil if none is set
+func GetTracer() Tracer {
+	if t := tracer.Load(); t != nil {
+		return *t
+	}
+	return nil
+}
+
+// spanScope starts spans as children of the span carried by its context.
+// The zero value doesn't trace.
+type spanScope struct {
+	ctx    context.Context
+	tracer Tracer
+}
+
+// newSpanScope returns a scope starting spans with t below the span of ctx
+func newSpanScope(ctx context.Context, t Tracer) spanScope {
+	return spanScope{ctx: ctx, tracer: t}
+}
+
+// start starts a span, returning it along with a scope for its children
+func (s spanScope) start(name string) (spanScope, Span) {
+	if s.tracer == nil {
+		return s, noopSpan{}
+	}
+	ctx, span := s.tracer.Start(s.ctx, name)
+	return spanScope{ctx: ctx, tracer: s.tracer}, span
+}
+
+// err returns an error wrapping ErrVerificationTimeout once the context of
+// the scope is done, so the stages of a verification can stop early. The
+// zero value never reports an error.
+func (s spanScope) err() error {
+	if s.ctx == nil {
+		return nil
+	}
+	return checkContext(s.ctx)
+}
+
+// noopSpan is the span of a scope without tracer
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) End(err error)                              {}
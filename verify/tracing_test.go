@@ -0,0 +1,126 @@
+package verify
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestTracingSpans(t *testing.T) {
+	defer SetTracer(GetTracer())
+
+	tv := walletTestVectors[2].msg
+	mismatch := tv
+	mismatch.Address = walletTestVectors[0].msg.Address
+
+	tests := []struct {
+		name   string
+		verify func(ctx context.Context)
+		want   []recordedSpan
+	}{
+		{
+			name: "Verifier",
+			verify: func(ctx context.Context) {
+				NewVerifier().VerifyContext(ctx, mismatch)
+			},
+			want: []recordedSpan{
+				{name: SpanDecode, parent: SpanVerify},
+				{name: SpanRecover, parent: SpanVerify},
+				{name: SpanDerive, parent: SpanVerify},
+				{name: SpanCompare, parent: SpanVerify, failed: true},
+				{name: SpanVerify, parent: "caller", failed: true},
+			},
+		},
+		{
+			name: "Batch",
+			verify: func(ctx context.Context) {
+				VerifyBatch(ctx, []SignedMessage{tv}, WithMaxConcurrency(1))
+			},
+			want: []recordedSpan{
+				{name: SpanDecode, parent: SpanVerify},
+				{name: SpanRecover, parent: SpanVerify},
+				{name: SpanDerive, parent: SpanVerify},
+				{name: SpanCompare, parent: SpanVerify},
+				{name: SpanVerify, parent: SpanBatch},
+				{name: SpanBatch, parent: "caller"},
+			},
+		},
+		{
+			name: "Context",
+			verify: func(ctx context.Context) {
+				VerifyBip137SignatureWithContext(ctx, tv)
+			},
+			want: []recordedSpan{
+				{name: SpanDecode, parent: SpanVerify},
+				{name: SpanRecover, parent: SpanVerify},
+				{name: SpanDerive, parent: SpanVerify},
+				{name: SpanCompare, parent: SpanVerify},
+				{name: SpanVerify, parent: "caller"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recordingTracer{}
+			SetTracer(rec)
+			tt.verify(context.WithValue(context.Background(), spanNameKey{}, "caller"))
+
+			if len(rec.spans) != len(tt.want) {
+				t.Fatalf("recorded %d spans, want %d: %+v", len(rec.spans), len(tt.want), rec.spans)
+			}
+			for i, got := range rec.spans {
+				if got != tt.want[i] {
+					t.Errorf("span %d = %+v, want %+v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestVerifierTracer(t *testing.T) {
+	defer SetTracer(GetTracer())
+
+	global := &recordingTracer{}
+	SetTracer(global)
+
+	own := &recordingTracer{}
+	NewVerifier(WithTracer(own)).Verify(walletTestVectors[2].msg)
+
+	if len(own.spans) == 0 || len(global.spans) != 0 {
+		t.Errorf("verifier recorded %d spans, package recorded %d, want some and 0", len(own.spans), len(global.spans))
+	}
+}
+
+// Helper types to record ended spans along with the name of their parent
+type spanNameKey struct{}
+
+type recordedSpan struct {
+	name   string
+	parent string
+	failed bool
+}
+
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []recordedSpan
+}
+
+func (r *recordingTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(spanNameKey{}).(string)
+	return context.WithValue(ctx, spanNameKey{}, name), &recordingSpan{tracer: r, span: recordedSpan{name: name, parent: parent}}
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	span   recordedSpan
+}
+
+func (s *recordingSpan) SetAttribute(key string, value interface{}) {}
+
+func (s *recordingSpan) End(err error) {
+	s.span.failed = err != nil
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans = append(s.tracer.spans, s.span)
+}
@@ -0,0 +1,70 @@
+package verify
+
+import (
+	"context"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	v1 "github.com/sero/btc/verify"
+)
+
+// The functions below have the signatures of the version 1 functions of the
+// same name, so code can switch its import to version 2 before moving to a
+// Verifier. Unlike in version 1 they don't log or report metrics, as there
+// is no package-level configuration to take them from.
+
+// SignedMessage is a message with its address and base64-encoded signature,
+// as taken by the version 1 functions
+type SignedMessage = v1.SignedMessage
+
+// VerifyBip137Signature verifies a signature of the message by the address
+// on mainnet.
+//
+// Deprecated: use Verifier.Verify.
+func VerifyBip137Signature(address, message, signatureBase64 string) (bool, error) {
+	return VerifyBip137SignatureWithContext(context.Background(), SignedMessage{Address: address, Message: message, Signature: signatureBase64})
+}
+
+// VerifyBip137SignatureWithParams is like VerifyBip137Signature, on the
+// network of params.
+//
+// Deprecated: use Verifier.Verify with WithNetwork.
+func VerifyBip137SignatureWithParams(address, message, signatureBase64 string, params *chaincfg.Params) (bool, error) {
+	network, err := networkOf(params)
+	if err != nil {
+		return false, err
+	}
+	return verifyCompat(context.Background(), NewVerifier(WithNetwork(network)), address, message, signatureBase64)
+}
+
+// VerifyBip137SignatureWithContext verifies a signed message on mainnet.
+//
+// Deprecated: use Verifier.Verify.
+func VerifyBip137SignatureWithContext(ctx context.Context, msg SignedMessage) (bool, error) {
+	return verifyCompat(ctx, NewVerifier(), msg.Address, msg.Message, msg.Signature)
+}
+
+// VerifyBip137SignatureWithPubKey verifies a compact signature of the
+// message by the public key on mainnet.
+//
+// Deprecated: use Verifier.VerifyPubKey.
+func VerifyBip137SignatureWithPubKey(pubKey *btcec.PublicKey, message, signatureBase64 string) (bool, error) {
+	sig, err := ParseSignature(signatureBase64)
+	if err != nil {
+		return false, err
+	}
+	result, err := NewVerifier().VerifyPubKey(context.Background(), pubKey, message, sig)
+	return result.Valid, err
+}
+
+// verifyCompat verifies a signature given in base64 with v. The signature is
+// decoded by the engine, so inputs are validated in the order of version 1.
+func verifyCompat(ctx context.Context, v *Verifier, address, message, signatureBase64 string) (bool, error) {
+	result, err := v.inner.VerifyContext(ctx, SignedMessage{Address: address, Message: message, Signature: signatureBase64})
+	return result.Valid, err
+}
+
+// networkOf returns the Network with the parameters
+func networkOf(params *chaincfg.Params) (Network, error) {
+	return ParseNetwork(params.Name)
+}
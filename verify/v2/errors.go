@@ -0,0 +1,111 @@
+package verify
+
+import (
+	v1 "github.com/sero/btc/verify"
+)
+
+// Errors of verification, the same values as in version 1, so errors.Is
+// works across both versions
+var (
+	ErrVerificationTimeout        = v1.ErrVerificationTimeout
+	ErrInvalidSignature           = v1.ErrInvalidSignature
+	ErrEmptyAddress               = v1.ErrEmptyAddress
+	ErrEmptyMessage               = v1.ErrEmptyMessage
+	ErrEmptySignature             = v1.ErrEmptySignature
+	ErrInvalidAddress             = v1.ErrInvalidAddress
+	ErrUnsupportedAddressType     = v1.ErrUnsupportedAddressType
+	ErrAddressMismatch            = v1.ErrAddressMismatch
+	ErrInvalidHeaderByte          = v1.ErrInvalidHeaderByte
+	ErrMalformedSignature         = v1.ErrMalformedSignature
+	ErrNetworkMismatch            = v1.ErrNetworkMismatch
+	ErrHighS                      = v1.ErrHighS
+	ErrInvalidArmor               = v1.ErrInvalidArmor
+	ErrMalformedContainer         = v1.ErrMalformedContainer
+	ErrMessageTooLarge            = v1.ErrMessageTooLarge
+	ErrEmptyPublicKey             = v1.ErrEmptyPublicKey
+	ErrContainerClosed            = v1.ErrContainerClosed
+	ErrHeaderAddressMismatch      = v1.ErrHeaderAddressMismatch
+	ErrSelfCheckFailed            = v1.ErrSelfCheckFailed
+	ErrEngineDisagreement         = v1.ErrEngineDisagreement
+	ErrMalformedJSON              = v1.ErrMalformedJSON
+	ErrMalformedDetachedSignature = v1.ErrMalformedDetachedSignature
+	ErrDigestMismatch             = v1.ErrDigestMismatch
+	ErrMalformedQRPayload         = v1.ErrMalformedQRPayload
+	ErrMalformedBundle            = v1.ErrMalformedBundle
+	ErrConversionUnsupported      = v1.ErrConversionUnsupported
+	ErrInvalidExtendedKey         = v1.ErrInvalidExtendedKey
+	ErrInvalidDescriptor          = v1.ErrInvalidDescriptor
+	ErrInvalidDerivationPath      = v1.ErrInvalidDerivationPath
+	ErrInvalidPublicKey           = v1.ErrInvalidPublicKey
+	ErrInvalidKeyringEntry        = v1.ErrInvalidKeyringEntry
+	ErrUntrustedSigner            = v1.ErrUntrustedSigner
+	ErrMalformedKeyring           = v1.ErrMalformedKeyring
+	ErrInvalidMultisigPolicy      = v1.ErrInvalidMultisigPolicy
+	ErrPolicyViolation            = v1.ErrPolicyViolation
+	ErrAddressBlocked             = v1.ErrAddressBlocked
+	ErrInvalidTimestamp           = v1.ErrInvalidTimestamp
+	ErrMessageExpired             = v1.ErrMessageExpired
+	ErrQueueFull                  = v1.ErrQueueFull
+	ErrQueueStopped               = v1.ErrQueueStopped
+)
+
+// ErrorCode is a stable, machine-readable identifier of a verification
+// failure
+type ErrorCode = v1.ErrorCode
+
+// Error codes of the verification errors
+const (
+	CodeUnknown                    = v1.CodeUnknown
+	CodeVerificationTimeout        = v1.CodeVerificationTimeout
+	CodeInvalidSignature           = v1.CodeInvalidSignature
+	CodeEmptyAddress               = v1.CodeEmptyAddress
+	CodeEmptyMessage               = v1.CodeEmptyMessage
+	CodeEmptySignature             = v1.CodeEmptySignature
+	CodeInvalidAddress             = v1.CodeInvalidAddress
+	CodeUnsupportedAddressType     = v1.CodeUnsupportedAddressType
+	CodeAddressMismatch            = v1.CodeAddressMismatch
+	CodeInvalidHeaderByte          = v1.CodeInvalidHeaderByte
+	CodeMalformedSignature         = v1.CodeMalformedSignature
+	CodeNetworkMismatch            = v1.CodeNetworkMismatch
+	CodeHighS                      = v1.CodeHighS
+	CodeInvalidArmor               = v1.CodeInvalidArmor
+	CodeMalformedContainer         = v1.CodeMalformedContainer
+	CodeMessageTooLarge            = v1.CodeMessageTooLarge
+	CodeEmptyPublicKey             = v1.CodeEmptyPublicKey
+	CodeContainerClosed            = v1.CodeContainerClosed
+	CodeHeaderAddressMismatch      = v1.CodeHeaderAddressMismatch
+	CodeSelfCheckFailed            = v1.CodeSelfCheckFailed
+	CodeEngineDisagreement         = v1.CodeEngineDisagreement
+	CodeMalformedJSON              = v1.CodeMalformedJSON
+	CodeMalformedDetachedSignature = v1.CodeMalformedDetachedSignature
+	CodeDigestMismatch             = v1.CodeDigestMismatch
+	CodeMalformedQRPayload         = v1.CodeMalformedQRPayload
+	CodeMalformedBundle            = v1.CodeMalformedBundle
+	CodeConversionUnsupported      = v1.CodeConversionUnsupported
+	CodeInvalidExtendedKey         = v1.CodeInvalidExtendedKey
+	CodeInvalidDescriptor          = v1.CodeInvalidDescriptor
+	CodeInvalidDerivationPath      = v1.CodeInvalidDerivationPath
+	CodeInvalidPublicKey           = v1.CodeInvalidPublicKey
+	CodeInvalidKeyringEntry        = v1.CodeInvalidKeyringEntry
+	CodeUntrustedSigner            = v1.CodeUntrustedSigner
+	CodeMalformedKeyring           = v1.CodeMalformedKeyring
+	CodeInvalidMultisigPolicy      = v1.CodeInvalidMultisigPolicy
+	CodePolicyViolation            = v1.CodePolicyViolation
+	CodeAddressBlocked             = v1.CodeAddressBlocked
+	CodeInvalidTimestamp           = v1.CodeInvalidTimestamp
+	CodeMessageExpired             = v1.CodeMessageExpired
+	CodeQueueFull                  = v1.CodeQueueFull
+	CodeQueueStopped               = v1.CodeQueueStopped
+)
+
+// VerifyError is a verification failure carrying its error code
+type VerifyError = v1.VerifyError
+
+// DefaultMaxMessageSize is the default maximum length of a message in bytes
+const DefaultMaxMessageSize = v1.DefaultMaxMessageSize
+
+// ErrorCodeOf returns the error code of a verification error, or CodeUnknown
+// for errors that don't come from verification
+func ErrorCodeOf(err error) ErrorCode {
+	return v1.ErrorCodeOf(err)
+}
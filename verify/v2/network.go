@@ -0,0 +1,65 @@
+package verify
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// Network is a Bitcoin network. The zero value is Mainnet.
+type Network int
+
+// Networks addresses can belong to
+const (
+	Mainnet Network = iota
+	Testnet
+	Regtest
+	Signet
+)
+
+// Params returns the parameters of the network
+func (n Network) Params() *chaincfg.Params {
+	switch n {
+	case Testnet:
+		return &chaincfg.TestNet3Params
+	case Regtest:
+		return &chaincfg.RegressionNetParams
+	case Signet:
+		return &chaincfg.SigNetParams
+	default:
+		return &chaincfg.MainNetParams
+	}
+}
+
+// String returns the name of the network, as accepted by ParseNetwork
+func (n Network) String() string {
+	switch n {
+	case Mainnet:
+		return "mainnet"
+	case Testnet:
+		return "testnet"
+	case Regtest:
+		return "regtest"
+	case Signet:
+		return "signet"
+	default:
+		return fmt.Sprintf("Network(%d)", int(n))
+	}
+}
+
+// ParseNetwork returns the network with the name: mainnet, testnet (or
+// testnet3), regtest or signet
+func ParseNetwork(name string) (Network, error) {
+	switch name {
+	case "mainnet":
+		return Mainnet, nil
+	case "testnet", "testnet3":
+		return Testnet, nil
+	case "regtest":
+		return Regtest, nil
+	case "signet":
+		return Signet, nil
+	default:
+		return 0, fmt.Errorf("unknown network %q", name)
+	}
+}
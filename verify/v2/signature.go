@@ -0,0 +1,31 @@
+package verify
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Signature is a decoded signature: a 65-byte BIP-0137 compact signature or
+// a BIP-322 witness
+type Signature []byte
+
+// ParseSignature decodes a base64-encoded signature, as produced by wallets.
+// Surrounding whitespace is ignored. Signatures that aren't valid base64
+// fail with ErrMalformedSignature, and empty ones with ErrEmptySignature.
+func ParseSignature(signatureBase64 string) (Signature, error) {
+	s := strings.TrimSpace(signatureBase64)
+	if s == "" {
+		return nil, ErrEmptySignature
+	}
+	sig, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid base64 signature: %v", ErrMalformedSignature, err)
+	}
+	return sig, nil
+}
+
+// String returns the signature in standard base64
+func (s Signature) String() string {
+	return base64.StdEncoding.EncodeToString(s)
+}
@@ -0,0 +1,361 @@
+// Package verify is version 2 of the BIP-0137 verification API. It has no
+// package-level state: logging, metrics and tracing are configured per
+// Verifier and are off unless set, so libraries embedding a verifier can't
+// affect each other. Every kind of verification is a method of the Verifier,
+// taking typed inputs:
+//
+//	sig, err := verify.ParseSignature(signatureBase64)
+//	v := verify.NewVerifier(verify.WithNetwork(verify.Testnet))
+//	result, err := v.Verify(ctx, address, message, sig)
+//
+// Verification is done by the engine of version 1, so both versions accept
+// and reject the same signatures, with the same error codes. Code using the
+// version 1 package-level functions can switch its import first using the
+// deprecated functions of compat.go, and move to a Verifier afterwards.
+package verify
+
+import (
+	"context"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/chaincfg"
+	v1 "github.com/sero/btc/verify"
+	"golang.org/x/time/rate"
+)
+
+// Types shared with version 1
+type (
+	// Logger receives the log messages of a Verifier
+	Logger = v1.Logger
+
+	// LogLevel determines the verbosity of logging
+	LogLevel = v1.LogLevel
+
+	// Metrics receives measurements of verifications
+	Metrics = v1.Metrics
+
+	// Tracer starts the spans of verifications
+	Tracer = v1.Tracer
+
+	// Span is a span started by a Tracer
+	Span = v1.Span
+
+	// AddressType identifies the kind of an address
+	AddressType = v1.AddressType
+
+	// LineEndings selects how line endings of multi-line messages are
+	// treated
+	LineEndings = v1.LineEndings
+
+	// Policy describes which signed messages are accepted beyond a valid
+	// signature
+	Policy = v1.Policy
+
+	// PolicyViolation is a rule of a Policy that a signed message breaks
+	PolicyViolation = v1.PolicyViolation
+
+	// AddressFilter decides which addresses proofs are accepted from
+	AddressFilter = v1.AddressFilter
+
+	// Recovery is the public key and address that signed a message
+	Recovery = v1.Recovery
+)
+
+// Log levels, from quietest to most verbose
+const (
+	LogLevelNone    = v1.LogLevelNone
+	LogLevelError   = v1.LogLevelError
+	LogLevelWarning = v1.LogLevelWarning
+	LogLevelInfo    = v1.LogLevelInfo
+	LogLevelDebug   = v1.LogLevelDebug
+	LogLevelTrace   = v1.LogLevelTrace
+)
+
+// Address types
+const (
+	AddressTypeP2PKH      = v1.AddressTypeP2PKH
+	AddressTypeP2SHP2WPKH = v1.AddressTypeP2SHP2WPKH
+	AddressTypeP2WPKH     = v1.AddressTypeP2WPKH
+	AddressTypeP2TR       = v1.AddressTypeP2TR
+)
+
+// Line ending modes
+const (
+	LineEndingsExact = v1.LineEndingsExact
+	LineEndingsLF    = v1.LineEndingsLF
+	LineEndingsCRLF  = v1.LineEndingsCRLF
+	LineEndingsAny   = v1.LineEndingsAny
+)
+
+// Verifier verifies signed messages. The zero value isn't usable; create one
+// with NewVerifier. A Verifier is safe for concurrent use.
+type Verifier struct {
+	network Network
+	inner   *v1.Verifier
+}
+
+// Result is the outcome of a verification
+type Result struct {
+	// Valid reports whether the signature is valid and passed every enabled
+	// check
+	Valid bool
+
+	// LowS reports whether the S value of a compact signature is in the
+	// lower half of the curve order
+	LowS bool
+
+	// LineEndings is the line ending convention the message verified with
+	LineEndings LineEndings
+
+	// Violations are the rules of the policy the message breaks
+	Violations []PolicyViolation
+}
+
+// Option configures a Verifier
+type Option func(*config)
+
+// config collects the options of a Verifier
+type config struct {
+	network Network
+	logger  Logger
+	level   LogLevel
+	metrics Metrics
+	tracer  Tracer
+	opts    []v1.Option
+}
+
+// NewVerifier creates a Verifier. Without options it verifies mainnet
+// signatures, accepts both low and high S values, and neither logs nor
+// reports metrics or spans.
+func NewVerifier(opts ...Option) *Verifier {
+	cfg := config{logger: discardLogger{}, level: LogLevelNone, metrics: noopMetrics{}, tracer: noopTracer{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	inner := append([]v1.Option{
+		v1.WithParams(cfg.network.Params()),
+		v1.WithLogger(cfg.logger),
+		v1.WithLogLevel(cfg.level),
+		v1.WithMetrics(cfg.metrics),
+		v1.WithTracer(cfg.tracer),
+		// Version 1 falls back to its package-level limit otherwise
+		v1.WithMaxMessageSize(v1.DefaultMaxMessageSize),
+	}, cfg.opts...)
+	return &Verifier{network: cfg.network, inner: v1.NewVerifier(inner...)}
+}
+
+// WithNetwork sets the network addresses belong to, Mainnet by default
+func WithNetwork(network Network) Option {
+	return func(c *config) {
+		c.network = network
+	}
+}
+
+// WithLogger makes the verifier log messages up to level to l
+func WithLogger(l Logger, level LogLevel) Option {
+	return func(c *config) {
+		c.logger = l
+		c.level = level
+	}
+}
+
+// WithMetrics makes the verifier report its verifications to m
+func WithMetrics(m Metrics) Option {
+	return func(c *config) {
+		c.metrics = m
+	}
+}
+
+// WithTracer makes the verifier start spans with t
+func WithTracer(t Tracer) Option {
+	return func(c *config) {
+		c.tracer = t
+	}
+}
+
+// WithRequireLowS rejects signatures with a high S value with ErrHighS
+func WithRequireLowS() Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithRequireLowS())
+	}
+}
+
+// WithStrictLength only accepts 65-byte compact signatures
+func WithStrictLength() Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithStrictLength())
+	}
+}
+
+// WithStrictHeader rejects compact signatures whose header byte is for
+// another address type than the address
+func WithStrictHeader() Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithStrictHeader())
+	}
+}
+
+// WithLineEndings sets how line endings of multi-line messages are treated
+func WithLineEndings(mode LineEndings) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithLineEndings(mode))
+	}
+}
+
+// WithMaxMessageSize sets the maximum message length in bytes, 0 for no
+// limit. It defaults to DefaultMaxMessageSize.
+func WithMaxMessageSize(size int64) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithMaxMessageSize(size))
+	}
+}
+
+// WithMaxMessageAge rejects messages timestamped more than maxAge ago
+func WithMaxMessageAge(maxAge time.Duration) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithMaxMessageAge(maxAge))
+	}
+}
+
+// WithPolicy checks signed messages against the policy before verifying
+// their signature
+func WithPolicy(policy Policy) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithPolicy(policy))
+	}
+}
+
+// WithAddressFilter rejects proofs from addresses the filter blocks
+func WithAddressFilter(filter AddressFilter) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithAddressFilter(filter))
+	}
+}
+
+// WithDefaultTimeout bounds the wall time of each verification, whatever
+// the deadline of the caller's context
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithDefaultTimeout(timeout))
+	}
+}
+
+// WithRateLimit caps the verifications of the verifier at limit per second,
+// with bursts of up to burst
+func WithRateLimit(limit rate.Limit, burst int) Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithRateLimit(limit, burst))
+	}
+}
+
+// WithDeduplication collapses identical concurrent verifications into one
+func WithDeduplication() Option {
+	return func(c *config) {
+		c.opts = append(c.opts, v1.WithDeduplication())
+	}
+}
+
+// Network returns the network of the verifier
+func (v *Verifier) Network() Network {
+	return v.network
+}
+
+// Verify verifies a signature of the message by the address. BIP-0137
+// compact signatures are accepted for every address type, and BIP-322
+// signatures for SegWit and Taproot addresses unless WithStrictLength is
+// set. When verification fails the error explains why.
+func (v *Verifier) Verify(ctx context.Context, address, message string, sig Signature) (Result, error) {
+	res, err := v.inner.VerifyContext(ctx, v1.SignedMessage{
+		Address:   address,
+		Message:   message,
+		Signature: sig.String(),
+	})
+	return Result{
+		Valid:       res.Valid,
+		LowS:        res.LowS,
+		LineEndings: res.LineEndings,
+		Violations:  res.Violations,
+	}, err
+}
+
+// VerifyPubKey verifies a compact signature of the message by the public
+// key, for the address type its header byte stands for. P2TR signatures
+// can't be verified this way, as their header byte doesn't tell them apart
+// from P2PKH ones; verify them against their address instead.
+func (v *Verifier) VerifyPubKey(ctx context.Context, pubKey *btcec.PublicKey, message string, sig Signature) (Result, error) {
+	if pubKey == nil {
+		return Result{}, ErrEmptyPublicKey
+	}
+	compact, err := v1.DecodeCompactSignature(sig.String())
+	if err != nil {
+		return Result{}, err
+	}
+	address, err := pubKeyAddress(pubKey, compact, v.network.Params())
+	if err != nil {
+		return Result{}, err
+	}
+	return v.Verify(ctx, address, message, sig)
+}
+
+// Recover recovers the public key and address that signed the message with
+// a compact signature. Any well-formed signature recovers some key, so the
+// result only identifies the signer when the address is known to be
+// expected.
+func (v *Verifier) Recover(message string, sig Signature) (Recovery, error) {
+	return v.inner.Recover(message, sig.String())
+}
+
+// pubKeyAddress returns the address of the public key that a compact
+// signature with the header would verify against
+func pubKeyAddress(pubKey *btcec.PublicKey, compact v1.CompactSignature, params *chaincfg.Params) (string, error) {
+	serialized := pubKey.SerializeCompressed()
+	if !compact.Compressed {
+		serialized = pubKey.SerializeUncompressed()
+	}
+	hash := btcutil.Hash160(serialized)
+
+	var addr btcutil.Address
+	var err error
+	switch compact.AddressType {
+	case AddressTypeP2PKH:
+		addr, err = btcutil.NewAddressPubKeyHash(hash, params)
+	case AddressTypeP2SHP2WPKH:
+		script := append([]byte{0x00, 0x14}, hash...)
+		addr, err = btcutil.NewAddressScriptHash(script, params)
+	case AddressTypeP2WPKH:
+		addr, err = btcutil.NewAddressWitnessPubKeyHash(hash, params)
+	default:
+		return "", ErrInvalidHeaderByte
+	}
+	if err != nil {
+		return "", err
+	}
+	return addr.EncodeAddress(), nil
+}
+
+// discardLogger drops every message
+type discardLogger struct{}
+
+func (discardLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {}
+
+// noopMetrics drops every measurement
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveVerification(valid bool, reason ErrorCode, duration time.Duration) {}
+func (noopMetrics) ObserveHashCache(hits, misses int)                                        {}
+
+// noopTracer starts spans that record nothing
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan records nothing
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(key string, value interface{}) {}
+func (noopSpan) End(err error)                              {}
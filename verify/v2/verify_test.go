@@ -0,0 +1,230 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	v1 "github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/signer"
+)
+
+// Compressed private key of the Bitcoin wiki WIF example
+const testWIF = "KwdMAjGmerYanjeui5SHS7JkmpZvVipYvB2LJGU1ZxJwYvP98617"
+
+func TestVerifierVerify(t *testing.T) {
+	ctx := context.Background()
+	key, err := signer.ImportWIF(testWIF, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, addrType := range []AddressType{AddressTypeP2PKH, AddressTypeP2SHP2WPKH, AddressTypeP2WPKH} {
+		t.Run(string(addrType), func(t *testing.T) {
+			msg, err := key.Sign("hello v2", addrType)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sig, err := ParseSignature(msg.Signature)
+			if err != nil {
+				t.Fatalf("ParseSignature() error = %v", err)
+			}
+
+			v := NewVerifier()
+			if result, err := v.Verify(ctx, msg.Address, msg.Message, sig); err != nil || !result.Valid {
+				t.Errorf("Verify() = %+v, %v, want valid", result, err)
+			}
+			if result, err := v.VerifyPubKey(ctx, key.PubKey(), msg.Message, sig); err != nil || !result.Valid {
+				t.Errorf("VerifyPubKey() = %+v, %v, want valid", result, err)
+			}
+			if _, err := v.Verify(ctx, msg.Address, "edited", sig); !errors.Is(err, ErrAddressMismatch) {
+				t.Errorf("Verify() of another message error = %v, want %v", err, ErrAddressMismatch)
+			}
+			if _, err := NewVerifier(WithNetwork(Testnet)).Verify(ctx, msg.Address, msg.Message, sig); !errors.Is(err, ErrNetworkMismatch) {
+				t.Errorf("Verify() on testnet error = %v, want %v", err, ErrNetworkMismatch)
+			}
+
+			rec, err := v.Recover(msg.Message, sig)
+			if err != nil || rec.Address != msg.Address {
+				t.Errorf("Recover() = %+v, %v, want %s", rec, err, msg.Address)
+			}
+		})
+	}
+}
+
+func TestVerifierOptions(t *testing.T) {
+	ctx := context.Background()
+	key, _ := signer.ImportWIF(testWIF, &chaincfg.MainNetParams)
+	msg, _ := key.Sign(v1.TimestampMessage("login", time.Now().Add(-time.Hour)), AddressTypeP2WPKH)
+	sig, _ := ParseSignature(msg.Signature)
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		wantErr error
+	}{
+		{name: "Defaults"},
+		{name: "Max message size", opts: []Option{WithMaxMessageSize(8)}, wantErr: ErrMessageTooLarge},
+		{name: "Max message age", opts: []Option{WithMaxMessageAge(time.Minute)}, wantErr: ErrMessageExpired},
+		{name: "Address filter", opts: []Option{WithAddressFilter(AddressFilter{Deny: []string{"bc1q*"}})}, wantErr: ErrAddressBlocked},
+		{name: "Policy", opts: []Option{WithPolicy(Policy{AllowedAddressTypes: []AddressType{AddressTypeP2PKH}})}, wantErr: ErrPolicyViolation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewVerifier(tt.opts...).Verify(ctx, msg.Address, msg.Message, sig)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if result.Valid != (tt.wantErr == nil) {
+				t.Errorf("Verify().Valid = %v, want %v", result.Valid, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+func TestNoGlobalState(t *testing.T) {
+	key, _ := signer.ImportWIF(testWIF, &chaincfg.MainNetParams)
+	msg, _ := key.Sign("no globals here", AddressTypeP2PKH)
+	sig, _ := ParseSignature(msg.Signature)
+
+	// Package-level configuration of version 1 must not leak into version 2
+	global := &recordingLogger{}
+	defer v1.SetLogger(v1.GetLogger())
+	defer v1.SetLogLevel(v1.GetLogLevel())
+	defer v1.SetMaxMessageSize(v1.MaxMessageSize())
+	v1.SetLogger(global)
+	v1.SetLogLevel(LogLevelTrace)
+	v1.SetMaxMessageSize(1)
+
+	own := &recordingLogger{}
+	v := NewVerifier(WithLogger(own, LogLevelDebug))
+	if result, err := v.Verify(context.Background(), msg.Address, msg.Message, sig); err != nil || !result.Valid {
+		t.Fatalf("Verify() = %+v, %v, want valid despite the version 1 message size limit", result, err)
+	}
+	if rec, err := v.Recover(msg.Message, sig); err != nil || rec.Address != msg.Address {
+		t.Fatalf("Recover() = %+v, %v, want %s despite the version 1 message size limit", rec, err, msg.Address)
+	}
+	if _, err := NewVerifier(WithMaxMessageSize(4)).Recover(msg.Message, sig); !errors.Is(err, ErrMessageTooLarge) {
+		t.Errorf("Recover() with a 4-byte limit error = %v, want %v", err, ErrMessageTooLarge)
+	}
+	if len(global.lines) != 0 {
+		t.Errorf("version 1 logger got %q, want nothing", global.lines)
+	}
+	if len(own.lines) == 0 {
+		t.Error("verifier logger got nothing, want the verification logged")
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantLen int
+		wantErr error
+	}{
+		{name: "Compact signature", in: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=", wantLen: 65},
+		{name: "Surrounding whitespace", in: " AAAA\n", wantLen: 3},
+		{name: "Empty", in: "  ", wantErr: ErrEmptySignature},
+		{name: "Not base64", in: "not base64!", wantErr: ErrMalformedSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig, err := ParseSignature(tt.in)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil && err != nil) {
+				t.Fatalf("ParseSignature() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(sig) != tt.wantLen {
+				t.Errorf("ParseSignature() = %d bytes, want %d", len(sig), tt.wantLen)
+			}
+			if tt.wantErr != nil && ErrorCodeOf(err) != CodeMalformedSignature && ErrorCodeOf(err) != CodeEmptySignature {
+				t.Errorf("ErrorCodeOf() = %v, want a signature error code", ErrorCodeOf(err))
+			}
+		})
+	}
+}
+
+func TestParseNetwork(t *testing.T) {
+	for _, network := range []Network{Mainnet, Testnet, Regtest, Signet} {
+		got, err := ParseNetwork(network.String())
+		if err != nil || got != network {
+			t.Errorf("ParseNetwork(%q) = %v, %v, want %v", network, got, err, network)
+		}
+	}
+	if got, _ := ParseNetwork(chaincfg.TestNet3Params.Name); got != Testnet {
+		t.Errorf("ParseNetwork(%q) = %v, want %v", chaincfg.TestNet3Params.Name, got, Testnet)
+	}
+	if _, err := ParseNetwork("litecoin"); err == nil {
+		t.Error("ParseNetwork(\"litecoin\") error = nil, want an error")
+	}
+}
+
+func TestCompat(t *testing.T) {
+	key, _ := signer.ImportWIF(testWIF, &chaincfg.MainNetParams)
+	msg, _ := key.Sign("migrating", AddressTypeP2WPKH)
+
+	tests := []struct {
+		name string
+		call func() (bool, error)
+		v1   func() (bool, error)
+	}{
+		{
+			name: "VerifyBip137Signature",
+			call: func() (bool, error) { return VerifyBip137Signature(msg.Address, msg.Message, msg.Signature) },
+			v1:   func() (bool, error) { return v1.VerifyBip137Signature(msg.Address, msg.Message, msg.Signature) },
+		},
+		{
+			name: "VerifyBip137SignatureWithParams on another network",
+			call: func() (bool, error) {
+				return VerifyBip137SignatureWithParams(msg.Address, msg.Message, msg.Signature, &chaincfg.SigNetParams)
+			},
+			v1: func() (bool, error) {
+				return v1.VerifyBip137SignatureWithParams(msg.Address, msg.Message, msg.Signature, &chaincfg.SigNetParams)
+			},
+		},
+		{
+			name: "VerifyBip137SignatureWithContext without address",
+			call: func() (bool, error) {
+				return VerifyBip137SignatureWithContext(context.Background(), SignedMessage{Message: msg.Message})
+			},
+			v1: func() (bool, error) {
+				return v1.VerifyBip137SignatureWithContext(context.Background(), SignedMessage{Message: msg.Message})
+			},
+		},
+		{
+			name: "VerifyBip137SignatureWithPubKey",
+			call: func() (bool, error) { return VerifyBip137SignatureWithPubKey(key.PubKey(), msg.Message, msg.Signature) },
+			v1: func() (bool, error) {
+				return v1.VerifyBip137SignatureWithPubKey(key.PubKey(), msg.Message, msg.Signature)
+			},
+		},
+	}
+
+	v1.SetLogLevel(LogLevelNone)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.call()
+			want, wantErr := tt.v1()
+			if got != want || ErrorCodeOf(err) != ErrorCodeOf(wantErr) || (err == nil) != (wantErr == nil) {
+				t.Errorf("got %v, %v, version 1 returns %v, %v", got, err, want, wantErr)
+			}
+		})
+	}
+}
+
+// Helper type to record logged messages
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingLogger) Log(level LogLevel, msg string, keyvals ...interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lines = append(r.lines, strings.TrimSpace(level.String()+" "+msg))
+}
@@ -0,0 +1,130 @@
+// Package vectors is a corpus of valid signed messages produced by real
+// wallets and signing libraries, for testing verifiers against the quirks of
+// each of them:
+//
+//	corpus, err := vectors.Corpus()
+//	for _, v := range vectors.ByWallet(corpus, vectors.WalletTrezor) {
+//		...
+//	}
+//
+// The corpus lives in testdata/wallets.json and is embedded in the package.
+// Every vector names the wallet that produced it and where it was published.
+// Most vectors are taken from the BitonicNL verify-signed-message test suite,
+// the others from Bitcoin Core and bitcoinjs-message tests. Ledger and
+// Sparrow are not covered, as no signatures of theirs with a verifiable
+// source have been collected; add them with their source once there are.
+package vectors
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Wallets with vectors in the corpus
+const (
+	WalletBitcoinCore      = "Bitcoin Core"
+	WalletElectrum         = "Electrum"
+	WalletTrezor           = "Trezor"
+	WalletMycelium         = "Mycelium"
+	WalletUniSat           = "UniSat"
+	WalletBMS              = "BMS"
+	WalletBitcoinJSMessage = "bitcoinjs-message"
+)
+
+// corpus is the embedded corpus returned by Corpus
+//
+//go:embed testdata/wallets.json
+var corpus []byte
+
+// Vector is a signed message produced by a wallet
+type Vector struct {
+	// Name describes the vector, unique within the corpus
+	Name string `json:"name"`
+
+	// Wallet is the wallet or library that produced the signature
+	Wallet string `json:"wallet"`
+
+	// Network is the chaincfg name of the network of the address, such as
+	// "mainnet" or "testnet3"
+	Network string `json:"network"`
+
+	// AddressType is the type of the address, as named by
+	// verify.AddressType
+	AddressType string `json:"address_type"`
+
+	Address   string `json:"address"`
+	Message   string `json:"message"`
+	Signature string `json:"signature"`
+
+	// Source is where the vector was published
+	Source string `json:"source"`
+}
+
+// Corpus returns the vectors of the embedded corpus
+func Corpus() ([]Vector, error) {
+	return decode(corpus)
+}
+
+// Load reads a corpus in the format of testdata/wallets.json, a JSON array
+// of vectors
+func Load(r io.Reader) ([]Vector, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data)
+}
+
+// LoadFile reads a corpus from the file at path
+func LoadFile(path string) ([]Vector, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
+
+// ByWallet returns the vectors produced by the wallet
+func ByWallet(vectors []Vector, wallet string) []Vector {
+	var matched []Vector
+	for _, v := range vectors {
+		if v.Wallet == wallet {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
+
+// ByAddressType returns the vectors for addresses of the type
+func ByAddressType(vectors []Vector, addrType string) []Vector {
+	var matched []Vector
+	for _, v := range vectors {
+		if v.AddressType == addrType {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
+
+// decode parses a corpus and checks that every vector is complete
+func decode(data []byte) ([]Vector, error) {
+	var vectors []Vector
+	if err := json.Unmarshal(data, &vectors); err != nil {
+		return nil, fmt.Errorf("decoding corpus: %w", err)
+	}
+	names := make(map[string]bool, len(vectors))
+	for i, v := range vectors {
+		if v.Name == "" || v.Wallet == "" || v.Network == "" || v.Address == "" || v.Message == "" || v.Signature == "" {
+			return nil, fmt.Errorf("vector %d: missing field", i)
+		}
+		if names[v.Name] {
+			return nil, fmt.Errorf("vector %d: duplicate name %q", i, v.Name)
+		}
+		names[v.Name] = true
+	}
+	return vectors, nil
+}
@@ -0,0 +1,70 @@
+package vectors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCorpus(t *testing.T) {
+	corpus, err := Corpus()
+	if err != nil {
+		t.Fatalf("Corpus() error = %v", err)
+	}
+
+	for _, wallet := range []string{WalletBitcoinCore, WalletElectrum, WalletTrezor, WalletMycelium, WalletUniSat, WalletBMS, WalletBitcoinJSMessage} {
+		if len(ByWallet(corpus, wallet)) == 0 {
+			t.Errorf("ByWallet(%q) is empty", wallet)
+		}
+	}
+	for _, addrType := range []string{"p2pkh", "p2sh-p2wpkh", "p2wpkh", "p2tr"} {
+		if len(ByAddressType(corpus, addrType)) == 0 {
+			t.Errorf("ByAddressType(%q) is empty", addrType)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "Valid corpus",
+			data: `[{"name":"a","wallet":"w","network":"mainnet","address":"1A","message":"m","signature":"s"}]`,
+			want: 1,
+		},
+		{
+			name: "Empty corpus",
+			data: `[]`,
+		},
+		{
+			name:    "Missing signature",
+			data:    `[{"name":"a","wallet":"w","network":"mainnet","address":"1A","message":"m"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Duplicate name",
+			data:    `[{"name":"a","wallet":"w","network":"mainnet","address":"1A","message":"m","signature":"s"},{"name":"a","wallet":"w","network":"mainnet","address":"1B","message":"m","signature":"s"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "Not JSON",
+			data:    `name: a`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Load(strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("Load() returned %d vectors, want %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,652 @@
+package verify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	verifier "github.com/bitonicnl/verify-signed-message/pkg"
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"golang.org/x/time/rate"
+)
+
+// Verifier verifies BIP-0137 signatures with configurable network and
+// strictness. Compact 65-byte signatures are verified by the native
+// verification engine, which also backs VerifyBatch and Explain; other
+// signatures are handed to the BitonicNL verifier like the package-level
+// functions do. A Verifier is safe for concurrent use.
+type Verifier struct {
+	params       *chaincfg.Params
+	requireLowS  bool
+	strictLength bool
+	base64Mode   Base64Mode
+	lineEndings  LineEndings
+	strictHeader bool
+	crossCheck   bool
+	debugTrace   bool
+	taprootKey   TaprootKey
+
+	// addressFilter rejects addresses before any other check when set
+	addressFilter *AddressFilter
+
+	// maxMessageAge rejects messages timestamped longer ago when set
+	maxMessageAge time.Duration
+
+	// policy is checked before verifying signatures when set
+	policy *Policy
+
+	// hashScheme overrides the Bitcoin signed message digest when set
+	hashScheme HashScheme
+
+	// hashCacheSize is the number of message hashes cached, none when 0
+	hashCacheSize int
+	hashes        *hashCache
+
+	// events logs on behalf of the verifier, the package logger by default
+	events eventLogger
+
+	// metrics overrides the package metrics when set
+	metrics Metrics
+
+	// tracer overrides the package tracer when set
+	tracer Tracer
+
+	// hooks are called around each verification
+	hooks Hooks
+
+	// maxMessageSize overrides the package-level limit when set
+	maxMessageSize    int64
+	hasMaxMessageSize bool
+
+	// defaultTimeout bounds the wall time of each verification when set
+	defaultTimeout time.Duration
+
+	// limiter caps the rate of verifications when set
+	limiter *rate.Limiter
+
+	// flights collapses identical concurrent verifications when set
+	flights *flightGroup
+}
+
+// MessageVerifier verifies signed messages. Verifier implements it, and
+// packages that only need to verify signatures, such as auth and httpserver,
+// accept it so tests can substitute verifytest.MockVerifier.
+type MessageVerifier interface {
+	// VerifyContext verifies a signed message. The returned result is never
+	// nil; when verification fails the error explains why.
+	VerifyContext(ctx context.Context, msg SignedMessage) (*Result, error)
+}
+
+var _ MessageVerifier = (*Verifier)(nil)
+
+// Option configures a Verifier
+type Option func(*Verifier)
+
+// Result is the structured outcome of a verification
+type Result struct {
+	// Valid reports whether the signature is valid and passed every enabled
+	// strictness check
+	Valid bool
+
+	// LowS reports whether the S value of the signature is in the lower half
+	// of the curve order, i.e. whether the signature is in canonical form
+	LowS bool
+
+	// LineEndings is the line ending convention the message verified with,
+	// LineEndingsExact when it verified as given
+	LineEndings LineEndings
+
+	// Violations are the rules of the policy of the verifier the message
+	// breaks, set with WithPolicy
+	Violations []PolicyViolation
+
+	// Trace records the intermediate values of the verification when the
+	// verifier was created with WithDebugTrace, and is nil otherwise
+	Trace *DebugTrace
+}
+
+// NewVerifier creates a Verifier. Without options it verifies mainnet
+// signatures and accepts both low and high S values, like the package-level
+// functions.
+func NewVerifier(opts ...Option) *Verifier {
+	v := &Verifier{
+		params: &chaincfg.MainNetParams,
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	if v.hashCacheSize > 0 {
+		v.hashes = newHashCache(v.hashMessage, v.hashCacheSize)
+	}
+	return v
+}
+
+// Params returns the network parameters of the verifier
+func (v *Verifier) Params() *chaincfg.Params {
+	return v.params
+}
+
+// WithParams sets the network parameters (mainnet, testnet, etc.)
+func WithParams(params *chaincfg.Params) Option {
+	return func(v *Verifier) {
+		v.params = params
+	}
+}
+
+// WithRequireLowS rejects signatures whose S value is in the upper half of
+// the curve order. Such signatures are valid, but malleable: anyone can turn
+// a low-S signature into a high-S one, so callers that need exactly one
+// canonical signature per message and key should enable this.
+func WithRequireLowS() Option {
+	return func(v *Verifier) {
+		v.requireLowS = true
+	}
+}
+
+// WithStrictLength rejects signatures that don't decode to exactly 65 bytes,
+// such as compact signatures with trailing garbage. By default other lengths
+// are passed to the BitonicNL verifier, which accepts BIP-322 signatures for
+// SegWit and Taproot addresses.
+func WithStrictLength() Option {
+	return func(v *Verifier) {
+		v.strictLength = true
+	}
+}
+
+// WithBase64Mode sets how strictly signatures are base64-decoded. Compliance
+// checks may want Base64Strict, while user-facing tools can use
+// Base64Permissive to accept signatures mangled by copy and paste.
+func WithBase64Mode(mode Base64Mode) Option {
+	return func(v *Verifier) {
+		v.base64Mode = mode
+	}
+}
+
+// WithLineEndings sets how line endings of multi-line messages are treated.
+// The default, LineEndingsExact, verifies messages byte for byte.
+func WithLineEndings(mode LineEndings) Option {
+	return func(v *Verifier) {
+		v.lineEndings = mode
+	}
+}
+
+// WithStrictHeader rejects signatures whose header byte stands for another
+// address type than the address they are verified against, such as a P2WPKH
+// address with a P2PKH header byte. Many wallets produce such signatures and
+// they verify by default; compliance checks may need to flag them instead.
+// BIP-0137 defines no header bytes for Taproot, so 65-byte signatures for
+// P2TR addresses are always rejected in this mode.
+func WithStrictHeader() Option {
+	return func(v *Verifier) {
+		v.strictHeader = true
+	}
+}
+
+// WithCrossCheck verifies compact signatures with both the native engine and
+// the BitonicNL verifier, failing with ErrEngineDisagreement when they reach
+// different verdicts. This doubles the cost of verification in exchange for a
+// guard against bugs in either implementation. Note that the BitonicNL
+// verifier also accepts signatures over the message with surrounding
+// whitespace trimmed, which the native engine doesn't; such signatures are
+// reported as a disagreement. Other signatures are only supported by the
+// BitonicNL verifier and aren't cross-checked.
+func WithCrossCheck() Option {
+	return func(v *Verifier) {
+		v.crossCheck = true
+	}
+}
+
+// WithTaprootKey sets which key of a P2TR address compact signatures are
+// made with. The default, TaprootKeyInternal, matches wallets that sign with
+// the internal key; TaprootKeyOutput verifies key-path proofs made with the
+// tweaked output key. The BitonicNL verifier only knows the internal key, so
+// WithCrossCheck reports output key signatures as a disagreement.
+func WithTaprootKey(key TaprootKey) Option {
+	return func(v *Verifier) {
+		v.taprootKey = key
+	}
+}
+
+// WithHashScheme makes the verifier compute the digest signatures sign with
+// the scheme instead of the Bitcoin signed message format, to verify forks
+// and wallets that deviate from it. A scheme only applies to 65-byte compact
+// signatures; others fail with ErrMalformedSignature, and WithCrossCheck
+// reports a disagreement since the BitonicNL verifier always uses the
+// Bitcoin format.
+func WithHashScheme(scheme HashScheme) Option {
+	return func(v *Verifier) {
+		v.hashScheme = scheme
+	}
+}
+
+// WithHashCacheSize caches the digests of the last size distinct messages
+// verified, so services verifying many signatures of the same challenge hash
+// it once. Each entry keeps its message, so the cache can hold up to size
+// times the maximum message size. A size of 0 disables the cache.
+func WithHashCacheSize(size int) Option {
+	return func(v *Verifier) {
+		v.hashCacheSize = size
+	}
+}
+
+// WithAddressFilter rejects proofs from addresses the filter blocks with
+// ErrAddressBlocked, before any other work is done on them
+func WithAddressFilter(filter AddressFilter) Option {
+	return func(v *Verifier) {
+		v.addressFilter = &filter
+	}
+}
+
+// WithMaxMessageAge rejects messages signed more than maxAge ago with
+// ErrMessageExpired, so old proofs can't be replayed indefinitely. Messages
+// must end with a timestamp line, as appended by TimestampMessage; those
+// without one, or timestamped more than a minute in the future, fail with
+// ErrInvalidTimestamp.
+func WithMaxMessageAge(maxAge time.Duration) Option {
+	return func(v *Verifier) {
+		v.maxMessageAge = maxAge
+	}
+}
+
+// WithPolicy checks signed messages against the policy before verifying
+// their signature. Messages breaking a rule fail with ErrPolicyViolation,
+// rather than an error about the signature, and the broken rules are listed
+// in the Violations of the result.
+func WithPolicy(policy Policy) Option {
+	return func(v *Verifier) {
+		v.policy = &policy
+	}
+}
+
+// WithLogger makes the verifier log to l instead of the package logger
+func WithLogger(l Logger) Option {
+	return func(v *Verifier) {
+		v.events.logger = l
+	}
+}
+
+// WithLogLevel sets the log level of the verifier, overriding the package
+// level set with SetLogLevel. Libraries can use it to keep their verifier
+// quiet without changing the verbosity of other users of the package.
+func WithLogLevel(level LogLevel) Option {
+	return func(v *Verifier) {
+		v.events.level = level
+		v.events.hasLevel = true
+	}
+}
+
+// WithMetrics makes the verifier report to m instead of the metrics set with
+// SetMetrics
+func WithMetrics(m Metrics) Option {
+	return func(v *Verifier) {
+		v.metrics = m
+	}
+}
+
+// WithTracer makes the verifier start its spans with t instead of the tracer
+// set with SetTracer
+func WithTracer(t Tracer) Option {
+	return func(v *Verifier) {
+		v.tracer = t
+	}
+}
+
+// WithMaxMessageSize sets the maximum message length in bytes, overriding the
+// package-level limit set with SetMaxMessageSize. A limit of 0 disables the
+// check.
+func WithMaxMessageSize(size int64) Option {
+	return func(v *Verifier) {
+		v.maxMessageSize = size
+		v.hasMaxMessageSize = true
+	}
+}
+
+// WithDefaultTimeout bounds the wall time of each verification to timeout,
+// even when the caller's context has no deadline, so pathological inputs
+// can't tie up a service. An earlier deadline of the caller still applies.
+// Verifications running out of time fail with ErrVerificationTimeout. A
+// timeout of 0 disables the bound, which is the default.
+func WithDefaultTimeout(timeout time.Duration) Option {
+	return func(v *Verifier) {
+		v.defaultTimeout = timeout
+	}
+}
+
+// WithRateLimit caps the verifications of the verifier at limit per second,
+// with bursts of up to burst, so services embedding the library bound the
+// CPU it consumes. Verifications over the limit wait for their turn; those
+// whose context would be done before it fail with ErrVerificationTimeout
+// right away. Messages rejected before any signature work, such as those
+// with an empty field, don't count against the limit.
+func WithRateLimit(limit rate.Limit, burst int) Option {
+	return func(v *Verifier) {
+		v.limiter = rate.NewLimiter(limit, max(burst, 1))
+	}
+}
+
+// Verify verifies a signed message. The returned result is never nil; when
+// verification fails the error explains why.
+func (v *Verifier) Verify(msg SignedMessage) (*Result, error) {
+	return v.VerifyContext(context.Background(), msg)
+}
+
+// VerifyContext is like Verify, but starts its spans as children of the span
+// in ctx when a tracer is configured. Verification stops between its stages
+// with ErrVerificationTimeout once ctx is done.
+func (v *Verifier) VerifyContext(ctx context.Context, msg SignedMessage) (*Result, error) {
+	if v.defaultTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.defaultTimeout)
+		defer cancel()
+	}
+
+	if v.flights != nil {
+		return v.flights.do(ctx, v.events, msg, func() (*Result, error) {
+			return v.verifyContext(ctx, msg)
+		})
+	}
+	return v.verifyContext(ctx, msg)
+}
+
+// verifyContext verifies a signed message on behalf of VerifyContext
+func (v *Verifier) verifyContext(ctx context.Context, msg SignedMessage) (result *Result, err error) {
+	t := v.tracer
+	if t == nil {
+		t = GetTracer()
+	}
+	spans, span := newSpanScope(ctx, t).start(SpanVerify)
+	span.SetAttribute("address", msg.Address)
+	span.SetAttribute("network", v.params.Name)
+
+	v.events.log(LogLevelInfo, "Starting BIP-0137 signature verification with verifier", "address", msg.Address, "network", v.params.Name)
+	v.events.log(LogLevelDebug, "Verification input", "address", msg.Address, "message", msg.Message, "signature", msg.Signature)
+
+	startTime := time.Now()
+	v.hooks.start(ctx, msg)
+	defer func() {
+		v.events.log(LogLevelDebug, "Verification completed", "address", msg.Address, "duration", time.Since(startTime))
+		if result.Trace != nil {
+			result.Trace.finish(result, err)
+		}
+		v.hooks.finish(ctx, msg, startTime, result, err)
+
+		m := v.metrics
+		if m == nil {
+			m = GetMetrics()
+		}
+		observeVerification(m, startTime, result.Valid, err)
+
+		span.SetAttribute("valid", result.Valid)
+		span.End(err)
+	}()
+
+	result = &Result{}
+	if v.debugTrace {
+		result.Trace = &DebugTrace{Address: msg.Address, Network: v.params.Name, Attempts: []TraceAttempt{}}
+	}
+
+	// Validate inputs
+	if msg.Address == "" {
+		v.events.log(LogLevelError, "Empty address provided")
+		return result, ErrEmptyAddress
+	}
+	if msg.Message == "" {
+		v.events.log(LogLevelError, "Empty message provided")
+		return result, ErrEmptyMessage
+	}
+	if msg.Signature == "" {
+		v.events.log(LogLevelError, "Empty signature provided")
+		return result, ErrEmptySignature
+	}
+
+	if v.addressFilter != nil {
+		if err := v.addressFilter.Check(msg.Address); err != nil {
+			v.events.log(LogLevelError, "Address rejected by the address filter", "address", msg.Address, "error", err)
+			return result, err
+		}
+	}
+
+	if err := checkMessageSize(v.events, msg.Message, v.messageSizeLimit()); err != nil {
+		return result, err
+	}
+
+	if v.maxMessageAge > 0 {
+		if err := checkMessageAge(msg.Message, v.maxMessageAge, time.Now()); err != nil {
+			v.events.log(LogLevelError, "Message rejected: timestamp out of range", "max_age", v.maxMessageAge, "error", err)
+			return result, err
+		}
+	}
+
+	if v.limiter != nil {
+		if err := v.limiter.Wait(ctx); err != nil {
+			if ctx.Err() == nil {
+				err = newVerifyError(ErrVerificationTimeout, "rate limited: %v", err)
+			} else {
+				err = checkContext(ctx)
+			}
+			v.events.log(LogLevelError, "Verification rate limited", "address", msg.Address, "error", err)
+			return result, err
+		}
+	}
+
+	sigBytes, err := decodeSignature(msg.Signature, v.base64Mode)
+	if err != nil {
+		v.events.log(LogLevelError, "Failed to decode base64 signature", "base64_mode", v.base64Mode, "error", err)
+		return result, err
+	}
+	if result.Trace != nil {
+		result.Trace.Signature = hex.EncodeToString(sigBytes)
+	}
+	if err := spans.err(); err != nil {
+		v.events.log(LogLevelError, "Verification stopped", "address", msg.Address, "error", err)
+		return result, err
+	}
+
+	variants := messageVariants(msg.Message, v.lineEndings)
+
+	if v.policy != nil {
+		if result.Violations = v.policy.check(msg, sigBytes, v.params, time.Now()); len(result.Violations) > 0 {
+			err := policyError(result.Violations)
+			v.events.log(LogLevelError, "Signed message rejected by the policy", "address", msg.Address, "error", err)
+			return result, err
+		}
+	}
+
+	// Check the strictness rules before doing any elliptic curve work
+	if len(sigBytes) != compactSignatureLength {
+		if v.hashScheme != nil {
+			v.events.log(LogLevelError, "Signature rejected: custom hash schemes need a compact signature", "length", len(sigBytes))
+			return result, newVerifyError(ErrMalformedSignature, "signature is %d bytes, custom hash schemes need %d", len(sigBytes), compactSignatureLength)
+		}
+		if v.strictLength {
+			v.events.log(LogLevelError, "Signature rejected: not a compact signature", "length", len(sigBytes))
+			return result, newVerifyError(ErrMalformedSignature, "signature is %d bytes, want %d", len(sigBytes), compactSignatureLength)
+		}
+		return v.verifyFull(msg, sigBytes, variants, result)
+	}
+	if result.Trace != nil {
+		result.Trace.Engine = EngineNative
+	}
+
+	result.LowS = isLowS(sigBytes)
+	v.events.log(LogLevelDebug, "Signature header", "header_byte", sigBytes[0], "rec_id", (sigBytes[0]-headerP2PKHUncompressed)&0x03, "low_s", result.LowS)
+
+	if v.requireLowS && !result.LowS {
+		v.events.log(LogLevelError, "Signature rejected: S value is in the upper half of the curve order")
+		return result, newVerifyError(ErrHighS, "S value is in the upper half of the curve order")
+	}
+
+	if v.strictHeader {
+		if err := v.checkHeaderAddressType(msg.Address, sigBytes[0]); err != nil {
+			v.events.log(LogLevelError, "Signature rejected", "header_byte", sigBytes[0], "error", err)
+			return result, err
+		}
+	}
+
+	var firstErr error
+	for _, variant := range variants {
+		report := v.verifyVariant(spans, msg.Address, variant, sigBytes, result.Trace)
+		if v.crossCheck {
+			if err := v.crossCheckReport(msg.Address, variant.message, sigBytes, report); err != nil {
+				v.events.log(LogLevelError, "Signature rejected", "error", err)
+				return result, err
+			}
+		}
+		if report.Valid {
+			result.Valid = true
+			result.LineEndings = variant.lineEndings
+			v.events.log(LogLevelInfo, "Signature verification successful",
+				"address", msg.Address, "network", v.params.Name, "line_endings", variant.lineEndings)
+			return result, nil
+		}
+
+		v.events.log(LogLevelDebug, "Verification attempt failed", "line_endings", variant.lineEndings, "stage", report.Stage, "error", report.Err)
+		if firstErr == nil {
+			firstErr = report.Err
+		}
+	}
+
+	v.events.log(LogLevelError, "Signature verification failed", "address", msg.Address, "network", v.params.Name, "error", firstErr)
+	return result, firstErr
+}
+
+// verifyVariant verifies a compact signature of a message variant with the
+// native engine, recording the attempts in the trace. Like the BitonicNL
+// verifier, a variant that fails is verified again with surrounding
+// whitespace trimmed, so the cross-check compares the same verdicts.
+func (v *Verifier) verifyVariant(spans spanScope, address string, variant messageVariant, sigBytes []byte, trace *DebugTrace) FailureReport {
+	report := v.explainVariant(spans, address, variant, sigBytes, trace)
+	if trimmed, ok := trimmedMessage(variant.message); ok && !report.Valid {
+		if retry := v.explainVariant(spans, address, messageVariant{variant.lineEndings, trimmed}, sigBytes, trace); retry.Valid {
+			return retry
+		}
+	}
+	return report
+}
+
+// explainVariant verifies a compact signature of a message variant with the
+// native engine, recording the attempt in the trace
+func (v *Verifier) explainVariant(spans spanScope, address string, variant messageVariant, sigBytes []byte, trace *DebugTrace) FailureReport {
+	digest := v.messageHash(variant.message)
+	report := explainDigest(v.events, spans, address, digest[:], sigBytes, v.params, v.taprootKey)
+	if trace != nil {
+		trace.addNative(variant, digest[:], sigBytes, report)
+	}
+	return report
+}
+
+// messageSizeLimit returns the maximum message length of the verifier, the
+// package-level limit unless WithMaxMessageSize set one
+func (v *Verifier) messageSizeLimit() int64 {
+	if v.hasMaxMessageSize {
+		return v.maxMessageSize
+	}
+	return MaxMessageSize()
+}
+
+// messageHash returns the digest a signature of the message signs, with the
+// hash scheme of the verifier
+func (v *Verifier) messageHash(message string) [32]byte {
+	if v.hashes != nil {
+		return v.hashes.get(message)
+	}
+	return v.hashMessage(message)
+}
+
+// hashMessage is messageHash without the cache
+func (v *Verifier) hashMessage(message string) [32]byte {
+	if v.hashScheme != nil {
+		return v.hashScheme.Hash(message)
+	}
+	return magicHashWithEvents(v.events, message)
+}
+
+// verifyFull verifies a signature that isn't a compact signature with the
+// BitonicNL verifier. The signature is re-encoded, as the BitonicNL verifier
+// only accepts standard base64.
+func (v *Verifier) verifyFull(msg SignedMessage, sigBytes []byte, variants []messageVariant, result *Result) (*Result, error) {
+	if _, err := decodeAddress(msg.Address, v.params); err != nil {
+		v.events.log(LogLevelError, "Invalid address", "address", msg.Address, "network", v.params.Name, "error", err)
+		return result, err
+	}
+
+	v.events.log(LogLevelDebug, "Calling BitonicNL verifier for a non-compact signature", "length", len(sigBytes))
+	if result.Trace != nil {
+		result.Trace.Engine = EngineBitonicNL
+	}
+	signature := base64.StdEncoding.EncodeToString(sigBytes)
+
+	var firstErr error
+	for _, variant := range variants {
+		valid, err := verifier.VerifyWithChain(verifier.SignedMessage{
+			Address:   msg.Address,
+			Message:   variant.message,
+			Signature: signature,
+		}, v.params)
+		if result.Trace != nil {
+			result.Trace.addBitonicNL(variant, valid, err)
+		}
+		if err == nil && valid {
+			result.Valid = true
+			result.LineEndings = variant.lineEndings
+			return result, nil
+		}
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("signature verification error: %w", classifyVerifierError(err))
+		}
+	}
+
+	if firstErr != nil {
+		v.events.log(LogLevelError, "Signature verification failed", "address", msg.Address, "network", v.params.Name, "error", firstErr)
+	}
+	return result, firstErr
+}
+
+// crossCheckReport verifies a compact signature with the BitonicNL verifier
+// and compares its verdict with the report of the native engine
+func (v *Verifier) crossCheckReport(address, message string, sigBytes []byte, report FailureReport) error {
+	valid, err := verifier.VerifyWithChain(verifier.SignedMessage{
+		Address:   address,
+		Message:   message,
+		Signature: base64.StdEncoding.EncodeToString(sigBytes),
+	}, v.params)
+	valid = valid && err == nil
+
+	v.events.log(LogLevelDebug, "Cross-checked verification engines", "native_valid", report.Valid, "bitonicnl_valid", valid)
+	if valid != report.Valid {
+		return newVerifyError(ErrEngineDisagreement, "native engine valid: %t (%v), BitonicNL verifier valid: %t (%v)", report.Valid, report.Err, valid, err)
+	}
+	return nil
+}
+
+// checkHeaderAddressType checks that the address type implied by the header
+// byte is the type of the address
+func (v *Verifier) checkHeaderAddressType(address string, header byte) error {
+	addr, err := decodeAddress(address, v.params)
+	if err != nil {
+		return err
+	}
+
+	want := addressTypeOf(addr)
+	got := headerAddressType(header)
+	if got == "" {
+		return newVerifyError(ErrInvalidHeaderByte, "0x%02x", header)
+	}
+	if got != want {
+		return newVerifyError(ErrHeaderAddressMismatch, "header byte 0x%02x is for %s addresses, not %s", header, got, want)
+	}
+	return nil
+}
+
+// isLowS reports whether the S value of a 65-byte compact signature is at
+// most half the curve order
+func isLowS(sigBytes []byte) bool {
+	var s btcec.ModNScalar
+	if overflow := s.SetByteSlice(sigBytes[33:compactSignatureLength]); overflow {
+		return false
+	}
+	return !s.IsOverHalfOrder()
+}
@@ -0,0 +1,359 @@
+package verify
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"golang.org/x/time/rate"
+)
+
+func TestVerifierVerify(t *testing.T) {
+	lowS := walletTestVectors[2].msg
+	highS := lowS
+	highS.Signature = highSVariant(t, lowS.Signature)
+
+	sigBytes, _ := base64.StdEncoding.DecodeString(lowS.Signature)
+	trailing := lowS
+	trailing.Signature = base64.StdEncoding.EncodeToString(append(sigBytes, 0x00, 0x01))
+
+	// Electrum trims messages before signing
+	whitespace := walletTestVectors[5].msg
+	whitespace.Message = " " + whitespace.Message + "\n"
+
+	// BIP-322 test vector #0
+	bip322 := SignedMessage{
+		Address:   "bc1q9vza2e8x573nczrlzms0wvx3gsqjx7vavgkx0l",
+		Message:   "Hello World",
+		Signature: "AkcwRAIgZRfIY3p7/DoVTty6YZbWS71bc5Vct9p9Fia83eRmw2QCICK/ENGfwLtptFluMGs2KsqoNSk89pO7F29zJLUx9a/sASECx/EgAxlkQpQ9hYjgGu6EBCPMVPwVIVJqO4XCsMvViHI=",
+	}
+
+	tests := []struct {
+		name      string
+		verifier  *Verifier
+		msg       SignedMessage
+		wantValid bool
+		wantLowS  bool
+		wantErr   error
+	}{
+		{
+			name:      "Low S signature",
+			verifier:  NewVerifier(),
+			msg:       lowS,
+			wantValid: true,
+			wantLowS:  true,
+		},
+		{
+			name:      "High S signature accepted by default",
+			verifier:  NewVerifier(),
+			msg:       highS,
+			wantValid: true,
+			wantLowS:  false,
+		},
+		{
+			name:      "High S signature rejected when low S is required",
+			verifier:  NewVerifier(WithRequireLowS()),
+			msg:       highS,
+			wantValid: false,
+			wantLowS:  false,
+			wantErr:   ErrHighS,
+		},
+		{
+			name:      "Low S signature accepted when low S is required",
+			verifier:  NewVerifier(WithRequireLowS()),
+			msg:       lowS,
+			wantValid: true,
+			wantLowS:  true,
+		},
+		{
+			name:      "Mainnet address with testnet parameters",
+			verifier:  NewVerifier(WithParams(&chaincfg.TestNet3Params)),
+			msg:       lowS,
+			wantValid: false,
+			wantLowS:  true,
+			wantErr:   ErrNetworkMismatch,
+		},
+		{
+			name:      "BIP-322 signature accepted by default",
+			verifier:  NewVerifier(),
+			msg:       bip322,
+			wantValid: true,
+		},
+		{
+			name:     "BIP-322 signature rejected with strict length",
+			verifier: NewVerifier(WithStrictLength()),
+			msg:      bip322,
+			wantErr:  ErrMalformedSignature,
+		},
+		{
+			name:     "Trailing garbage rejected with strict length",
+			verifier: NewVerifier(WithStrictLength()),
+			msg:      trailing,
+			wantErr:  ErrMalformedSignature,
+		},
+		{
+			name:      "Compact signature accepted with strict length",
+			verifier:  NewVerifier(WithStrictLength()),
+			msg:       lowS,
+			wantValid: true,
+			wantLowS:  true,
+		},
+		{
+			name:      "Message with surrounding whitespace",
+			verifier:  NewVerifier(),
+			msg:       whitespace,
+			wantValid: true,
+			wantLowS:  true,
+		},
+		{
+			name:     "Empty address",
+			verifier: NewVerifier(),
+			msg:      SignedMessage{Message: "test message", Signature: lowS.Signature},
+			wantErr:  ErrEmptyAddress,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.verifier.Verify(tt.msg)
+
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Verifier.Verify() error = %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verifier.Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if result.Valid != tt.wantValid {
+				t.Errorf("Verifier.Verify().Valid = %v, want %v", result.Valid, tt.wantValid)
+			}
+			if result.LowS != tt.wantLowS {
+				t.Errorf("Verifier.Verify().LowS = %v, want %v", result.LowS, tt.wantLowS)
+			}
+		})
+	}
+}
+
+// Helper function to turn a low S signature into its equally valid high S
+// counterpart, negating S and flipping the recovery ID
+func highSVariant(t *testing.T, signature string) string {
+	t.Helper()
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		t.Fatalf("base64.DecodeString() error = %v", err)
+	}
+
+	var s btcec.ModNScalar
+	s.SetByteSlice(sigBytes[33:65])
+	s.Negate()
+	sBytes := s.Bytes()
+
+	highS := append([]byte{}, sigBytes...)
+	recoveryID := (highS[0] - 27) & 0x03
+	highS[0] += (recoveryID ^ 0x01) - recoveryID
+	copy(highS[33:], sBytes[:])
+	return base64.StdEncoding.EncodeToString(highS)
+}
+
+func TestVerifierStrictHeader(t *testing.T) {
+	tests := []struct {
+		name          string
+		msg           SignedMessage
+		wantDefault   bool
+		wantStrict    bool
+		wantStrictErr error
+	}{
+		{name: "P2PKH header with P2PKH address", msg: walletTestVectors[2].msg, wantDefault: true, wantStrict: true},
+		{name: "P2SH-P2WPKH header with P2SH address", msg: walletTestVectors[4].msg, wantDefault: true, wantStrict: true},
+		{name: "P2WPKH header with P2WPKH address", msg: walletTestVectors[5].msg, wantDefault: true, wantStrict: true},
+		{name: "P2PKH header with P2SH address", msg: walletTestVectors[3].msg, wantDefault: true, wantStrictErr: ErrHeaderAddressMismatch},
+		{name: "P2PKH header with P2WPKH address", msg: walletTestVectors[6].msg, wantDefault: true, wantStrictErr: ErrHeaderAddressMismatch},
+		{name: "P2PKH header with P2TR address", msg: walletTestVectors[7].msg, wantDefault: true, wantStrictErr: ErrHeaderAddressMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewVerifier().Verify(tt.msg)
+			if result.Valid != tt.wantDefault {
+				t.Errorf("Verifier.Verify().Valid = %v, want %v (error: %v)", result.Valid, tt.wantDefault, err)
+			}
+
+			result, err = NewVerifier(WithStrictHeader()).Verify(tt.msg)
+			if result.Valid != tt.wantStrict {
+				t.Errorf("Verifier.Verify().Valid with strict header = %v, want %v (error: %v)", result.Valid, tt.wantStrict, err)
+			}
+			if tt.wantStrictErr != nil && !errors.Is(err, tt.wantStrictErr) {
+				t.Errorf("Verifier.Verify() with strict header error = %v, want %v", err, tt.wantStrictErr)
+			}
+		})
+	}
+}
+
+func TestVerifierCrossCheck(t *testing.T) {
+	trimmed := walletTestVectors[2].msg
+	trimmed.Message = " " + trimmed.Message + "\n"
+
+	mismatch := walletTestVectors[2].msg
+	mismatch.Address = walletTestVectors[0].msg.Address
+
+	// The BitonicNL verifier only retries the trimmed message on mainnet
+	testnetTrimmed := trimmed
+	testnetTrimmed.Address = "msgXyBvhCMiwsopsDU8D8KyUinbzp5AzHw"
+
+	tests := []struct {
+		name      string
+		msg       SignedMessage
+		params    *chaincfg.Params
+		wantValid bool
+		wantErr   error
+	}{
+		{name: "Engines agree on valid signature", msg: walletTestVectors[2].msg, wantValid: true},
+		{name: "Engines agree on P2WPKH signature", msg: walletTestVectors[5].msg, wantValid: true},
+		{name: "Engines agree on P2TR signature", msg: walletTestVectors[7].msg, wantValid: true},
+		{name: "Engines agree on address mismatch", msg: mismatch, wantErr: ErrAddressMismatch},
+		{name: "Engines agree on trimmed message", msg: trimmed, wantValid: true},
+		{name: "Engines disagree on trimmed testnet message", msg: testnetTrimmed, params: &chaincfg.TestNet3Params, wantErr: ErrEngineDisagreement},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{WithCrossCheck()}
+			if tt.params != nil {
+				opts = append(opts, WithParams(tt.params))
+			}
+			result, err := NewVerifier(opts...).Verify(tt.msg)
+			if result.Valid != tt.wantValid {
+				t.Errorf("Verifier.Verify().Valid = %v, want %v (error: %v)", result.Valid, tt.wantValid, err)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verifier.Verify() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifierDefaultTimeout(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		timeout   time.Duration
+		wantValid bool
+		wantErr   error
+	}{
+		{name: "No timeout", ctx: context.Background(), wantValid: true},
+		{name: "Generous timeout", ctx: context.Background(), timeout: time.Minute, wantValid: true},
+		{name: "Timeout without caller deadline", ctx: context.Background(), timeout: time.Nanosecond, wantErr: context.DeadlineExceeded},
+		{name: "Caller context done first", ctx: cancelled, timeout: time.Minute, wantErr: context.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := NewVerifier(WithDefaultTimeout(tt.timeout)).VerifyContext(tt.ctx, walletTestVectors[2].msg)
+			if result.Valid != tt.wantValid {
+				t.Errorf("Verifier.VerifyContext().Valid = %v, want %v (error: %v)", result.Valid, tt.wantValid, err)
+			}
+			if tt.wantErr != nil && !(errors.Is(err, tt.wantErr) && errors.Is(err, ErrVerificationTimeout)) {
+				t.Errorf("Verifier.VerifyContext() error = %v, want %v and %v", err, ErrVerificationTimeout, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifierRateLimit(t *testing.T) {
+	msg := walletTestVectors[2].msg
+	v := NewVerifier(WithRateLimit(rate.Every(time.Hour), 2))
+
+	// Rejected inputs don't use up the burst
+	if _, err := v.Verify(SignedMessage{Address: msg.Address, Message: msg.Message}); !errors.Is(err, ErrEmptySignature) {
+		t.Fatalf("Verifier.Verify() error = %v, want %v", err, ErrEmptySignature)
+	}
+	for i := range 2 {
+		if result, err := v.Verify(msg); err != nil || !result.Valid {
+			t.Fatalf("Verifier.Verify() call %d = %+v, %v, want valid", i, result, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	start := time.Now()
+	if _, err := v.VerifyContext(ctx, msg); !errors.Is(err, ErrVerificationTimeout) {
+		t.Errorf("Verifier.VerifyContext() over the limit error = %v, want %v", err, ErrVerificationTimeout)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Verifier.VerifyContext() waited %s for a turn past its deadline", elapsed)
+	}
+}
+
+func TestVerifierHashCache(t *testing.T) {
+	litecoin := MessagePrefix("Litecoin Signed Message:\n")
+	bitcoin := walletTestVectors[2].msg
+	forked := signTestMessage(t, testKeySeed, litecoin.Hash, bitcoin.Message)
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		msg      SignedMessage
+	}{
+		{name: "Bitcoin message hash", verifier: NewVerifier(WithHashCacheSize(1)), msg: bitcoin},
+		{name: "Hash scheme set after the cache", verifier: NewVerifier(WithHashCacheSize(1), WithHashScheme(litecoin)), msg: forked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := range 2 {
+				if result, err := tt.verifier.Verify(tt.msg); err != nil || !result.Valid {
+					t.Fatalf("Verifier.Verify() call %d = %+v, %v, want valid", i, result, err)
+				}
+			}
+			if hits, misses := tt.verifier.hashes.stats(); hits != 1 || misses != 1 {
+				t.Errorf("hashCache.stats() = %d, %d, want 1, 1", hits, misses)
+			}
+		})
+	}
+}
+
+func TestVerifierLogger(t *testing.T) {
+	defer SetLogger(GetLogger())
+	defer SetLogLevel(GetLogLevel())
+
+	global := &recordingLogger{}
+	SetLogger(global)
+	SetLogLevel(LogLevelNone)
+
+	verbose := &recordingLogger{}
+	quiet := &recordingLogger{}
+	tv := walletTestVectors[2].msg
+
+	if _, err := NewVerifier(WithLogger(verbose), WithLogLevel(LogLevelTrace)).Verify(tv); err != nil {
+		t.Fatalf("Verifier.Verify() error = %v", err)
+	}
+	if _, err := NewVerifier(WithLogger(quiet), WithLogLevel(LogLevelError)).Verify(tv); err != nil {
+		t.Fatalf("Verifier.Verify() error = %v", err)
+	}
+
+	if _, ok := verbose.value("pubkey"); !ok {
+		t.Errorf("verifier at trace level didn't log the recovered public key, logged %q", verbose.lines)
+	}
+	if len(quiet.lines) != 0 {
+		t.Errorf("verifier at error level logged %q for a valid signature", quiet.lines)
+	}
+	if len(global.lines) != 0 {
+		t.Errorf("package logger received %q from verifiers with their own logger", global.lines)
+	}
+
+	// Without its own settings a verifier follows the package configuration
+	SetLogLevel(LogLevelInfo)
+	if _, err := NewVerifier().Verify(tv); err != nil {
+		t.Fatalf("Verifier.Verify() error = %v", err)
+	}
+	if len(global.lines) == 0 {
+		t.Errorf("default verifier didn't log to the package logger")
+	}
+}
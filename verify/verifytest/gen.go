@@ -0,0 +1,186 @@
+package verifytest
+
+import (
+	"encoding/base64"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/keys"
+	"github.com/sero/btc/verify/signer"
+)
+
+// Gen generates random values for property tests. The same source of
+// randomness always gives the same value, so a failing value can be
+// reproduced from its seed with Example. Generators plug into property
+// testing libraries by drawing a seed, e.g. with rapid:
+//
+//	rapid.Custom(func(t *rapid.T) string {
+//		return gen.Example(rapid.Uint64().Draw(t, "seed"))
+//	})
+type Gen[T any] func(r *rand.Rand) T
+
+// Example returns the value generated from the seed
+func (g Gen[T]) Example(seed uint64) T {
+	return g(newRand(seed))
+}
+
+// ForAll checks the property against n generated values, reporting a test
+// error with the seed of each value the property fails for
+func ForAll[T any](t testing.TB, n int, g Gen[T], property func(T) error) {
+	t.Helper()
+
+	seeds := rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	for range n {
+		seed := seeds.Uint64()
+		if err := property(g.Example(seed)); err != nil {
+			t.Errorf("property failed for seed %d: %v", seed, err)
+		}
+	}
+}
+
+// messageAlphabet are the pieces generated messages are assembled from,
+// including line endings and multi-byte UTF-8 characters
+var messageAlphabet = []string{
+	"a", "b", "z", "A", "Z", "0", "9", " ", ".", ":", "-", "=",
+	"\n", "\r\n", "\t", "é", "€", "₿", "日本", "🔑",
+}
+
+// Messages generates non-empty messages of up to maxLen pieces of text,
+// mixing ASCII, line endings and multi-byte characters
+func Messages(maxLen int) Gen[string] {
+	return func(r *rand.Rand) string {
+		var sb strings.Builder
+		for range 1 + r.IntN(max(maxLen, 1)) {
+			sb.WriteString(messageAlphabet[r.IntN(len(messageAlphabet))])
+		}
+		return sb.String()
+	}
+}
+
+// PrivateKeys generates random private keys
+func PrivateKeys() Gen[*btcec.PrivateKey] {
+	return func(r *rand.Rand) *btcec.PrivateKey {
+		var b [32]byte
+		for i := range 4 {
+			v := r.Uint64()
+			for j := range 8 {
+				b[i*8+j] = byte(v >> (8 * j))
+			}
+		}
+		// A scalar of zero, or of the curve order, has a probability of
+		// about 2^-256 and isn't worth handling
+		privKey, _ := btcec.PrivKeyFromBytes(b[:])
+		return privKey
+	}
+}
+
+// Addresses generates addresses of random keys on the network, of every
+// supported type
+func Addresses(params *chaincfg.Params) Gen[string] {
+	privKeys := PrivateKeys()
+	return func(r *rand.Rand) string {
+		info, err := keys.Describe(privKeys(r), params)
+		if err != nil {
+			panic(err)
+		}
+		addresses := []string{info.P2PKH, info.P2PKHUncompressed, info.P2SHP2WPKH, info.P2WPKH, info.P2TR}
+		return addresses[r.IntN(len(addresses))]
+	}
+}
+
+// SignedMessages generates messages signed by random keys for random
+// addresses on the network, which always verify
+func SignedMessages(params *chaincfg.Params) Gen[verify.SignedMessage] {
+	privKeys := PrivateKeys()
+	messages := Messages(64)
+	addrTypes := []verify.AddressType{verify.AddressTypeP2PKH, verify.AddressTypeP2SHP2WPKH, verify.AddressTypeP2WPKH}
+	return func(r *rand.Rand) verify.SignedMessage {
+		privKey := privKeys(r)
+		message := messages(r)
+
+		// One in four signatures is by an uncompressed key, which only
+		// signs for P2PKH addresses
+		key := signer.NewKey(privKey, true, params)
+		addrType := addrTypes[r.IntN(len(addrTypes))]
+		if r.IntN(4) == 0 {
+			key = signer.NewKey(privKey, false, params)
+			addrType = verify.AddressTypeP2PKH
+		}
+
+		msg, err := key.Sign(message, addrType)
+		if err != nil {
+			panic(err)
+		}
+		return msg
+	}
+}
+
+// InvalidSignedMessages generates signed messages whose signature is well
+// formed, a 65-byte compact signature with a valid header byte in valid
+// base64, but doesn't verify: the message was altered, or the signature is
+// of another key. Messages are never altered by whitespace around them only,
+// which verifiers trim like Electrum does.
+func InvalidSignedMessages(params *chaincfg.Params) Gen[verify.SignedMessage] {
+	signed := SignedMessages(params)
+	return func(r *rand.Rand) verify.SignedMessage {
+		msg := signed(r)
+		other := signed(r)
+		switch r.IntN(3) {
+		case 0:
+			msg.Message += "."
+		case 1:
+			if strings.TrimSpace(other.Message) == "" {
+				other.Message += "."
+			}
+			msg.Message = other.Message + msg.Message
+		default:
+			msg.Signature = other.Signature
+		}
+		return msg
+	}
+}
+
+// Signatures generates arbitrary base64 strings of up to 100 bytes, most of
+// them starting with a valid header byte, and some with broken base64, for
+// checking that verification never panics
+func Signatures() Gen[string] {
+	return func(r *rand.Rand) string {
+		b := make([]byte, r.IntN(101))
+		for i := range b {
+			b[i] = byte(r.UintN(256))
+		}
+		if len(b) > 0 && r.IntN(4) != 0 {
+			b[0] = byte(27 + r.IntN(16))
+		}
+		s := base64.StdEncoding.EncodeToString(b)
+		if s != "" && r.IntN(8) == 0 {
+			i := r.IntN(len(s))
+			s = s[:i] + "!" + s[i+1:]
+		}
+		return s
+	}
+}
+
+// ArbitrarySignedMessages generates signed messages of unrelated addresses,
+// messages and signatures, for checking that verification never panics
+func ArbitrarySignedMessages(params *chaincfg.Params) Gen[verify.SignedMessage] {
+	addresses := Addresses(params)
+	messages := Messages(64)
+	signatures := Signatures()
+	return func(r *rand.Rand) verify.SignedMessage {
+		return verify.SignedMessage{
+			Address:   addresses(r),
+			Message:   messages(r),
+			Signature: signatures(r),
+		}
+	}
+}
+
+// newRand returns a source of randomness seeded with seed
+func newRand(seed uint64) *rand.Rand {
+	return rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))
+}
@@ -0,0 +1,51 @@
+package verifytest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+)
+
+func TestGenProperties(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	for _, params := range []*chaincfg.Params{&chaincfg.MainNetParams, &chaincfg.TestNet3Params} {
+		v := verify.NewVerifier(verify.WithParams(params))
+
+		t.Run(params.Name+"/valid roundtrip always verifies", func(t *testing.T) {
+			ForAll(t, 50, SignedMessages(params), func(msg verify.SignedMessage) error {
+				if result, err := v.Verify(msg); err != nil || !result.Valid {
+					return fmt.Errorf("Verify(%+v) = %+v, %v, want valid", msg, result, err)
+				}
+				return nil
+			})
+		})
+
+		t.Run(params.Name+"/invalid never verifies", func(t *testing.T) {
+			ForAll(t, 50, InvalidSignedMessages(params), func(msg verify.SignedMessage) error {
+				if result, err := v.Verify(msg); err == nil || result.Valid {
+					return fmt.Errorf("Verify(%+v) = %+v, %v, want an error", msg, result, err)
+				}
+				return nil
+			})
+		})
+
+		t.Run(params.Name+"/verify never panics", func(t *testing.T) {
+			ForAll(t, 200, ArbitrarySignedMessages(params), func(msg verify.SignedMessage) error {
+				v.Verify(msg)
+				return nil
+			})
+		})
+	}
+}
+
+func TestGenExample(t *testing.T) {
+	for seed := range uint64(10) {
+		a, b := SignedMessages(&chaincfg.MainNetParams).Example(seed), SignedMessages(&chaincfg.MainNetParams).Example(seed)
+		if a != b {
+			t.Errorf("Example(%d) = %+v and %+v, want the same value", seed, a, b)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package verifytest
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+// compactLength is the length of a compact signature in bytes
+const compactLength = 65
+
+// Errors a verifier rejects classes of corrupted signatures with. Altering
+// R, S or the recovery ID either recovers another key or no key at all,
+// depending on the values. Signatures that aren't 65 bytes long are taken for
+// BIP-322 signatures by lenient verifiers, which reject them as invalid or,
+// for P2SH addresses, as unsupported.
+var (
+	wantHeader    = []error{verify.ErrInvalidHeaderByte}
+	wantRecovery  = []error{verify.ErrAddressMismatch, verify.ErrInvalidSignature}
+	wantLength    = []error{verify.ErrMalformedSignature, verify.ErrInvalidSignature, verify.ErrUnsupportedAddressType}
+	wantBase64    = []error{verify.ErrMalformedSignature}
+	wantZeroValue = []error{verify.ErrInvalidSignature}
+)
+
+// Mutation is a corrupted copy of a valid signature and the errors a
+// verifier may reject it with
+type Mutation struct {
+	// Name describes the corruption
+	Name string
+
+	// Signature is the corrupted base64 signature
+	Signature string
+
+	// Want lists the errors a verifier may reject the signature with; the
+	// verification error must wrap one of them
+	Want []error
+}
+
+// Mutate returns corrupted copies of a valid compact signature, one per kind
+// of corruption: header bytes out of range, a flipped recovery ID, truncated
+// and padded signatures, swapped, altered or zeroed R and S values, and broken
+// base64. The signature itself isn't checked; it must verify against its
+// message and address for the expectations of the mutations to hold.
+func Mutate(signatureBase64 string) ([]Mutation, error) {
+	sig, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil {
+		return nil, fmt.Errorf("decoding signature: %w", err)
+	}
+	if len(sig) != compactLength {
+		return nil, fmt.Errorf("signature is %d bytes, want %d", len(sig), compactLength)
+	}
+
+	encode := base64.StdEncoding.EncodeToString
+	mutated := func(f func(sig []byte)) string {
+		c := append([]byte(nil), sig...)
+		f(c)
+		return encode(c)
+	}
+
+	return []Mutation{
+		{
+			Name:      "header below range",
+			Signature: mutated(func(sig []byte) { sig[0] = 26 }),
+			Want:      wantHeader,
+		},
+		{
+			Name:      "header above range",
+			Signature: mutated(func(sig []byte) { sig[0] = 0xff }),
+			Want:      wantHeader,
+		},
+		{
+			Name:      "flipped recovery ID",
+			Signature: mutated(func(sig []byte) { sig[0] ^= 0x01 }),
+			Want:      wantRecovery,
+		},
+		{
+			Name:      "truncated",
+			Signature: encode(sig[:compactLength-1]),
+			Want:      wantLength,
+		},
+		{
+			Name:      "padded",
+			Signature: encode(append(append([]byte(nil), sig...), 0)),
+			Want:      wantLength,
+		},
+		{
+			Name:      "header only",
+			Signature: encode(sig[:1]),
+			Want:      wantLength,
+		},
+		{
+			Name: "swapped R and S",
+			Signature: mutated(func(sig []byte) {
+				r := append([]byte(nil), sig[1:33]...)
+				copy(sig[1:33], sig[33:65])
+				copy(sig[33:65], r)
+			}),
+			Want: wantRecovery,
+		},
+		{
+			Name:      "flipped bit in R",
+			Signature: mutated(func(sig []byte) { sig[32] ^= 0x01 }),
+			Want:      wantRecovery,
+		},
+		{
+			Name:      "flipped bit in S",
+			Signature: mutated(func(sig []byte) { sig[64] ^= 0x01 }),
+			Want:      wantRecovery,
+		},
+		{
+			Name:      "zero R",
+			Signature: mutated(func(sig []byte) { clear(sig[1:33]) }),
+			Want:      wantZeroValue,
+		},
+		{
+			Name:      "zero S",
+			Signature: mutated(func(sig []byte) { clear(sig[33:65]) }),
+			Want:      wantZeroValue,
+		},
+		{
+			Name:      "invalid base64 character",
+			Signature: "!" + signatureBase64[1:],
+			Want:      wantBase64,
+		},
+		{
+			Name:      "missing base64 padding",
+			Signature: signatureBase64[:len(signatureBase64)-1],
+			Want:      wantBase64,
+		},
+		{
+			Name:      "truncated base64",
+			Signature: signatureBase64[:len(signatureBase64)-5],
+			Want:      wantBase64,
+		},
+	}, nil
+}
+
+// CheckMutations verifies every mutation of the signature of msg, which
+// must be a valid signed message with a compact signature, and reports a
+// test error for each one that v accepts or rejects with an error other than
+// the expected one
+func CheckMutations(t testing.TB, v verify.MessageVerifier, msg verify.SignedMessage) {
+	t.Helper()
+
+	mutations, err := Mutate(msg.Signature)
+	if err != nil {
+		t.Fatalf("Mutate() error = %v", err)
+	}
+	for _, m := range mutations {
+		mutated := msg
+		mutated.Signature = m.Signature
+		result, err := v.VerifyContext(t.Context(), mutated)
+		if result != nil && result.Valid {
+			t.Errorf("%s: signature %s verified", m.Name, m.Signature)
+			continue
+		}
+		if !slices.ContainsFunc(m.Want, func(want error) bool { return errors.Is(err, want) }) {
+			t.Errorf("%s: error = %v, want one of %v", m.Name, err, m.Want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package verifytest
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/vectors"
+)
+
+func TestCheckMutations(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	corpus, err := vectors.Corpus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, vec := range corpus {
+		t.Run(vec.Name, func(t *testing.T) {
+			params := &chaincfg.MainNetParams
+			if vec.Network == chaincfg.TestNet3Params.Name {
+				params = &chaincfg.TestNet3Params
+			}
+			msg := verify.SignedMessage{Address: vec.Address, Message: vec.Message, Signature: vec.Signature}
+
+			CheckMutations(t, verify.NewVerifier(verify.WithParams(params)), msg)
+			CheckMutations(t, verify.NewVerifier(verify.WithParams(params), verify.WithStrictLength()), msg)
+		})
+	}
+}
+
+func TestMutate(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature string
+		wantErr   bool
+	}{
+		{
+			name:      "Compact signature",
+			signature: "IOeVH/0KqgmS3XKwqCJiwlcHonwxKMQN6fbOW5UsXSDZB4EGCVTXx6c+ZU/Ae5qO94MSBZn2aPOiUsupRIwBaAU=",
+		},
+		{
+			name:      "Invalid base64",
+			signature: "not base64!",
+			wantErr:   true,
+		},
+		{
+			name:      "Not a compact signature",
+			signature: "AAAA",
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mutations, err := Mutate(tt.signature)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Mutate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			seen := make(map[string]bool)
+			for _, m := range mutations {
+				if m.Signature == tt.signature || seen[m.Signature] || len(m.Want) == 0 {
+					t.Errorf("mutation %q = %q, want a distinct corruption with expected errors", m.Name, m.Signature)
+				}
+				seen[m.Signature] = true
+			}
+		})
+	}
+}
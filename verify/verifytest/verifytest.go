@@ -0,0 +1,110 @@
+// Package verifytest provides a scriptable verify.MessageVerifier, so
+// services built on the verify package can unit-test their authentication
+// flows without real keys or signatures:
+//
+//	m := &verifytest.MockVerifier{}
+//	m.Accept(verify.SignedMessage{Address: address, Message: message})
+//	a := auth.New("example.com", auth.WithVerifier(m))
+//
+// Messages that weren't scripted are rejected with verify.ErrInvalidSignature.
+package verifytest
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sero/btc/verify"
+)
+
+// MockVerifier is a verify.MessageVerifier returning scripted responses. The
+// zero value rejects every message. It is safe for concurrent use.
+type MockVerifier struct {
+	// Fallback answers messages matching no scripted response when set.
+	// Without it they are rejected with verify.ErrInvalidSignature.
+	Fallback func(ctx context.Context, msg verify.SignedMessage) (*verify.Result, error)
+
+	mu        sync.Mutex
+	responses []response
+	calls     []verify.SignedMessage
+}
+
+// response is a scripted answer to the messages matching msg
+type response struct {
+	msg    verify.SignedMessage
+	result verify.Result
+	err    error
+}
+
+var _ verify.MessageVerifier = (*MockVerifier)(nil)
+
+// Accept makes the verifier report messages matching msg as valid. An empty
+// signature in msg matches any signature.
+func (m *MockVerifier) Accept(msg verify.SignedMessage) {
+	m.Respond(msg, verify.Result{Valid: true, LowS: true}, nil)
+}
+
+// Reject makes the verifier fail messages matching msg with err, or with
+// verify.ErrInvalidSignature when err is nil. An empty signature in msg
+// matches any signature.
+func (m *MockVerifier) Reject(msg verify.SignedMessage, err error) {
+	if err == nil {
+		err = verify.ErrInvalidSignature
+	}
+	m.Respond(msg, verify.Result{}, err)
+}
+
+// Respond makes the verifier answer messages matching msg with result and
+// err. An empty signature in msg matches any signature. Responses scripted
+// later take precedence, so a test can change its mind.
+func (m *MockVerifier) Respond(msg verify.SignedMessage, result verify.Result, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = append(m.responses, response{msg: msg, result: result, err: err})
+}
+
+// VerifyContext returns the scripted response matching msg
+func (m *MockVerifier) VerifyContext(ctx context.Context, msg verify.SignedMessage) (*verify.Result, error) {
+	m.mu.Lock()
+	m.calls = append(m.calls, msg)
+	for i := len(m.responses) - 1; i >= 0; i-- {
+		r := m.responses[i]
+		if r.matches(msg) {
+			m.mu.Unlock()
+			result := r.result
+			return &result, r.err
+		}
+	}
+	fallback := m.Fallback
+	m.mu.Unlock()
+
+	if fallback != nil {
+		return fallback(ctx, msg)
+	}
+	return &verify.Result{}, verify.ErrInvalidSignature
+}
+
+// Verify is like VerifyContext with a background context
+func (m *MockVerifier) Verify(msg verify.SignedMessage) (*verify.Result, error) {
+	return m.VerifyContext(context.Background(), msg)
+}
+
+// Calls returns the messages the verifier was asked to verify, in order
+func (m *MockVerifier) Calls() []verify.SignedMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]verify.SignedMessage(nil), m.calls...)
+}
+
+// Reset forgets the scripted responses and recorded calls
+func (m *MockVerifier) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses = nil
+	m.calls = nil
+}
+
+// matches reports whether the response answers msg
+func (r response) matches(msg verify.SignedMessage) bool {
+	return r.msg.Address == msg.Address && r.msg.Message == msg.Message &&
+		(r.msg.Signature == "" || r.msg.Signature == msg.Signature)
+}
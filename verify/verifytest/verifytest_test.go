@@ -0,0 +1,131 @@
+package verifytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sero/btc/verify"
+	"github.com/sero/btc/verify/auth"
+)
+
+func TestMockVerifier(t *testing.T) {
+	alice := verify.SignedMessage{Address: "1Alice", Message: "hello", Signature: "sig-a"}
+	bob := verify.SignedMessage{Address: "1Bob", Message: "hello", Signature: "sig-b"}
+
+	tests := []struct {
+		name      string
+		script    func(m *MockVerifier)
+		msg       verify.SignedMessage
+		wantValid bool
+		wantErr   error
+	}{
+		{
+			name:    "Unscripted message",
+			msg:     alice,
+			wantErr: verify.ErrInvalidSignature,
+		},
+		{
+			name:      "Accepted message",
+			script:    func(m *MockVerifier) { m.Accept(alice) },
+			msg:       alice,
+			wantValid: true,
+		},
+		{
+			name: "Accepted with any signature",
+			script: func(m *MockVerifier) {
+				m.Accept(verify.SignedMessage{Address: alice.Address, Message: alice.Message})
+			},
+			msg:       verify.SignedMessage{Address: alice.Address, Message: alice.Message, Signature: "other"},
+			wantValid: true,
+		},
+		{
+			name:    "Accepted with another signature",
+			script:  func(m *MockVerifier) { m.Accept(alice) },
+			msg:     verify.SignedMessage{Address: alice.Address, Message: alice.Message, Signature: "other"},
+			wantErr: verify.ErrInvalidSignature,
+		},
+		{
+			name:    "Another address",
+			script:  func(m *MockVerifier) { m.Accept(alice) },
+			msg:     bob,
+			wantErr: verify.ErrInvalidSignature,
+		},
+		{
+			name:    "Rejected with an error",
+			script:  func(m *MockVerifier) { m.Reject(bob, verify.ErrAddressMismatch) },
+			msg:     bob,
+			wantErr: verify.ErrAddressMismatch,
+		},
+		{
+			name: "Later response takes precedence",
+			script: func(m *MockVerifier) {
+				m.Accept(alice)
+				m.Reject(alice, nil)
+			},
+			msg:     alice,
+			wantErr: verify.ErrInvalidSignature,
+		},
+		{
+			name: "Fallback",
+			script: func(m *MockVerifier) {
+				m.Fallback = func(ctx context.Context, msg verify.SignedMessage) (*verify.Result, error) {
+					return &verify.Result{Valid: msg.Address == bob.Address}, nil
+				}
+			},
+			msg:       bob,
+			wantValid: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &MockVerifier{}
+			if tt.script != nil {
+				tt.script(m)
+			}
+
+			result, err := m.Verify(tt.msg)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %v", err, tt.wantErr)
+			}
+			if result == nil || result.Valid != tt.wantValid {
+				t.Errorf("Verify() = %+v, want valid %v", result, tt.wantValid)
+			}
+			if calls := m.Calls(); len(calls) != 1 || calls[0] != tt.msg {
+				t.Errorf("Calls() = %v, want [%v]", calls, tt.msg)
+			}
+		})
+	}
+}
+
+func TestMockVerifierReset(t *testing.T) {
+	msg := verify.SignedMessage{Address: "1Alice", Message: "hello"}
+	m := &MockVerifier{}
+	m.Accept(msg)
+	m.Verify(msg)
+	m.Reset()
+
+	if _, err := m.Verify(msg); !errors.Is(err, verify.ErrInvalidSignature) {
+		t.Errorf("Verify() after Reset() error = %v, want %v", err, verify.ErrInvalidSignature)
+	}
+	if calls := m.Calls(); len(calls) != 1 {
+		t.Errorf("Calls() after Reset() = %v, want one call", calls)
+	}
+}
+
+func TestMockVerifierAuth(t *testing.T) {
+	m := &MockVerifier{}
+	a := auth.New("example.com", auth.WithVerifier(m))
+
+	c, err := a.Challenge(context.Background(), "1Alice")
+	if err != nil {
+		t.Fatalf("Challenge() error = %v", err)
+	}
+	m.Accept(verify.SignedMessage{Address: c.Address, Message: c.Message})
+
+	address, err := a.Verify(context.Background(), c.Nonce, "not a real signature")
+	if err != nil || address != "1Alice" {
+		t.Errorf("Verify() = %q, %v, want %q", address, err, "1Alice")
+	}
+}
@@ -0,0 +1,67 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/base64"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// LiftXOnlyPubKey lifts a 32-byte BIP-340 x-only public key, as used by
+// Taproot, to the full public key that signed the message. An x-only key
+// stands for both points with its x coordinate; the one recovered from the
+// signature is returned when it matches, and the point with an even y
+// coordinate otherwise, which then fails verification as any other key
+// that didn't sign the message.
+//
+// The returned key can be passed to any of the pubkey-based verification
+// functions.
+func LiftXOnlyPubKey(xOnlyPubKey []byte, message, signatureBase64 string) (*btcec.PublicKey, error) {
+	if len(xOnlyPubKey) == 0 {
+		return nil, ErrEmptyPublicKey
+	}
+	if len(xOnlyPubKey) != schnorr.PubKeyBytesLen {
+		return nil, newVerifyError(ErrInvalidPublicKey, "x-only public key is %d bytes, want %d", len(xOnlyPubKey), schnorr.PubKeyBytesLen)
+	}
+	even, err := schnorr.ParsePubKey(xOnlyPubKey)
+	if err != nil {
+		return nil, newVerifyError(ErrInvalidPublicKey, "%v", err)
+	}
+
+	sigBytes, err := base64.StdEncoding.DecodeString(signatureBase64)
+	if err != nil || len(sigBytes) != compactSignatureLength {
+		return even, nil
+	}
+	digest := magicHash(message)
+	recovered, _, err := recoverPubKey(sigBytes, digest[:])
+	if err != nil || !bytes.Equal(schnorr.SerializePubKey(recovered), xOnlyPubKey) {
+		return even, nil
+	}
+	logEvent(LogLevelDebug, "Lifted x-only public key", "odd_y", recovered.Y().Bit(0) == 1)
+	return recovered, nil
+}
+
+// VerifyBip137SignatureWithXOnlyPubKey is like VerifyBip137SignatureWithPubKey
+// for a 32-byte x-only public key, lifted with LiftXOnlyPubKey.
+func VerifyBip137SignatureWithXOnlyPubKey(xOnlyPubKey []byte, message, signatureBase64 string) (bool, error) {
+	pubKey, err := LiftXOnlyPubKey(xOnlyPubKey, message, signatureBase64)
+	if err != nil {
+		logEvent(LogLevelError, "Invalid x-only public key", "error", err)
+		return false, err
+	}
+	return VerifyBip137SignatureWithPubKey(pubKey, message, signatureBase64)
+}
+
+// VerifyBip137SignatureWithXOnlyPubKeyAndParams is like
+// VerifyBip137SignatureWithXOnlyPubKey, using the provided network
+// parameters.
+func VerifyBip137SignatureWithXOnlyPubKeyAndParams(xOnlyPubKey []byte, message, signatureBase64 string, params *chaincfg.Params) (bool, error) {
+	pubKey, err := LiftXOnlyPubKey(xOnlyPubKey, message, signatureBase64)
+	if err != nil {
+		logEvent(LogLevelError, "Invalid x-only public key", "error", err)
+		return false, err
+	}
+	return VerifyBip137SignatureWithPubKeyAndParams(pubKey, message, signatureBase64, params)
+}
@@ -0,0 +1,89 @@
+package verify
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcec/v2/ecdsa"
+	"github.com/btcsuite/btcd/btcec/v2/schnorr"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestVerifyBip137SignatureWithXOnlyPubKey(t *testing.T) {
+	const message = "x-only test message"
+
+	// A key and its negation share the x-only key, one with an even and one
+	// with an odd y coordinate
+	privKey := testPrivKey("x-only test key")
+	negated := new(btcec.ModNScalar).NegateVal(&privKey.Key)
+	negKey := btcec.PrivKeyFromScalar(negated)
+
+	xOnly := schnorr.SerializePubKey(privKey.PubKey())
+	otherKey := testPrivKey("other key")
+
+	tests := []struct {
+		name      string
+		xOnly     []byte
+		signer    *btcec.PrivateKey
+		wantValid bool
+		wantErr   error
+	}{
+		{name: "Signed by the key", xOnly: xOnly, signer: privKey, wantValid: true},
+		{name: "Signed by the negated key", xOnly: xOnly, signer: negKey, wantValid: true},
+		{name: "Signed by another key", xOnly: xOnly, signer: otherKey, wantValid: false},
+		{name: "Compressed key", xOnly: privKey.PubKey().SerializeCompressed(), signer: privKey, wantErr: ErrInvalidPublicKey},
+		{name: "Not on the curve", xOnly: make([]byte, 32), signer: privKey, wantErr: ErrInvalidPublicKey},
+		{name: "Empty key", signer: privKey, wantErr: ErrEmptyPublicKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			digest := magicHash(message)
+			sig := base64.StdEncoding.EncodeToString(ecdsa.SignCompact(tt.signer, digest[:], true))
+
+			for name, verifyFunc := range map[string]func() (bool, error){
+				"VerifyBip137SignatureWithXOnlyPubKey": func() (bool, error) {
+					return VerifyBip137SignatureWithXOnlyPubKey(tt.xOnly, message, sig)
+				},
+				"VerifyBip137SignatureWithXOnlyPubKeyAndParams": func() (bool, error) {
+					return VerifyBip137SignatureWithXOnlyPubKeyAndParams(tt.xOnly, message, sig, &chaincfg.MainNetParams)
+				},
+			} {
+				valid, err := verifyFunc()
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Errorf("%s() error = %v, want %v", name, err, tt.wantErr)
+					}
+					continue
+				}
+				if valid != tt.wantValid {
+					t.Errorf("%s() = %v, %v, want %v", name, valid, err, tt.wantValid)
+				}
+			}
+		})
+	}
+}
+
+func TestLiftXOnlyPubKey(t *testing.T) {
+	msg := signTestMessage(t, testKeySeed, nil, "lift test message")
+	privKey := testPrivKey(testKeySeed)
+
+	lifted, err := LiftXOnlyPubKey(schnorr.SerializePubKey(privKey.PubKey()), msg.Message, msg.Signature)
+	if err != nil {
+		t.Fatalf("LiftXOnlyPubKey() error = %v", err)
+	}
+	if !lifted.IsEqual(privKey.PubKey()) {
+		t.Errorf("LiftXOnlyPubKey() = %x, want %x", lifted.SerializeCompressed(), privKey.PubKey().SerializeCompressed())
+	}
+
+	// Without a usable signature, the even point is returned
+	lifted, err = LiftXOnlyPubKey(schnorr.SerializePubKey(privKey.PubKey()), msg.Message, "not base64")
+	if err != nil {
+		t.Fatalf("LiftXOnlyPubKey() error = %v", err)
+	}
+	if lifted.Y().Bit(0) != 0 {
+		t.Error("LiftXOnlyPubKey() without a signature returned an odd point")
+	}
+}
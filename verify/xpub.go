@@ -0,0 +1,149 @@
+package verify
+
+import (
+	"bytes"
+	"encoding/hex"
+
+	"github.com/btcsuite/btcd/btcec/v2"
+	"github.com/btcsuite/btcd/btcutil"
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+// DefaultGapLimit is the number of receive and change addresses scanned when
+// no gap limit is given, the gap limit of BIP-44 wallets
+const DefaultGapLimit = 20
+
+// xpubVersions maps the SLIP-132 version bytes of extended public keys to
+// the address type of the wallet and the version of the same key for the
+// network, as set in chaincfg.Params.HDPublicKeyID
+var xpubVersions = []struct {
+	version     [4]byte
+	networkID   [4]byte
+	addressType AddressType
+}{
+	{[4]byte{0x04, 0x88, 0xb2, 0x1e}, [4]byte{0x04, 0x88, 0xb2, 0x1e}, AddressTypeP2PKH},      // xpub
+	{[4]byte{0x04, 0x9d, 0x7c, 0xb2}, [4]byte{0x04, 0x88, 0xb2, 0x1e}, AddressTypeP2SHP2WPKH}, // ypub
+	{[4]byte{0x04, 0xb2, 0x47, 0x46}, [4]byte{0x04, 0x88, 0xb2, 0x1e}, AddressTypeP2WPKH},     // zpub
+	{[4]byte{0x04, 0x35, 0x87, 0xcf}, [4]byte{0x04, 0x35, 0x87, 0xcf}, AddressTypeP2PKH},      // tpub
+	{[4]byte{0x04, 0x4a, 0x52, 0x62}, [4]byte{0x04, 0x35, 0x87, 0xcf}, AddressTypeP2SHP2WPKH}, // upub
+	{[4]byte{0x04, 0x5f, 0x1c, 0xf6}, [4]byte{0x04, 0x35, 0x87, 0xcf}, AddressTypeP2WPKH},     // vpub
+}
+
+// XpubMatch identifies the address of an extended public key that signed a
+// message
+type XpubMatch struct {
+	// Address is the signing address
+	Address string
+
+	// AddressType is the address type of the wallet, given by the version
+	// of the extended key
+	AddressType AddressType
+
+	// Change reports whether the address is a change address, derived at
+	// 1/Index rather than 0/Index
+	Change bool
+
+	// Index is the index of the address on its chain
+	Index uint32
+}
+
+// VerifyAgainstXpub verifies that a message was signed by one of the
+// addresses of a wallet known by its extended public key, using the Bitcoin
+// mainnet parameters. See VerifyAgainstXpubWithParams.
+func VerifyAgainstXpub(xpub, message, signatureBase64 string, gapLimit int) (bool, XpubMatch, error) {
+	return VerifyAgainstXpubWithParams(xpub, message, signatureBase64, gapLimit, &chaincfg.MainNetParams)
+}
+
+// VerifyAgainstXpubWithParams is like VerifyAgainstXpub, using the provided
+// network parameters. The public key is recovered from the signature and
+// compared with the keys of the first gapLimit receive (0/i) and change
+// (1/i) addresses derived from the account-level key; a gap limit of 0 or
+// less scans DefaultGapLimit addresses. xpub, ypub and zpub keys (tpub, upub
+// and vpub on test networks) select P2PKH, P2SH-P2WPKH and P2WPKH addresses.
+func VerifyAgainstXpubWithParams(xpub, message, signatureBase64 string, gapLimit int, params *chaincfg.Params) (bool, XpubMatch, error) {
+	if gapLimit <= 0 {
+		gapLimit = DefaultGapLimit
+	}
+
+	account, addrType, err := parseXpub(xpub, params)
+	if err != nil {
+		logEvent(LogLevelError, "Invalid extended public key", "error", err)
+		return false, XpubMatch{}, err
+	}
+
+	// Recovery validates the inputs and finds the one key to look for
+	rec, err := Recover(message, signatureBase64, params)
+	if err != nil {
+		return false, XpubMatch{}, err
+	}
+	recovered, _ := hex.DecodeString(rec.PubKey)
+	signer, err := btcec.ParsePubKey(recovered)
+	if err != nil {
+		return false, XpubMatch{}, newVerifyError(ErrInvalidSignature, "could not parse recovered pubkey: %v", err)
+	}
+	target := signer.SerializeCompressed()
+
+	for _, change := range []bool{false, true} {
+		var branch uint32
+		if change {
+			branch = 1
+		}
+		chain, err := account.Derive(branch)
+		if err != nil {
+			return false, XpubMatch{}, newVerifyError(ErrInvalidExtendedKey, "deriving chain %d: %v", branch, err)
+		}
+		for i := uint32(0); i < uint32(gapLimit); i++ {
+			child, err := chain.Derive(i)
+			if err != nil {
+				// An invalid child is skipped, as BIP-32 prescribes
+				continue
+			}
+			pubKey, err := child.ECPubKey()
+			if err != nil || !bytes.Equal(pubKey.SerializeCompressed(), target) {
+				continue
+			}
+
+			addr, err := pubKeyHashAddress(addrType, btcutil.Hash160(target), params)
+			if err != nil {
+				return false, XpubMatch{}, err
+			}
+			match := XpubMatch{Address: addr.EncodeAddress(), AddressType: addrType, Change: change, Index: i}
+			// The key matches; verifying against its address also checks
+			// that the header byte suits the address type
+			valid, err := VerifyBip137SignatureWithParams(match.Address, message, signatureBase64, params)
+			if err != nil || !valid {
+				return false, XpubMatch{}, err
+			}
+			logEvent(LogLevelInfo, "Signature matches extended public key", "address", match.Address, "change", change, "index", i)
+			return true, match, nil
+		}
+	}
+
+	logEvent(LogLevelError, "Signer not derived from extended public key", "recovered_pubkey", rec.PubKey, "gap_limit", gapLimit)
+	return false, XpubMatch{}, newVerifyError(ErrAddressMismatch,
+		"signer is not among the first %d receive and change addresses of the extended public key", gapLimit)
+}
+
+// parseXpub parses an extended public key for the network, returning the
+// address type its version stands for
+func parseXpub(xpub string, params *chaincfg.Params) (*hdkeychain.ExtendedKey, AddressType, error) {
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, "", newVerifyError(ErrInvalidExtendedKey, "%v", err)
+	}
+	if key.IsPrivate() {
+		return nil, "", newVerifyError(ErrInvalidExtendedKey, "extended private keys aren't accepted, pass the public key")
+	}
+
+	for _, v := range xpubVersions {
+		if !bytes.Equal(key.Version(), v.version[:]) {
+			continue
+		}
+		if v.networkID != params.HDPublicKeyID {
+			return nil, "", newVerifyError(ErrNetworkMismatch, "extended public key is not for %s", params.Name)
+		}
+		return key, v.addressType, nil
+	}
+	return nil, "", newVerifyError(ErrInvalidExtendedKey, "unknown version %x", key.Version())
+}
@@ -0,0 +1,148 @@
+package verify
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/btcutil/hdkeychain"
+	"github.com/btcsuite/btcd/chaincfg"
+)
+
+func TestVerifyAgainstXpub(t *testing.T) {
+	SetLogLevel(LogLevelNone)
+
+	account := newTestAccount(t, &chaincfg.MainNetParams)
+	xpub := testXpub(t, account, xpubVersions[0].version)
+	zpub := testXpub(t, account, xpubVersions[2].version)
+
+	receive := signWithAccount(t, account, 0, 7, AddressTypeP2PKH, "prove wallet ownership")
+	change := signWithAccount(t, account, 1, 3, AddressTypeP2WPKH, "prove wallet ownership")
+	beyondGap := signWithAccount(t, account, 0, 25, AddressTypeP2PKH, "prove wallet ownership")
+	other := signTestMessage(t, testKeySeed, nil, "prove wallet ownership")
+
+	tests := []struct {
+		name      string
+		xpub      string
+		msg       SignedMessage
+		gapLimit  int
+		want      XpubMatch
+		wantValid bool
+		wantErr   error
+	}{
+		{
+			name:      "Receive address",
+			xpub:      xpub,
+			msg:       receive,
+			want:      XpubMatch{Address: receive.Address, AddressType: AddressTypeP2PKH, Index: 7},
+			wantValid: true,
+		},
+		{
+			name:      "Change address of zpub",
+			xpub:      zpub,
+			msg:       change,
+			want:      XpubMatch{Address: change.Address, AddressType: AddressTypeP2WPKH, Change: true, Index: 3},
+			wantValid: true,
+		},
+		{
+			name:    "Beyond default gap limit",
+			xpub:    xpub,
+			msg:     beyondGap,
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			name:      "Within larger gap limit",
+			xpub:      xpub,
+			msg:       beyondGap,
+			gapLimit:  30,
+			want:      XpubMatch{Address: beyondGap.Address, AddressType: AddressTypeP2PKH, Index: 25},
+			wantValid: true,
+		},
+		{
+			name:    "Other wallet",
+			xpub:    xpub,
+			msg:     other,
+			wantErr: ErrAddressMismatch,
+		},
+		{
+			name:    "Testnet key",
+			xpub:    testXpub(t, newTestAccount(t, &chaincfg.TestNet3Params), xpubVersions[3].version),
+			msg:     receive,
+			wantErr: ErrNetworkMismatch,
+		},
+		{
+			name:    "Private key",
+			xpub:    account.String(),
+			msg:     receive,
+			wantErr: ErrInvalidExtendedKey,
+		},
+		{
+			name:    "Malformed key",
+			xpub:    "xpub123",
+			msg:     receive,
+			wantErr: ErrInvalidExtendedKey,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			valid, match, err := VerifyAgainstXpub(tt.xpub, tt.msg.Message, tt.msg.Signature, tt.gapLimit)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("VerifyAgainstXpub() error = %v, want %v", err, tt.wantErr)
+			}
+			if valid != tt.wantValid || match != tt.want {
+				t.Errorf("VerifyAgainstXpub() = %v, %+v, want %v, %+v", valid, match, tt.wantValid, tt.want)
+			}
+		})
+	}
+}
+
+// Helper function to create a deterministic account-level private key
+func newTestAccount(t *testing.T, params *chaincfg.Params) *hdkeychain.ExtendedKey {
+	t.Helper()
+	master, err := hdkeychain.NewMaster([]byte("verify test wallet seed, 32 byte"), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := master.Derive(hdkeychain.HardenedKeyStart)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account
+}
+
+// Helper function to serialize the public key of an account with the given
+// version bytes
+func testXpub(t *testing.T, account *hdkeychain.ExtendedKey, version [4]byte) string {
+	t.Helper()
+	public, err := account.Neuter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	versioned, err := public.CloneWithVersion(version[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return versioned.String()
+}
+
+// Helper function to sign a message with the key of an account address
+func signWithAccount(t *testing.T, account *hdkeychain.ExtendedKey, branch, index uint32, addrType AddressType, message string) SignedMessage {
+	t.Helper()
+	chain, err := account.Derive(branch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	child, err := chain.Derive(index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	privKey, err := child.ECPrivKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := signMessage(privKey, message, addrType, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return msg
+}
@@ -0,0 +1,200 @@
+// Package verifyfuzz provides fuzz targets for the signature parsing of the
+// verify package, seeded with signatures produced by real wallets.
+//
+// The targets are regular Go fuzz functions, so downstream projects can run
+// them from their own tests:
+//
+//	func FuzzSignatureParsing(f *testing.F) {
+//		verifyfuzz.FuzzSignatureParsing(f)
+//	}
+//
+// and OSS-Fuzz can build them directly with go-118-fuzz-build.
+package verifyfuzz
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/sero/btc/verify"
+)
+
+// seedVectors are compact signatures produced by wallets, one per supported
+// address type
+var seedVectors = []verify.SignedMessage{
+	{
+		Address:   "19f7adDYqhHSJm2v7igFWZAqxXHj1vUa3T",
+		Message:   "test message",
+		Signature: "HFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+	},
+	{
+		Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+		Message:   "test message",
+		Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+	},
+	{
+		Address:   "3L6TyTisPBmrDAj6RoKmDzNnj4eQi54gD2",
+		Message:   "This is an example of a signed message.",
+		Signature: "I3RN5FFvrFwUCAgBVmRRajL+rZTeiXdc7H4k28JP4TMHWsCTAcTMjhl76ktkgWYdW46b8Z2Le4o4Ls21PC7gdQ0=",
+	},
+	{
+		Address:   "bc1qannfxke2tfd4l7vhepehpvt05y83v3qsf6nfkk",
+		Message:   "This is an example of a signed message.",
+		Signature: "KLVddgDZ6afipJFV3fPP2455bCB/qrgzAQ+kH7eCiIm8R89iNIp6qgkjwIMqWJ+rVB6PEutU+3EckOIwfw9msZQ=",
+	},
+	{
+		Address:   "bc1pgc9k3vdmr9aecmwj09qg5qv550qyyrydufyfmxrsvk5474rxenuqrq4lcz",
+		Message:   "hello world",
+		Signature: "H/KLWcCfl/P34V9TdPzcSlG3sdhllArBXjypbz9BBY1GXDRCwYogO50Crznm8I9P/JAfhnojgbV5vPYSAhWA1p0=",
+	},
+}
+
+// Header byte range defined by BIP-0137
+const (
+	headerMin = 27
+	headerMax = 42
+)
+
+// compactSignatureLength is the length of a decoded BIP-0137 signature
+const compactSignatureLength = 65
+
+// FuzzSignatureParsing feeds arbitrary signature bytes to the native engine.
+// Whatever the input, verification must not panic, must reject signatures of
+// the wrong length or with a header byte outside the BIP-0137 ranges, and may
+// only succeed for the address that was recovered.
+func FuzzSignatureParsing(f *testing.F) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	for _, vec := range seedVectors {
+		sig, _ := base64.StdEncoding.DecodeString(vec.Signature)
+		f.Add(uint8(0), sig)
+		f.Add(uint8(0), sig[:compactSignatureLength-1])
+		f.Add(uint8(0), append(sig, 0x00))
+	}
+	f.Add(uint8(0), []byte{})
+	f.Add(uint8(0), make([]byte, compactSignatureLength))
+
+	f.Fuzz(func(t *testing.T, vector uint8, sig []byte) {
+		vec := seedVectors[int(vector)%len(seedVectors)]
+		report := verify.Explain(vec.Address, vec.Message, base64.StdEncoding.EncodeToString(sig))
+
+		switch {
+		case len(sig) == 0:
+			if !errors.Is(report.Err, verify.ErrEmptySignature) {
+				t.Fatalf("empty signature: error = %v, want %v", report.Err, verify.ErrEmptySignature)
+			}
+		case len(sig) != compactSignatureLength:
+			if !errors.Is(report.Err, verify.ErrMalformedSignature) {
+				t.Fatalf("%d-byte signature: error = %v, want %v", len(sig), report.Err, verify.ErrMalformedSignature)
+			}
+		case report.HeaderByte != sig[0]:
+			t.Fatalf("HeaderByte = 0x%02x, want 0x%02x", report.HeaderByte, sig[0])
+		case sig[0] < headerMin || sig[0] > headerMax:
+			if !errors.Is(report.Err, verify.ErrInvalidHeaderByte) {
+				t.Fatalf("header byte 0x%02x: error = %v, want %v", sig[0], report.Err, verify.ErrInvalidHeaderByte)
+			}
+		}
+
+		if report.Valid != (report.Err == nil) {
+			t.Fatalf("Valid = %t with error %v", report.Valid, report.Err)
+		}
+		if report.Valid && report.RecoveredAddress != vec.Address {
+			t.Fatalf("valid signature recovered %s, want %s", report.RecoveredAddress, vec.Address)
+		}
+	})
+}
+
+// FuzzBase64Decoding verifies arbitrary signature strings in every
+// Base64Mode. Base64Strict accepts a subset of what Base64Default accepts,
+// which in turn is a subset of what Base64Permissive accepts, so once the
+// strict mode gets past decoding all modes must reach the same verdict.
+func FuzzBase64Decoding(f *testing.F) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	for _, vec := range seedVectors {
+		f.Add(uint8(0), vec.Signature)
+		f.Add(uint8(0), vec.Signature[:40]+"\n"+vec.Signature[40:])
+		f.Add(uint8(0), vec.Signature[:len(vec.Signature)-1])
+	}
+	f.Add(uint8(0), "not base64")
+	f.Add(uint8(0), "-_-_")
+
+	modes := []verify.Base64Mode{verify.Base64Strict, verify.Base64Default, verify.Base64Permissive}
+
+	f.Fuzz(func(t *testing.T, vector uint8, signature string) {
+		msg := seedVectors[int(vector)%len(seedVectors)]
+		msg.Signature = signature
+
+		var results []*verify.Result
+		var errs []error
+		for _, mode := range modes {
+			// Only compact signatures keep verification fast and native
+			v := verify.NewVerifier(verify.WithBase64Mode(mode), verify.WithStrictLength())
+			result, err := v.Verify(msg)
+			if result == nil {
+				t.Fatalf("%s mode returned a nil result", mode)
+			}
+			results = append(results, result)
+			errs = append(errs, err)
+		}
+
+		if errors.Is(errs[0], verify.ErrMalformedSignature) || errors.Is(errs[0], verify.ErrEmptySignature) {
+			return
+		}
+		for i := 1; i < len(modes); i++ {
+			if results[i].Valid != results[0].Valid || verify.ErrorCodeOf(errs[i]) != verify.ErrorCodeOf(errs[0]) {
+				t.Fatalf("%s mode: valid = %t (%v), %s mode: valid = %t (%v)",
+					modes[i], results[i].Valid, errs[i], modes[0], results[0].Valid, errs[0])
+			}
+		}
+	})
+}
+
+// FuzzHeaderClassification replaces the header byte of wallet signatures.
+// The native engine, VerifyBip137SignatureEx and a strict-header Verifier
+// must agree on the header bytes they accept, and a signature may only verify
+// with a header byte carrying the recovery ID it was produced with.
+func FuzzHeaderClassification(f *testing.F) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	for i := range seedVectors {
+		for header := 0; header < 48; header++ {
+			f.Add(uint8(i), uint8(header))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, vector uint8, header uint8) {
+		msg := seedVectors[int(vector)%len(seedVectors)]
+		sig, err := base64.StdEncoding.DecodeString(msg.Signature)
+		if err != nil {
+			t.Fatalf("invalid seed signature: %v", err)
+		}
+		recID := (sig[0] - headerMin) % 4
+		sig[0] = header
+		msg.Signature = base64.StdEncoding.EncodeToString(sig)
+
+		report := verify.Explain(msg.Address, msg.Message, msg.Signature)
+		valid, info, err := verify.VerifyBip137SignatureEx(msg.Address, msg.Message, msg.Signature)
+		if valid != report.Valid {
+			t.Fatalf("header 0x%02x: VerifyBip137SignatureEx valid = %t (%v), Explain valid = %t (%v)", header, valid, err, report.Valid, report.Err)
+		}
+
+		if header < headerMin || header > headerMax {
+			if !errors.Is(err, verify.ErrInvalidHeaderByte) {
+				t.Fatalf("header 0x%02x: error = %v, want %v", header, err, verify.ErrInvalidHeaderByte)
+			}
+			return
+		}
+		if valid && (header-headerMin)%4 != recID {
+			t.Fatalf("header 0x%02x verified with recovery ID %d, signed with %d", header, (header-headerMin)%4, recID)
+		}
+
+		result, err := verify.NewVerifier(verify.WithStrictHeader()).Verify(msg)
+		if result.Valid && !valid {
+			t.Fatalf("header 0x%02x: strict header verifier accepted a signature that doesn't verify (%v)", header, err)
+		}
+		if result.Valid && info.AddressType == verify.AddressTypeP2TR {
+			t.Fatalf("header 0x%02x: strict header verifier accepted a P2TR signature", header)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package verifyfuzz
+
+import "testing"
+
+// The fuzz targets are exported for other modules; these wrappers let
+// `go test -fuzz` run them in this repository
+
+func FuzzSignatureParsingTarget(f *testing.F) {
+	FuzzSignatureParsing(f)
+}
+
+func FuzzBase64DecodingTarget(f *testing.F) {
+	FuzzBase64Decoding(f)
+}
+
+func FuzzHeaderClassificationTarget(f *testing.F) {
+	FuzzHeaderClassification(f)
+}
@@ -0,0 +1,70 @@
+// Package verifyotel exports the spans of the verify package to
+// OpenTelemetry.
+//
+//	verify.SetTracer(verifyotel.NewTracer(otel.Tracer("btcverify")))
+package verifyotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sero/btc/verify"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NewTracer returns a verify.Tracer starting its spans with t
+func NewTracer(t trace.Tracer) verify.Tracer {
+	return tracer{t: t}
+}
+
+// tracer adapts a trace.Tracer to the verify.Tracer interface
+type tracer struct {
+	t trace.Tracer
+}
+
+// Start starts an OpenTelemetry span below the span of ctx
+func (t tracer) Start(ctx context.Context, name string) (context.Context, verify.Span) {
+	ctx, s := t.t.Start(ctx, name)
+	return ctx, span{s: s}
+}
+
+// span adapts a trace.Span to the verify.Span interface
+type span struct {
+	s trace.Span
+}
+
+// SetAttribute sets an attribute, converting the value to the closest
+// attribute type
+func (s span) SetAttribute(key string, value interface{}) {
+	s.s.SetAttributes(attributeOf(key, value))
+}
+
+// End records err on the span, if any, and ends it
+func (s span) End(err error) {
+	if err != nil {
+		s.s.RecordError(err)
+		s.s.SetStatus(codes.Error, err.Error())
+	}
+	s.s.End()
+}
+
+// attributeOf converts a key-value pair to an attribute, formatting values
+// of types without an attribute type as strings
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	default:
+		return attribute.String(key, fmt.Sprint(v))
+	}
+}
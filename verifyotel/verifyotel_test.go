@@ -0,0 +1,76 @@
+package verifyotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sero/btc/verify"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracer(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	exp := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exp))
+	defer tp.Shutdown(context.Background())
+
+	v := verify.NewVerifier(verify.WithTracer(NewTracer(tp.Tracer("test"))))
+
+	tests := []struct {
+		name       string
+		address    string
+		wantSpans  []string
+		wantStatus codes.Code
+	}{
+		{
+			name:       "Valid signature",
+			address:    "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+			wantSpans:  []string{verify.SpanDecode, verify.SpanRecover, verify.SpanDerive, verify.SpanCompare, verify.SpanVerify},
+			wantStatus: codes.Unset,
+		},
+		{
+			name:       "Address mismatch",
+			address:    "14wPe34dikRzK4tMYvtwMMJCEZbJ7ar35V",
+			wantSpans:  []string{verify.SpanDecode, verify.SpanRecover, verify.SpanDerive, verify.SpanCompare, verify.SpanVerify},
+			wantStatus: codes.Error,
+		},
+		{
+			name:       "Invalid address",
+			address:    "invalid",
+			wantSpans:  []string{verify.SpanDecode, verify.SpanVerify},
+			wantStatus: codes.Error,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp.Reset()
+
+			v.Verify(verify.SignedMessage{
+				Address:   tt.address,
+				Message:   "test message",
+				Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+			})
+
+			spans := exp.GetSpans()
+			if len(spans) != len(tt.wantSpans) {
+				t.Fatalf("got %d spans, want %d", len(spans), len(tt.wantSpans))
+			}
+			root := spans[len(spans)-1]
+			for i, s := range spans {
+				if s.Name != tt.wantSpans[i] {
+					t.Errorf("span %d = %q, want %q", i, s.Name, tt.wantSpans[i])
+				}
+				if i < len(spans)-1 && s.Parent.SpanID() != root.SpanContext.SpanID() {
+					t.Errorf("span %q isn't a child of %q", s.Name, root.Name)
+				}
+			}
+			if root.Status.Code != tt.wantStatus {
+				t.Errorf("root span status = %v, want %v", root.Status.Code, tt.wantStatus)
+			}
+		})
+	}
+}
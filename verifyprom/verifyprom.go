@@ -0,0 +1,98 @@
+// Package verifyprom exports the metrics of the verify package to Prometheus.
+//
+//	m := verifyprom.New("btcverify")
+//	prometheus.MustRegister(m)
+//	verify.SetMetrics(m)
+package verifyprom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sero/btc/verify"
+)
+
+// Metrics implements verify.Metrics with Prometheus collectors. It is itself
+// a prometheus.Collector, so it can be registered as a whole.
+type Metrics struct {
+	verifications *prometheus.CounterVec
+	failures      *prometheus.CounterVec
+	duration      prometheus.Histogram
+	cacheHits     prometheus.Counter
+	cacheMisses   prometheus.Counter
+}
+
+var _ verify.Metrics = (*Metrics)(nil)
+
+// New creates the collectors, prefixing the metric names with namespace:
+//
+//   - <namespace>_verifications_total, by result (valid or invalid)
+//   - <namespace>_verification_failures_total, by reason (the error code)
+//   - <namespace>_verification_duration_seconds
+//   - <namespace>_hash_cache_hits_total and <namespace>_hash_cache_misses_total,
+//     from which the batch message hash cache hit rate can be derived
+func New(namespace string) *Metrics {
+	return &Metrics{
+		verifications: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "verifications_total",
+			Help:      "Number of completed signature verifications by result.",
+		}, []string{"result"}),
+		failures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "verification_failures_total",
+			Help:      "Number of failed signature verifications by reason.",
+		}, []string{"reason"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "verification_duration_seconds",
+			Help:      "Latency of signature verifications.",
+			Buckets:   prometheus.ExponentialBuckets(0.0001, 2, 14),
+		}),
+		cacheHits: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "hash_cache_hits_total",
+			Help:      "Number of batch message hash cache hits.",
+		}),
+		cacheMisses: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "hash_cache_misses_total",
+			Help:      "Number of batch message hash cache misses.",
+		}),
+	}
+}
+
+// ObserveVerification implements verify.Metrics
+func (m *Metrics) ObserveVerification(valid bool, reason verify.ErrorCode, duration time.Duration) {
+	if valid {
+		m.verifications.WithLabelValues("valid").Inc()
+	} else {
+		m.verifications.WithLabelValues("invalid").Inc()
+		m.failures.WithLabelValues(string(reason)).Inc()
+	}
+	m.duration.Observe(duration.Seconds())
+}
+
+// ObserveHashCache implements verify.Metrics
+func (m *Metrics) ObserveHashCache(hits, misses int) {
+	m.cacheHits.Add(float64(hits))
+	m.cacheMisses.Add(float64(misses))
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.verifications.Describe(ch)
+	m.failures.Describe(ch)
+	m.duration.Describe(ch)
+	m.cacheHits.Describe(ch)
+	m.cacheMisses.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.verifications.Collect(ch)
+	m.failures.Collect(ch)
+	m.duration.Collect(ch)
+	m.cacheHits.Collect(ch)
+	m.cacheMisses.Collect(ch)
+}
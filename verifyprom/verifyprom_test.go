@@ -0,0 +1,60 @@
+package verifyprom
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sero/btc/verify"
+)
+
+func TestMetrics(t *testing.T) {
+	verify.SetLogLevel(verify.LogLevelNone)
+
+	m := New("test")
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(m)
+
+	verify.SetMetrics(m)
+	defer verify.SetMetrics(nil)
+
+	valid := verify.SignedMessage{
+		Address:   "1DAag8qiPLHh6hMFVu9qJQm9ro1HtwuyK5",
+		Message:   "test message",
+		Signature: "IFqUo4/sxBEFkfK8mZeeN56V13BqOc0D90oPBChF3gTqMXtNSCTN79UxC33kZ8Mi0cHy4zYCnQfCxTyLpMVXKeA=",
+	}
+	mismatch := valid
+	mismatch.Address = "14wPe34dikRzK4tMYvtwMMJCEZbJ7ar35V"
+
+	verify.VerifyBip137Signature(valid.Address, valid.Message, valid.Signature)
+	verify.VerifyBip137Signature(mismatch.Address, mismatch.Message, mismatch.Signature)
+	verify.NewVerifier().Verify(verify.SignedMessage{Address: valid.Address, Message: valid.Message})
+	verify.VerifyBatch(context.Background(), []verify.SignedMessage{valid, valid, mismatch}, verify.WithMaxConcurrency(1))
+
+	want := `
+# HELP test_verification_failures_total Number of failed signature verifications by reason.
+# TYPE test_verification_failures_total counter
+test_verification_failures_total{reason="address_mismatch"} 2
+test_verification_failures_total{reason="empty_signature"} 1
+# HELP test_verifications_total Number of completed signature verifications by result.
+# TYPE test_verifications_total counter
+test_verifications_total{result="invalid"} 3
+test_verifications_total{result="valid"} 3
+# HELP test_hash_cache_hits_total Number of batch message hash cache hits.
+# TYPE test_hash_cache_hits_total counter
+test_hash_cache_hits_total 2
+# HELP test_hash_cache_misses_total Number of batch message hash cache misses.
+# TYPE test_hash_cache_misses_total counter
+test_hash_cache_misses_total 1
+`
+	names := []string{"test_verifications_total", "test_verification_failures_total", "test_hash_cache_hits_total", "test_hash_cache_misses_total"}
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(want), names...); err != nil {
+		t.Error(err)
+	}
+
+	if got := testutil.CollectAndCount(m, "test_verification_duration_seconds"); got != 1 {
+		t.Errorf("CollectAndCount(duration) = %d, want 1", got)
+	}
+}
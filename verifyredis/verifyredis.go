@@ -0,0 +1,52 @@
+// Package verifyredis stores the nonces of the verify/auth package in Redis,
+// so servers running as several instances consume each challenge and signed
+// message only once.
+//
+//	store := verifyredis.NewNonceStore(redis.NewClient(&redis.Options{Addr: "localhost:6379"}), "btcverify:")
+//	a := auth.New("example.com", auth.WithNonceStore(store))
+package verifyredis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sero/btc/verify/auth"
+)
+
+// NonceStore implements auth.NonceStore with Redis keys expiring with their
+// nonce. Take uses GETDEL, which requires Redis 6.2 or later.
+type NonceStore struct {
+	client redis.UniversalClient
+	prefix string
+}
+
+var _ auth.NonceStore = (*NonceStore)(nil)
+
+// NewNonceStore creates a NonceStore keeping nonces under keys starting
+// with prefix
+func NewNonceStore(client redis.UniversalClient, prefix string) *NonceStore {
+	return &NonceStore{client: client, prefix: prefix}
+}
+
+// Put records a nonce with its data until ttl elapses
+func (s *NonceStore) Put(ctx context.Context, nonce string, data []byte, ttl time.Duration) (bool, error) {
+	// SetNX would keep the key forever
+	if ttl <= 0 {
+		return false, auth.ErrInvalidTTL
+	}
+	return s.client.SetNX(ctx, s.prefix+nonce, data, ttl).Result()
+}
+
+// Take removes a recorded nonce and returns its data
+func (s *NonceStore) Take(ctx context.Context, nonce string) ([]byte, bool, error) {
+	data, err := s.client.GetDel(ctx, s.prefix+nonce).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return data, true, nil
+}
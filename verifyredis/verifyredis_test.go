@@ -0,0 +1,56 @@
+package verifyredis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sero/btc/verify/auth"
+)
+
+func TestNonceStore(t *testing.T) {
+	ctx := context.Background()
+	mr := miniredis.RunT(t)
+	s := NewNonceStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "test:")
+
+	if ok, err := s.Put(ctx, "a", []byte("data"), time.Minute); !ok || err != nil {
+		t.Fatalf("Put() = %v, %v, want true", ok, err)
+	}
+	if !mr.Exists("test:a") {
+		t.Errorf("key test:a not set")
+	}
+	if ok, _ := s.Put(ctx, "a", []byte("other"), time.Minute); ok {
+		t.Errorf("Put() of a recorded nonce = true, want false")
+	}
+
+	data, ok, err := s.Take(ctx, "a")
+	if !ok || err != nil || string(data) != "data" {
+		t.Errorf("Take() = %q, %v, %v, want %q", data, ok, err, "data")
+	}
+	if _, ok, _ := s.Take(ctx, "a"); ok {
+		t.Errorf("second Take() = true, want false")
+	}
+
+	s.Put(ctx, "b", nil, time.Minute)
+	mr.FastForward(2 * time.Minute)
+	if _, ok, _ := s.Take(ctx, "b"); ok {
+		t.Errorf("Take() of an expired nonce = true, want false")
+	}
+}
+
+func TestNonceStoreInvalidTTL(t *testing.T) {
+	mr := miniredis.RunT(t)
+	s := NewNonceStore(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "test:")
+
+	for _, ttl := range []time.Duration{0, -time.Minute} {
+		if ok, err := s.Put(context.Background(), "a", nil, ttl); ok || !errors.Is(err, auth.ErrInvalidTTL) {
+			t.Errorf("Put() with ttl %s = %v, %v, want %v", ttl, ok, err, auth.ErrInvalidTTL)
+		}
+	}
+	if mr.Exists("test:a") {
+		t.Errorf("key test:a set, want nothing stored")
+	}
+}